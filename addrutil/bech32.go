@@ -0,0 +1,147 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrutil
+
+import (
+	"strings"
+)
+
+// bech32Charset is the set of characters used in the data section of bech32
+// strings.  The position of each character is the 5-bit value it encodes.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Encoding identifies the checksum variant of a bech32 string.
+type bech32Encoding int
+
+const (
+	// encodingBech32 is the original checksum variant defined by BIP0173
+	// and used for version 0 segwit addresses.
+	encodingBech32 bech32Encoding = iota
+
+	// encodingBech32m is the modified checksum variant defined by BIP0350
+	// and used for segwit addresses with a version of 1 or higher.
+	encodingBech32m
+)
+
+const (
+	// bech32Const and bech32mConst are the values the checksum of valid
+	// bech32 and bech32m strings respectively produce.
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+
+	// maxBech32Len is the maximum length of a bech32 string.
+	maxBech32Len = 90
+
+	// bech32ChecksumLen is the number of characters in the checksum.
+	bech32ChecksumLen = 6
+)
+
+// bech32Polymod calculates the BCH checksum of the passed values.
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd,
+		0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := uint(0); i < 5; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand expands the human-readable part for use in the checksum.
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// bech32Decode decodes the passed bech32 or bech32m string and returns its
+// lowercase human-readable part, the 5-bit values of its data part without the
+// checksum, and the checksum variant it was encoded with.
+func bech32Decode(s string) (string, []byte, bech32Encoding, error) {
+	if len(s) > maxBech32Len {
+		return "", nil, 0, ErrInvalidFormat
+	}
+
+	// Mixed case strings are not allowed.
+	lower := strings.ToLower(s)
+	if s != lower && s != strings.ToUpper(s) {
+		return "", nil, 0, ErrInvalidFormat
+	}
+	for i := 0; i < len(lower); i++ {
+		if lower[i] < 33 || lower[i] > 126 {
+			return "", nil, 0, ErrInvalidFormat
+		}
+	}
+
+	// The human-readable part is separated from the data by the last
+	// occurrence of '1' and both must be non-empty, with the data part
+	// at least as long as the checksum.
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+bech32ChecksumLen+1 > len(lower) {
+		return "", nil, 0, ErrInvalidFormat
+	}
+	hrp := lower[:sep]
+	data := make([]byte, 0, len(lower)-sep-1)
+	for i := sep + 1; i < len(lower); i++ {
+		v := strings.IndexByte(bech32Charset, lower[i])
+		if v < 0 {
+			return "", nil, 0, ErrInvalidFormat
+		}
+		data = append(data, byte(v))
+	}
+
+	var encoding bech32Encoding
+	switch bech32Polymod(append(bech32HRPExpand(hrp), data...)) {
+	case bech32Const:
+		encoding = encodingBech32
+	case bech32mConst:
+		encoding = encodingBech32m
+	default:
+		return "", nil, 0, ErrBadChecksum
+	}
+
+	return hrp, data[:len(data)-bech32ChecksumLen], encoding, nil
+}
+
+// convertBits regroups the passed values from groups of fromBits bits into
+// groups of toBits bits.  When pad is false, any incomplete group left over
+// must consist of fewer than fromBits zero bits.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxv := uint32(1)<<toBits - 1
+	result := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, ErrInvalidFormat
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, ErrInvalidFormat
+	}
+	return result, nil
+}
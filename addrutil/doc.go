@@ -0,0 +1,23 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package addrutil provides stateless validation of navcoin addresses.
+
+ValidateAddress decodes an address string in any of the supported formats for
+a given network and returns its type along with the script which pays to it.
+Unlike the validateaddress RPC, it does not depend on any wallet state, which
+makes it suitable for validating user input.
+
+The supported formats are base58check encoded pay-to-pubkey-hash and
+pay-to-script-hash addresses, bech32 encoded version 0 segwit addresses as
+defined by BIP0173, and bech32m encoded segwit addresses with a version of 1 or
+higher, such as taproot addresses, as defined by BIP0350.
+
+Addresses which are malformed, fail their checksum, or belong to a different
+network are rejected with ErrInvalidFormat, ErrBadChecksum, and
+ErrWrongNetwork respectively, so callers can report clearly why an address was
+rejected.
+*/
+package addrutil
@@ -0,0 +1,222 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrutil
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/navcoin/navd/chaincfg"
+	"github.com/navcoin/navd/txscript"
+	"github.com/navcoin/navutil/base58"
+)
+
+var (
+	// ErrInvalidFormat describes an error where an address is not encoded
+	// in any of the supported formats or its payload is malformed.
+	ErrInvalidFormat = errors.New("invalid address format")
+
+	// ErrBadChecksum describes an error where the checksum of an address
+	// does not match its contents.
+	ErrBadChecksum = errors.New("bad address checksum")
+
+	// ErrWrongNetwork describes an error where an address is well formed
+	// but belongs to a different network than the one requested.
+	ErrWrongNetwork = errors.New("address is for a different network")
+
+	// ErrUnsupportedWitnessVersion describes an error where a segwit
+	// address is encoded with the checksum variant that does not apply to
+	// its witness version.
+	ErrUnsupportedWitnessVersion = errors.New("witness version does not " +
+		"match address encoding")
+)
+
+// AddressType identifies the type of script an address pays to.
+type AddressType int
+
+const (
+	// PubKeyHashTy is a base58 encoded pay-to-pubkey-hash address.
+	PubKeyHashTy AddressType = iota
+
+	// ScriptHashTy is a base58 encoded pay-to-script-hash address.
+	ScriptHashTy
+
+	// WitnessV0PubKeyHashTy is a bech32 encoded version 0 segwit address
+	// with a 20-byte witness program.
+	WitnessV0PubKeyHashTy
+
+	// WitnessV0ScriptHashTy is a bech32 encoded version 0 segwit address
+	// with a 32-byte witness program.
+	WitnessV0ScriptHashTy
+
+	// WitnessV1TaprootTy is a bech32m encoded version 1 segwit address
+	// with a 32-byte witness program.
+	WitnessV1TaprootTy
+
+	// WitnessUnknownTy is a bech32m encoded segwit address with a witness
+	// version or program length that has no defined meaning yet.
+	WitnessUnknownTy
+)
+
+// addressTypeToName houses the human-readable strings which describe each
+// address type.
+var addressTypeToName = []string{
+	PubKeyHashTy:          "pubkeyhash",
+	ScriptHashTy:          "scripthash",
+	WitnessV0PubKeyHashTy: "witness_v0_keyhash",
+	WitnessV0ScriptHashTy: "witness_v0_scripthash",
+	WitnessV1TaprootTy:    "witness_v1_taproot",
+	WitnessUnknownTy:      "witness_unknown",
+}
+
+// String implements the Stringer interface by returning the name of the
+// address type.
+func (t AddressType) String() string {
+	if int(t) >= len(addressTypeToName) || t < 0 {
+		return "Invalid"
+	}
+	return addressTypeToName[t]
+}
+
+// AddressInfo describes a decoded address.
+type AddressInfo struct {
+	// Type is the type of script the address pays to.
+	Type AddressType
+
+	// ScriptPubKey is the script which pays to the address.
+	ScriptPubKey []byte
+
+	// IsWitness indicates whether the address is a segwit address, in
+	// which case WitnessVersion and WitnessProgram are also set.
+	IsWitness      bool
+	WitnessVersion byte
+	WitnessProgram []byte
+}
+
+// ValidateAddress decodes the passed address for the given network and returns
+// its type along with the script which pays to it.  It does not depend on any
+// wallet state.
+//
+// ErrWrongNetwork is returned when the address is well formed but belongs to a
+// different network, ErrBadChecksum when its checksum does not match, and
+// ErrInvalidFormat or ErrUnsupportedWitnessVersion when it is otherwise
+// malformed.
+func ValidateAddress(addr string, params *chaincfg.Params) (AddressInfo, error) {
+	// Segwit addresses are identified by the human-readable part of any
+	// known network followed by the separator.
+	if sep := strings.LastIndexByte(addr, '1'); sep > 0 &&
+		chaincfg.IsBech32SegwitPrefix(addr[:sep+1]) {
+
+		return validateSegwitAddress(addr, params)
+	}
+
+	return validateBase58Address(addr, params)
+}
+
+// validateBase58Address decodes the passed base58check encoded address for the
+// given network.
+func validateBase58Address(addr string, params *chaincfg.Params) (AddressInfo, error) {
+	payload, netID, err := base58.CheckDecode(addr)
+	if err != nil {
+		if err == base58.ErrChecksum {
+			return AddressInfo{}, ErrBadChecksum
+		}
+		return AddressInfo{}, ErrInvalidFormat
+	}
+	if len(payload) != 20 {
+		return AddressInfo{}, ErrInvalidFormat
+	}
+
+	var info AddressInfo
+	switch netID {
+	case params.PubKeyHashAddrID:
+		info.Type = PubKeyHashTy
+		info.ScriptPubKey, err = txscript.NewScriptBuilder().
+			AddOp(txscript.OP_DUP).AddOp(txscript.OP_HASH160).
+			AddData(payload).AddOp(txscript.OP_EQUALVERIFY).
+			AddOp(txscript.OP_CHECKSIG).Script()
+
+	case params.ScriptHashAddrID:
+		info.Type = ScriptHashTy
+		info.ScriptPubKey, err = txscript.NewScriptBuilder().
+			AddOp(txscript.OP_HASH160).AddData(payload).
+			AddOp(txscript.OP_EQUAL).Script()
+
+	default:
+		if chaincfg.IsPubKeyHashAddrID(netID) ||
+			chaincfg.IsScriptHashAddrID(netID) {
+
+			return AddressInfo{}, ErrWrongNetwork
+		}
+		return AddressInfo{}, ErrInvalidFormat
+	}
+	if err != nil {
+		return AddressInfo{}, err
+	}
+
+	return info, nil
+}
+
+// validateSegwitAddress decodes the passed bech32 or bech32m encoded segwit
+// address for the given network.
+func validateSegwitAddress(addr string, params *chaincfg.Params) (AddressInfo, error) {
+	hrp, data, encoding, err := bech32Decode(addr)
+	if err != nil {
+		return AddressInfo{}, err
+	}
+	if hrp != params.Bech32HRPSegwit {
+		return AddressInfo{}, ErrWrongNetwork
+	}
+
+	// The first value of the data is the witness version and the rest is
+	// the witness program regrouped into 5-bit values.
+	if len(data) < 1 || data[0] > 16 {
+		return AddressInfo{}, ErrInvalidFormat
+	}
+	version := data[0]
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return AddressInfo{}, err
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return AddressInfo{}, ErrInvalidFormat
+	}
+
+	// Version 0 addresses must use bech32 while later versions must use
+	// bech32m.
+	if (version == 0) != (encoding == encodingBech32) {
+		return AddressInfo{}, ErrUnsupportedWitnessVersion
+	}
+
+	info := AddressInfo{
+		IsWitness:      true,
+		WitnessVersion: version,
+		WitnessProgram: program,
+	}
+	switch {
+	case version == 0 && len(program) == 20:
+		info.Type = WitnessV0PubKeyHashTy
+	case version == 0 && len(program) == 32:
+		info.Type = WitnessV0ScriptHashTy
+	case version == 0:
+		return AddressInfo{}, ErrInvalidFormat
+	case version == 1 && len(program) == 32:
+		info.Type = WitnessV1TaprootTy
+	default:
+		info.Type = WitnessUnknownTy
+	}
+
+	versionOp := byte(txscript.OP_0)
+	if version != 0 {
+		versionOp = txscript.OP_1 + version - 1
+	}
+	info.ScriptPubKey, err = txscript.NewScriptBuilder().AddOp(versionOp).
+		AddData(program).Script()
+	if err != nil {
+		return AddressInfo{}, err
+	}
+
+	return info, nil
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg"
+)
+
+// hexToBytes converts the passed hex string into bytes and will panic if there
+// is an error.  This is only provided for the hard-coded constants so errors in
+// the source code can be detected.  It will only (and must only) be called with
+// hard-coded values.
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
+
+// TestValidateAddress ensures addresses of each supported type decode to the
+// expected type and script and that malformed addresses and addresses for
+// other networks are rejected.
+func TestValidateAddress(t *testing.T) {
+	t.Parallel()
+
+	// All of the addresses below commit to the same 20-byte hash or 32-byte
+	// witness program made up of the incrementing values from 1.
+	const hash20 = "0102030405060708090a0b0c0d0e0f1011121314"
+	const hash32 = "0102030405060708090a0b0c0d0e0f10111213141516171819" +
+		"1a1b1c1d1e1f20"
+
+	tests := []struct {
+		name    string
+		addr    string
+		params  *chaincfg.Params
+		addrTy  AddressType
+		version byte
+		script  []byte
+		err     error
+	}{
+		{
+			name:   "mainnet p2pkh",
+			addr:   "NL1JGjDe22U44R57ZXVSeRa4T7Jo1HDLF4",
+			params: &chaincfg.MainNetParams,
+			addrTy: PubKeyHashTy,
+			script: hexToBytes("76a914" + hash20 + "88ac"),
+		},
+		{
+			name:   "mainnet p2sh",
+			addr:   "bCpbnCkrjoJ6EHXtLx9eASHEbFYyikt35C",
+			params: &chaincfg.MainNetParams,
+			addrTy: ScriptHashTy,
+			script: hexToBytes("a914" + hash20 + "87"),
+		},
+		{
+			name:   "mainnet p2wpkh",
+			addr:   "nav1qqypqxpq9qcrsszg2pvxq6rs0zqg3yyc53gcaqc",
+			params: &chaincfg.MainNetParams,
+			addrTy: WitnessV0PubKeyHashTy,
+			script: hexToBytes("0014" + hash20),
+		},
+		{
+			name:   "mainnet p2wpkh uppercase",
+			addr:   "NAV1QQYPQXPQ9QCRSSZG2PVXQ6RS0ZQG3YYC53GCAQC",
+			params: &chaincfg.MainNetParams,
+			addrTy: WitnessV0PubKeyHashTy,
+			script: hexToBytes("0014" + hash20),
+		},
+		{
+			name: "mainnet p2wsh",
+			addr: "nav1qqypqxpq9qcrsszg2pvxq6rs0zqg3yyc5z5tpwxqergd3c8g7" +
+				"rusqyyvemx",
+			params: &chaincfg.MainNetParams,
+			addrTy: WitnessV0ScriptHashTy,
+			script: hexToBytes("0020" + hash32),
+		},
+		{
+			name: "mainnet p2tr",
+			addr: "nav1pqypqxpq9qcrsszg2pvxq6rs0zqg3yyc5z5tpwxqergd3c8g7" +
+				"rusqwnvsr6",
+			params:  &chaincfg.MainNetParams,
+			addrTy:  WitnessV1TaprootTy,
+			version: 1,
+			script:  hexToBytes("5120" + hash32),
+		},
+		{
+			name:    "mainnet witness v2",
+			addr:    "nav1zqypqxpq9qcrsszg2pvxq6rs0zqg3yyc5jp08lv",
+			params:  &chaincfg.MainNetParams,
+			addrTy:  WitnessUnknownTy,
+			version: 2,
+			script:  hexToBytes("5214" + hash20),
+		},
+		{
+			name:   "testnet p2pkh",
+			addr:   "mfcHP2WMCVLsVZA8yrovmhMgxNFW9r98xw",
+			params: &chaincfg.TestNet3Params,
+			addrTy: PubKeyHashTy,
+			script: hexToBytes("76a914" + hash20 + "88ac"),
+		},
+		{
+			name:   "testnet p2sh",
+			addr:   "2MsLZ5FqqYpjM1Q1W4X81zMVZTF9gdbhVwd",
+			params: &chaincfg.TestNet3Params,
+			addrTy: ScriptHashTy,
+			script: hexToBytes("a914" + hash20 + "87"),
+		},
+		{
+			name: "testnet p2tr",
+			addr: "tb1pqypqxpq9qcrsszg2pvxq6rs0zqg3yyc5z5tpwxqergd3c8g7" +
+				"rusqe7ea7u",
+			params:  &chaincfg.TestNet3Params,
+			addrTy:  WitnessV1TaprootTy,
+			version: 1,
+			script:  hexToBytes("5120" + hash32),
+		},
+		{
+			name:   "testnet p2pkh on mainnet",
+			addr:   "mfcHP2WMCVLsVZA8yrovmhMgxNFW9r98xw",
+			params: &chaincfg.MainNetParams,
+			err:    ErrWrongNetwork,
+		},
+		{
+			name:   "testnet p2sh on mainnet",
+			addr:   "2MsLZ5FqqYpjM1Q1W4X81zMVZTF9gdbhVwd",
+			params: &chaincfg.MainNetParams,
+			err:    ErrWrongNetwork,
+		},
+		{
+			name:   "testnet p2wpkh on mainnet",
+			addr:   "tb1qqypqxpq9qcrsszg2pvxq6rs0zqg3yyc5r7fxez",
+			params: &chaincfg.MainNetParams,
+			err:    ErrWrongNetwork,
+		},
+		{
+			name:   "mainnet p2pkh on testnet",
+			addr:   "NL1JGjDe22U44R57ZXVSeRa4T7Jo1HDLF4",
+			params: &chaincfg.TestNet3Params,
+			err:    ErrWrongNetwork,
+		},
+		{
+			name: "mainnet p2tr on testnet",
+			addr: "nav1pqypqxpq9qcrsszg2pvxq6rs0zqg3yyc5z5tpwxqergd3c8g7" +
+				"rusqwnvsr6",
+			params: &chaincfg.TestNet3Params,
+			err:    ErrWrongNetwork,
+		},
+		{
+			name:   "base58 bad checksum",
+			addr:   "NL1JGjDe22U44R57ZXVSeRa4T7Jo1HDLF5",
+			params: &chaincfg.MainNetParams,
+			err:    ErrBadChecksum,
+		},
+		{
+			name:   "bech32 bad checksum",
+			addr:   "nav1qqypqxpq9qcrsszg2pvxq6rs0zqg3yyc53gcaqd",
+			params: &chaincfg.MainNetParams,
+			err:    ErrBadChecksum,
+		},
+		{
+			name: "taproot with bech32 checksum",
+			addr: "nav1pqypqxpq9qcrsszg2pvxq6rs0zqg3yyc5z5tpwxqergd3c8g7" +
+				"rusqm0uuxc",
+			params: &chaincfg.MainNetParams,
+			err:    ErrUnsupportedWitnessVersion,
+		},
+		{
+			name:   "version 0 with bech32m checksum",
+			addr:   "nav1qqypqxpq9qcrsszg2pvxq6rs0zqg3yyc5y5g396",
+			params: &chaincfg.MainNetParams,
+			err:    ErrUnsupportedWitnessVersion,
+		},
+		{
+			name:   "mixed case",
+			addr:   "nav1qqypqxpq9qcrsszg2pvxq6rs0zqg3yyc53gcaQC",
+			params: &chaincfg.MainNetParams,
+			err:    ErrInvalidFormat,
+		},
+		{
+			name:   "unknown bech32 prefix",
+			addr:   "bc1qqypqxpq9qcrsszg2pvxq6rs0zqg3yyc5fcj4z3",
+			params: &chaincfg.MainNetParams,
+			err:    ErrInvalidFormat,
+		},
+		{
+			name:   "empty",
+			addr:   "",
+			params: &chaincfg.MainNetParams,
+			err:    ErrInvalidFormat,
+		},
+	}
+
+	for _, test := range tests {
+		info, err := ValidateAddress(test.addr, test.params)
+		if err != test.err {
+			t.Errorf("%s: unexpected error - got %v, want %v",
+				test.name, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if info.Type != test.addrTy {
+			t.Errorf("%s: unexpected type - got %v, want %v",
+				test.name, info.Type, test.addrTy)
+			continue
+		}
+		if !bytes.Equal(info.ScriptPubKey, test.script) {
+			t.Errorf("%s: unexpected script - got %x, want %x",
+				test.name, info.ScriptPubKey, test.script)
+			continue
+		}
+		wantWitness := test.addrTy >= WitnessV0PubKeyHashTy
+		if info.IsWitness != wantWitness {
+			t.Errorf("%s: unexpected witness flag - got %v, want %v",
+				test.name, info.IsWitness, wantWitness)
+			continue
+		}
+		if wantWitness && info.WitnessVersion != test.version {
+			t.Errorf("%s: unexpected witness version - got %d, "+
+				"want %d", test.name, info.WitnessVersion,
+				test.version)
+			continue
+		}
+	}
+}
+
+// TestBech32Decode ensures the bech32 and bech32m test vectors from BIP0173 and
+// BIP0350 decode with the expected checksum variant.
+func TestBech32Decode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		str      string
+		encoding bech32Encoding
+		program  []byte
+		err      error
+	}{
+		{
+			str:      "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+			encoding: encodingBech32,
+			program:  hexToBytes("751e76e8199196d454941c45d1b3a323f1433bd6"),
+		},
+		{
+			str: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7" +
+				"vqzk5jj0",
+			encoding: encodingBech32m,
+			program: hexToBytes("79be667ef9dcbbac55a06295ce870b07029bfcdb2" +
+				"dce28d959f2815b16f81798"),
+		},
+		{
+			// A version 1 program with a bech32 checksum is decoded
+			// as such so the address validation can reject it.
+			str: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7" +
+				"vqh2y7hd",
+			encoding: encodingBech32,
+			program: hexToBytes("79be667ef9dcbbac55a06295ce870b07029bfcdb2" +
+				"dce28d959f2815b16f81798"),
+		},
+		{
+			str: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7" +
+				"vqzk5jjq",
+			err: ErrBadChecksum,
+		},
+		{
+			str: "an84characterslonghumanreadablepartthatcontainsthenumber" +
+				"1andtheexcludedcharactersbio1569pvx",
+			err: ErrInvalidFormat,
+		},
+		{
+			str: "1qzzfhee",
+			err: ErrInvalidFormat,
+		},
+	}
+
+	for _, test := range tests {
+		_, data, encoding, err := bech32Decode(test.str)
+		if err != test.err {
+			t.Errorf("%s: unexpected error - got %v, want %v",
+				test.str, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if encoding != test.encoding {
+			t.Errorf("%s: unexpected encoding - got %v, want %v",
+				test.str, encoding, test.encoding)
+			continue
+		}
+		program, err := convertBits(data[1:], 5, 8, false)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.str, err)
+			continue
+		}
+		if !bytes.Equal(program, test.program) {
+			t.Errorf("%s: unexpected program - got %x, want %x",
+				test.str, program, test.program)
+			continue
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package bip38
+
+import (
+	"bytes"
+	"crypto/aes"
+	"errors"
+	"math/big"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navutil"
+	"github.com/navcoin/navutil/base58"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// encryptedKeyLen is the length of a decoded encrypted key including
+	// the two prefix bytes and excluding the base58check checksum.
+	encryptedKeyLen = 39
+
+	// prefixNoECMultiply and prefixECMultiply are the second byte of the
+	// two byte prefix which identifies the variant of an encrypted key.
+	// The first byte is always 0x01.
+	prefixNoECMultiply = 0x42
+	prefixECMultiply   = 0x43
+
+	// flagNoECMultiply is set in the flag byte of keys encrypted without
+	// EC multiplication.
+	flagNoECMultiply = 0xc0
+
+	// flagCompressed is set in the flag byte when the address the key
+	// commits to uses the compressed public key.
+	flagCompressed = 0x20
+
+	// flagLotSequence is set in the flag byte of EC-multiplied keys whose
+	// owner entropy includes a lot and sequence number.
+	flagLotSequence = 0x04
+
+	// Scrypt parameters used to derive the key which encrypts the private
+	// key from the passphrase and the address hash.
+	scryptN      = 16384
+	scryptR      = 8
+	scryptP      = 8
+	scryptKeyLen = 64
+
+	// Scrypt parameters used to derive the encryption key of EC-multiplied
+	// keys from the passphrase point and the owner entropy.
+	seedScryptN = 1024
+	seedScryptR = 1
+	seedScryptP = 1
+)
+
+var (
+	// ErrInvalidEncryptedKey describes an error in which the string passed
+	// to DecryptBIP38 is not a well formed BIP0038 encrypted key.
+	ErrInvalidEncryptedKey = errors.New("malformed BIP0038 encrypted key")
+
+	// ErrWrongPassphrase describes an error in which the key decrypted
+	// with the passphrase passed to DecryptBIP38 does not match the
+	// address hash committed to by the encrypted key, which means the
+	// passphrase was incorrect.
+	ErrWrongPassphrase = errors.New("incorrect passphrase for BIP0038 " +
+		"encrypted key")
+)
+
+// addressHash returns the first four bytes of the double SHA-256 of the
+// pay-to-pubkey-hash address of the passed public key on the given network.
+func addressHash(pubKey *btcec.PublicKey, compressed bool, net *chaincfg.Params) []byte {
+	var serialized []byte
+	if compressed {
+		serialized = pubKey.SerializeCompressed()
+	} else {
+		serialized = pubKey.SerializeUncompressed()
+	}
+	addr := base58.CheckEncode(navutil.Hash160(serialized),
+		net.PubKeyHashAddrID)
+	return chainhash.DoubleHashB([]byte(addr))[:4]
+}
+
+// xorBytes returns the exclusive or of the passed equal length slices.
+func xorBytes(a, b []byte) []byte {
+	result := make([]byte, len(a))
+	for i := range a {
+		result[i] = a[i] ^ b[i]
+	}
+	return result
+}
+
+// EncryptBIP38 encrypts the passed private key with the passphrase using the
+// non-EC-multiply variant of BIP0038 and returns the encrypted key.  The
+// compressed flag selects whether the key is associated with the address of
+// its compressed or uncompressed public key.  The address hash is calculated
+// for the main network.
+func EncryptBIP38(key *btcec.PrivateKey, passphrase string, compressed bool) (string, error) {
+	return EncryptBIP38WithParams(key, passphrase, compressed,
+		&chaincfg.MainNetParams)
+}
+
+// EncryptBIP38WithParams is the same as EncryptBIP38 except the address hash
+// is calculated for the network described by the passed chain parameters.
+func EncryptBIP38WithParams(key *btcec.PrivateKey, passphrase string, compressed bool, net *chaincfg.Params) (string, error) {
+	addrHash := addressHash(key.PubKey(), compressed, net)
+	derived, err := scrypt.Key([]byte(passphrase), addrHash, scryptN,
+		scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return "", err
+	}
+	derivedHalf1, derivedHalf2 := derived[:32], derived[32:]
+
+	block, err := aes.NewCipher(derivedHalf2)
+	if err != nil {
+		return "", err
+	}
+	privKey := key.Serialize()
+	encrypted := xorBytes(privKey, derivedHalf1)
+	block.Encrypt(encrypted[:16], encrypted[:16])
+	block.Encrypt(encrypted[16:], encrypted[16:])
+
+	flag := byte(flagNoECMultiply)
+	if compressed {
+		flag |= flagCompressed
+	}
+
+	// The encoded key starts with the 0x01 version byte followed by the
+	// second prefix byte so base58check encoding produces a "6P" prefix.
+	payload := make([]byte, 0, encryptedKeyLen-1)
+	payload = append(payload, prefixNoECMultiply, flag)
+	payload = append(payload, addrHash...)
+	payload = append(payload, encrypted...)
+	return base58.CheckEncode(payload, 0x01), nil
+}
+
+// DecryptBIP38 decrypts the passed BIP0038 encrypted key with the passphrase.
+// Both the non-EC-multiply and EC-multiply variants are supported.  The
+// returned boolean reports whether the key is associated with the address of
+// its compressed public key.  The address hash is verified against the main
+// network.
+//
+// ErrInvalidEncryptedKey is returned when the string is not a well formed
+// encrypted key and ErrWrongPassphrase is returned when the decrypted key does
+// not match the address hash, which indicates an incorrect passphrase.
+func DecryptBIP38(encrypted, passphrase string) (*btcec.PrivateKey, bool, error) {
+	return DecryptBIP38WithParams(encrypted, passphrase,
+		&chaincfg.MainNetParams)
+}
+
+// DecryptBIP38WithParams is the same as DecryptBIP38 except the address hash
+// is verified against the network described by the passed chain parameters.
+func DecryptBIP38WithParams(encrypted, passphrase string, net *chaincfg.Params) (*btcec.PrivateKey, bool, error) {
+	payload, version, err := base58.CheckDecode(encrypted)
+	if err != nil || version != 0x01 || len(payload) != encryptedKeyLen-1 {
+		return nil, false, ErrInvalidEncryptedKey
+	}
+
+	flag := payload[1]
+	compressed := flag&flagCompressed != 0
+	addrHash := payload[2:6]
+
+	var key *btcec.PrivateKey
+	switch payload[0] {
+	case prefixNoECMultiply:
+		if flag&^flagCompressed != flagNoECMultiply {
+			return nil, false, ErrInvalidEncryptedKey
+		}
+		key, err = decryptNoECMultiply(payload[6:], addrHash, passphrase)
+
+	case prefixECMultiply:
+		if flag&^(flagCompressed|flagLotSequence) != 0 {
+			return nil, false, ErrInvalidEncryptedKey
+		}
+		key, err = decryptECMultiply(payload[6:], addrHash,
+			flag&flagLotSequence != 0, passphrase)
+
+	default:
+		return nil, false, ErrInvalidEncryptedKey
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !bytes.Equal(addressHash(key.PubKey(), compressed, net), addrHash) {
+		return nil, false, ErrWrongPassphrase
+	}
+	return key, compressed, nil
+}
+
+// decryptNoECMultiply decrypts the 32 byte encrypted private key of a key
+// encrypted with the non-EC-multiply variant.
+func decryptNoECMultiply(encrypted, addrHash []byte, passphrase string) (*btcec.PrivateKey, error) {
+	derived, err := scrypt.Key([]byte(passphrase), addrHash, scryptN,
+		scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	derivedHalf1, derivedHalf2 := derived[:32], derived[32:]
+
+	block, err := aes.NewCipher(derivedHalf2)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make([]byte, 32)
+	block.Decrypt(decrypted[:16], encrypted[:16])
+	block.Decrypt(decrypted[16:], encrypted[16:])
+
+	privKey := new(big.Int).SetBytes(xorBytes(decrypted, derivedHalf1))
+	if privKey.Sign() == 0 || privKey.Cmp(btcec.S256().N) >= 0 {
+		return nil, ErrWrongPassphrase
+	}
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), privKey.Bytes())
+	return key, nil
+}
+
+// decryptECMultiply recovers the private key of a key produced with the
+// EC-multiply variant.  The passed data consists of the 8 byte owner entropy,
+// the first 8 bytes of the first encrypted part, and the 16 byte second
+// encrypted part.
+func decryptECMultiply(data, addrHash []byte, lotSequence bool, passphrase string) (*btcec.PrivateKey, error) {
+	ownerEntropy := data[:8]
+	encryptedPart1 := data[8:16]
+	encryptedPart2 := data[16:32]
+
+	// The owner salt excludes the lot and sequence number when present.
+	ownerSalt := ownerEntropy
+	if lotSequence {
+		ownerSalt = ownerEntropy[:4]
+	}
+	passFactor, err := scrypt.Key([]byte(passphrase), ownerSalt, scryptN,
+		scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	if lotSequence {
+		passFactor = chainhash.DoubleHashB(append(passFactor,
+			ownerEntropy...))
+	}
+
+	curve := btcec.S256()
+	passFactorInt := new(big.Int).SetBytes(passFactor)
+	if passFactorInt.Sign() == 0 || passFactorInt.Cmp(curve.N) >= 0 {
+		return nil, ErrWrongPassphrase
+	}
+	_, passPoint := btcec.PrivKeyFromBytes(curve, passFactor)
+
+	salt := make([]byte, 0, 12)
+	salt = append(salt, addrHash...)
+	salt = append(salt, ownerEntropy...)
+	derived, err := scrypt.Key(passPoint.SerializeCompressed(), salt,
+		seedScryptN, seedScryptR, seedScryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	derivedHalf1, derivedHalf2 := derived[:32], derived[32:]
+
+	block, err := aes.NewCipher(derivedHalf2)
+	if err != nil {
+		return nil, err
+	}
+
+	// The second encrypted part holds the last 8 bytes of the first
+	// encrypted part followed by the last 8 bytes of seedb.
+	decrypted := make([]byte, 16)
+	block.Decrypt(decrypted, encryptedPart2)
+	decrypted = xorBytes(decrypted, derivedHalf1[16:])
+
+	seedB := make([]byte, 24)
+	copy(seedB[16:], decrypted[8:])
+
+	part1 := make([]byte, 0, 16)
+	part1 = append(part1, encryptedPart1...)
+	part1 = append(part1, decrypted[:8]...)
+	block.Decrypt(seedB[:16], part1)
+	copy(seedB[:16], xorBytes(seedB[:16], derivedHalf1[:16]))
+
+	factorB := new(big.Int).SetBytes(chainhash.DoubleHashB(seedB))
+	privKey := factorB.Mul(factorB, passFactorInt)
+	privKey.Mod(privKey, curve.N)
+	if privKey.Sign() == 0 {
+		return nil, ErrWrongPassphrase
+	}
+	key, _ := btcec.PrivKeyFromBytes(curve, privKey.Bytes())
+	return key, nil
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package bip38
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg"
+)
+
+// bitcoinParams returns chain parameters using the bitcoin main network
+// pay-to-pubkey-hash address version so the vectors published with BIP0038
+// can be reproduced.
+func bitcoinParams() *chaincfg.Params {
+	params := chaincfg.MainNetParams
+	params.PubKeyHashAddrID = 0x00
+	return &params
+}
+
+// hexToBytes converts the passed hex string into bytes and will panic if there
+// is an error.  This is only provided for the hard-coded constants so errors in
+// the source code can be detected.  It will only (and must only) be called with
+// hard-coded values.
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
+
+// TestBIP38Vectors ensures the test vectors published with BIP0038 decrypt to
+// the expected keys and that keys encrypted without EC multiplication
+// reproduce the published encrypted keys.
+func TestBIP38Vectors(t *testing.T) {
+	tests := []struct {
+		name       string
+		encrypted  string
+		passphrase string
+		privKey    string
+		compressed bool
+		ecMultiply bool
+	}{
+		{
+			name:       "no EC multiply, uncompressed",
+			encrypted:  "6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGg",
+			passphrase: "TestingOneTwoThree",
+			privKey:    "cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+		},
+		{
+			name:       "no EC multiply, compressed",
+			encrypted:  "6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo",
+			passphrase: "TestingOneTwoThree",
+			privKey:    "cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+			compressed: true,
+		},
+		{
+			name:       "EC multiply, no lot and sequence",
+			encrypted:  "6PfQu77ygVyJLZjfvMLyhLMQbYnu5uguoJJ4kMCLqWwPEdfpwANVS76gTX",
+			passphrase: "TestingOneTwoThree",
+			privKey:    "a43a940577f4e97f5c4d39eb14ff083a98187c64ea7c99ef7ce460833959a519",
+			ecMultiply: true,
+		},
+		{
+			name:       "EC multiply, lot and sequence",
+			encrypted:  "6PgNBNNzDkKdhkT6uJntUXwwzQV8Rr2tZcbkDcuC9DZRsS6AtHts4Ypo1j",
+			passphrase: "MOLON LABE",
+			privKey:    "44ea95afbf138356a05ea32110dfd627232d0f2991ad221187be356f19fa8190",
+			ecMultiply: true,
+		},
+	}
+
+	net := bitcoinParams()
+	for _, test := range tests {
+		key, compressed, err := DecryptBIP38WithParams(test.encrypted,
+			test.passphrase, net)
+		if err != nil {
+			t.Errorf("%s: unexpected decrypt error: %v", test.name, err)
+			continue
+		}
+		wantKey := hexToBytes(test.privKey)
+		if !bytes.Equal(key.Serialize(), wantKey) {
+			t.Errorf("%s: mismatched private key - got %x, want %x",
+				test.name, key.Serialize(), wantKey)
+			continue
+		}
+		if compressed != test.compressed {
+			t.Errorf("%s: mismatched compressed flag - got %v, want %v",
+				test.name, compressed, test.compressed)
+			continue
+		}
+
+		// Keys produced with EC multiplication can only be decrypted.
+		if test.ecMultiply {
+			continue
+		}
+		encrypted, err := EncryptBIP38WithParams(key, test.passphrase,
+			test.compressed, net)
+		if err != nil {
+			t.Errorf("%s: unexpected encrypt error: %v", test.name, err)
+			continue
+		}
+		if encrypted != test.encrypted {
+			t.Errorf("%s: mismatched encrypted key - got %s, want %s",
+				test.name, encrypted, test.encrypted)
+			continue
+		}
+	}
+}
+
+// TestBIP38RoundTrip ensures keys encrypted for the main network decrypt to
+// the original key and that decryption with the wrong passphrase or network
+// fails the address hash check.
+func TestBIP38RoundTrip(t *testing.T) {
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), hexToBytes(
+		"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d"))
+
+	for _, compressed := range []bool{false, true} {
+		encrypted, err := EncryptBIP38(key, "passphrase", compressed)
+		if err != nil {
+			t.Fatalf("EncryptBIP38 (compressed %v): unexpected error: %v",
+				compressed, err)
+		}
+		if encrypted[:2] != "6P" {
+			t.Errorf("EncryptBIP38 (compressed %v): unexpected prefix "+
+				"in %s", compressed, encrypted)
+		}
+
+		gotKey, gotCompressed, err := DecryptBIP38(encrypted, "passphrase")
+		if err != nil {
+			t.Fatalf("DecryptBIP38 (compressed %v): unexpected error: %v",
+				compressed, err)
+		}
+		if !bytes.Equal(gotKey.Serialize(), key.Serialize()) {
+			t.Errorf("DecryptBIP38 (compressed %v): mismatched key - "+
+				"got %x, want %x", compressed, gotKey.Serialize(),
+				key.Serialize())
+		}
+		if gotCompressed != compressed {
+			t.Errorf("DecryptBIP38 (compressed %v): mismatched "+
+				"compressed flag %v", compressed, gotCompressed)
+		}
+
+		_, _, err = DecryptBIP38(encrypted, "wrong passphrase")
+		if err != ErrWrongPassphrase {
+			t.Errorf("DecryptBIP38 (compressed %v): wrong passphrase "+
+				"- got error %v, want %v", compressed, err,
+				ErrWrongPassphrase)
+		}
+
+		_, _, err = DecryptBIP38WithParams(encrypted, "passphrase",
+			bitcoinParams())
+		if err != ErrWrongPassphrase {
+			t.Errorf("DecryptBIP38 (compressed %v): wrong network "+
+				"- got error %v, want %v", compressed, err,
+				ErrWrongPassphrase)
+		}
+	}
+}
+
+// TestBIP38Malformed ensures malformed encrypted keys are rejected.
+func TestBIP38Malformed(t *testing.T) {
+	tests := []struct {
+		name      string
+		encrypted string
+	}{
+		{
+			name:      "empty",
+			encrypted: "",
+		},
+		{
+			name:      "bad checksum",
+			encrypted: "6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGh",
+		},
+		{
+			name:      "not an encrypted key",
+			encrypted: "5KN7MzqK5wt2TP1fQCYyHBtDrXdJuXbUzm4A9rKAteGu3Qi5CVR",
+		},
+	}
+
+	for _, test := range tests {
+		_, _, err := DecryptBIP38(test.encrypted, "TestingOneTwoThree")
+		if err != ErrInvalidEncryptedKey {
+			t.Errorf("%s: unexpected error - got %v, want %v",
+				test.name, err, ErrInvalidEncryptedKey)
+		}
+	}
+}
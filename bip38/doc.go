@@ -0,0 +1,27 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package bip38 implements passphrase-protected private keys as described by
+BIP0038, which are commonly used by paper wallets.
+
+An encrypted key is a base58check encoded string starting with "6P".  It
+commits to the first four bytes of the double SHA-256 of the pay-to-pubkey-hash
+address for the key, which is used both as the salt of the scrypt key
+derivation and as a checksum that detects an incorrect passphrase on
+decryption.  Since that address depends on the network, the functions which
+do not accept chain parameters use the main network.
+
+Two variants are defined.  Keys encrypted with EncryptBIP38 use the
+non-EC-multiply variant, where the private key is encrypted with AES-256 using
+a key derived from the passphrase.  The EC-multiply variant is produced by
+third parties which generate a key on behalf of the passphrase owner without
+learning it, so it can only be decrypted, which DecryptBIP38 supports along
+with the optional lot and sequence numbers.
+
+Passphrases are used exactly as given.  BIP0038 requires them to be in Unicode
+normalization form C, so callers accepting arbitrary text should normalize it
+before calling into this package.
+*/
+package bip38
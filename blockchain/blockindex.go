@@ -217,6 +217,83 @@ func (node *blockNode) CalcPastMedianTime() time.Time {
 	return time.Unix(medianTimestamp, 0)
 }
 
+// Ensure blockNode implements the HeaderAccessor interface.
+var _ HeaderAccessor = (*blockNode)(nil)
+
+// Height returns the height of the block node.
+//
+// This is part of the HeaderAccessor interface implementation.
+func (node *blockNode) Height() int32 {
+	return node.height
+}
+
+// Version returns the block version of the block node.
+//
+// This is part of the HeaderAccessor interface implementation.
+func (node *blockNode) Version() int32 {
+	return node.version
+}
+
+// Bits returns the compact difficulty target of the block node.
+//
+// This is part of the HeaderAccessor interface implementation.
+func (node *blockNode) Bits() uint32 {
+	return node.bits
+}
+
+// Timestamp returns the time the block was created.
+//
+// This is part of the HeaderAccessor interface implementation.
+func (node *blockNode) Timestamp() time.Time {
+	return time.Unix(node.timestamp, 0)
+}
+
+// PastMedianTime returns the median time of the previous few blocks prior to,
+// and including, the block node.
+//
+// This is part of the HeaderAccessor interface implementation.
+func (node *blockNode) PastMedianTime() time.Time {
+	return node.CalcPastMedianTime()
+}
+
+// AncestorHeader returns the ancestor block node at the provided height.  Nil
+// is returned when the height is out of range.
+//
+// This is part of the HeaderAccessor interface implementation.
+func (node *blockNode) AncestorHeader(height int32) HeaderAccessor {
+	// Avoid returning a typed nil value for out of range heights.
+	ancestor := node.Ancestor(height)
+	if ancestor == nil {
+		return nil
+	}
+	return ancestor
+}
+
+// CalcMedianTimePast calculates the median time of the passed headers, which
+// are expected to be a block header followed by its ancestors.  Only the first
+// few headers, per the number of blocks used by the consensus rules, are
+// considered, so fewer headers may be provided near the beginning of the block
+// chain.  The zero time is returned when no headers are provided.
+//
+// Consistent with CalcPastMedianTime, the later of the two middle timestamps is
+// selected for an even number of headers rather than averaging them.
+func CalcMedianTimePast(headers []HeaderAccessor) time.Time {
+	if len(headers) > medianTimeBlocks {
+		headers = headers[:medianTimeBlocks]
+	}
+	if len(headers) == 0 {
+		return time.Time{}
+	}
+
+	timestamps := make([]int64, 0, len(headers))
+	for _, header := range headers {
+		timestamps = append(timestamps, header.Timestamp().Unix())
+	}
+	sort.Sort(timeSorter(timestamps))
+
+	return time.Unix(timestamps[len(timestamps)/2], 0)
+}
+
 // blockIndex provides facilities for keeping track of an in-memory index of the
 // block chain.  Although the name block chain suggests a single chain of
 // blocks, it is actually a tree-shaped structure where any node can have
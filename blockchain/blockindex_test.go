@@ -0,0 +1,62 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCalcMedianTimePast ensures the median time of a set of headers is
+// selected as intended, including when fewer than the full number of headers
+// are available and when there are more.
+func TestCalcMedianTimePast(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		timestamps []int64
+		want       int64
+	}{
+		{"none", nil, 0},
+		{"one header", []int64{1000}, 1000},
+		{"five headers", []int64{1005, 1001, 1004, 1002, 1003}, 1003},
+		{"two headers selects later", []int64{1001, 1002}, 1002},
+		{"four headers selects later middle",
+			[]int64{1004, 1001, 1003, 1002}, 1003},
+		{"eleven headers", []int64{1010, 1000, 1009, 1001, 1008, 1002,
+			1007, 1003, 1006, 1004, 1005}, 1005},
+		{"eleven headers with duplicates", []int64{1000, 1000, 1000,
+			1000, 1000, 1000, 2000, 2000, 2000, 2000, 2000}, 1000},
+		{"only first eleven headers used", []int64{1010, 1000, 1009,
+			1001, 1008, 1002, 1007, 1003, 1006, 1004, 1005, 9000,
+			9000, 9000}, 1005},
+	}
+
+	for _, test := range tests {
+		// Build a chain where the timestamp of the tip is first so the
+		// headers are ordered from the tip to its ancestors.
+		numHeaders := int32(len(test.timestamps))
+		fields := make([]testHeaderFields, numHeaders)
+		for i, timestamp := range test.timestamps {
+			fields[numHeaders-1-int32(i)].timestamp = timestamp
+		}
+		headers := make([]HeaderAccessor, 0, numHeaders)
+		for height := numHeaders - 1; height >= 0; height-- {
+			headers = append(headers, &testHeader{height: height,
+				headers: fields})
+		}
+
+		var want time.Time
+		if test.timestamps != nil {
+			want = time.Unix(test.want, 0)
+		}
+		got := CalcMedianTimePast(headers)
+		if !got.Equal(want) {
+			t.Errorf("%s: unexpected median time - got %v, want %v",
+				test.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2013-2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/navcoin/navd/wire"
+	"github.com/navcoin/navutil"
+)
+
+// feeRatePercentiles are the percentiles, in hundredths, of the fee rates
+// reported in BlockStats.
+var feeRatePercentiles = [...]int64{10, 25, 50, 75, 90}
+
+// BlockStats houses statistics about the transactions in a block.  Apart from
+// the transaction and output counts, the statistics only cover the
+// non-coinbase transactions of the block.
+type BlockStats struct {
+	// TxCount is the number of transactions in the block, including the
+	// coinbase.
+	TxCount int
+
+	// Inputs is the number of inputs, excluding the coinbase input.
+	Inputs int
+
+	// Outputs is the number of outputs, including the coinbase outputs.
+	Outputs int
+
+	// TotalSize is the combined serialized size of the non-coinbase
+	// transactions.
+	TotalSize int64
+
+	// TotalWeight is the combined weight of the non-coinbase transactions.
+	TotalWeight int64
+
+	// TotalFee is the combined fee paid by the transactions.
+	TotalFee int64
+
+	// FeeRatePercentiles houses the 10th, 25th, 50th, 75th and 90th
+	// percentile fee rates of the transactions in atoms per virtual byte.
+	// Each transaction is weighted by its weight when determining the
+	// percentiles.
+	FeeRatePercentiles [len(feeRatePercentiles)]int64
+
+	// Subsidy is the amount claimed by the coinbase in excess of the fees,
+	// which is the block subsidy for blocks claiming the full reward.
+	Subsidy int64
+}
+
+// ComputeBlockStats returns statistics about the transactions in the passed
+// block.  The input values map the outputs spent by the transactions in the
+// block to their amounts.  Outputs created by earlier transactions in the
+// same block do not need to be provided.
+//
+// An error is returned when the amount of an output spent by the block is not
+// known or when a transaction spends more than its inputs.
+func ComputeBlockStats(block *wire.MsgBlock, inputValues map[wire.OutPoint]int64) (BlockStats, error) {
+	var stats BlockStats
+	if len(block.Transactions) == 0 {
+		return stats, nil
+	}
+
+	// txFeeRate houses the fee rate and weight of a transaction for the
+	// purposes of calculating the fee rate percentiles.
+	type txFeeRate struct {
+		feeRate int64
+		weight  int64
+	}
+	feeRates := make([]txFeeRate, 0, len(block.Transactions)-1)
+
+	// Outputs created by transactions in the block, keyed by outpoint, so
+	// transactions spending outputs from earlier in the block do not need
+	// them to be provided.
+	blockOutputs := make(map[wire.OutPoint]int64)
+
+	var coinbaseValue int64
+	stats.TxCount = len(block.Transactions)
+	for i, tx := range block.Transactions {
+		stats.Outputs += len(tx.TxOut)
+
+		txHash := tx.TxHash()
+		for txOutIdx, txOut := range tx.TxOut {
+			prevOut := wire.OutPoint{Hash: txHash, Index: uint32(txOutIdx)}
+			blockOutputs[prevOut] = txOut.Value
+		}
+
+		if i == 0 && IsCoinBaseTx(tx) {
+			for _, txOut := range tx.TxOut {
+				coinbaseValue += txOut.Value
+			}
+			continue
+		}
+
+		inputAmounts := make([]int64, 0, len(tx.TxIn))
+		for _, txIn := range tx.TxIn {
+			amount, ok := inputValues[txIn.PreviousOutPoint]
+			if !ok {
+				amount, ok = blockOutputs[txIn.PreviousOutPoint]
+			}
+			if !ok {
+				return BlockStats{}, fmt.Errorf("unknown value for "+
+					"output %v spent by transaction %v",
+					txIn.PreviousOutPoint, txHash)
+			}
+			inputAmounts = append(inputAmounts, amount)
+		}
+		fee, err := CalcTxFee(tx, inputAmounts)
+		if err != nil {
+			return BlockStats{}, err
+		}
+
+		weight := GetTransactionWeight(navutil.NewTx(tx))
+		stats.Inputs += len(tx.TxIn)
+		stats.TotalSize += int64(tx.SerializeSize())
+		stats.TotalWeight += weight
+		stats.TotalFee += fee
+
+		var feeRate int64
+		if weight > 0 {
+			feeRate = fee * WitnessScaleFactor / weight
+		}
+		feeRates = append(feeRates, txFeeRate{feeRate, weight})
+	}
+	stats.Subsidy = coinbaseValue - stats.TotalFee
+
+	// Determine the fee rate percentiles by weight.  Each percentile is the
+	// fee rate of the transaction at which the cumulative weight of the
+	// transactions sorted by fee rate reaches that percentile of the total
+	// weight.
+	sort.SliceStable(feeRates, func(i, j int) bool {
+		return feeRates[i].feeRate < feeRates[j].feeRate
+	})
+	var cumulativeWeight int64
+	next := 0
+	for _, fr := range feeRates {
+		cumulativeWeight += fr.weight
+		for next < len(feeRatePercentiles) && cumulativeWeight*100 >=
+			stats.TotalWeight*feeRatePercentiles[next] {
+
+			stats.FeeRatePercentiles[next] = fr.feeRate
+			next++
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2013-2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// TestComputeBlockStats ensures the statistics computed for a small block
+// match hand-computed values.
+func TestComputeBlockStats(t *testing.T) {
+	pkScript := bytes.Repeat([]byte{0x51}, 25)
+
+	// The block consists of a coinbase claiming a subsidy of 50 coins plus
+	// the fees, a transaction spending an output from a prior block and
+	// paying a fee of 10000, and a transaction spending the output of the
+	// prior one and paying a fee of 1000.  Each of the non-coinbase
+	// transactions is 90 bytes with a weight of 360.
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{},
+		wire.MaxPrevOutIndex), []byte{0x51, 0x51}, nil))
+	coinbase.AddTxOut(wire.NewTxOut(5000000000+11000, pkScript))
+
+	priorOut := wire.OutPoint{Hash: chainhash.Hash{0x01}, Index: 0}
+	tx1 := wire.NewMsgTx(1)
+	tx1.AddTxIn(wire.NewTxIn(&priorOut, nil, nil))
+	tx1.AddTxOut(wire.NewTxOut(90000, pkScript))
+
+	tx1Hash := tx1.TxHash()
+	tx2 := wire.NewMsgTx(1)
+	tx2.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&tx1Hash, 0), nil, nil))
+	tx2.AddTxOut(wire.NewTxOut(89000, pkScript))
+
+	block := &wire.MsgBlock{
+		Transactions: []*wire.MsgTx{coinbase, tx1, tx2},
+	}
+	inputValues := map[wire.OutPoint]int64{priorOut: 100000}
+
+	// The fee rates are 10000*4/360 = 111 and 1000*4/360 = 11 atoms per
+	// virtual byte.  Since both transactions have the same weight, the
+	// lower fee rate covers up to and including the 50th percentile.
+	want := BlockStats{
+		TxCount:            3,
+		Inputs:             2,
+		Outputs:            3,
+		TotalSize:          180,
+		TotalWeight:        720,
+		TotalFee:           11000,
+		FeeRatePercentiles: [5]int64{11, 11, 11, 111, 111},
+		Subsidy:            5000000000,
+	}
+	stats, err := ComputeBlockStats(block, inputValues)
+	if err != nil {
+		t.Fatalf("ComputeBlockStats: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Fatalf("ComputeBlockStats: unexpected stats - got %+v, want %+v",
+			stats, want)
+	}
+
+	// Ensure a block with only a coinbase has no fees and the coinbase
+	// value is reported as the subsidy.
+	coinbaseOnly := &wire.MsgBlock{Transactions: []*wire.MsgTx{coinbase}}
+	stats, err = ComputeBlockStats(coinbaseOnly, nil)
+	if err != nil {
+		t.Fatalf("ComputeBlockStats: unexpected error: %v", err)
+	}
+	want = BlockStats{TxCount: 1, Outputs: 1, Subsidy: 5000011000}
+	if !reflect.DeepEqual(stats, want) {
+		t.Fatalf("ComputeBlockStats: unexpected stats for coinbase only "+
+			"block - got %+v, want %+v", stats, want)
+	}
+
+	// Ensure an unknown input value is an error.
+	if _, err := ComputeBlockStats(block, nil); err == nil {
+		t.Fatalf("ComputeBlockStats: did not receive expected error for " +
+			"unknown input value")
+	}
+
+	// Ensure a transaction spending more than its inputs is an error.
+	inputValues[priorOut] = 1000
+	_, err = ComputeBlockStats(block, inputValues)
+	if rerr, ok := err.(RuleError); !ok || rerr.ErrorCode != ErrSpendTooHigh {
+		t.Fatalf("ComputeBlockStats: did not receive expected "+
+			"ErrSpendTooHigh - got %v", err)
+	}
+}
@@ -40,6 +40,61 @@ const (
 // [17a 16a 15 14 13 12 11 10 9 8 7 6 4 genesis]
 type BlockLocator []*chainhash.Hash
 
+// BuildBlockLocator returns a block locator for the block at the passed height
+// using the passed function to look up the hash of the block at each height
+// in the chain being located, down to and including the genesis block.  It
+// allows a locator to be built from any source of block hashes, such as a
+// chain of headers that has not been connected to a BlockChain instance.
+//
+// See the BlockLocator type for details on the algorithm used to create a block
+// locator.  A nil locator is returned when the height is negative.
+func BuildBlockLocator(tipHeight int32, hashAt func(int32) chainhash.Hash) BlockLocator {
+	if tipHeight < 0 {
+		return nil
+	}
+
+	// Calculate the max number of entries that will ultimately be in the
+	// block locator.  See the description of the algorithm for how these
+	// numbers are derived.
+	var maxEntries uint8
+	if tipHeight <= 12 {
+		maxEntries = uint8(tipHeight) + 1
+	} else {
+		// Requested hash itself + previous 10 entries + genesis block.
+		// Then floor(log2(height-10)) entries for the skip portion.
+		adjustedHeight := uint32(tipHeight) - 10
+		maxEntries = 12 + fastLog2Floor(adjustedHeight)
+	}
+	locator := make(BlockLocator, 0, maxEntries)
+
+	step := int32(1)
+	height := tipHeight
+	for {
+		hash := hashAt(height)
+		locator = append(locator, &hash)
+
+		// Nothing more to add once the genesis block has been added.
+		if height == 0 {
+			break
+		}
+
+		// Calculate the previous height to include ensuring the final
+		// entry is the genesis block.
+		height -= step
+		if height < 0 {
+			height = 0
+		}
+
+		// Once 11 entries have been included, start doubling the
+		// distance between included hashes.
+		if len(locator) > 10 {
+			step *= 2
+		}
+	}
+
+	return locator
+}
+
 // orphanBlock represents a block that we don't yet have the parent for.  It
 // is a normal block plus an expiration time to prevent caching the orphan
 // forever.
@@ -6,6 +6,8 @@ package blockchain
 
 import (
 	"sync"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
 )
 
 // approxNodesPerWeek is an approximation of the number of new blocks there are
@@ -357,36 +359,9 @@ func (c *chainView) blockLocator(node *blockNode) BlockLocator {
 		return nil
 	}
 
-	// Calculate the max number of entries that will ultimately be in the
-	// block locator.  See the description of the algorithm for how these
-	// numbers are derived.
-	var maxEntries uint8
-	if node.height <= 12 {
-		maxEntries = uint8(node.height) + 1
-	} else {
-		// Requested hash itself + previous 10 entries + genesis block.
-		// Then floor(log2(height-10)) entries for the skip portion.
-		adjustedHeight := uint32(node.height) - 10
-		maxEntries = 12 + fastLog2Floor(adjustedHeight)
-	}
-	locator := make(BlockLocator, 0, maxEntries)
-
-	step := int32(1)
-	for node != nil {
-		locator = append(locator, &node.hash)
-
-		// Nothing more to add once the genesis block has been added.
-		if node.height == 0 {
-			break
-		}
-
-		// Calculate height of previous node to include ensuring the
-		// final node is the genesis block.
-		height := node.height - step
-		if height < 0 {
-			height = 0
-		}
-
+	// The heights are requested in decreasing order, so each lookup
+	// starts from the node found by the previous one.
+	return BuildBlockLocator(node.height, func(height int32) chainhash.Hash {
 		// When the node is in the current chain view, all of its
 		// ancestors must be too, so use a much faster O(1) lookup in
 		// that case.  Otherwise, fall back to walking backwards through
@@ -396,15 +371,8 @@ func (c *chainView) blockLocator(node *blockNode) BlockLocator {
 		} else {
 			node = node.Ancestor(height)
 		}
-
-		// Once 11 entries have been included, start doubling the
-		// distance between included hashes.
-		if len(locator) > 10 {
-			step *= 2
-		}
-	}
-
-	return locator
+		return node.hash
+	})
 }
 
 // BlockLocator returns a block locator for the passed block node.  The passed
@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/navcoin/navd/chaincfg/chainhash"
 	"github.com/navcoin/navd/wire"
 )
 
@@ -490,3 +491,63 @@ func TestChainViewNil(t *testing.T) {
 			locator, wantLocator)
 	}
 }
+
+// TestBuildBlockLocator ensures block locators built from a hash lookup
+// function have the expected shape and match those built by a chain view.
+func TestBuildBlockLocator(t *testing.T) {
+	// Construct a chain of 101 nodes so the tip is at height 100.
+	nodes := chainedNodes(nil, 101)
+	view := newChainView(tstTip(nodes))
+	hashAt := func(height int32) chainhash.Hash {
+		return nodes[height].hash
+	}
+
+	tests := []struct {
+		name      string
+		tipHeight int32
+		want      BlockLocator
+	}{
+		{
+			name:      "negative height",
+			tipHeight: -1,
+			want:      nil,
+		},
+		{
+			name:      "genesis only",
+			tipHeight: 0,
+			want:      locatorHashes(nodes, 0),
+		},
+		{
+			name:      "short chain",
+			tipHeight: 3,
+			want:      locatorHashes(nodes, 3, 2, 1, 0),
+		},
+		{
+			// The first 11 entries are dense after which the step
+			// doubles until the genesis block is reached.
+			name:      "100 blocks",
+			tipHeight: 100,
+			want: locatorHashes(nodes, 100, 99, 98, 97, 96, 95, 94,
+				93, 92, 91, 90, 89, 87, 83, 75, 59, 27, 0),
+		},
+	}
+
+	for _, test := range tests {
+		locator := BuildBlockLocator(test.tipHeight, hashAt)
+		if !reflect.DeepEqual(locator, test.want) {
+			t.Errorf("%s: unexpected locator -- got %v, want %v",
+				test.name, locator, test.want)
+			continue
+		}
+
+		// Ensure the locator matches the one built by the chain view.
+		if test.tipHeight < 0 {
+			continue
+		}
+		wantLocator := view.BlockLocator(nodes[test.tipHeight])
+		if !reflect.DeepEqual(locator, wantLocator) {
+			t.Errorf("%s: locator does not match chain view -- got "+
+				"%v, want %v", test.name, locator, wantLocator)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/navcoin/navd/chaincfg"
+)
+
+// HeaderAccessor provides access to the data of a chain of block headers which
+// is needed to calculate the state of a deployment or the required difficulty.
+// It allows these to be calculated by callers which do not have a fully
+// validated block index, such as those which only track headers.
+type HeaderAccessor interface {
+	// Height returns the height of the header.
+	Height() int32
+
+	// Version returns the block version of the header.
+	Version() int32
+
+	// Bits returns the compact difficulty target of the header.
+	Bits() uint32
+
+	// Timestamp returns the time the block was created.
+	Timestamp() time.Time
+
+	// PastMedianTime returns the median time of the previous blocks ending
+	// with the header as defined by the consensus rules.
+	PastMedianTime() time.Time
+
+	// AncestorHeader returns the ancestor of the header at the provided
+	// height.  It must return nil, rather than a typed nil value, when the
+	// height is negative or greater than the height of the header.
+	AncestorHeader(height int32) HeaderAccessor
+}
+
+// CalcDeploymentState returns the rule change threshold state of the given
+// deployment ID for the block AFTER the passed header, using the confirmation
+// window and activation threshold of the passed chain parameters.  A nil
+// header is treated as the parent of the genesis block.
+//
+// The calculation follows the same rules as the threshold states calculated by
+// the chain, so the state only changes at the boundaries of each confirmation
+// window, which are aligned to the heights that are one less than a multiple
+// of the window size.  Unlike the chain, no states are cached, so every window
+// since the deployment started is evaluated on each call.
+func CalcDeploymentState(params *chaincfg.Params, deploymentID uint32, prevHeader HeaderAccessor) (ThresholdState, error) {
+	if deploymentID >= uint32(len(params.Deployments)) {
+		return ThresholdFailed, DeploymentError(deploymentID)
+	}
+	deployment := &params.Deployments[deploymentID]
+	confirmationWindow := int32(params.MinerConfirmationWindow)
+
+	// The threshold state for the window that contains the genesis block is
+	// defined by definition.
+	if prevHeader == nil || prevHeader.Height()+1 < confirmationWindow {
+		return ThresholdDefined, nil
+	}
+
+	// Get the ancestor that is the last block of the previous confirmation
+	// window since the state is the same for all blocks within a window.
+	height := prevHeader.Height()
+	prevHeader = prevHeader.AncestorHeader(height -
+		(height+1)%confirmationWindow)
+
+	// Iterate backwards through each of the previous confirmation windows
+	// until one before the start time of the deployment is found, since
+	// the state is simply defined prior to that.
+	var windowEnds []HeaderAccessor
+	for prevHeader != nil {
+		medianTime := uint64(prevHeader.PastMedianTime().Unix())
+		if medianTime < deployment.StartTime {
+			break
+		}
+		windowEnds = append(windowEnds, prevHeader)
+		prevHeader = prevHeader.AncestorHeader(prevHeader.Height() -
+			confirmationWindow)
+	}
+
+	// Since each threshold state depends on the state of the previous
+	// window, iterate starting from the oldest window.
+	conditionMask := uint32(1) << deployment.BitNumber
+	state := ThresholdDefined
+	for i := len(windowEnds) - 1; i >= 0; i-- {
+		windowEnd := windowEnds[i]
+		medianTime := uint64(windowEnd.PastMedianTime().Unix())
+
+		switch state {
+		case ThresholdDefined:
+			// The deployment of the rule change fails if it expires
+			// before it is accepted and locked in, and otherwise
+			// starts since the start time has been reached.
+			if medianTime >= deployment.ExpireTime {
+				state = ThresholdFailed
+				break
+			}
+			state = ThresholdStarted
+
+		case ThresholdStarted:
+			// The deployment of the rule change fails if it expires
+			// before it is accepted and locked in.
+			if medianTime >= deployment.ExpireTime {
+				state = ThresholdFailed
+				break
+			}
+
+			// Count the blocks in the window which signal for the
+			// deployment.
+			var count uint32
+			endHeight := windowEnd.Height()
+			for h := endHeight; h > endHeight-confirmationWindow; h-- {
+				header := windowEnd.AncestorHeader(h)
+				if header == nil {
+					return ThresholdFailed, AssertError(fmt.Sprintf(
+						"CalcDeploymentState: missing "+
+							"ancestor at height %d", h))
+				}
+				version := uint32(header.Version())
+				if version&vbTopMask == vbTopBits &&
+					version&conditionMask != 0 {
+
+					count++
+				}
+			}
+
+			// The state is locked in if the number of blocks in the
+			// window that signalled for the rule change meets the
+			// activation threshold.
+			if count >= params.RuleChangeActivationThreshold {
+				state = ThresholdLockedIn
+			}
+
+		case ThresholdLockedIn:
+			// The new rule becomes active when its previous state
+			// was locked in.
+			state = ThresholdActive
+
+		// Nothing to do if the previous state is active or failed since
+		// they are both terminal states.
+		case ThresholdActive:
+		case ThresholdFailed:
+		}
+	}
+
+	return state, nil
+}
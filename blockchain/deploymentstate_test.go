@@ -0,0 +1,154 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/navcoin/navd/chaincfg"
+)
+
+// testHeaderFields houses the fields of each header in a synthetic header
+// chain.
+type testHeaderFields struct {
+	version   int32
+	bits      uint32
+	timestamp int64
+}
+
+// testHeader is a HeaderAccessor for a synthetic header chain where the fields
+// of each header are given by its height and the past median time is 100
+// seconds per block.
+type testHeader struct {
+	height  int32
+	headers []testHeaderFields
+}
+
+// Height returns the height of the header.
+func (h *testHeader) Height() int32 {
+	return h.height
+}
+
+// Version returns the block version of the header.
+func (h *testHeader) Version() int32 {
+	return h.headers[h.height].version
+}
+
+// Bits returns the compact difficulty target of the header.
+func (h *testHeader) Bits() uint32 {
+	return h.headers[h.height].bits
+}
+
+// Timestamp returns the time the block was created.
+func (h *testHeader) Timestamp() time.Time {
+	return time.Unix(h.headers[h.height].timestamp, 0)
+}
+
+// PastMedianTime returns the past median time of the header.
+func (h *testHeader) PastMedianTime() time.Time {
+	return time.Unix(int64(h.height)*100, 0)
+}
+
+// AncestorHeader returns the ancestor of the header at the provided height.
+func (h *testHeader) AncestorHeader(height int32) HeaderAccessor {
+	if height < 0 || height > h.height {
+		return nil
+	}
+	return &testHeader{height: height, headers: h.headers}
+}
+
+// newTestHeaderChain returns the tip of a synthetic header chain of the given
+// number of blocks in which the blocks at the heights in the signal range
+// signal for the passed bit and all others use the plain version bits version.
+func newTestHeaderChain(numBlocks int32, bit uint8, signalFrom, signalTo int32) *testHeader {
+	headers := make([]testHeaderFields, numBlocks)
+	for i := range headers {
+		headers[i].version = vbTopBits
+		if int32(i) >= signalFrom && int32(i) < signalTo {
+			headers[i].version |= 1 << bit
+		}
+	}
+	return &testHeader{height: numBlocks - 1, headers: headers}
+}
+
+// TestCalcDeploymentState ensures the deployment state calculated from a
+// header chain moves through each of the threshold states at the window
+// boundaries.
+func TestCalcDeploymentState(t *testing.T) {
+	t.Parallel()
+
+	// Use a window of 10 blocks with a threshold of 8 blocks and a
+	// deployment which starts at height 10 and expires at height 45 given
+	// the 100 second past median time per block of the test chains.
+	params := chaincfg.RegressionNetParams
+	params.MinerConfirmationWindow = 10
+	params.RuleChangeActivationThreshold = 8
+	params.Deployments[chaincfg.DeploymentTestDummy] = chaincfg.ConsensusDeployment{
+		BitNumber:  5,
+		StartTime:  1000,
+		ExpireTime: 4500,
+	}
+	const id = chaincfg.DeploymentTestDummy
+
+	// The deployment is started for the window of blocks 20 through 29,
+	// which is the first whose previous window end has reached the start
+	// time.
+	activated := newTestHeaderChain(60, 5, 20, 30)
+	threshold := newTestHeaderChain(60, 5, 22, 30)
+	belowThreshold := newTestHeaderChain(60, 5, 23, 30)
+	wrongBit := newTestHeaderChain(60, 4, 20, 30)
+
+	tests := []struct {
+		name       string
+		chain      *testHeader
+		prevHeight int32
+		want       ThresholdState
+	}{
+		{"genesis window", activated, 0, ThresholdDefined},
+		{"end of first window", activated, 8, ThresholdDefined},
+		{"before start time", activated, 9, ThresholdDefined},
+		{"start time reached", activated, 18, ThresholdDefined},
+		{"started", activated, 19, ThresholdStarted},
+		{"end of started window", activated, 28, ThresholdStarted},
+		{"locked in", activated, 29, ThresholdLockedIn},
+		{"end of locked in window", activated, 38, ThresholdLockedIn},
+		{"active", activated, 39, ThresholdActive},
+		{"active after expiration", activated, 59, ThresholdActive},
+		{"exactly threshold", threshold, 29, ThresholdLockedIn},
+		{"one below threshold", belowThreshold, 29, ThresholdStarted},
+		{"below threshold before expiration", belowThreshold, 39,
+			ThresholdStarted},
+		{"failed", belowThreshold, 49, ThresholdFailed},
+		{"failed is terminal", belowThreshold, 59, ThresholdFailed},
+		{"other bit signalled", wrongBit, 49, ThresholdFailed},
+	}
+
+	for _, test := range tests {
+		prevHeader := test.chain.AncestorHeader(test.prevHeight)
+		state, err := CalcDeploymentState(&params, id, prevHeader)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if state != test.want {
+			t.Errorf("%s: unexpected state - got %v, want %v",
+				test.name, state, test.want)
+		}
+	}
+
+	// The block after a nil header is the genesis block.
+	state, err := CalcDeploymentState(&params, id, nil)
+	if err != nil || state != ThresholdDefined {
+		t.Errorf("nil header: unexpected state %v, err %v", state, err)
+	}
+
+	// Ensure an unknown deployment is rejected.
+	_, err = CalcDeploymentState(&params, chaincfg.DefinedDeployments,
+		activated)
+	if _, ok := err.(DeploymentError); !ok {
+		t.Errorf("unknown deployment: unexpected error %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/navcoin/navd/chaincfg"
 	"github.com/navcoin/navd/chaincfg/chainhash"
 )
 
@@ -35,9 +36,9 @@ func HashToBig(hash *chainhash.Hash) *big.Int {
 	return new(big.Int).SetBytes(buf[:])
 }
 
-// CompactToBig converts a compact representation of a whole number N to an
-// unsigned 32-bit number.  The representation is similar to IEEE754 floating
-// point numbers.
+// CompactToBig converts a compact representation of a whole number N, stored
+// as an unsigned 32-bit number, to a big integer.  The representation is
+// similar to IEEE754 floating point numbers.
 //
 // Like IEEE754 floating point, there are three basic components: the sign,
 // the exponent, and the mantissa.  They are broken out as follows:
@@ -57,7 +58,13 @@ func HashToBig(hash *chainhash.Hash) *big.Int {
 //
 // This compact form is only used in navcoin to encode unsigned 256-bit numbers
 // which represent difficulty targets, thus there really is not a need for a
-// sign bit, but it is implemented here to stay consistent with navcoind.
+// sign bit, but it is implemented here to stay consistent with navcoind.  A
+// sign bit with a zero mantissa results in zero rather than a negative number.
+//
+// Unlike navcoind, compact values with an exponent large enough to overflow
+// 256 bits are not flagged since the full value is returned.  Such values
+// always exceed the proof-of-work limit and are therefore rejected by the
+// proof-of-work checks.
 func CompactToBig(compact uint32) *big.Int {
 	// Extract the mantissa, sign bit, and exponent.
 	mantissa := compact & 0x007fffff
@@ -107,7 +114,10 @@ func BigToCompact(n *big.Int) uint32 {
 		mantissa <<= 8 * (3 - exponent)
 	} else {
 		// Use a copy to avoid modifying the caller's original number.
-		tn := new(big.Int).Set(n)
+		// The absolute value is shifted since the shift rounds negative
+		// numbers towards negative infinity and the sign is encoded
+		// separately.
+		tn := new(big.Int).Abs(n)
 		mantissa = uint32(tn.Rsh(tn, 8*(exponent-3)).Bits()[0])
 	}
 
@@ -190,82 +200,93 @@ func (b *BlockChain) calcEasiestDifficulty(bits uint32, duration time.Duration)
 	return BigToCompact(newTarget)
 }
 
-// findPrevTestNetDifficulty returns the difficulty of the previous block which
-// did not have the special testnet minimum difficulty rule applied.
-//
-// This function MUST be called with the chain state lock held (for writes).
-func (b *BlockChain) findPrevTestNetDifficulty(startNode *blockNode) uint32 {
-	// Search backwards through the chain for the last block without
-	// the special rule applied.
-	iterNode := startNode
-	for iterNode != nil && iterNode.height%b.blocksPerRetarget != 0 &&
-		iterNode.bits == b.chainParams.PowLimitBits {
-
-		iterNode = iterNode.parent
-	}
-
-	// Return the found difficulty or the minimum difficulty if no
-	// appropriate block was found.
-	lastBits := b.chainParams.PowLimitBits
-	if iterNode != nil {
-		lastBits = iterNode.bits
-	}
-	return lastBits
-}
-
 // calcNextRequiredDifficulty calculates the required difficulty for the block
 // after the passed previous block node based on the difficulty retarget rules.
 // This function differs from the exported CalcNextRequiredDifficulty in that
 // the exported version uses the current best chain as the previous block node
 // while this function accepts any block node.
 func (b *BlockChain) calcNextRequiredDifficulty(lastNode *blockNode, newBlockTime time.Time) (uint32, error) {
-	// Genesis block.
+	// Genesis block.  This is handled here since a nil node would
+	// otherwise be passed as a non-nil header.
 	if lastNode == nil {
 		return b.chainParams.PowLimitBits, nil
 	}
 
+	return CalcHeaderNextRequiredDifficulty(lastNode, newBlockTime,
+		b.chainParams)
+}
+
+// CalcHeaderNextRequiredDifficulty calculates the required difficulty for the
+// block after the passed header, which is to be created at the passed time,
+// based on the difficulty retarget rules of the passed chain parameters.  A nil
+// header is treated as the parent of the genesis block.
+//
+// This function differs from CalcNextRequiredDifficulty in that it only
+// requires access to a chain of headers rather than the current best chain.
+// The chain uses it to calculate the difficulty of its own blocks as well.
+//
+// The difficulty is retargeted once every TargetTimespan worth of blocks based
+// on the time taken to create the blocks of the interval.  Consistent with
+// navcoind, the timespan is measured from the first block of the interval, so
+// it only spans one less block than the interval.  The adjustment is limited
+// by RetargetAdjustmentFactor and the resulting target is limited to PowLimit.
+func CalcHeaderNextRequiredDifficulty(lastHeader HeaderAccessor, newBlockTime time.Time, params *chaincfg.Params) (uint32, error) {
+	// Genesis block.
+	if lastHeader == nil {
+		return params.PowLimitBits, nil
+	}
+
+	targetTimespan := int64(params.TargetTimespan / time.Second)
+	targetTimePerBlock := int64(params.TargetTimePerBlock / time.Second)
+	blocksPerRetarget := int32(targetTimespan / targetTimePerBlock)
+
 	// Return the previous block's difficulty requirements if this block
 	// is not at a difficulty retarget interval.
-	if (lastNode.height+1)%b.blocksPerRetarget != 0 {
+	if (lastHeader.Height()+1)%blocksPerRetarget != 0 {
 		// For networks that support it, allow special reduction of the
 		// required difficulty once too much time has elapsed without
 		// mining a block.
-		if b.chainParams.ReduceMinDifficulty {
+		if params.ReduceMinDifficulty {
 			// Return minimum difficulty when more than the desired
 			// amount of time has elapsed without mining a block.
-			reductionTime := int64(b.chainParams.MinDiffReductionTime /
+			reductionTime := int64(params.MinDiffReductionTime /
 				time.Second)
-			allowMinTime := lastNode.timestamp + reductionTime
+			allowMinTime := lastHeader.Timestamp().Unix() + reductionTime
 			if newBlockTime.Unix() > allowMinTime {
-				return b.chainParams.PowLimitBits, nil
+				return params.PowLimitBits, nil
 			}
 
 			// The block was mined within the desired timeframe, so
 			// return the difficulty for the last block which did
 			// not have the special minimum difficulty rule applied.
-			return b.findPrevTestNetDifficulty(lastNode), nil
+			return findPrevTestNetDifficulty(lastHeader,
+				blocksPerRetarget, params), nil
 		}
 
 		// For the main network (or any unrecognized networks), simply
 		// return the previous block's difficulty requirements.
-		return lastNode.bits, nil
+		return lastHeader.Bits(), nil
 	}
 
-	// Get the block node at the previous retarget (targetTimespan days
-	// worth of blocks).
-	firstNode := lastNode.RelativeAncestor(b.blocksPerRetarget - 1)
-	if firstNode == nil {
+	// Get the header at the previous retarget (targetTimespan days worth
+	// of blocks).
+	firstHeader := lastHeader.AncestorHeader(lastHeader.Height() -
+		(blocksPerRetarget - 1))
+	if firstHeader == nil {
 		return 0, AssertError("unable to obtain previous retarget block")
 	}
 
 	// Limit the amount of adjustment that can occur to the previous
 	// difficulty.
-	actualTimespan := lastNode.timestamp - firstNode.timestamp
+	minRetargetTimespan := targetTimespan / params.RetargetAdjustmentFactor
+	maxRetargetTimespan := targetTimespan * params.RetargetAdjustmentFactor
+	actualTimespan := lastHeader.Timestamp().Unix() -
+		firstHeader.Timestamp().Unix()
 	adjustedTimespan := actualTimespan
-	if actualTimespan < b.minRetargetTimespan {
-		adjustedTimespan = b.minRetargetTimespan
-	} else if actualTimespan > b.maxRetargetTimespan {
-		adjustedTimespan = b.maxRetargetTimespan
+	if actualTimespan < minRetargetTimespan {
+		adjustedTimespan = minRetargetTimespan
+	} else if actualTimespan > maxRetargetTimespan {
+		adjustedTimespan = maxRetargetTimespan
 	}
 
 	// Calculate new target difficulty as:
@@ -273,14 +294,13 @@ func (b *BlockChain) calcNextRequiredDifficulty(lastNode *blockNode, newBlockTim
 	// The result uses integer division which means it will be slightly
 	// rounded down.  NavCoind also uses integer division to calculate this
 	// result.
-	oldTarget := CompactToBig(lastNode.bits)
+	oldTarget := CompactToBig(lastHeader.Bits())
 	newTarget := new(big.Int).Mul(oldTarget, big.NewInt(adjustedTimespan))
-	targetTimeSpan := int64(b.chainParams.TargetTimespan / time.Second)
-	newTarget.Div(newTarget, big.NewInt(targetTimeSpan))
+	newTarget.Div(newTarget, big.NewInt(targetTimespan))
 
 	// Limit new value to the proof of work limit.
-	if newTarget.Cmp(b.chainParams.PowLimit) > 0 {
-		newTarget.Set(b.chainParams.PowLimit)
+	if newTarget.Cmp(params.PowLimit) > 0 {
+		newTarget.Set(params.PowLimit)
 	}
 
 	// Log new target difficulty and return it.  The new target logging is
@@ -288,17 +308,40 @@ func (b *BlockChain) calcNextRequiredDifficulty(lastNode *blockNode, newBlockTim
 	// newTarget since conversion to the compact representation loses
 	// precision.
 	newTargetBits := BigToCompact(newTarget)
-	log.Debugf("Difficulty retarget at block height %d", lastNode.height+1)
-	log.Debugf("Old target %08x (%064x)", lastNode.bits, oldTarget)
+	log.Debugf("Difficulty retarget at block height %d",
+		lastHeader.Height()+1)
+	log.Debugf("Old target %08x (%064x)", lastHeader.Bits(), oldTarget)
 	log.Debugf("New target %08x (%064x)", newTargetBits, CompactToBig(newTargetBits))
 	log.Debugf("Actual timespan %v, adjusted timespan %v, target timespan %v",
 		time.Duration(actualTimespan)*time.Second,
 		time.Duration(adjustedTimespan)*time.Second,
-		b.chainParams.TargetTimespan)
+		params.TargetTimespan)
 
 	return newTargetBits, nil
 }
 
+// findPrevTestNetDifficulty returns the difficulty of the header at or before
+// the passed header which did not have the special testnet minimum difficulty
+// rule applied.
+func findPrevTestNetDifficulty(startHeader HeaderAccessor, blocksPerRetarget int32, params *chaincfg.Params) uint32 {
+	// Search backwards through the chain for the last block without
+	// the special rule applied.
+	iterHeader := startHeader
+	for iterHeader != nil && iterHeader.Height()%blocksPerRetarget != 0 &&
+		iterHeader.Bits() == params.PowLimitBits {
+
+		iterHeader = iterHeader.AncestorHeader(iterHeader.Height() - 1)
+	}
+
+	// Return the found difficulty or the minimum difficulty if no
+	// appropriate block was found.
+	lastBits := params.PowLimitBits
+	if iterHeader != nil {
+		lastBits = iterHeader.Bits()
+	}
+	return lastBits
+}
+
 // CalcNextRequiredDifficulty calculates the required difficulty for the block
 // after the end of the current best chain based on the difficulty retarget
 // rules.
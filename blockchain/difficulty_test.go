@@ -7,6 +7,9 @@ package blockchain
 import (
 	"math/big"
 	"testing"
+	"time"
+
+	"github.com/navcoin/navd/chaincfg"
 )
 
 // TestBigToCompact ensures BigToCompact converts big integers to the expected
@@ -18,6 +21,12 @@ func TestBigToCompact(t *testing.T) {
 	}{
 		{0, 0},
 		{-1, 25231360},
+		{0x12, 0x01120000},
+		{0x1234, 0x02123400},
+		{0x123456, 0x03123456},
+		{0x12345678, 0x04123456},
+		{0x80, 0x02008000},
+		{-0x12345678, 0x04923456},
 	}
 
 	for x, test := range tests {
@@ -39,6 +48,13 @@ func TestCompactToBig(t *testing.T) {
 		out int64
 	}{
 		{10000000, 0},
+		{0x01120000, 0x12},
+		{0x01003456, 0},
+		{0x02123400, 0x1234},
+		{0x03123456, 0x123456},
+		{0x04123456, 0x12345600},
+		{0x04923456, -0x12345600},
+		{0x01800000, 0},
 	}
 
 	for x, test := range tests {
@@ -60,6 +76,11 @@ func TestCalcWork(t *testing.T) {
 		out int64
 	}{
 		{10000000, 0},
+		{0x1d00ffff, 4295032833},
+		{0x1b0404cb, 70040908352512},
+		{0x207fffff, 2},
+		{0x1d80ffff, 0},
+		{0xff123456, 0},
 	}
 
 	for x, test := range tests {
@@ -73,3 +94,236 @@ func TestCalcWork(t *testing.T) {
 		}
 	}
 }
+
+// TestCompactRoundTrip ensures converting compact values to big integers and
+// back again results in the original compact value for values that are
+// already in their normalized form.
+func TestCompactRoundTrip(t *testing.T) {
+	tests := []uint32{
+		0x01120000,
+		0x02123400,
+		0x03123456,
+		0x04923456,
+		0x05009234,
+		0x181bc330,
+		0x1a05db8b,
+		0x1b0404cb,
+		0x1d00ffff,
+		0x207fffff,
+	}
+
+	for x, bits := range tests {
+		r := BigToCompact(CompactToBig(bits))
+		if r != bits {
+			t.Errorf("TestCompactRoundTrip test #%d failed: got %08x "+
+				"want %08x\n", x, r, bits)
+		}
+	}
+}
+
+// TestCompactOverflow ensures compact values with exponents that overflow 256
+// bits are converted to their full value and exceed the proof-of-work limit of
+// every network.
+func TestCompactOverflow(t *testing.T) {
+	tests := []uint32{0x21010000, 0x22000100, 0xff123456}
+
+	for x, bits := range tests {
+		n := CompactToBig(bits)
+		if n.BitLen() <= 256 {
+			t.Errorf("TestCompactOverflow test #%d failed: got %d "+
+				"bits want more than 256\n", x, n.BitLen())
+			continue
+		}
+		if n.Cmp(chaincfg.RegressionNetParams.PowLimit) <= 0 {
+			t.Errorf("TestCompactOverflow test #%d failed: %064x "+
+				"does not exceed the pow limit\n", x, n)
+		}
+	}
+}
+
+// TestCalcWorkChainwork ensures the work of consecutive blocks accumulates to
+// the expected chainwork.
+func TestCalcWorkChainwork(t *testing.T) {
+	tests := []struct {
+		bits      []uint32
+		chainwork string
+	}{
+		{[]uint32{0x1d00ffff}, "100010001"},
+		{[]uint32{0x1d00ffff, 0x1d00ffff}, "200020002"},
+		{[]uint32{0x1d00ffff, 0x1d00ffff, 0x1d00ffff}, "300030003"},
+		{[]uint32{0x1d00ffff, 0x1b0404cb, 0x181bc330},
+			"93899759a4c7a22bd"},
+	}
+
+	for x, test := range tests {
+		chainwork := new(big.Int)
+		for _, bits := range test.bits {
+			chainwork.Add(chainwork, CalcWork(bits))
+		}
+		if chainwork.Text(16) != test.chainwork {
+			t.Errorf("TestCalcWorkChainwork test #%d failed: got %x "+
+				"want %s\n", x, chainwork, test.chainwork)
+		}
+	}
+}
+
+// TestCalcHeaderNextRequiredDifficulty ensures the difficulty retarget rules
+// are applied to a header chain, including reproducing a known retarget of the
+// bitcoin main network which uses the same rules.
+func TestCalcHeaderNextRequiredDifficulty(t *testing.T) {
+	t.Parallel()
+
+	// Use the bitcoin main network retarget parameters, which retarget
+	// every 2016 blocks.
+	params := chaincfg.MainNetParams
+	params.PowLimit = new(big.Int).Sub(new(big.Int).Lsh(bigOne, 224), bigOne)
+	params.PowLimitBits = 0x1d00ffff
+	params.TargetTimespan = time.Hour * 24 * 14
+	params.TargetTimePerBlock = time.Minute * 10
+	params.RetargetAdjustmentFactor = 4
+	params.ReduceMinDifficulty = false
+
+	// newChain returns a chain ending at the last block before the retarget
+	// at height 32256 where the first block of the interval, at height
+	// 30240, has the passed timestamp and the last has 1262152739.
+	newChain := func(firstTimestamp int64) *testHeader {
+		headers := make([]testHeaderFields, 32256)
+		for i := range headers {
+			headers[i].bits = 0x1d00ffff
+		}
+		headers[30240].timestamp = firstTimestamp
+		headers[32255].timestamp = 1262152739
+		return &testHeader{height: 32255, headers: headers}
+	}
+	chain := newChain(1261130161)
+
+	tests := []struct {
+		name       string
+		lastHeader HeaderAccessor
+		want       uint32
+	}{
+		{"genesis", nil, 0x1d00ffff},
+		{"not at retarget interval", chain.AncestorHeader(32254), 0x1d00ffff},
+		{"mainnet retarget at height 32256", chain, 0x1d00d86a},
+		{"clamped to max increase", newChain(1262152739), 0x1c3fffc0},
+		{"clamped to pow limit", newChain(0), 0x1d00ffff},
+	}
+
+	for _, test := range tests {
+		bits, err := CalcHeaderNextRequiredDifficulty(test.lastHeader,
+			time.Unix(1262153000, 0), &params)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if bits != test.want {
+			t.Errorf("%s: unexpected bits - got %08x, want %08x",
+				test.name, bits, test.want)
+		}
+	}
+
+	// Ensure the test network rules allow minimum difficulty blocks once
+	// too much time has elapsed and otherwise use the difficulty of the
+	// last block without the special rule applied.
+	params.ReduceMinDifficulty = true
+	params.MinDiffReductionTime = time.Minute * 20
+	headers := make([]testHeaderFields, 32250)
+	for i := range headers {
+		headers[i].bits = 0x1c3fffc0
+		headers[i].timestamp = int64(i) * 600
+	}
+	headers[32248].bits = params.PowLimitBits
+	headers[32249].bits = params.PowLimitBits
+	testnetChain := &testHeader{height: 32249, headers: headers}
+	lastTime := time.Unix(headers[32249].timestamp, 0)
+
+	minDiffTests := []struct {
+		name     string
+		blockGap time.Duration
+		want     uint32
+	}{
+		{"within reduction time", time.Minute * 20, 0x1c3fffc0},
+		{"after reduction time", time.Minute*20 + time.Second,
+			params.PowLimitBits},
+	}
+	for _, test := range minDiffTests {
+		bits, err := CalcHeaderNextRequiredDifficulty(testnetChain,
+			lastTime.Add(test.blockGap), &params)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if bits != test.want {
+			t.Errorf("%s: unexpected bits - got %08x, want %08x",
+				test.name, bits, test.want)
+		}
+	}
+}
+
+// TestCalcNextRequiredDifficultyNodes ensures block nodes provide the header
+// data needed by the difficulty retarget rules, so the difficulty the chain
+// requires for the block after a block node follows the same rules as for a
+// chain of headers.
+func TestCalcNextRequiredDifficultyNodes(t *testing.T) {
+	t.Parallel()
+
+	// Use test network rules which retarget every 10 blocks.
+	params := chaincfg.MainNetParams
+	params.PowLimit = new(big.Int).Sub(new(big.Int).Lsh(bigOne, 224), bigOne)
+	params.PowLimitBits = 0x1d00ffff
+	params.TargetTimespan = time.Minute * 100
+	params.TargetTimePerBlock = time.Minute * 10
+	params.RetargetAdjustmentFactor = 4
+	params.ReduceMinDifficulty = true
+	params.MinDiffReductionTime = time.Minute * 20
+	chain := newFakeChain(&params)
+
+	// Create a chain through height 12 where the blocks after the retarget
+	// at height 10 have the minimum difficulty.
+	const bits = 0x1c3fffc0
+	node := chain.bestChain.Tip()
+	baseTime := time.Unix(node.timestamp, 0)
+	for height := int32(1); height <= 12; height++ {
+		nodeBits := uint32(bits)
+		if height > 10 {
+			nodeBits = params.PowLimitBits
+		}
+		node = newFakeNode(node, 1, nodeBits,
+			baseTime.Add(time.Duration(height)*params.TargetTimePerBlock))
+	}
+	lastTime := time.Unix(node.timestamp, 0)
+
+	// Ensure out of range ancestors are reported as nil rather than typed
+	// nil block nodes.
+	if ancestor := node.AncestorHeader(13); ancestor != nil {
+		t.Fatalf("AncestorHeader: unexpected ancestor %v", ancestor)
+	}
+	if ancestor := node.AncestorHeader(10); ancestor.Bits() != bits {
+		t.Fatalf("AncestorHeader: unexpected bits - got %08x, want %08x",
+			ancestor.Bits(), uint32(bits))
+	}
+
+	tests := []struct {
+		name     string
+		lastNode *blockNode
+		blockGap time.Duration
+		want     uint32
+	}{
+		{"genesis", nil, 0, params.PowLimitBits},
+		{"within reduction time", node, time.Minute * 20, bits},
+		{"after reduction time", node, time.Minute*20 + time.Second,
+			params.PowLimitBits},
+	}
+	for _, test := range tests {
+		got, err := chain.calcNextRequiredDifficulty(test.lastNode,
+			lastTime.Add(test.blockGap))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: unexpected bits - got %08x, want %08x",
+				test.name, got, test.want)
+		}
+	}
+}
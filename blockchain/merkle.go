@@ -154,6 +154,32 @@ func BuildMerkleTreeStore(transactions []*navutil.Tx, witness bool) []*chainhash
 	return merkles
 }
 
+// calcWitnessCommitment returns the witness commitment for a block with the
+// passed witness merkle root and coinbase witness nonce, which is
+// SHA256d(witness root || witness nonce).
+func calcWitnessCommitment(witnessMerkleRoot *chainhash.Hash, witnessNonce []byte) []byte {
+	var witnessPreimage [chainhash.HashSize * 2]byte
+	copy(witnessPreimage[:], witnessMerkleRoot[:])
+	copy(witnessPreimage[chainhash.HashSize:], witnessNonce)
+
+	return chainhash.DoubleHashB(witnessPreimage[:])
+}
+
+// WitnessCommitmentScript returns the public key script of the coinbase output
+// which commits to the witness data of a block with the passed witness merkle
+// root.  The witness nonce is the only item of the coinbase transaction's
+// witness and must be CoinbaseWitnessDataLen bytes for the commitment to be
+// valid.  The script is of the form:
+// OP_RETURN OP_DATA_36 {0xaa21a9ed || SHA256d(witness root || witness nonce)}.
+func WitnessCommitmentScript(witnessMerkleRoot chainhash.Hash, witnessNonce []byte) []byte {
+	witnessCommitment := calcWitnessCommitment(&witnessMerkleRoot,
+		witnessNonce)
+
+	script := make([]byte, 0, CoinbaseWitnessPkScriptLength)
+	script = append(script, WitnessMagicBytes...)
+	return append(script, witnessCommitment...)
+}
+
 // ExtractWitnessCommitment attempts to locate, and return the witness
 // commitment for a block. The witness commitment is of the form:
 // SHA256(witness root || witness nonce). The function additionally returns a
@@ -249,11 +275,8 @@ func ValidateWitnessCommitment(blk *navutil.Block) error {
 	witnessMerkleTree := BuildMerkleTreeStore(blk.Transactions(), true)
 	witnessMerkleRoot := witnessMerkleTree[len(witnessMerkleTree)-1]
 
-	var witnessPreimage [chainhash.HashSize * 2]byte
-	copy(witnessPreimage[:], witnessMerkleRoot[:])
-	copy(witnessPreimage[chainhash.HashSize:], witnessNonce)
-
-	computedCommitment := chainhash.DoubleHashB(witnessPreimage[:])
+	computedCommitment := calcWitnessCommitment(witnessMerkleRoot,
+		witnessNonce)
 	if !bytes.Equal(computedCommitment, witnessCommitment) {
 		str := fmt.Sprintf("witness commitment does not match: "+
 			"computed %v, coinbase includes %v", computedCommitment,
@@ -5,8 +5,12 @@
 package blockchain
 
 import (
+	"bytes"
+	"encoding/hex"
 	"testing"
 
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
 	"github.com/navcoin/navutil"
 )
 
@@ -21,3 +25,106 @@ func TestMerkle(t *testing.T) {
 			"got %v, want %v", calculatedMerkleRoot, wantMerkle)
 	}
 }
+
+// TestWitnessCommitmentScript ensures the witness commitment output script is
+// built as expected and is accepted by the witness commitment validation.
+func TestWitnessCommitmentScript(t *testing.T) {
+	t.Parallel()
+
+	// Ensure the script commits to SHA256d(witness root || witness nonce)
+	// for both the all zero nonce used by miners and an arbitrary nonce.
+	root := Block100000.Header.MerkleRoot
+	tests := []struct {
+		name  string
+		nonce []byte
+		want  string
+	}{
+		{
+			name:  "zero nonce",
+			nonce: make([]byte, CoinbaseWitnessDataLen),
+			want: "6a24aa21a9ed2d6ca1005c8486037e45db45d6b827012b9f57b" +
+				"0fc52dfc490de5d475d98b4c6",
+		},
+		{
+			name: "arbitrary nonce",
+			nonce: []byte{
+				0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+				0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+				0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17,
+				0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f,
+			},
+			want: "6a24aa21a9edb29ebc8d08a1579ff27dc700e9b41b40dcb9c8f" +
+				"f2714dcd295024d5d84b0e707",
+		},
+	}
+	for _, test := range tests {
+		script := WitnessCommitmentScript(root, test.nonce)
+		if got := hex.EncodeToString(script); got != test.want {
+			t.Errorf("%s: mismatched script - got %s, want %s",
+				test.name, got, test.want)
+		}
+		if len(script) != CoinbaseWitnessPkScriptLength {
+			t.Errorf("%s: unexpected script length %d", test.name,
+				len(script))
+		}
+	}
+
+	// Build a block with a transaction carrying witness data and ensure
+	// the commitment created for it is found and validates.
+	witnessNonce := make([]byte, CoinbaseWitnessDataLen)
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{},
+			wire.MaxPrevOutIndex),
+		SignatureScript: []byte{0x51, 0x51},
+		Witness:         wire.TxWitness{witnessNonce},
+		Sequence:        wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(5000000000, []byte{0x51}))
+
+	spend := wire.NewMsgTx(1)
+	spend.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&root, 0),
+		Witness:          wire.TxWitness{{0x01, 0x02}, {0x03}},
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	spend.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+
+	msgBlock := wire.MsgBlock{
+		Transactions: []*wire.MsgTx{coinbase, spend},
+	}
+	block := navutil.NewBlock(&msgBlock)
+	witnessMerkleTree := BuildMerkleTreeStore(block.Transactions(), true)
+	witnessRoot := witnessMerkleTree[len(witnessMerkleTree)-1]
+	if got := wire.CalcWitnessMerkleRoot(msgBlock.Transactions); got != *witnessRoot {
+		t.Fatalf("CalcWitnessMerkleRoot: mismatched root - got %v, "+
+			"want %v", got, witnessRoot)
+	}
+	script := WitnessCommitmentScript(*witnessRoot, witnessNonce)
+	coinbase.AddTxOut(wire.NewTxOut(0, script))
+
+	commitment, found := ExtractWitnessCommitment(block.Transactions()[0])
+	if !found {
+		t.Fatalf("ExtractWitnessCommitment: commitment not found")
+	}
+	if !bytes.Equal(commitment, script[len(WitnessMagicBytes):]) {
+		t.Fatalf("ExtractWitnessCommitment: mismatched commitment - "+
+			"got %x, want %x", commitment,
+			script[len(WitnessMagicBytes):])
+	}
+	if err := ValidateWitnessCommitment(block); err != nil {
+		t.Fatalf("ValidateWitnessCommitment: unexpected error: %v", err)
+	}
+
+	// Changing the nonce must invalidate the commitment.
+	badNonce := make([]byte, CoinbaseWitnessDataLen)
+	badNonce[0] = 0x01
+	coinbase.TxIn[0].Witness = wire.TxWitness{badNonce}
+	err := ValidateWitnessCommitment(block)
+	if rerr, ok := err.(RuleError); !ok ||
+		rerr.ErrorCode != ErrWitnessCommitmentMismatch {
+
+		t.Fatalf("ValidateWitnessCommitment: unexpected error - got %v, "+
+			"want %v", err, ErrWitnessCommitmentMismatch)
+	}
+}
@@ -114,6 +114,15 @@ func IsCoinBase(tx *navutil.Tx) bool {
 	return IsCoinBaseTx(tx.MsgTx())
 }
 
+// IsCoinBaseMature returns whether or not the outputs of a coinbase included in
+// a block at coinbaseHeight may be spent by a transaction included in a block at
+// spendHeight.  Coinbase outputs may only be spent once the network's coinbase
+// maturity number of blocks have passed since the coinbase was mined.
+func IsCoinBaseMature(coinbaseHeight, spendHeight int32, params *chaincfg.Params) bool {
+	blocksSincePrev := spendHeight - coinbaseHeight
+	return blocksSincePrev >= int32(params.CoinbaseMaturity)
+}
+
 // SequenceLockActive determines if a transaction's sequence locks have been
 // met, meaning that all the inputs of a given transaction have reached a
 // height or time sufficient for their relative lock-time maturity.
@@ -344,6 +353,19 @@ func CheckProofOfWork(block *navutil.Block, powLimit *big.Int) error {
 	return checkProofOfWork(&block.MsgBlock().Header, powLimit, BFNone)
 }
 
+// CheckHeaderProofOfWork ensures the bits of the passed block header, which
+// indicate the target difficulty, are in min/max range and that the header hash
+// is less than the target difficulty as claimed.  Targets which are negative,
+// zero, or overflow the maximum allowed when decoded from their compact form
+// are rejected with ErrUnexpectedDifficulty and a header hash above the target
+// is rejected with ErrHighHash.
+//
+// This is useful for verifying headers, such as those received during headers
+// first synchronization, before the full block is available.
+func CheckHeaderProofOfWork(header *wire.BlockHeader, powLimit *big.Int) error {
+	return checkProofOfWork(header, powLimit, BFNone)
+}
+
 // CountSigOps returns the number of signature operations for all transaction
 // input and output scripts in the provided transaction.  This uses the
 // quicker, but imprecise, signature operation counting mechanism from
@@ -894,15 +916,13 @@ func CheckTransactionInputs(tx *navutil.Tx, txHeight int32, utxoView *UtxoViewpo
 		// yet reached the required coinbase maturity.
 		if utxoEntry.IsCoinBase() {
 			originHeight := utxoEntry.BlockHeight()
-			blocksSincePrev := txHeight - originHeight
-			coinbaseMaturity := int32(chainParams.CoinbaseMaturity)
-			if blocksSincePrev < coinbaseMaturity {
+			if !IsCoinBaseMature(originHeight, txHeight, chainParams) {
 				str := fmt.Sprintf("tried to spend coinbase "+
 					"transaction %v from height %v at "+
 					"height %v before required maturity "+
 					"of %v blocks", originTxHash,
 					originHeight, txHeight,
-					coinbaseMaturity)
+					chainParams.CoinbaseMaturity)
 				return 0, ruleError(ErrImmatureSpend, str)
 			}
 		}
@@ -965,6 +985,74 @@ func CheckTransactionInputs(tx *navutil.Tx, txHeight int32, utxoView *UtxoViewpo
 	return txFeeInSatoshi, nil
 }
 
+// CalcTxFee returns the fee paid by the passed transaction, which is the total
+// of the passed input amounts less the total of its outputs.  The input amounts
+// are the values of the outputs spent by the inputs of the transaction and must
+// be in the same order.  Since coinbase transactions do not spend any outputs,
+// they pay no fee and the input amounts are ignored.
+//
+// An error is returned when the number of input amounts does not match the
+// number of inputs, when an amount is out of range, or when the transaction
+// spends more than its inputs, in which case the error is a RuleError with the
+// ErrSpendTooHigh error code.
+func CalcTxFee(tx *wire.MsgTx, inputAmounts []int64) (int64, error) {
+	// Coinbase transactions have no inputs.
+	if IsCoinBaseTx(tx) {
+		return 0, nil
+	}
+
+	if len(inputAmounts) != len(tx.TxIn) {
+		return 0, fmt.Errorf("transaction %v has %d inputs but %d input "+
+			"amounts were provided", tx.TxHash(), len(tx.TxIn),
+			len(inputAmounts))
+	}
+
+	// sumAmounts returns the total of the passed amounts, ensuring each of
+	// them and the total are within the range of valid amounts.
+	sumAmounts := func(amounts []int64, kind string) (int64, error) {
+		var total int64
+		for _, amount := range amounts {
+			if amount < 0 || amount > navutil.MaxSatoshi {
+				str := fmt.Sprintf("transaction %s value of %v is "+
+					"outside the valid range of 0 to %v", kind,
+					navutil.Amount(amount), navutil.MaxSatoshi)
+				return 0, ruleError(ErrBadTxOutValue, str)
+			}
+			total += amount
+			if total > navutil.MaxSatoshi {
+				str := fmt.Sprintf("total value of all transaction "+
+					"%ss is higher than max allowed value of %v",
+					kind, navutil.MaxSatoshi)
+				return 0, ruleError(ErrBadTxOutValue, str)
+			}
+		}
+		return total, nil
+	}
+
+	totalSatoshiIn, err := sumAmounts(inputAmounts, "input")
+	if err != nil {
+		return 0, err
+	}
+	outputAmounts := make([]int64, 0, len(tx.TxOut))
+	for _, txOut := range tx.TxOut {
+		outputAmounts = append(outputAmounts, txOut.Value)
+	}
+	totalSatoshiOut, err := sumAmounts(outputAmounts, "output")
+	if err != nil {
+		return 0, err
+	}
+
+	// Ensure the transaction does not spend more than its inputs.
+	if totalSatoshiIn < totalSatoshiOut {
+		str := fmt.Sprintf("total value of all transaction inputs for "+
+			"transaction %v is %v which is less than the amount "+
+			"spent of %v", tx.TxHash(), totalSatoshiIn, totalSatoshiOut)
+		return 0, ruleError(ErrSpendTooHigh, str)
+	}
+
+	return totalSatoshiIn - totalSatoshiOut, nil
+}
+
 // checkConnectBlock performs several checks to confirm connecting the passed
 // block to the chain represented by the passed view does not violate any rules.
 // In addition, the passed view is updated to spend all of the referenced
@@ -169,6 +169,146 @@ func TestCheckBlockSanity(t *testing.T) {
 	}
 }
 
+// TestCheckHeaderProofOfWork ensures CheckHeaderProofOfWork accepts a valid
+// header and rejects headers with an invalid target or insufficient work.
+func TestCheckHeaderProofOfWork(t *testing.T) {
+	powLimit := chaincfg.MainNetParams.PowLimit
+	header := Block100000.Header
+	if err := CheckHeaderProofOfWork(&header, powLimit); err != nil {
+		t.Fatalf("CheckHeaderProofOfWork: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*wire.BlockHeader)
+		want   ErrorCode
+	}{
+		{
+			name:   "tampered nonce",
+			modify: func(h *wire.BlockHeader) { h.Nonce++ },
+			want:   ErrHighHash,
+		},
+		{
+			name:   "negative target",
+			modify: func(h *wire.BlockHeader) { h.Bits = 0x1b84864c },
+			want:   ErrUnexpectedDifficulty,
+		},
+		{
+			name:   "zero target",
+			modify: func(h *wire.BlockHeader) { h.Bits = 0x1b000000 },
+			want:   ErrUnexpectedDifficulty,
+		},
+		{
+			name:   "overflow target",
+			modify: func(h *wire.BlockHeader) { h.Bits = 0xff7fffff },
+			want:   ErrUnexpectedDifficulty,
+		},
+		{
+			name:   "target above pow limit",
+			modify: func(h *wire.BlockHeader) { h.Bits = 0x1d01ffff },
+			want:   ErrUnexpectedDifficulty,
+		},
+	}
+
+	for _, test := range tests {
+		header := Block100000.Header
+		test.modify(&header)
+		err := CheckHeaderProofOfWork(&header, powLimit)
+		rerr, ok := err.(RuleError)
+		if !ok {
+			t.Errorf("%s: did not receive expected rule error - got %v",
+				test.name, err)
+			continue
+		}
+		if rerr.ErrorCode != test.want {
+			t.Errorf("%s: mismatched error code - got %v, want %v",
+				test.name, rerr.ErrorCode, test.want)
+		}
+	}
+}
+
+// TestCalcTxFee ensures CalcTxFee returns the difference between the input
+// and output amounts of a transaction and rejects invalid amounts.
+func TestCalcTxFee(t *testing.T) {
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{0x01}}, nil,
+		nil))
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{0x02}}, nil,
+		nil))
+	tx.AddTxOut(wire.NewTxOut(30000, nil))
+	tx.AddTxOut(wire.NewTxOut(15000, nil))
+
+	tests := []struct {
+		name    string
+		amounts []int64
+		want    int64
+		code    ErrorCode
+		isErr   bool
+	}{
+		{
+			name:    "fee paid",
+			amounts: []int64{40000, 10000},
+			want:    5000,
+		},
+		{
+			name:    "no fee",
+			amounts: []int64{45000, 0},
+			want:    0,
+		},
+		{
+			name:    "over-spend",
+			amounts: []int64{40000, 4999},
+			code:    ErrSpendTooHigh,
+			isErr:   true,
+		},
+		{
+			name:    "negative input amount",
+			amounts: []int64{50000, -1},
+			code:    ErrBadTxOutValue,
+			isErr:   true,
+		},
+		{
+			name:    "input total above max",
+			amounts: []int64{navutil.MaxSatoshi, 1},
+			code:    ErrBadTxOutValue,
+			isErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		fee, err := CalcTxFee(tx, test.amounts)
+		if !test.isErr {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+				continue
+			}
+			if fee != test.want {
+				t.Errorf("%s: mismatched fee - got %d, want %d",
+					test.name, fee, test.want)
+			}
+			continue
+		}
+		rerr, ok := err.(RuleError)
+		if !ok || rerr.ErrorCode != test.code {
+			t.Errorf("%s: unexpected error - got %v, want %v",
+				test.name, err, test.code)
+		}
+	}
+
+	// Ensure a mismatched number of input amounts is rejected.
+	if _, err := CalcTxFee(tx, []int64{50000}); err == nil {
+		t.Errorf("CalcTxFee: did not reject mismatched input amounts")
+	}
+
+	// Ensure coinbase transactions pay no fee.
+	coinbase := Block100000.Transactions[0]
+	fee, err := CalcTxFee(coinbase, nil)
+	if err != nil || fee != 0 {
+		t.Errorf("CalcTxFee: unexpected coinbase result - got %d, %v",
+			fee, err)
+	}
+}
+
 // TestCheckSerializedHeight tests the checkSerializedHeight function with
 // various serialized heights and also does negative tests to ensure errors
 // and handled properly.
@@ -234,6 +374,73 @@ func TestCheckSerializedHeight(t *testing.T) {
 	}
 }
 
+// TestIsCoinBaseTx ensures only transactions with a single input spending the
+// null previous outpoint are identified as coinbases.
+func TestIsCoinBaseTx(t *testing.T) {
+	coinbaseOutpoint := wire.NewOutPoint(&chainhash.Hash{}, math.MaxUint32)
+	coinbaseTx := wire.NewMsgTx(1)
+	coinbaseTx.AddTxIn(wire.NewTxIn(coinbaseOutpoint, nil, nil))
+
+	nonZeroHashTx := wire.NewMsgTx(1)
+	nonZeroHashTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(
+		&chainhash.Hash{0x01}, math.MaxUint32), nil, nil))
+
+	nonMaxIndexTx := wire.NewMsgTx(1)
+	nonMaxIndexTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(
+		&chainhash.Hash{}, 0), nil, nil))
+
+	multiInputTx := coinbaseTx.Copy()
+	multiInputTx.AddTxIn(wire.NewTxIn(coinbaseOutpoint, nil, nil))
+
+	tests := []struct {
+		name string
+		tx   *wire.MsgTx
+		want bool
+	}{
+		{"coinbase", coinbaseTx, true},
+		{"non-zero previous hash", nonZeroHashTx, false},
+		{"non-max previous index", nonMaxIndexTx, false},
+		{"multiple inputs", multiInputTx, false},
+		{"no inputs", wire.NewMsgTx(1), false},
+	}
+
+	for _, test := range tests {
+		if got := IsCoinBaseTx(test.tx); got != test.want {
+			t.Errorf("IsCoinBaseTx (%s): got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestIsCoinBaseMature ensures coinbase outputs are only considered spendable
+// once the network's coinbase maturity has been reached.
+func TestIsCoinBaseMature(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	maturity := int32(params.CoinbaseMaturity)
+
+	tests := []struct {
+		name           string
+		coinbaseHeight int32
+		spendHeight    int32
+		want           bool
+	}{
+		{"same block", 1000, 1000, false},
+		{"immature", 1000, 1000 + maturity - 1, false},
+		{"mature boundary", 1000, 1000 + maturity, true},
+		{"mature", 1000, 1000 + maturity + 1, true},
+		{"spend before coinbase", 1000, 999, false},
+	}
+
+	for _, test := range tests {
+		got := IsCoinBaseMature(test.coinbaseHeight, test.spendHeight,
+			params)
+		if got != test.want {
+			t.Errorf("IsCoinBaseMature (%s): got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
 // Block100000 defines block 100,000 of the block chain.  It is used to
 // test Block operations.
 var Block100000 = wire.MsgBlock{
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/navcoin/navd/txscript"
+	"github.com/navcoin/navd/wire"
 	"github.com/navcoin/navutil"
 )
 
@@ -65,6 +66,38 @@ func GetTransactionWeight(tx *navutil.Tx) int64 {
 	return int64((baseSize * (WitnessScaleFactor - 1)) + totalSize)
 }
 
+// byteCounter is an io.Writer which counts the bytes written to it.
+type byteCounter int64
+
+// Write counts the bytes in the passed slice.
+//
+// This is part of the io.Writer interface.
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// TxWeight returns the weight of the passed transaction as defined by BIP0141,
+// which is the size of the transaction serialized without witness data scaled
+// by WitnessScaleFactor-1 plus the size of the transaction serialized with any
+// witness data.
+//
+// Unlike GetTransactionWeight, which relies on the calculated serialized sizes,
+// the sizes are determined by actually serializing the transaction, so it may
+// be used to audit the weight used for a claimed fee rate.  The marker and flag
+// bytes are only counted when at least one input has a witness, while every
+// input of such a transaction contributes its witness, even when it is empty.
+func TxWeight(tx *wire.MsgTx) int64 {
+	// Writing to a byteCounter can't fail and the encoding does not
+	// validate the transaction, so the errors are ignored.
+	var baseSize, totalSize byteCounter
+	_ = tx.SerializeNoWitness(&baseSize)
+	_ = tx.Serialize(&totalSize)
+
+	// (baseSize * 3) + totalSize
+	return int64(baseSize)*(WitnessScaleFactor-1) + int64(totalSize)
+}
+
 // GetSigOpCost returns the unified sig op cost for the passed transaction
 // respecting current active soft-forks which modified sig op cost counting.
 // The unified sig op cost for a transaction is computed as the sum of: the
@@ -0,0 +1,80 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+	"github.com/navcoin/navutil"
+)
+
+// TestTxWeight ensures TxWeight returns the expected weights for legacy and
+// segwit transactions and agrees with GetTransactionWeight.
+func TestTxWeight(t *testing.T) {
+	t.Parallel()
+
+	pkScript := bytes.Repeat([]byte{0x51}, 25)
+
+	// newTx returns a transaction with an input for each of the passed
+	// witnesses and a single output.
+	newTx := func(witnesses ...wire.TxWitness) *wire.MsgTx {
+		tx := wire.NewMsgTx(1)
+		for i, witness := range witnesses {
+			prevOut := wire.NewOutPoint(&chainhash.Hash{0x01}, uint32(i))
+			tx.AddTxIn(wire.NewTxIn(prevOut, nil, witness))
+		}
+		tx.AddTxOut(wire.NewTxOut(1000, pkScript))
+		return tx
+	}
+
+	// A transaction with a single input and output paying to a 25-byte
+	// script serializes to 90 bytes, and each additional input without a
+	// signature script adds 41 bytes.  A transaction with witness data
+	// adds the marker and flag bytes and the serialized witness of every
+	// input, where an empty witness is a single byte.
+	tests := []struct {
+		name   string
+		tx     *wire.MsgTx
+		weight int64
+	}{{
+		name:   "legacy",
+		tx:     newTx(nil),
+		weight: 90 * 4,
+	}, {
+		name:   "legacy with empty witnesses",
+		tx:     newTx(wire.TxWitness{}, wire.TxWitness{}),
+		weight: 131 * 4,
+	}, {
+		name:   "segwit",
+		tx:     newTx(wire.TxWitness{{0x01, 0x02}}),
+		weight: 90*3 + 90 + 2 + 4,
+	}, {
+		name:   "partially populated witnesses",
+		tx:     newTx(wire.TxWitness{{0x01, 0x02}}, nil),
+		weight: 131*3 + 131 + 2 + 4 + 1,
+	}, {
+		name:   "empty witness item",
+		tx:     newTx(nil, wire.TxWitness{{}}),
+		weight: 131*3 + 131 + 2 + 1 + 2,
+	}}
+
+	for _, test := range tests {
+		weight := TxWeight(test.tx)
+		if weight != test.weight {
+			t.Errorf("%s: unexpected weight - got %d, want %d",
+				test.name, weight, test.weight)
+			continue
+		}
+
+		calculated := GetTransactionWeight(navutil.NewTx(test.tx))
+		if weight != calculated {
+			t.Errorf("%s: weight %d does not match calculated "+
+				"weight %d", test.name, weight, calculated)
+		}
+	}
+}
@@ -121,6 +121,74 @@ func ParsePubKey(pubKeyStr []byte, curve *KoblitzCurve) (key *PublicKey, err err
 	return &pubkey, nil
 }
 
+// PubKeyFormat describes the format a public key is serialized in.
+type PubKeyFormat int
+
+// These constants define the supported public key serialization formats.
+const (
+	// PubKeyFormatUncompressed is the 65-byte format consisting of the
+	// 0x04 prefix followed by the X and Y coordinates.
+	PubKeyFormatUncompressed PubKeyFormat = iota
+
+	// PubKeyFormatCompressed is the 33-byte format consisting of a 0x02 or
+	// 0x03 prefix, which encodes the oddness of the Y coordinate, followed
+	// by the X coordinate.
+	PubKeyFormatCompressed
+
+	// PubKeyFormatHybrid is the 65-byte format consisting of a 0x06 or
+	// 0x07 prefix, which encodes the oddness of the Y coordinate, followed
+	// by the X and Y coordinates.
+	PubKeyFormatHybrid
+)
+
+// pubKeyFormatStrings is a map of public key formats back to their constant
+// names for pretty printing.
+var pubKeyFormatStrings = map[PubKeyFormat]string{
+	PubKeyFormatUncompressed: "PubKeyFormatUncompressed",
+	PubKeyFormatCompressed:   "PubKeyFormatCompressed",
+	PubKeyFormatHybrid:       "PubKeyFormatHybrid",
+}
+
+// String returns the PubKeyFormat as a human-readable name.
+func (f PubKeyFormat) String() string {
+	if s := pubKeyFormatStrings[f]; s != "" {
+		return s
+	}
+	return fmt.Sprintf("Unknown PubKeyFormat (%d)", int(f))
+}
+
+// ParsePubKeyFormat parses a public key in the same way as ParsePubKey and
+// additionally returns the format it was serialized in.  When strict is set,
+// public keys in the hybrid format are rejected, as required for public keys
+// in witness scripts.  A public key in any format may be normalized to the
+// compressed format with SerializeCompressed.
+func ParsePubKeyFormat(pubKeyStr []byte, curve *KoblitzCurve, strict bool) (*PublicKey, PubKeyFormat, error) {
+	pubKey, err := ParsePubKey(pubKeyStr, curve)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// ParsePubKey ignores the low bit of the prefix of uncompressed keys
+	// since it carries no information, so reject the 0x05 prefix here.
+	var format PubKeyFormat
+	switch pubKeyStr[0] &^ 0x1 {
+	case pubkeyUncompressed:
+		if pubKeyStr[0] != pubkeyUncompressed {
+			return nil, 0, fmt.Errorf("invalid magic in uncompressed "+
+				"pubkey string: %d", pubKeyStr[0])
+		}
+		format = PubKeyFormatUncompressed
+	case pubkeyCompressed:
+		format = PubKeyFormatCompressed
+	case pubkeyHybrid:
+		format = PubKeyFormatHybrid
+	}
+	if strict && format == PubKeyFormatHybrid {
+		return nil, 0, errors.New("hybrid pubkey format is not allowed")
+	}
+	return pubKey, format, nil
+}
+
 // PublicKey is an ecdsa.PublicKey with additional functions to
 // serialize in uncompressed, compressed, and hybrid formats.
 type PublicKey ecdsa.PublicKey
@@ -294,3 +294,82 @@ func TestIsCompressed(t *testing.T) {
 		}
 	}
 }
+
+func TestParsePubKeyFormat(t *testing.T) {
+	wantFormats := map[byte]PubKeyFormat{
+		pubkeyUncompressed: PubKeyFormatUncompressed,
+		pubkeyCompressed:   PubKeyFormatCompressed,
+		pubkeyHybrid:       PubKeyFormatHybrid,
+	}
+	for _, test := range pubKeyTests {
+		for _, strict := range []bool{false, true} {
+			pk, format, err := ParsePubKeyFormat(test.key, S256(),
+				strict)
+			wantValid := test.isValid &&
+				!(strict && test.format == pubkeyHybrid)
+			if err != nil {
+				if wantValid {
+					t.Errorf("%s (strict %v) pubkey failed when "+
+						"shouldn't %v", test.name, strict, err)
+				}
+				continue
+			}
+			if !wantValid {
+				t.Errorf("%s (strict %v) counted as valid when it "+
+					"should fail", test.name, strict)
+				continue
+			}
+			if format != wantFormats[test.format] {
+				t.Errorf("%s (strict %v) unexpected format, got "+
+					"%v, want %v", test.name, strict, format,
+					wantFormats[test.format])
+				continue
+			}
+
+			// Normalizing to the compressed format must produce a
+			// key which parses back to the same point.
+			compressed := pk.SerializeCompressed()
+			pk2, format, err := ParsePubKeyFormat(compressed, S256(),
+				strict)
+			if err != nil {
+				t.Errorf("%s (strict %v) compressed pubkey failed "+
+					"to parse: %v", test.name, strict, err)
+				continue
+			}
+			if format != PubKeyFormatCompressed || !pk.IsEqual(pk2) {
+				t.Errorf("%s (strict %v) compressed pubkey does "+
+					"not match", test.name, strict)
+			}
+		}
+	}
+
+	// Uncompressed keys with the low bit of the prefix set are rejected
+	// even though ParsePubKey ignores it.
+	for _, test := range pubKeyTests {
+		if !test.isValid || test.format != pubkeyUncompressed {
+			continue
+		}
+		key := append([]byte{pubkeyUncompressed | 0x1}, test.key[1:]...)
+		if _, _, err := ParsePubKeyFormat(key, S256(), false); err == nil {
+			t.Errorf("%s with 0x05 prefix counted as valid when it "+
+				"should fail", test.name)
+		}
+	}
+}
+
+func TestPubKeyFormatStringer(t *testing.T) {
+	tests := []struct {
+		in   PubKeyFormat
+		want string
+	}{
+		{PubKeyFormatUncompressed, "PubKeyFormatUncompressed"},
+		{PubKeyFormatCompressed, "PubKeyFormatCompressed"},
+		{PubKeyFormatHybrid, "PubKeyFormatHybrid"},
+		{0xff, "Unknown PubKeyFormat (255)"},
+	}
+	for _, test := range tests {
+		if got := test.in.String(); got != test.want {
+			t.Errorf("String: got %q, want %q", got, test.want)
+		}
+	}
+}
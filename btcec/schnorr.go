@@ -0,0 +1,241 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package btcec
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// References:
+//   [BIP340]: Schnorr Signatures for secp256k1
+//   https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+
+const (
+	// SchnorrPubKeyLen is the length of a BIP340 x-only public key.
+	SchnorrPubKeyLen = 32
+
+	// SchnorrSigLen is the length of a BIP340 Schnorr signature.
+	SchnorrSigLen = 64
+
+	// schnorrMsgLen is the length of the messages signed and verified by
+	// the BIP340 functions in this package.
+	schnorrMsgLen = 32
+)
+
+// Tags used to domain separate the hashes in the BIP340 signing and
+// verification algorithms.
+const (
+	tagBIP340Aux       = "BIP0340/aux"
+	tagBIP340Nonce     = "BIP0340/nonce"
+	tagBIP340Challenge = "BIP0340/challenge"
+)
+
+// XOnlyPubKey is the 32-byte serialization of a public key used by BIP340.
+// Only the x coordinate is encoded and the y coordinate is implicitly the even
+// one of the two possible solutions.
+type XOnlyPubKey [SchnorrPubKeyLen]byte
+
+// XOnly returns the x-only serialization of the public key.  Note that the
+// parity of the y coordinate is discarded, so the key recovered from the
+// result via PubKey is the negation of p when p has an odd y coordinate.
+func (p *PublicKey) XOnly() XOnlyPubKey {
+	var xOnly XOnlyPubKey
+	copy(xOnly[:], paddedAppend(SchnorrPubKeyLen, nil, p.X.Bytes()))
+	return xOnly
+}
+
+// PubKey returns the public key with the x coordinate encoded by the x-only
+// key and an even y coordinate.  An error is returned when the x coordinate is
+// not less than the field prime or is not on the secp256k1 curve.
+func (k XOnlyPubKey) PubKey() (*PublicKey, error) {
+	curve := S256()
+	x := new(big.Int).SetBytes(k[:])
+	if x.Cmp(curve.P) >= 0 {
+		return nil, fmt.Errorf("x-only pubkey X parameter is >= to P")
+	}
+
+	y, err := decompressPoint(curve, x, false)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("x-only pubkey isn't on secp256k1 curve")
+	}
+	return &PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// LiftX returns the public key with the passed 32-byte x coordinate and an even
+// y coordinate as defined by the lift_x function of BIP340.  An error is
+// returned when the passed slice is not SchnorrPubKeyLen bytes, the x
+// coordinate is not less than the field prime, or there is no point on the
+// secp256k1 curve with the x coordinate.
+func LiftX(x []byte) (*PublicKey, error) {
+	if len(x) != SchnorrPubKeyLen {
+		return nil, fmt.Errorf("malformed x-only pubkey: invalid "+
+			"length: %d", len(x))
+	}
+
+	var xOnly XOnlyPubKey
+	copy(xOnly[:], x)
+	return xOnly.PubKey()
+}
+
+// ParseXOnlyPubKey parses the passed 32-byte x-only public key, verifying that
+// it encodes a point on the secp256k1 curve.
+func ParseXOnlyPubKey(x []byte) (XOnlyPubKey, error) {
+	var xOnly XOnlyPubKey
+	if _, err := LiftX(x); err != nil {
+		return xOnly, err
+	}
+	copy(xOnly[:], x)
+	return xOnly, nil
+}
+
+// Negate returns the negation of the public key, which has the same x
+// coordinate and the other of the two possible y coordinates.  Since x-only
+// public keys always imply an even y coordinate, keys with an odd y coordinate
+// are negated to obtain the key an x-only public key refers to.
+func (p *PublicKey) Negate() *PublicKey {
+	y := new(big.Int).Sub(p.Curve.Params().P, p.Y)
+	return &PublicKey{Curve: p.Curve, X: new(big.Int).Set(p.X), Y: y}
+}
+
+// TaggedHash returns the BIP340 tagged hash of the concatenation of the passed
+// messages.  That is SHA256(SHA256(tag) || SHA256(tag) || msgs...).
+func TaggedHash(tag string, msgs ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, msg := range msgs {
+		h.Write(msg)
+	}
+	return h.Sum(nil)
+}
+
+// schnorrChallenge returns the BIP340 challenge for the passed serialized
+// nonce point x coordinate, x-only public key, and message reduced modulo the
+// group order.
+func schnorrChallenge(rx, pubKey, msg []byte) *big.Int {
+	e := new(big.Int).SetBytes(TaggedHash(tagBIP340Challenge, rx, pubKey,
+		msg))
+	return e.Mod(e, S256().N)
+}
+
+// SignSchnorr produces a BIP340 Schnorr signature of the 32-byte msg with the
+// given private key.  Fresh auxiliary randomness is mixed into the nonce as
+// recommended by BIP340 to protect against side-channel attacks.  The
+// signature is verified before it is returned.
+func SignSchnorr(privKey *PrivateKey, msg []byte) ([]byte, error) {
+	var auxRand [32]byte
+	if _, err := rand.Read(auxRand[:]); err != nil {
+		return nil, err
+	}
+	return signSchnorr(privKey, msg, auxRand[:])
+}
+
+// signSchnorr produces a BIP340 Schnorr signature of the 32-byte msg with the
+// given private key and auxiliary random data.  It is separated from
+// SignSchnorr so the deterministic BIP340 test vectors can be reproduced.
+func signSchnorr(privKey *PrivateKey, msg, auxRand []byte) ([]byte, error) {
+	if len(msg) != schnorrMsgLen {
+		return nil, fmt.Errorf("schnorr message must be %d bytes, got %d",
+			schnorrMsgLen, len(msg))
+	}
+
+	curve := S256()
+	d := new(big.Int).Set(privKey.D)
+	if d.Sign() == 0 || d.Cmp(curve.N) >= 0 {
+		return nil, errors.New("private key is out of range")
+	}
+
+	// Negate the private key when needed so that it corresponds to the
+	// public key with an even y coordinate which the x-only serialization
+	// implies.
+	px, py := curve.ScalarBaseMult(d.Bytes())
+	if isOdd(py) {
+		d.Sub(curve.N, d)
+	}
+	dBytes := paddedAppend(32, nil, d.Bytes())
+	pBytes := paddedAppend(SchnorrPubKeyLen, nil, px.Bytes())
+
+	// Derive the nonce from the masked private key, the public key, and
+	// the message.
+	t := TaggedHash(tagBIP340Aux, auxRand)
+	for i := range t {
+		t[i] ^= dBytes[i]
+	}
+	k := new(big.Int).SetBytes(TaggedHash(tagBIP340Nonce, t, pBytes, msg))
+	k.Mod(k, curve.N)
+	if k.Sign() == 0 {
+		return nil, errors.New("calculated nonce is zero")
+	}
+
+	// Likewise negate the nonce when needed so the nonce point has an even
+	// y coordinate.
+	rx, ry := curve.ScalarBaseMult(k.Bytes())
+	if isOdd(ry) {
+		k.Sub(curve.N, k)
+	}
+	rBytes := paddedAppend(32, nil, rx.Bytes())
+
+	// s = k + e*d mod N
+	e := schnorrChallenge(rBytes, pBytes, msg)
+	s := e.Mul(e, d)
+	s.Add(s, k)
+	s.Mod(s, curve.N)
+
+	sig := make([]byte, 0, SchnorrSigLen)
+	sig = append(sig, rBytes...)
+	sig = paddedAppend(32, sig, s.Bytes())
+
+	var xOnly XOnlyPubKey
+	copy(xOnly[:], pBytes)
+	if !VerifySchnorr(xOnly, msg, sig) {
+		return nil, errors.New("produced schnorr signature is invalid")
+	}
+	return sig, nil
+}
+
+// VerifySchnorr returns whether or not sig is a valid BIP340 Schnorr signature
+// of the 32-byte msg for the given x-only public key.
+func VerifySchnorr(pubKey XOnlyPubKey, msg, sig []byte) bool {
+	if len(msg) != schnorrMsgLen || len(sig) != SchnorrSigLen {
+		return false
+	}
+
+	curve := S256()
+	pk, err := pubKey.PubKey()
+	if err != nil {
+		return false
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	if r.Cmp(curve.P) >= 0 {
+		return false
+	}
+	s := new(big.Int).SetBytes(sig[32:])
+	if s.Cmp(curve.N) >= 0 {
+		return false
+	}
+
+	// R = s*G - e*P
+	e := schnorrChallenge(sig[:32], pubKey[:], msg)
+	e.Sub(curve.N, e)
+	sgx, sgy := curve.ScalarBaseMult(sig[32:])
+	epx, epy := curve.ScalarMult(pk.X, pk.Y, e.Bytes())
+	rx, ry := curve.Add(sgx, sgy, epx, epy)
+
+	// The signature is only valid when R is not the point at infinity, has
+	// an even y coordinate, and has the x coordinate committed to by the
+	// signature.
+	if rx.Sign() == 0 && ry.Sign() == 0 {
+		return false
+	}
+	return !isOdd(ry) && rx.Cmp(r) == 0
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package btcec
+
+import (
+	"bytes"
+	"testing"
+)
+
+// bip340Test describes a BIP340 test vector.  Vectors with an empty secKey
+// only exercise verification.
+type bip340Test struct {
+	name    string
+	secKey  string
+	pubKey  string
+	auxRand string
+	msg     string
+	sig     string
+	valid   bool
+}
+
+// bip340Tests houses the test vectors from BIP340.
+var bip340Tests = []bip340Test{
+	{
+		name:    "vector 0",
+		secKey:  "0000000000000000000000000000000000000000000000000000000000000003",
+		pubKey:  "f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+		auxRand: "0000000000000000000000000000000000000000000000000000000000000000",
+		msg:     "0000000000000000000000000000000000000000000000000000000000000000",
+		sig:     "e907831f80848d1069a5371b402410364bdf1c5f8307b0084c55f1ce2dca821525f66a4a85ea8b71e482a74f382d2ce5ebeee8fdb2172f477df4900d310536c0",
+		valid:   true,
+	},
+	{
+		name:    "vector 1",
+		secKey:  "b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef",
+		pubKey:  "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659",
+		auxRand: "0000000000000000000000000000000000000000000000000000000000000001",
+		msg:     "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:     "6896bd60eeae296db48a229ff71dfe071bde413e6d43f917dc8dcf8c78de33418906d11ac976abccb20b091292bff4ea897efcb639ea871cfa95f6de339e4b0a",
+		valid:   true,
+	},
+	{
+		name:    "vector 2",
+		secKey:  "c90fdaa22168c234c4c6628b80dc1cd129024e088a67cc74020bbea63b14e5c9",
+		pubKey:  "dd308afec5777e13121fa72b9cc1b7cc0139715309b086c960e18fd969774eb8",
+		auxRand: "c87aa53824b4d7ae2eb035a2b5bbbccc080e76cdc6d1692c4b0b62d798e6d906",
+		msg:     "7e2d58d8b3bcdf1abadec7829054f90dda9805aab56c77333024b9d0a508b75c",
+		sig:     "5831aaeed7b44bb74e5eab94ba9d4294c49bcf2a60728d8b4c200f50dd313c1bab745879a5ad954a72c45a91c3a51d3c7adea98d82f8481e0e1e03674a6f3fb7",
+		valid:   true,
+	},
+	{
+		name:    "vector 3 (test fails if msg is reduced modulo p or n)",
+		secKey:  "0b432b2677937381aef05bb02a66ecd012773062cf3fa2549e44f58ed2401710",
+		pubKey:  "25d1dff95105f5253c4022f628a996ad3a0d95fbf21d468a1b33f8c160d8f517",
+		auxRand: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		msg:     "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		sig:     "7eb0509757e246f19449885651611cb965ecc1a187dd51b64fda1edc9637d5ec97582b9cb13db3933705b32ba982af5af25fd78881ebb32771fc5922efc66ea3",
+		valid:   true,
+	},
+	{
+		name:   "vector 4",
+		pubKey: "d69c3509bb99e412e68b0fe8544e72837dfa30746d8be2aa65975f29d22dc7b9",
+		msg:    "4df3c3f68fcc83b27e9d42c90431a72499f17875c81a599b566c9889b9696703",
+		sig:    "00000000000000000000003b78ce563f89a0ed9414f5aa28ad0d96d6795f9c6376afb1548af603b3eb45c9f8207dee1060cb71c04e80f593060b07d28308d7f4",
+		valid:  true,
+	},
+	{
+		name:   "vector 5 (public key not on the curve)",
+		pubKey: "eefdea4cdb677750a420fee807eacf21eb9898ae79b9768766e4faa04a2d4a34",
+		msg:    "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:    "6cff5c3ba86c69ea4b7376f31a9bcb4f74c1976089b2d9963da2e5543e17776969e89b4c5564d00349106b8497785dd7d1d713a8ae82b32fa79d5f7fc407d39b",
+	},
+	{
+		name:   "vector 6 (has_even_y(R) is false)",
+		pubKey: "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659",
+		msg:    "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:    "fff97bd5755eeea420453a14355235d382f6472f8568a18b2f057a14602975563cc27944640ac607cd107ae10923d9ef7a73c643e166be5ebeafa34b1ac553e2",
+	},
+	{
+		name:   "vector 7 (negated message)",
+		pubKey: "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659",
+		msg:    "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:    "1fa62e331edbc21c394792d2ab1100a7b432b013df3f6ff4f99fcb33e0e1515f28890b3edb6e7189b630448b515ce4f8622a954cfe545735aaea5134fccdb2bd",
+	},
+	{
+		name:   "vector 8 (negated s value)",
+		pubKey: "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659",
+		msg:    "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:    "6cff5c3ba86c69ea4b7376f31a9bcb4f74c1976089b2d9963da2e5543e177769961764b3aa9b2ffcb6ef947b6887a226e8d7c93e00c5ed0c1834ff0d0c2e6da6",
+	},
+	{
+		name:   "vector 9 (sG - eP is infinite, x(inf) defined as 0)",
+		pubKey: "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659",
+		msg:    "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:    "0000000000000000000000000000000000000000000000000000000000000000123dda8328af9c23a94c1feecfd123ba4fb73476f0d594dcb65c6425bd186051",
+	},
+	{
+		name:   "vector 10 (sG - eP is infinite, x(inf) defined as 1)",
+		pubKey: "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659",
+		msg:    "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:    "00000000000000000000000000000000000000000000000000000000000000017615fbaf5ae28864013c099742deadb4dba87f11ac6754f93780d5a1837cf197",
+	},
+	{
+		name:   "vector 11 (sig[0:32] is not an X coordinate on the curve)",
+		pubKey: "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659",
+		msg:    "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:    "4a298dacae57395a15d0795ddbfd1dcb564da82b0f269bc70a74f8220429ba1d69e89b4c5564d00349106b8497785dd7d1d713a8ae82b32fa79d5f7fc407d39b",
+	},
+	{
+		name:   "vector 12 (sig[0:32] is equal to field size)",
+		pubKey: "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659",
+		msg:    "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:    "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f69e89b4c5564d00349106b8497785dd7d1d713a8ae82b32fa79d5f7fc407d39b",
+	},
+	{
+		name:   "vector 13 (sig[32:64] is equal to curve order)",
+		pubKey: "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659",
+		msg:    "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:    "6cff5c3ba86c69ea4b7376f31a9bcb4f74c1976089b2d9963da2e5543e177769fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+	},
+	{
+		name:   "vector 14 (public key is not a valid X coordinate because it exceeds the field size)",
+		pubKey: "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc30",
+		msg:    "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+		sig:    "6cff5c3ba86c69ea4b7376f31a9bcb4f74c1976089b2d9963da2e5543e17776969e89b4c5564d00349106b8497785dd7d1d713a8ae82b32fa79d5f7fc407d39b",
+	},
+}
+
+// TestSchnorrVectors ensures signing and verification reproduce the BIP340
+// test vectors.
+func TestSchnorrVectors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range bip340Tests {
+		var pubKey XOnlyPubKey
+		copy(pubKey[:], decodeHex(test.pubKey))
+		msg := decodeHex(test.msg)
+		sig := decodeHex(test.sig)
+
+		if test.secKey != "" {
+			privKey, pub := PrivKeyFromBytes(S256(),
+				decodeHex(test.secKey))
+			if pub.XOnly() != pubKey {
+				t.Errorf("%s: mismatched x-only pubkey - got %x, "+
+					"want %x", test.name, pub.XOnly(), pubKey)
+				continue
+			}
+
+			gotSig, err := signSchnorr(privKey, msg,
+				decodeHex(test.auxRand))
+			if err != nil {
+				t.Errorf("%s: unexpected sign error: %v",
+					test.name, err)
+				continue
+			}
+			if !bytes.Equal(gotSig, sig) {
+				t.Errorf("%s: mismatched signature - got %x, "+
+					"want %x", test.name, gotSig, sig)
+				continue
+			}
+		}
+
+		if got := VerifySchnorr(pubKey, msg, sig); got != test.valid {
+			t.Errorf("%s: mismatched verify result - got %v, want %v",
+				test.name, got, test.valid)
+		}
+	}
+}
+
+// TestSignSchnorr ensures signatures produced with random auxiliary data
+// verify, including for keys with an odd y coordinate which must be negated
+// during signing, and that malformed inputs are rejected.
+func TestSignSchnorr(t *testing.T) {
+	t.Parallel()
+
+	msg := decodeHex("243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89")
+	for i := 0; i < 10; i++ {
+		privKey, err := NewPrivateKey(S256())
+		if err != nil {
+			t.Fatalf("NewPrivateKey: unexpected error: %v", err)
+		}
+
+		sig, err := SignSchnorr(privKey, msg)
+		if err != nil {
+			t.Fatalf("SignSchnorr: unexpected error: %v", err)
+		}
+		if len(sig) != SchnorrSigLen {
+			t.Fatalf("SignSchnorr: unexpected signature length %d",
+				len(sig))
+		}
+
+		// The x-only key must round trip to the key with an even y
+		// coordinate.
+		xOnly := privKey.PubKey().XOnly()
+		pubKey, err := xOnly.PubKey()
+		if err != nil {
+			t.Fatalf("PubKey: unexpected error: %v", err)
+		}
+		if isOdd(pubKey.Y) || pubKey.X.Cmp(privKey.PubKey().X) != 0 {
+			t.Fatalf("PubKey: did not lift to the even y key")
+		}
+
+		if !VerifySchnorr(xOnly, msg, sig) {
+			t.Fatalf("VerifySchnorr: valid signature rejected")
+		}
+
+		// Tampering with the message or signature must invalidate it.
+		badMsg := append([]byte{}, msg...)
+		badMsg[0] ^= 0x01
+		if VerifySchnorr(xOnly, badMsg, sig) {
+			t.Fatalf("VerifySchnorr: signature accepted for wrong " +
+				"message")
+		}
+		badSig := append([]byte{}, sig...)
+		badSig[SchnorrSigLen-1] ^= 0x01
+		if VerifySchnorr(xOnly, msg, badSig) {
+			t.Fatalf("VerifySchnorr: tampered signature accepted")
+		}
+		if VerifySchnorr(xOnly, msg, sig[:SchnorrSigLen-1]) {
+			t.Fatalf("VerifySchnorr: short signature accepted")
+		}
+	}
+
+	privKey, _ := PrivKeyFromBytes(S256(), decodeHex(bip340Tests[1].secKey))
+	if _, err := SignSchnorr(privKey, msg[:31]); err == nil {
+		t.Fatalf("SignSchnorr: short message accepted")
+	}
+}
+
+// TestLiftX ensures x-only public keys lift to the point with an even y
+// coordinate, round trip through their serialization, and that x coordinates
+// without a point on the curve are rejected.
+func TestLiftX(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		x     string
+		y     string // expected y coordinate, empty when invalid
+		valid bool
+	}{
+		{
+			name:  "generator",
+			x:     "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			y:     "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+			valid: true,
+		},
+		{
+			name:  "twice the generator",
+			x:     "c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5",
+			y:     "1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a",
+			valid: true,
+		},
+		{
+			name:  "no point with x coordinate",
+			x:     "0000000000000000000000000000000000000000000000000000000000000005",
+			valid: false,
+		},
+		{
+			name:  "x coordinate equal to field prime",
+			x:     "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f",
+			valid: false,
+		},
+		{
+			name:  "short",
+			x:     "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f817",
+			valid: false,
+		},
+	}
+
+	for _, test := range tests {
+		x := decodeHex(test.x)
+		pubKey, err := LiftX(x)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: unexpected LiftX error %v", test.name, err)
+			continue
+		}
+		xOnly, err := ParseXOnlyPubKey(x)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: unexpected ParseXOnlyPubKey error %v",
+				test.name, err)
+			continue
+		}
+		if !test.valid {
+			continue
+		}
+
+		if !bytes.Equal(pubKey.Y.Bytes(), decodeHex(test.y)) {
+			t.Errorf("%s: mismatched y - got %x, want %s", test.name,
+				pubKey.Y.Bytes(), test.y)
+			continue
+		}
+		if !bytes.Equal(xOnly[:], x) || pubKey.XOnly() != xOnly {
+			t.Errorf("%s: x-only serialization does not round trip",
+				test.name)
+			continue
+		}
+
+		// The negated key shares the x-only serialization and lifts
+		// back to the original key.
+		negated := pubKey.Negate()
+		if !isOdd(negated.Y) || negated.XOnly() != xOnly {
+			t.Errorf("%s: unexpected negated key", test.name)
+			continue
+		}
+		lifted, err := negated.XOnly().PubKey()
+		if err != nil || !lifted.IsEqual(pubKey) {
+			t.Errorf("%s: negated key did not lift to the original",
+				test.name)
+			continue
+		}
+		if !negated.Negate().IsEqual(pubKey) {
+			t.Errorf("%s: double negation mismatch", test.name)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"crypto/elliptic"
 	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"hash"
@@ -412,13 +413,60 @@ func RecoverCompact(curve *KoblitzCurve, signature,
 	return key, ((signature[0] - 27) & 4) == 4, nil
 }
 
+// maxLowRIterations is the maximum number of nonces SignLowR tries before it
+// falls back to the signature produced by the standard nonce.  Each nonce has
+// about a 50% chance of producing a low R value, so the limit is only reached
+// with negligible probability.
+const maxLowRIterations = 256
+
+// SignLowR generates a deterministic ECDSA signature of the passed hash with the
+// passed private key whose R value has its high bit clear, so R is encoded in
+// at most 32 bytes and the DER encoding of the signature is at most 70 bytes.
+// This saves a byte for about half of all signatures and makes the size of
+// signatures predictable when estimating fees.
+//
+// The nonce is generated according to RFC 6979 and is first tried without
+// extra entropy, so it produces the same signature as PrivateKey.Sign whenever
+// that signature already has a low R value.  Otherwise, an incrementing
+// counter is used as additional data for the nonce generation until a low R
+// value is found.  The signature without extra entropy is returned if no low R
+// value is found within a bounded number of attempts.
+func SignLowR(key *PrivateKey, hash []byte) (*Signature, error) {
+	var fallback *Signature
+	var extra [32]byte
+	for counter := uint32(0); counter < maxLowRIterations; counter++ {
+		var extraData []byte
+		if counter > 0 {
+			binary.LittleEndian.PutUint32(extra[:4], counter)
+			extraData = extra[:]
+		}
+		k := nonceRFC6979WithExtra(key.D, hash, extraData)
+		sig, err := signWithNonce(key, hash, k)
+		if err != nil {
+			return nil, err
+		}
+		if sig.R.BitLen() < 256 {
+			return sig, nil
+		}
+		if fallback == nil {
+			fallback = sig
+		}
+	}
+	return fallback, nil
+}
+
 // signRFC6979 generates a deterministic ECDSA signature according to RFC 6979 and BIP 62.
 func signRFC6979(privateKey *PrivateKey, hash []byte) (*Signature, error) {
+	k := nonceRFC6979(privateKey.D, hash)
+	return signWithNonce(privateKey, hash, k)
+}
 
+// signWithNonce generates an ECDSA signature of the passed hash using the
+// passed nonce and normalizes it to a low S value as required by BIP 62.
+func signWithNonce(privateKey *PrivateKey, hash []byte, k *big.Int) (*Signature, error) {
 	privkey := privateKey.ToECDSA()
 	N := S256().N
 	halfOrder := S256().halfOrder
-	k := nonceRFC6979(privkey.D, hash)
 	inv := new(big.Int).ModInverse(k, N)
 	r, _ := privkey.Curve.ScalarBaseMult(k.Bytes())
 	if r.Cmp(N) == 1 {
@@ -447,7 +495,14 @@ func signRFC6979(privateKey *PrivateKey, hash []byte) (*Signature, error) {
 // nonceRFC6979 generates an ECDSA nonce (`k`) deterministically according to RFC 6979.
 // It takes a 32-byte hash as an input and returns 32-byte nonce to be used in ECDSA algorithm.
 func nonceRFC6979(privkey *big.Int, hash []byte) *big.Int {
+	return nonceRFC6979WithExtra(privkey, hash, nil)
+}
 
+// nonceRFC6979WithExtra is the same as nonceRFC6979 except the passed extra
+// data, if any, is appended to the private key and hash as the additional data
+// described in section 3.6 of RFC 6979.  This is the same construction used by
+// libsecp256k1, so it produces the same nonces for the same extra data.
+func nonceRFC6979WithExtra(privkey *big.Int, hash []byte, extra []byte) *big.Int {
 	curve := S256()
 	q := curve.Params().N
 	x := privkey
@@ -457,6 +512,7 @@ func nonceRFC6979(privkey *big.Int, hash []byte) *big.Int {
 	holen := alg().Size()
 	rolen := (qlen + 7) >> 3
 	bx := append(int2octets(x, rolen), bits2octets(hash, curve, rolen)...)
+	bx = append(bx, extra...)
 
 	// Step B
 	v := bytes.Repeat(oneInitializer, holen)
@@ -605,6 +605,53 @@ func TestRFC6979(t *testing.T) {
 	}
 }
 
+// TestSignLowR ensures SignLowR produces valid signatures with a low R value,
+// which are at most 70 bytes when DER encoded, and that it produces the same
+// signature as Sign when that signature already has a low R value.
+func TestSignLowR(t *testing.T) {
+	privKey, _ := PrivKeyFromBytes(S256(), decodeHex("fad12b5c14a2b4b44f"+
+		"2e1d9e3f3b7a0c1e5e1c8c8b2cb0c3e3c1f2b8b6c6d5e4"))
+
+	var ground int
+	for i := 0; i < 32; i++ {
+		hash := sha256.Sum256([]byte(fmt.Sprintf("low r test %d", i)))
+		sig, err := SignLowR(privKey, hash[:])
+		if err != nil {
+			t.Fatalf("SignLowR #%d: unexpected error: %v", i, err)
+		}
+		if sig.R.BitLen() > 255 {
+			t.Errorf("SignLowR #%d: R value %x has its high bit set",
+				i, sig.R)
+		}
+		if serialized := sig.Serialize(); len(serialized) > 70 {
+			t.Errorf("SignLowR #%d: serialized signature is %d "+
+				"bytes", i, len(serialized))
+		}
+		if !sig.Verify(hash[:], privKey.PubKey()) {
+			t.Errorf("SignLowR #%d: signature does not verify", i)
+		}
+
+		stdSig, err := privKey.Sign(hash[:])
+		if err != nil {
+			t.Fatalf("Sign #%d: unexpected error: %v", i, err)
+		}
+		if stdSig.R.BitLen() > 255 {
+			ground++
+			continue
+		}
+		if !sig.IsEqual(stdSig) {
+			t.Errorf("SignLowR #%d: signature differs from Sign "+
+				"although it has a low R value", i)
+		}
+	}
+
+	// About half of the standard signatures have a high R value, so ensure
+	// some of them needed grinding.
+	if ground == 0 {
+		t.Errorf("SignLowR: no signature needed grinding")
+	}
+}
+
 func TestSignatureIsEqual(t *testing.T) {
 	sig1 := &Signature{
 		R: fromHex("0082235e21a2300022738dabb8e1bbd9d19cfb1e7ab8c30a23b0afbb8d178abcf3"),
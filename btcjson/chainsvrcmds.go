@@ -127,11 +127,55 @@ func NewGetBestBlockHashCmd() *GetBestBlockHashCmd {
 	return &GetBestBlockHashCmd{}
 }
 
+// BlockVerbosity defines the level of detail of a block returned by the
+// getblock JSON-RPC command.
+type BlockVerbosity int
+
+const (
+	// BlockVerbosityHex returns the block as a hex-encoded string.
+	BlockVerbosityHex BlockVerbosity = 0
+
+	// BlockVerbosityTxIDs returns the block as a JSON object along with the
+	// ids of its transactions.
+	BlockVerbosityTxIDs BlockVerbosity = 1
+
+	// BlockVerbosityTxs returns the block as a JSON object along with its
+	// transactions as JSON objects.
+	BlockVerbosityTxs BlockVerbosity = 2
+)
+
+// UnmarshalJSON provides a custom Unmarshal method for BlockVerbosity.  In
+// addition to an integer verbosity level, it accepts the legacy boolean verbose
+// flag for backwards compatibility, where true and false are equivalent to
+// BlockVerbosityTxIDs and BlockVerbosityHex, respectively.
+func (v *BlockVerbosity) UnmarshalJSON(data []byte) error {
+	var verbose bool
+	if err := json.Unmarshal(data, &verbose); err == nil {
+		*v = BlockVerbosityHex
+		if verbose {
+			*v = BlockVerbosityTxIDs
+		}
+		return nil
+	}
+
+	var level int
+	if err := json.Unmarshal(data, &level); err != nil {
+		return err
+	}
+	*v = BlockVerbosity(level)
+	return nil
+}
+
 // GetBlockCmd defines the getblock JSON-RPC command.
+//
+// NOTE: VerboseTx is only provided for backwards compatibility with the legacy
+// form of the command which used boolean verbose and verbosetx flags.  When
+// set along with a verbosity of BlockVerbosityTxIDs, it is equivalent to a
+// verbosity of BlockVerbosityTxs.
 type GetBlockCmd struct {
 	Hash      string
-	Verbose   *bool `jsonrpcdefault:"true"`
-	VerboseTx *bool `jsonrpcdefault:"false"`
+	Verbosity *BlockVerbosity `jsonrpcdefault:"1"`
+	VerboseTx *bool           `jsonrpcdefault:"false"`
 }
 
 // NewGetBlockCmd returns a new instance which can be used to issue a getblock
@@ -139,12 +183,13 @@ type GetBlockCmd struct {
 //
 // The parameters which are pointers indicate they are optional.  Passing nil
 // for optional parameters will use the default value.
-func NewGetBlockCmd(hash string, verbose, verboseTx *bool) *GetBlockCmd {
-	return &GetBlockCmd{
-		Hash:      hash,
-		Verbose:   verbose,
-		VerboseTx: verboseTx,
+func NewGetBlockCmd(hash string, verbosity *int) *GetBlockCmd {
+	cmd := &GetBlockCmd{Hash: hash}
+	if verbosity != nil {
+		level := BlockVerbosity(*verbosity)
+		cmd.Verbosity = &level
 	}
+	return cmd
 }
 
 // GetBlockChainInfoCmd defines the getblockchaininfo JSON-RPC command.
@@ -200,6 +245,9 @@ type TemplateRequest struct {
 	Mode         string   `json:"mode,omitempty"`
 	Capabilities []string `json:"capabilities,omitempty"`
 
+	// Rules the client supports, such as "segwit", as defined in BIP 0009.
+	Rules []string `json:"rules,omitempty"`
+
 	// Optional long polling.
 	LongPollID string `json:"longpollid,omitempty"`
 
@@ -330,6 +378,22 @@ func NewGetConnectionCountCmd() *GetConnectionCountCmd {
 	return &GetConnectionCountCmd{}
 }
 
+// GetDeploymentInfoCmd defines the getdeploymentinfo JSON-RPC command.
+type GetDeploymentInfoCmd struct {
+	BlockHash *string
+}
+
+// NewGetDeploymentInfoCmd returns a new instance which can be used to issue a
+// getdeploymentinfo JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetDeploymentInfoCmd(blockHash *string) *GetDeploymentInfoCmd {
+	return &GetDeploymentInfoCmd{
+		BlockHash: blockHash,
+	}
+}
+
 // GetDifficultyCmd defines the getdifficulty JSON-RPC command.
 type GetDifficultyCmd struct{}
 
@@ -357,6 +421,22 @@ func NewGetHashesPerSecCmd() *GetHashesPerSecCmd {
 	return &GetHashesPerSecCmd{}
 }
 
+// GetIndexInfoCmd defines the getindexinfo JSON-RPC command.
+type GetIndexInfoCmd struct {
+	IndexName *string
+}
+
+// NewGetIndexInfoCmd returns a new instance which can be used to issue a
+// getindexinfo JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetIndexInfoCmd(indexName *string) *GetIndexInfoCmd {
+	return &GetIndexInfoCmd{
+		IndexName: indexName,
+	}
+}
+
 // GetInfoCmd defines the getinfo JSON-RPC command.
 type GetInfoCmd struct{}
 
@@ -366,6 +446,42 @@ func NewGetInfoCmd() *GetInfoCmd {
 	return &GetInfoCmd{}
 }
 
+// GetMempoolAncestorsCmd defines the getmempoolancestors JSON-RPC command.
+type GetMempoolAncestorsCmd struct {
+	TxID    string
+	Verbose *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetMempoolAncestorsCmd returns a new instance which can be used to issue
+// a getmempoolancestors JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMempoolAncestorsCmd(txHash string, verbose *bool) *GetMempoolAncestorsCmd {
+	return &GetMempoolAncestorsCmd{
+		TxID:    txHash,
+		Verbose: verbose,
+	}
+}
+
+// GetMempoolDescendantsCmd defines the getmempooldescendants JSON-RPC command.
+type GetMempoolDescendantsCmd struct {
+	TxID    string
+	Verbose *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetMempoolDescendantsCmd returns a new instance which can be used to
+// issue a getmempooldescendants JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMempoolDescendantsCmd(txHash string, verbose *bool) *GetMempoolDescendantsCmd {
+	return &GetMempoolDescendantsCmd{
+		TxID:    txHash,
+		Verbose: verbose,
+	}
+}
+
 // GetMempoolEntryCmd defines the getmempoolentry JSON-RPC command.
 type GetMempoolEntryCmd struct {
 	TxID string
@@ -793,10 +909,14 @@ func init() {
 	MustRegisterCmd("getcfilterheader", (*GetCFilterHeaderCmd)(nil), flags)
 	MustRegisterCmd("getchaintips", (*GetChainTipsCmd)(nil), flags)
 	MustRegisterCmd("getconnectioncount", (*GetConnectionCountCmd)(nil), flags)
+	MustRegisterCmd("getdeploymentinfo", (*GetDeploymentInfoCmd)(nil), flags)
 	MustRegisterCmd("getdifficulty", (*GetDifficultyCmd)(nil), flags)
 	MustRegisterCmd("getgenerate", (*GetGenerateCmd)(nil), flags)
 	MustRegisterCmd("gethashespersec", (*GetHashesPerSecCmd)(nil), flags)
+	MustRegisterCmd("getindexinfo", (*GetIndexInfoCmd)(nil), flags)
 	MustRegisterCmd("getinfo", (*GetInfoCmd)(nil), flags)
+	MustRegisterCmd("getmempoolancestors", (*GetMempoolAncestorsCmd)(nil), flags)
+	MustRegisterCmd("getmempooldescendants", (*GetMempoolDescendantsCmd)(nil), flags)
 	MustRegisterCmd("getmempoolentry", (*GetMempoolEntryCmd)(nil), flags)
 	MustRegisterCmd("getmempoolinfo", (*GetMempoolInfoCmd)(nil), flags)
 	MustRegisterCmd("getmininginfo", (*GetMiningInfoCmd)(nil), flags)
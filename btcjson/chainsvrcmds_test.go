@@ -145,47 +145,92 @@ func TestChainSvrCmds(t *testing.T) {
 				return btcjson.NewCmd("getblock", "123")
 			},
 			staticCmd: func() interface{} {
-				return btcjson.NewGetBlockCmd("123", nil, nil)
+				return btcjson.NewGetBlockCmd("123", nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123"],"id":1}`,
 			unmarshalled: &btcjson.GetBlockCmd{
 				Hash:      "123",
-				Verbose:   btcjson.Bool(true),
+				Verbosity: blockVerbosity(btcjson.BlockVerbosityTxIDs),
 				VerboseTx: btcjson.Bool(false),
 			},
 		},
 		{
-			name: "getblock required optional1",
+			name: "getblock verbosity 0",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getblock", "123", 0)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetBlockCmd("123", btcjson.Int(0))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",0],"id":1}`,
+			unmarshalled: &btcjson.GetBlockCmd{
+				Hash:      "123",
+				Verbosity: blockVerbosity(btcjson.BlockVerbosityHex),
+				VerboseTx: btcjson.Bool(false),
+			},
+		},
+		{
+			name: "getblock verbosity 1",
 			newCmd: func() (interface{}, error) {
 				// Intentionally use a source param that is
 				// more pointers than the destination to
 				// exercise that path.
-				verbosePtr := btcjson.Bool(true)
-				return btcjson.NewCmd("getblock", "123", &verbosePtr)
+				verbosityPtr := btcjson.Int(1)
+				return btcjson.NewCmd("getblock", "123", &verbosityPtr)
 			},
 			staticCmd: func() interface{} {
-				return btcjson.NewGetBlockCmd("123", btcjson.Bool(true), nil)
+				return btcjson.NewGetBlockCmd("123", btcjson.Int(1))
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",1],"id":1}`,
 			unmarshalled: &btcjson.GetBlockCmd{
 				Hash:      "123",
-				Verbose:   btcjson.Bool(true),
+				Verbosity: blockVerbosity(btcjson.BlockVerbosityTxIDs),
 				VerboseTx: btcjson.Bool(false),
 			},
 		},
 		{
-			name: "getblock required optional2",
+			name: "getblock verbosity 2",
 			newCmd: func() (interface{}, error) {
-				return btcjson.NewCmd("getblock", "123", true, true)
+				return btcjson.NewCmd("getblock", "123", 2)
 			},
 			staticCmd: func() interface{} {
-				return btcjson.NewGetBlockCmd("123", btcjson.Bool(true), btcjson.Bool(true))
+				return btcjson.NewGetBlockCmd("123", btcjson.Int(2))
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true,true],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",2],"id":1}`,
 			unmarshalled: &btcjson.GetBlockCmd{
 				Hash:      "123",
-				Verbose:   btcjson.Bool(true),
-				VerboseTx: btcjson.Bool(true),
+				Verbosity: blockVerbosity(btcjson.BlockVerbosityTxs),
+				VerboseTx: btcjson.Bool(false),
+			},
+		},
+		{
+			name: "getblock legacy verbose false",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getblock", "123", "false")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetBlockCmd("123", btcjson.Int(0))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",0],"id":1}`,
+			unmarshalled: &btcjson.GetBlockCmd{
+				Hash:      "123",
+				Verbosity: blockVerbosity(btcjson.BlockVerbosityHex),
+				VerboseTx: btcjson.Bool(false),
+			},
+		},
+		{
+			name: "getblock legacy verbose true",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getblock", "123", "true")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetBlockCmd("123", btcjson.Int(1))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",1],"id":1}`,
+			unmarshalled: &btcjson.GetBlockCmd{
+				Hash:      "123",
+				Verbosity: blockVerbosity(btcjson.BlockVerbosityTxIDs),
+				VerboseTx: btcjson.Bool(false),
 			},
 		},
 		{
@@ -318,6 +363,50 @@ func TestChainSvrCmds(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "getblocktemplate optional - template request with segwit rules",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getblocktemplate", `{"mode":"template","capabilities":["longpoll","coinbasetxn"],"rules":["segwit"]}`)
+			},
+			staticCmd: func() interface{} {
+				template := btcjson.TemplateRequest{
+					Mode:         "template",
+					Capabilities: []string{"longpoll", "coinbasetxn"},
+					Rules:        []string{"segwit"},
+				}
+				return btcjson.NewGetBlockTemplateCmd(&template)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocktemplate","params":[{"mode":"template","capabilities":["longpoll","coinbasetxn"],"rules":["segwit"]}],"id":1}`,
+			unmarshalled: &btcjson.GetBlockTemplateCmd{
+				Request: &btcjson.TemplateRequest{
+					Mode:         "template",
+					Capabilities: []string{"longpoll", "coinbasetxn"},
+					Rules:        []string{"segwit"},
+				},
+			},
+		},
+		{
+			name: "getblocktemplate optional - long poll request",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getblocktemplate", `{"mode":"template","capabilities":["longpoll"],"longpollid":"0000000000000000000000000000000000000000000000000000000000000000123"}`)
+			},
+			staticCmd: func() interface{} {
+				template := btcjson.TemplateRequest{
+					Mode:         "template",
+					Capabilities: []string{"longpoll"},
+					LongPollID:   "0000000000000000000000000000000000000000000000000000000000000000123",
+				}
+				return btcjson.NewGetBlockTemplateCmd(&template)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocktemplate","params":[{"mode":"template","capabilities":["longpoll"],"longpollid":"0000000000000000000000000000000000000000000000000000000000000000123"}],"id":1}`,
+			unmarshalled: &btcjson.GetBlockTemplateCmd{
+				Request: &btcjson.TemplateRequest{
+					Mode:         "template",
+					Capabilities: []string{"longpoll"},
+					LongPollID:   "0000000000000000000000000000000000000000000000000000000000000000123",
+				},
+			},
+		},
 		{
 			name: "getcfilter",
 			newCmd: func() (interface{}, error) {
@@ -372,6 +461,32 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getconnectioncount","params":[],"id":1}`,
 			unmarshalled: &btcjson.GetConnectionCountCmd{},
 		},
+		{
+			name: "getdeploymentinfo",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getdeploymentinfo")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetDeploymentInfoCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getdeploymentinfo","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetDeploymentInfoCmd{
+				BlockHash: nil,
+			},
+		},
+		{
+			name: "getdeploymentinfo optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getdeploymentinfo", "123")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetDeploymentInfoCmd(btcjson.String("123"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getdeploymentinfo","params":["123"],"id":1}`,
+			unmarshalled: &btcjson.GetDeploymentInfoCmd{
+				BlockHash: btcjson.String("123"),
+			},
+		},
 		{
 			name: "getdifficulty",
 			newCmd: func() (interface{}, error) {
@@ -405,6 +520,32 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"gethashespersec","params":[],"id":1}`,
 			unmarshalled: &btcjson.GetHashesPerSecCmd{},
 		},
+		{
+			name: "getindexinfo",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getindexinfo")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetIndexInfoCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getindexinfo","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetIndexInfoCmd{
+				IndexName: nil,
+			},
+		},
+		{
+			name: "getindexinfo optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getindexinfo", "txindex")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetIndexInfoCmd(btcjson.String("txindex"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getindexinfo","params":["txindex"],"id":1}`,
+			unmarshalled: &btcjson.GetIndexInfoCmd{
+				IndexName: btcjson.String("txindex"),
+			},
+		},
 		{
 			name: "getinfo",
 			newCmd: func() (interface{}, error) {
@@ -416,6 +557,62 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getinfo","params":[],"id":1}`,
 			unmarshalled: &btcjson.GetInfoCmd{},
 		},
+		{
+			name: "getmempoolancestors",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getmempoolancestors", "txhash")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetMempoolAncestorsCmd("txhash", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempoolancestors","params":["txhash"],"id":1}`,
+			unmarshalled: &btcjson.GetMempoolAncestorsCmd{
+				TxID:    "txhash",
+				Verbose: btcjson.Bool(false),
+			},
+		},
+		{
+			name: "getmempoolancestors optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getmempoolancestors", "txhash", true)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetMempoolAncestorsCmd("txhash", btcjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempoolancestors","params":["txhash",true],"id":1}`,
+			unmarshalled: &btcjson.GetMempoolAncestorsCmd{
+				TxID:    "txhash",
+				Verbose: btcjson.Bool(true),
+			},
+		},
+		{
+			name: "getmempooldescendants",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getmempooldescendants", "txhash")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetMempoolDescendantsCmd("txhash", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempooldescendants","params":["txhash"],"id":1}`,
+			unmarshalled: &btcjson.GetMempoolDescendantsCmd{
+				TxID:    "txhash",
+				Verbose: btcjson.Bool(false),
+			},
+		},
+		{
+			name: "getmempooldescendants optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getmempooldescendants", "txhash", true)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetMempoolDescendantsCmd("txhash", btcjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempooldescendants","params":["txhash",true],"id":1}`,
+			unmarshalled: &btcjson.GetMempoolDescendantsCmd{
+				TxID:    "txhash",
+				Verbose: btcjson.Bool(true),
+			},
+		},
 		{
 			name: "getmempoolentry",
 			newCmd: func() (interface{}, error) {
@@ -1156,6 +1353,79 @@ func TestChainSvrCmds(t *testing.T) {
 	}
 }
 
+// blockVerbosity is a helper routine that allocates a new BlockVerbosity value
+// to store v and returns a pointer to it.
+func blockVerbosity(v btcjson.BlockVerbosity) *btcjson.BlockVerbosity {
+	return &v
+}
+
+// TestGetBlockCmdLegacyVerbose ensures the legacy boolean verbose flags of the
+// getblock command still unmarshal into the expected verbosity.
+func TestGetBlockCmdLegacyVerbose(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		marshalled   string
+		unmarshalled *btcjson.GetBlockCmd
+	}{
+		{
+			name:       "verbose false",
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",false],"id":1}`,
+			unmarshalled: &btcjson.GetBlockCmd{
+				Hash:      "123",
+				Verbosity: blockVerbosity(btcjson.BlockVerbosityHex),
+				VerboseTx: btcjson.Bool(false),
+			},
+		},
+		{
+			name:       "verbose true",
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true],"id":1}`,
+			unmarshalled: &btcjson.GetBlockCmd{
+				Hash:      "123",
+				Verbosity: blockVerbosity(btcjson.BlockVerbosityTxIDs),
+				VerboseTx: btcjson.Bool(false),
+			},
+		},
+		{
+			name:       "verbose true with verbosetx",
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true,true],"id":1}`,
+			unmarshalled: &btcjson.GetBlockCmd{
+				Hash:      "123",
+				Verbosity: blockVerbosity(btcjson.BlockVerbosityTxIDs),
+				VerboseTx: btcjson.Bool(true),
+			},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		var request btcjson.Request
+		err := json.Unmarshal([]byte(test.marshalled), &request)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error while "+
+				"unmarshalling JSON-RPC request: %v", i,
+				test.name, err)
+			continue
+		}
+
+		cmd, err := btcjson.UnmarshalCmd(&request)
+		if err != nil {
+			t.Errorf("UnmarshalCmd #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(cmd, test.unmarshalled) {
+			t.Errorf("Test #%d (%s) unexpected unmarshalled command "+
+				"- got %s, want %s", i, test.name,
+				fmt.Sprintf("(%T) %+[1]v", cmd),
+				fmt.Sprintf("(%T) %+[1]v\n", test.unmarshalled))
+			continue
+		}
+	}
+}
+
 // TestChainSvrCmdErrors ensures any errors that occur in the command during
 // custom mashal and unmarshal are as expected.
 func TestChainSvrCmdErrors(t *testing.T) {
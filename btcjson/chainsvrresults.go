@@ -4,7 +4,10 @@
 
 package btcjson
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sort"
+)
 
 // GetBlockHeaderVerboseResult models the data from the getblockheader command when
 // the verbose flag is set.  When the verbose flag is not set, getblockheader
@@ -25,9 +28,31 @@ type GetBlockHeaderVerboseResult struct {
 }
 
 // GetBlockVerboseResult models the data from the getblock command when the
-// verbose flag is set.  When the verbose flag is not set, getblock returns a
-// hex-encoded string.
+// verbosity level is BlockVerbosityTxIDs.  When the verbosity level is
+// BlockVerbosityHex, getblock returns a hex-encoded string.
 type GetBlockVerboseResult struct {
+	Hash          string   `json:"hash"`
+	Confirmations uint64   `json:"confirmations"`
+	StrippedSize  int32    `json:"strippedsize"`
+	Size          int32    `json:"size"`
+	Weight        int32    `json:"weight"`
+	Height        int64    `json:"height"`
+	Version       int32    `json:"version"`
+	VersionHex    string   `json:"versionHex"`
+	MerkleRoot    string   `json:"merkleroot"`
+	Tx            []string `json:"tx,omitempty"`
+	Time          int64    `json:"time"`
+	Nonce         uint32   `json:"nonce"`
+	Bits          string   `json:"bits"`
+	Difficulty    float64  `json:"difficulty"`
+	PreviousHash  string   `json:"previousblockhash"`
+	NextHash      string   `json:"nextblockhash,omitempty"`
+}
+
+// GetBlockVerboseTxResult models the data from the getblock command when the
+// verbosity level is BlockVerbosityTxs, in which case the transactions are
+// returned as JSON objects rather than their ids.
+type GetBlockVerboseTxResult struct {
 	Hash          string        `json:"hash"`
 	Confirmations uint64        `json:"confirmations"`
 	StrippedSize  int32         `json:"strippedsize"`
@@ -37,8 +62,7 @@ type GetBlockVerboseResult struct {
 	Version       int32         `json:"version"`
 	VersionHex    string        `json:"versionHex"`
 	MerkleRoot    string        `json:"merkleroot"`
-	Tx            []string      `json:"tx,omitempty"`
-	RawTx         []TxRawResult `json:"rawtx,omitempty"`
+	Tx            []TxRawResult `json:"tx,omitempty"`
 	Time          int64         `json:"time"`
 	Nonce         uint32        `json:"nonce"`
 	Bits          string        `json:"bits"`
@@ -52,6 +76,7 @@ type GetBlockVerboseResult struct {
 type CreateMultiSigResult struct {
 	Address      string `json:"address"`
 	RedeemScript string `json:"redeemScript"`
+	Descriptor   string `json:"descriptor,omitempty"`
 }
 
 // DecodeScriptResult models the data returned from the decodescript command.
@@ -77,28 +102,51 @@ type GetAddedNodeInfoResult struct {
 	Addresses *[]GetAddedNodeInfoResultAddr `json:"addresses,omitempty"`
 }
 
-// SoftForkDescription describes the current state of a soft-fork which was
-// deployed using a super-majority block signalling.
+// SoftForkDescription describes the current state of a soft-fork as reported
+// within the softforks object of the getblockchaininfo command.  Buried
+// deployments are identified by a type of "buried" and only set the activation
+// height, while BIP0009 deployments are identified by a type of "bip9" and
+// describe their signalling progress via Bip9.  The height of a BIP0009
+// deployment is only set once it is active.
 type SoftForkDescription struct {
-	ID      string `json:"id"`
-	Version uint32 `json:"version"`
-	Reject  struct {
-		Status bool `json:"status"`
-	} `json:"reject"`
+	Type   string                   `json:"type"`
+	Bip9   *Bip9SoftForkDescription `json:"bip9,omitempty"`
+	Height *int32                   `json:"height,omitempty"`
+	Active bool                     `json:"active"`
+}
+
+// Bip9Statistics describes the signalling progress of a BIP0009 deployment
+// within the current retarget period.
+type Bip9Statistics struct {
+	Period    int32 `json:"period"`
+	Threshold int32 `json:"threshold"`
+	Elapsed   int32 `json:"elapsed"`
+	Count     int32 `json:"count"`
+	Possible  bool  `json:"possible"`
 }
 
 // Bip9SoftForkDescription describes the current state of a defined BIP0009
 // version bits soft-fork.
+//
+// NOTE: The start time is named startTime within the legacy bip9_softforks
+// object and start_time within the softforks object, so both are provided.
+// The statistics are only set while the deployment is started.
 type Bip9SoftForkDescription struct {
-	Status    string `json:"status"`
-	Bit       uint8  `json:"bit"`
-	StartTime int64  `json:"startTime"`
-	Timeout   int64  `json:"timeout"`
-	Since     int32  `json:"since"`
+	Status     string          `json:"status"`
+	Bit        uint8           `json:"bit"`
+	StartTime1 int64           `json:"startTime"`
+	StartTime2 int64           `json:"start_time"`
+	Timeout    int64           `json:"timeout"`
+	Since      int32           `json:"since"`
+	Statistics *Bip9Statistics `json:"statistics,omitempty"`
 }
 
 // GetBlockChainInfoResult models the data returned from the getblockchaininfo
 // command.
+//
+// NOTE: The pruning fields other than Pruned are only set for pruned nodes.
+// AutomaticPruning is a pointer so a pruned node which only prunes manually can
+// be distinguished from a node which does not report it.
 type GetBlockChainInfoResult struct {
 	Chain                string                              `json:"chain"`
 	Blocks               int32                               `json:"blocks"`
@@ -107,17 +155,112 @@ type GetBlockChainInfoResult struct {
 	Difficulty           float64                             `json:"difficulty"`
 	MedianTime           int64                               `json:"mediantime"`
 	VerificationProgress float64                             `json:"verificationprogress,omitempty"`
+	InitialBlockDownload bool                                `json:"initialblockdownload"`
+	ChainWork            string                              `json:"chainwork,omitempty"`
+	SizeOnDisk           int64                               `json:"size_on_disk,omitempty"`
 	Pruned               bool                                `json:"pruned"`
 	PruneHeight          int32                               `json:"pruneheight,omitempty"`
-	ChainWork            string                              `json:"chainwork,omitempty"`
-	SoftForks            []*SoftForkDescription              `json:"softforks"`
-	Bip9SoftForks        map[string]*Bip9SoftForkDescription `json:"bip9_softforks"`
+	AutomaticPruning     *bool                               `json:"automatic_pruning,omitempty"`
+	PruneTargetSize      int64                               `json:"prune_target_size,omitempty"`
+	SoftForks            map[string]*SoftForkDescription     `json:"softforks"`
+	Bip9SoftForks        map[string]*Bip9SoftForkDescription `json:"bip9_softforks,omitempty"`
+}
+
+// Bip9DeploymentInfo describes the state of a BIP0009 version bits deployment
+// as reported within a deployment of the getdeploymentinfo command.
+//
+// NOTE: The bit is not set for deployments which are always active, so it is
+// a pointer to distinguish that case from bit 0.  The statistics and the
+// signalling string are only set while the deployment is started or locked in.
+type Bip9DeploymentInfo struct {
+	Bit                 *uint8          `json:"bit,omitempty"`
+	StartTime           int64           `json:"start_time"`
+	Timeout             int64           `json:"timeout"`
+	MinActivationHeight int32           `json:"min_activation_height"`
+	Status              string          `json:"status"`
+	Since               int32           `json:"since"`
+	StatusNext          string          `json:"status_next"`
+	Statistics          *Bip9Statistics `json:"statistics,omitempty"`
+	Signalling          string          `json:"signalling,omitempty"`
+}
+
+// DeploymentInfo describes the state of a soft-fork deployment as reported
+// within the deployments object of the getdeploymentinfo command.  Buried
+// deployments are identified by a type of "buried" and only set the activation
+// height, while BIP0009 deployments are identified by a type of "bip9" and
+// describe their state via Bip9.  The height of a BIP0009 deployment is only
+// set once it is active.
+type DeploymentInfo struct {
+	Type   string              `json:"type"`
+	Height *int32              `json:"height,omitempty"`
+	Active bool                `json:"active"`
+	Bip9   *Bip9DeploymentInfo `json:"bip9,omitempty"`
+}
+
+// GetDeploymentInfoResult models the data returned from the getdeploymentinfo
+// command.
+type GetDeploymentInfoResult struct {
+	Hash        string                     `json:"hash"`
+	Height      int32                      `json:"height"`
+	Deployments map[string]*DeploymentInfo `json:"deployments"`
+}
+
+// These constants define the possible status values of a chain tip as
+// returned by the getchaintips command.
+const (
+	// ChainTipStatusActive is the status of the tip of the main chain.
+	ChainTipStatusActive = "active"
+
+	// ChainTipStatusValidFork is the status of a fully validated tip which
+	// is not part of the main chain.
+	ChainTipStatusValidFork = "valid-fork"
+
+	// ChainTipStatusValidHeaders is the status of a tip whose blocks are
+	// all available but have not been fully validated.
+	ChainTipStatusValidHeaders = "valid-headers"
+
+	// ChainTipStatusHeadersOnly is the status of a tip for which not all
+	// blocks are available.
+	ChainTipStatusHeadersOnly = "headers-only"
+
+	// ChainTipStatusInvalid is the status of a tip on a branch containing
+	// at least one invalid block.
+	ChainTipStatusInvalid = "invalid"
+)
+
+// ChainTip models a single chain tip returned from the getchaintips command.
+// The branch length is the number of blocks between the tip and the main
+// chain and is zero for the active tip.
+type ChainTip struct {
+	Height    int32  `json:"height"`
+	Hash      string `json:"hash"`
+	BranchLen int32  `json:"branchlen"`
+	Status    string `json:"status"`
+}
+
+// GetChainTipsResult models the data returned from the getchaintips command.
+type GetChainTipsResult []ChainTip
+
+// GetChainTips returns the passed chain tips in the order they are returned
+// by the getchaintips command, which is by descending height with ties broken
+// by hash.  The passed slice is not modified.
+func GetChainTips(tips []ChainTip) []ChainTip {
+	sorted := make([]ChainTip, len(tips))
+	copy(sorted, tips)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Height != sorted[j].Height {
+			return sorted[i].Height > sorted[j].Height
+		}
+		return sorted[i].Hash < sorted[j].Hash
+	})
+	return sorted
 }
 
 // GetBlockTemplateResultTx models the transactions field of the
 // getblocktemplate command.
 type GetBlockTemplateResultTx struct {
 	Data    string  `json:"data"`
+	TxID    string  `json:"txid"`
 	Hash    string  `json:"hash"`
 	Depends []int64 `json:"depends"`
 	Fee     int64   `json:"fee"`
@@ -173,10 +316,22 @@ type GetBlockTemplateResult struct {
 	RejectReasion string   `json:"reject-reason,omitempty"`
 }
 
+// GetIndexInfoResult models the data returned for each index from the
+// getindexinfo command.  The command returns a map of these keyed by index
+// name.
+type GetIndexInfoResult struct {
+	Synced          bool  `json:"synced"`
+	BestBlockHeight int32 `json:"best_block_height"`
+}
+
 // GetMempoolEntryResult models the data returned from the getmempoolentry
-// command.
+// command.  It is also the type of the values of the map returned by the
+// getmempoolancestors and getmempooldescendants commands when the verbose flag
+// is set.  When the verbose flag is not set, those commands return an array of
+// transaction hashes instead.
 type GetMempoolEntryResult struct {
 	Size             int32    `json:"size"`
+	Vsize            int32    `json:"vsize"`
 	Fee              float64  `json:"fee"`
 	ModifiedFee      float64  `json:"modifiedfee"`
 	Time             int64    `json:"time"`
@@ -260,17 +415,27 @@ type GetPeerInfoResult struct {
 }
 
 // GetRawMempoolVerboseResult models the data returned from the getrawmempool
-// command when the verbose flag is set.  When the verbose flag is not set,
+// command when the verbose flag is set.  The result is a map of transaction
+// hashes to instances of this type.  When the verbose flag is not set,
 // getrawmempool returns an array of transaction hashes.
 type GetRawMempoolVerboseResult struct {
-	Size             int32    `json:"size"`
-	Vsize            int32    `json:"vsize"`
-	Fee              float64  `json:"fee"`
-	Time             int64    `json:"time"`
-	Height           int64    `json:"height"`
-	StartingPriority float64  `json:"startingpriority"`
-	CurrentPriority  float64  `json:"currentpriority"`
-	Depends          []string `json:"depends"`
+	Size              int32    `json:"size"`
+	Vsize             int32    `json:"vsize"`
+	Weight            int32    `json:"weight"`
+	Fee               float64  `json:"fee"`
+	ModifiedFee       float64  `json:"modifiedfee"`
+	Time              int64    `json:"time"`
+	Height            int64    `json:"height"`
+	StartingPriority  float64  `json:"startingpriority"`
+	CurrentPriority   float64  `json:"currentpriority"`
+	DescendantCount   int64    `json:"descendantcount"`
+	DescendantSize    int64    `json:"descendantsize"`
+	AncestorCount     int64    `json:"ancestorcount"`
+	AncestorSize      int64    `json:"ancestorsize"`
+	Depends           []string `json:"depends"`
+	SpentBy           []string `json:"spentby"`
+	BIP125Replaceable bool     `json:"bip125-replaceable"`
+	Unbroadcast       bool     `json:"unbroadcast"`
 }
 
 // ScriptPubKeyResult models the scriptPubKey data of a tx script.  It is
@@ -462,19 +627,24 @@ type Vout struct {
 }
 
 // GetMiningInfoResult models the data from the getmininginfo command.
+//
+// The current block fields are omitted when they are not available, such as
+// when the server has not built a block yet.
 type GetMiningInfoResult struct {
 	Blocks             int64   `json:"blocks"`
-	CurrentBlockSize   uint64  `json:"currentblocksize"`
-	CurrentBlockWeight uint64  `json:"currentblockweight"`
-	CurrentBlockTx     uint64  `json:"currentblocktx"`
+	CurrentBlockSize   uint64  `json:"currentblocksize,omitempty"`
+	CurrentBlockWeight uint64  `json:"currentblockweight,omitempty"`
+	CurrentBlockTx     uint64  `json:"currentblocktx,omitempty"`
 	Difficulty         float64 `json:"difficulty"`
 	Errors             string  `json:"errors"`
 	Generate           bool    `json:"generate"`
 	GenProcLimit       int32   `json:"genproclimit"`
 	HashesPerSec       int64   `json:"hashespersec"`
-	NetworkHashPS      int64   `json:"networkhashps"`
+	NetworkHashPS      float64 `json:"networkhashps"`
 	PooledTx           uint64  `json:"pooledtx"`
 	TestNet            bool    `json:"testnet"`
+	Chain              string  `json:"chain"`
+	Warnings           string  `json:"warnings"`
 }
 
 // GetWorkResult models the data from the getwork command.
@@ -485,6 +655,36 @@ type GetWorkResult struct {
 	Target   string `json:"target"`
 }
 
+// SubmitBlockResult models the data returned from the submitblock command.
+// The command returns null when the block was accepted and otherwise a string
+// with the reason the block was rejected, so the result marshals to and
+// unmarshals from either of those instead of a JSON object.
+type SubmitBlockResult struct {
+	// RejectReason is the reason the block was rejected.  It is nil when
+	// the block was accepted.
+	RejectReason *string
+}
+
+// Accepted returns whether the submitted block was accepted.
+func (r SubmitBlockResult) Accepted() bool {
+	return r.RejectReason == nil
+}
+
+// MarshalJSON provides a custom Marshal method for SubmitBlockResult.
+func (r SubmitBlockResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.RejectReason)
+}
+
+// UnmarshalJSON provides a custom Unmarshal method for SubmitBlockResult.
+func (r *SubmitBlockResult) UnmarshalJSON(data []byte) error {
+	var reason *string
+	if err := json.Unmarshal(data, &reason); err != nil {
+		return err
+	}
+	r.RejectReason = reason
+	return nil
+}
+
 // InfoChainResult models the data returned by the chain server getinfo command.
 type InfoChainResult struct {
 	Version         int32   `json:"version"`
@@ -6,17 +6,41 @@ package btcjson_test
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/navcoin/navd/btcjson"
 )
 
 // TestChainSvrCustomResults ensures any results that have custom marshalling
-// work as inteded.
-// and unmarshal code of results are as expected.
+// work as inteded, and that results marshal to and unmarshal from the expected
+// JSON.
 func TestChainSvrCustomResults(t *testing.T) {
 	t.Parallel()
 
+	mempoolEntry := btcjson.GetMempoolEntryResult{
+		Size:            250,
+		Vsize:           166,
+		Fee:             0.0001,
+		ModifiedFee:     0.0001,
+		Time:            1500000000,
+		Height:          100,
+		DescendantCount: 1,
+		DescendantSize:  250,
+		DescendantFees:  0.0001,
+		AncestorCount:   2,
+		AncestorSize:    500,
+		AncestorFees:    0.0002,
+		Depends:         []string{"parenttxid"},
+	}
+	mempoolEntryJSON := `{"size":250,"vsize":166,"fee":0.0001,` +
+		`"modifiedfee":0.0001,"time":1500000000,"height":100,` +
+		`"startingpriority":0,"currentpriority":0,"descendantcount":1,` +
+		`"descendantsize":250,"descendantfees":0.0001,"ancestorcount":2,` +
+		`"ancestorsize":500,"ancestorfees":0.0002,"depends":["parenttxid"]}`
+
+	deploymentBit := uint8(28)
+
 	tests := []struct {
 		name     string
 		result   interface{}
@@ -68,6 +92,467 @@ func TestChainSvrCustomResults(t *testing.T) {
 			},
 			expected: `{"txid":"123","vout":1,"scriptSig":{"asm":"0","hex":"00"},"prevOut":{"addresses":["addr1"],"value":0},"sequence":4294967295}`,
 		},
+		{
+			name:     "getrawmempool non-verbose",
+			result:   &[]string{"parenttxid", "childtxid"},
+			expected: `["parenttxid","childtxid"]`,
+		},
+		{
+			name:     "getrawmempool empty",
+			result:   &[]string{},
+			expected: `[]`,
+		},
+		{
+			name: "getrawmempool verbose",
+			result: &map[string]btcjson.GetRawMempoolVerboseResult{
+				"childtxid": {
+					Size:              222,
+					Vsize:             141,
+					Weight:            561,
+					Fee:               0.00000282,
+					ModifiedFee:       0.00000282,
+					Time:              1600000000,
+					Height:            650000,
+					DescendantCount:   1,
+					DescendantSize:    141,
+					AncestorCount:     2,
+					AncestorSize:      366,
+					Depends:           []string{"parenttxid"},
+					SpentBy:           []string{},
+					BIP125Replaceable: true,
+				},
+				"parenttxid": {
+					Size:            225,
+					Vsize:           225,
+					Weight:          900,
+					Fee:             0.0000045,
+					ModifiedFee:     0.0000045,
+					Time:            1599999990,
+					Height:          649999,
+					DescendantCount: 2,
+					DescendantSize:  366,
+					AncestorCount:   1,
+					AncestorSize:    225,
+					Depends:         []string{},
+					SpentBy:         []string{"childtxid"},
+					Unbroadcast:     true,
+				},
+			},
+			expected: `{"childtxid":{"size":222,"vsize":141,` +
+				`"weight":561,"fee":0.00000282,` +
+				`"modifiedfee":0.00000282,"time":1600000000,` +
+				`"height":650000,"startingpriority":0,` +
+				`"currentpriority":0,"descendantcount":1,` +
+				`"descendantsize":141,"ancestorcount":2,` +
+				`"ancestorsize":366,"depends":["parenttxid"],` +
+				`"spentby":[],"bip125-replaceable":true,` +
+				`"unbroadcast":false},"parenttxid":{"size":225,` +
+				`"vsize":225,"weight":900,"fee":0.0000045,` +
+				`"modifiedfee":0.0000045,"time":1599999990,` +
+				`"height":649999,"startingpriority":0,` +
+				`"currentpriority":0,"descendantcount":2,` +
+				`"descendantsize":366,"ancestorcount":1,` +
+				`"ancestorsize":225,"depends":[],` +
+				`"spentby":["childtxid"],"bip125-replaceable":false,` +
+				`"unbroadcast":true}}`,
+		},
+		{
+			name: "getblockchaininfo softforks",
+			result: &btcjson.GetBlockChainInfoResult{
+				Chain:                "test",
+				Blocks:               1832000,
+				Headers:              1832000,
+				BestBlockHash:        "000000000000003ec2e27a07a6b5c6b3a6b8d3c6e54f7af22b7a92d25d8a8a6e",
+				Difficulty:           4194304,
+				MedianTime:           1596120000,
+				VerificationProgress: 0.9999,
+				ChainWork:            "000000000000000000000000000000000000000000000000016e3d39aa09d5e5f6",
+				SizeOnDisk:           30000000000,
+				SoftForks: map[string]*btcjson.SoftForkDescription{
+					"bip34": {
+						Type:   "buried",
+						Height: btcjson.Int32(21111),
+						Active: true,
+					},
+					"csv": {
+						Type: "bip9",
+						Bip9: &btcjson.Bip9SoftForkDescription{
+							Status:     "active",
+							StartTime2: 1456790400,
+							Timeout:    1493596800,
+							Since:      770112,
+						},
+						Height: btcjson.Int32(770112),
+						Active: true,
+					},
+					"segwit": {
+						Type:   "buried",
+						Height: btcjson.Int32(834624),
+						Active: true,
+					},
+					"taproot": {
+						Type: "bip9",
+						Bip9: &btcjson.Bip9SoftForkDescription{
+							Status:     "started",
+							Bit:        2,
+							StartTime2: 1619222400,
+							Timeout:    1628640000,
+							Since:      1832000,
+							Statistics: &btcjson.Bip9Statistics{
+								Period:    2016,
+								Threshold: 1512,
+								Elapsed:   1000,
+								Count:     900,
+								Possible:  true,
+							},
+						},
+					},
+				},
+			},
+			expected: `{"chain":"test","blocks":1832000,"headers":1832000,` +
+				`"bestblockhash":"000000000000003ec2e27a07a6b5c6b3a6b8d3c6e54f7a` +
+				`f22b7a92d25d8a8a6e","difficulty":4194304,` +
+				`"mediantime":1596120000,"verificationprogress":0.9999,` +
+				`"initialblockdownload":false,"chainwork":"00000000000000000000` +
+				`0000000000000000000000000000016e3d39aa09d5e5f6","size_on_disk":` +
+				`30000000000,"pruned":false,"softforks":{` +
+				`"bip34":{"type":"buried","height":21111,"active":true},` +
+				`"csv":{"type":"bip9","bip9":{"status":"active","bit":0,` +
+				`"startTime":0,"start_time":1456790400,` +
+				`"timeout":1493596800,"since":770112},` +
+				`"height":770112,"active":true},` +
+				`"segwit":{"type":"buried","height":834624,"active":true},` +
+				`"taproot":{"type":"bip9","bip9":{"status":"started","bit":2,` +
+				`"startTime":0,"start_time":1619222400,"timeout":1628640000,` +
+				`"since":1832000,"statistics":{"period":2016,` +
+				`"threshold":1512,"elapsed":1000,"count":900,` +
+				`"possible":true}},"active":false}}}`,
+		},
+		{
+			name:     "submitblock accepted",
+			result:   &btcjson.SubmitBlockResult{},
+			expected: `null`,
+		},
+		{
+			name: "submitblock rejected",
+			result: &btcjson.SubmitBlockResult{
+				RejectReason: btcjson.String("rejected: duplicate block"),
+			},
+			expected: `"rejected: duplicate block"`,
+		},
+		{
+			name: "getblocktemplate long poll",
+			result: &btcjson.GetBlockTemplateResult{
+				Bits:          "1d00ffff",
+				CurTime:       1500000000,
+				Height:        100,
+				PreviousHash:  "prevhash",
+				Transactions:  []btcjson.GetBlockTemplateResultTx{},
+				Version:       4,
+				CoinbaseValue: btcjson.Int64(5000000000),
+				LongPollID:    "prevhash42",
+				LongPollURI:   "/longpoll",
+				SubmitOld:     btcjson.Bool(false),
+				Expires:       120,
+			},
+			expected: `{"bits":"1d00ffff","curtime":1500000000,"height":100,` +
+				`"previousblockhash":"prevhash","transactions":[],"version":4,` +
+				`"coinbasevalue":5000000000,"longpollid":"prevhash42",` +
+				`"longpolluri":"/longpoll","submitold":false,"expires":120}`,
+		},
+		{
+			name:     "getmempoolentry",
+			result:   &mempoolEntry,
+			expected: mempoolEntryJSON,
+		},
+		{
+			name:     "getmempoolancestors non-verbose",
+			result:   &[]string{"parenttxid", "grandparenttxid"},
+			expected: `["parenttxid","grandparenttxid"]`,
+		},
+		{
+			name: "getmempooldescendants verbose",
+			result: &map[string]btcjson.GetMempoolEntryResult{
+				"childtxid": mempoolEntry,
+			},
+			expected: `{"childtxid":` + mempoolEntryJSON + `}`,
+		},
+		{
+			name: "getindexinfo",
+			result: &map[string]btcjson.GetIndexInfoResult{
+				"addrindex": {Synced: false, BestBlockHeight: 1200},
+				"txindex":   {Synced: true, BestBlockHeight: 1500},
+			},
+			expected: `{"addrindex":{"synced":false,"best_block_height":1200},` +
+				`"txindex":{"synced":true,"best_block_height":1500}}`,
+		},
+		{
+			name: "getblocktemplate segwit",
+			result: &btcjson.GetBlockTemplateResult{
+				Bits:         "1d00ffff",
+				CurTime:      1500000000,
+				Height:       1000,
+				PreviousHash: "prevhash",
+				SigOpLimit:   80000,
+				SizeLimit:    4000000,
+				WeightLimit:  4000000,
+				Transactions: []btcjson.GetBlockTemplateResultTx{{
+					Data:    "txdata",
+					TxID:    "txid",
+					Hash:    "wtxid",
+					Depends: []int64{1},
+					Fee:     1000,
+					SigOps:  4,
+					Weight:  800,
+				}},
+				Version:                  536870912,
+				CoinbaseAux:              &btcjson.GetBlockTemplateResultAux{},
+				CoinbaseValue:            btcjson.Int64(5000000000),
+				DefaultWitnessCommitment: "6a24aa21a9ed",
+				Target:                   "00ff",
+				MinTime:                  1499999000,
+				Mutable:                  []string{"time", "transactions", "prevblock"},
+				NonceRange:               "00000000ffffffff",
+			},
+			expected: `{"bits":"1d00ffff","curtime":1500000000,"height":1000,` +
+				`"previousblockhash":"prevhash","sigoplimit":80000,` +
+				`"sizelimit":4000000,"weightlimit":4000000,"transactions":[{` +
+				`"data":"txdata","txid":"txid","hash":"wtxid","depends":[1],` +
+				`"fee":1000,"sigops":4,"weight":800}],"version":536870912,` +
+				`"coinbaseaux":{"flags":""},"coinbasevalue":5000000000,` +
+				`"default_witness_commitment":"6a24aa21a9ed","target":"00ff",` +
+				`"mintime":1499999000,"mutable":["time","transactions",` +
+				`"prevblock"],"noncerange":"00000000ffffffff"}`,
+		},
+		{
+			name: "getmininginfo with current block",
+			result: &btcjson.GetMiningInfoResult{
+				Blocks:             506000,
+				CurrentBlockSize:   1000,
+				CurrentBlockWeight: 3992,
+				CurrentBlockTx:     3,
+				Difficulty:         1.5,
+				GenProcLimit:       -1,
+				NetworkHashPS:      2500000000.75,
+				PooledTx:           12,
+				Chain:              "mainnet",
+			},
+			expected: `{"blocks":506000,"currentblocksize":1000,` +
+				`"currentblockweight":3992,"currentblocktx":3,` +
+				`"difficulty":1.5,"errors":"","generate":false,` +
+				`"genproclimit":-1,"hashespersec":0,` +
+				`"networkhashps":2500000000.75,"pooledtx":12,` +
+				`"testnet":false,"chain":"mainnet","warnings":""}`,
+		},
+		{
+			name: "getmininginfo without current block",
+			result: &btcjson.GetMiningInfoResult{
+				Difficulty:   1,
+				GenProcLimit: -1,
+				TestNet:      true,
+				Chain:        "testnet3",
+				Warnings:     "test warning",
+			},
+			expected: `{"blocks":0,"difficulty":1,"errors":"",` +
+				`"generate":false,"genproclimit":-1,"hashespersec":0,` +
+				`"networkhashps":0,"pooledtx":0,"testnet":true,` +
+				`"chain":"testnet3","warnings":"test warning"}`,
+		},
+		{
+			name: "getdeploymentinfo",
+			result: &btcjson.GetDeploymentInfoResult{
+				Hash:   "000000000000000000052d314a259755ca65944e68df6b12a067ea8f1f5a7091",
+				Height: 700000,
+				Deployments: map[string]*btcjson.DeploymentInfo{
+					"bip34": {
+						Type:   "buried",
+						Height: btcjson.Int32(227931),
+						Active: true,
+					},
+					"segwit": {
+						Type:   "buried",
+						Height: btcjson.Int32(481824),
+						Active: true,
+					},
+					"testdummy": {
+						Type: "bip9",
+						Bip9: &btcjson.Bip9DeploymentInfo{
+							Bit:        &deploymentBit,
+							StartTime:  1199145601,
+							Timeout:    1230767999,
+							Status:     "started",
+							Since:      699552,
+							StatusNext: "started",
+							Statistics: &btcjson.Bip9Statistics{
+								Period:    2016,
+								Threshold: 1815,
+								Elapsed:   448,
+								Count:     12,
+								Possible:  true,
+							},
+							Signalling: "---#-",
+						},
+					},
+					"taproot": {
+						Type:   "bip9",
+						Height: btcjson.Int32(709632),
+						Active: true,
+						Bip9: &btcjson.Bip9DeploymentInfo{
+							StartTime:           -1,
+							Timeout:             9223372036854775807,
+							MinActivationHeight: 709632,
+							Status:              "active",
+							Since:               0,
+							StatusNext:          "active",
+						},
+					},
+				},
+			},
+			expected: `{"hash":"000000000000000000052d314a259755ca65944e68df6b12` +
+				`a067ea8f1f5a7091","height":700000,"deployments":{` +
+				`"bip34":{"type":"buried","height":227931,"active":true},` +
+				`"segwit":{"type":"buried","height":481824,"active":true},` +
+				`"taproot":{"type":"bip9","height":709632,"active":true,` +
+				`"bip9":{"start_time":-1,"timeout":9223372036854775807,` +
+				`"min_activation_height":709632,"status":"active","since":0,` +
+				`"status_next":"active"}},` +
+				`"testdummy":{"type":"bip9","active":false,"bip9":{"bit":28,` +
+				`"start_time":1199145601,"timeout":1230767999,` +
+				`"min_activation_height":0,"status":"started","since":699552,` +
+				`"status_next":"started","statistics":{"period":2016,` +
+				`"threshold":1815,"elapsed":448,"count":12,"possible":true},` +
+				`"signalling":"---#-"}}}}`,
+		},
+		{
+			name: "getblockchaininfo pruned",
+			result: &btcjson.GetBlockChainInfoResult{
+				Chain:            "main",
+				Blocks:           700000,
+				Headers:          700000,
+				BestBlockHash:    "000000000000000000052d314a259755ca65944e68df6b12a067ea8f1f5a7091",
+				Difficulty:       18415156832118.24,
+				MedianTime:       1631331598,
+				SizeOnDisk:       5586448195,
+				Pruned:           true,
+				PruneHeight:      690000,
+				AutomaticPruning: btcjson.Bool(true),
+				PruneTargetSize:  576716800,
+			},
+			expected: `{"chain":"main","blocks":700000,` +
+				`"headers":700000,"bestblockhash":"00000000000000000` +
+				`0052d314a259755ca65944e68df6b12a067ea8f1f5a7091",` +
+				`"difficulty":18415156832118.24,` +
+				`"mediantime":1631331598,` +
+				`"initialblockdownload":false,"size_on_disk":` +
+				`5586448195,"pruned":true,` +
+				`"pruneheight":690000,"automatic_pruning":true,` +
+				`"prune_target_size":576716800,"softforks":null}`,
+		},
+		{
+			name: "getblockchaininfo unpruned",
+			result: &btcjson.GetBlockChainInfoResult{
+				Chain:         "main",
+				Blocks:        700000,
+				Headers:       700000,
+				BestBlockHash: "000000000000000000052d314a259755ca65944e68df6b12a067ea8f1f5a7091",
+				Difficulty:    18415156832118.24,
+				MedianTime:    1631331598,
+				SizeOnDisk:    416870474342,
+			},
+			expected: `{"chain":"main","blocks":700000,` +
+				`"headers":700000,"bestblockhash":"00000000000000000` +
+				`0052d314a259755ca65944e68df6b12a067ea8f1f5a7091",` +
+				`"difficulty":18415156832118.24,` +
+				`"mediantime":1631331598,` +
+				`"initialblockdownload":false,"size_on_disk":` +
+				`416870474342,"pruned":false,"softforks":null}`,
+		},
+		{
+			name: "getblockchaininfo syncing",
+			result: &btcjson.GetBlockChainInfoResult{
+				Chain:                "main",
+				Blocks:               350000,
+				Headers:              700000,
+				BestBlockHash:        "0000000000000000053cf64f0400bb38e0c4b3872c38795ddde27acb40a112bb",
+				Difficulty:           49402014931.22746,
+				MedianTime:           1427125063,
+				VerificationProgress: 0.0816,
+				InitialBlockDownload: true,
+				ChainWork:            "00000000000000000000000000000000000000000005309ba56fb1cb2d8d0b2e",
+				SizeOnDisk:           34146291845,
+			},
+			expected: `{"chain":"main","blocks":350000,` +
+				`"headers":700000,"bestblockhash":"00000000000000000` +
+				`53cf64f0400bb38e0c4b3872c38795ddde27acb40a112bb",` +
+				`"difficulty":49402014931.22746,` +
+				`"mediantime":1427125063,` +
+				`"verificationprogress":0.0816,` +
+				`"initialblockdownload":true,"chainwork":"000000000` +
+				`00000000000000000000000000000000005309ba56fb1cb2d8d` +
+				`0b2e","size_on_disk":34146291845,"pruned":false,` +
+				`"softforks":null}`,
+		},
+		{
+			name: "getnetworkinfo",
+			result: &btcjson.GetNetworkInfoResult{
+				Version:         70016,
+				SubVersion:      "/navd:0.12.0/",
+				ProtocolVersion: 70016,
+				LocalServices:   "0000000000000409",
+				LocalRelay:      true,
+				TimeOffset:      -1,
+				Connections:     10,
+				NetworkActive:   true,
+				Networks: []btcjson.NetworksResult{
+					{
+						Name:      "ipv4",
+						Reachable: true,
+					},
+					{
+						Name:                      "onion",
+						Limited:                   true,
+						Proxy:                     "127.0.0.1:9050",
+						ProxyRandomizeCredentials: true,
+					},
+				},
+				RelayFee:       0.00001,
+				IncrementalFee: 0.00001,
+				LocalAddresses: []btcjson.LocalAddressesResult{
+					{
+						Address: "203.0.113.7",
+						Port:    44440,
+						Score:   4,
+					},
+				},
+			},
+			expected: `{"version":70016,"subversion":"/navd:0.12.0/",` +
+				`"protocolversion":70016,"localservices":"0000000000000409",` +
+				`"localrelay":true,"timeoffset":-1,"connections":10,` +
+				`"networkactive":true,"networks":[{"name":"ipv4",` +
+				`"limited":false,"reachable":true,"proxy":"",` +
+				`"proxy_randomize_credentials":false},{"name":"onion",` +
+				`"limited":true,"reachable":false,"proxy":"127.0.0.1:9050",` +
+				`"proxy_randomize_credentials":true}],"relayfee":0.00001,` +
+				`"incrementalfee":0.00001,"localaddresses":[{"address":` +
+				`"203.0.113.7","port":44440,"score":4}],"warnings":""}`,
+		},
+		{
+			name: "getchaintips",
+			result: &btcjson.GetChainTipsResult{
+				{Height: 100, Hash: "aa", Status: btcjson.ChainTipStatusActive},
+				{Height: 101, Hash: "bb", BranchLen: 3,
+					Status: btcjson.ChainTipStatusHeadersOnly},
+				{Height: 99, Hash: "cc", BranchLen: 1,
+					Status: btcjson.ChainTipStatusValidFork},
+				{Height: 100, Hash: "a0", BranchLen: 2,
+					Status: btcjson.ChainTipStatusInvalid},
+			},
+			expected: `[` +
+				`{"height":100,"hash":"aa","branchlen":0,"status":"active"},` +
+				`{"height":101,"hash":"bb","branchlen":3,"status":"headers-only"},` +
+				`{"height":99,"hash":"cc","branchlen":1,"status":"valid-fork"},` +
+				`{"height":100,"hash":"a0","branchlen":2,"status":"invalid"}]`,
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -84,5 +569,84 @@ func TestChainSvrCustomResults(t *testing.T) {
 				test.expected)
 			continue
 		}
+
+		// Ensure the expected JSON unmarshals back to the same result.
+		unmarshalled := reflect.New(reflect.TypeOf(test.result).Elem())
+		err = json.Unmarshal([]byte(test.expected), unmarshalled.Interface())
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(unmarshalled.Interface(), test.result) {
+			t.Errorf("Test #%d (%s) unexpected unmarshalled result "+
+				"- got %+v, want %+v", i, test.name,
+				unmarshalled.Interface(), test.result)
+			continue
+		}
+	}
+}
+
+// TestGetChainTips ensures GetChainTips orders the tips as the getchaintips
+// command does without modifying the passed tips.
+func TestGetChainTips(t *testing.T) {
+	t.Parallel()
+
+	active := btcjson.ChainTip{
+		Height: 100, Hash: "aa", Status: btcjson.ChainTipStatusActive,
+	}
+	headersOnly := btcjson.ChainTip{
+		Height: 101, Hash: "bb", BranchLen: 3,
+		Status: btcjson.ChainTipStatusHeadersOnly,
+	}
+	validFork := btcjson.ChainTip{
+		Height: 99, Hash: "cc", BranchLen: 1,
+		Status: btcjson.ChainTipStatusValidFork,
+	}
+	invalid := btcjson.ChainTip{
+		Height: 100, Hash: "a0", BranchLen: 2,
+		Status: btcjson.ChainTipStatusInvalid,
+	}
+	tips := btcjson.GetChainTipsResult{active, headersOnly, validFork, invalid}
+	expected := btcjson.GetChainTipsResult{active, headersOnly, validFork, invalid}
+
+	// Ensure the tips are ordered by descending height with ties broken by
+	// hash and that the passed tips are left untouched.
+	got := btcjson.GetChainTips(tips)
+	want := []btcjson.ChainTip{headersOnly, invalid, active, validFork}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetChainTips: unexpected order - got %+v, want %+v",
+			got, want)
+	}
+	if !reflect.DeepEqual(tips, expected) {
+		t.Fatalf("GetChainTips: modified the passed tips - got %+v",
+			tips)
+	}
+}
+
+// TestChainSvrSubmitBlockResult ensures submitblock results marshal to null or
+// the reject reason when they are not addressable, such as when they are held
+// by value in another type, and that results which are neither null nor a
+// reason string are rejected.
+func TestChainSvrSubmitBlockResult(t *testing.T) {
+	t.Parallel()
+
+	results := []btcjson.SubmitBlockResult{
+		{},
+		{RejectReason: btcjson.String("rejected: duplicate block")},
+	}
+	marshalled, err := json.Marshal(results)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `[null,"rejected: duplicate block"]`
+	if string(marshalled) != expected {
+		t.Errorf("unexpected marshalled data - got %s, want %s",
+			marshalled, expected)
+	}
+
+	var result btcjson.SubmitBlockResult
+	if err := json.Unmarshal([]byte(`{"reason":"bad"}`), &result); err == nil {
+		t.Errorf("unexpected success unmarshalling object result")
 	}
 }
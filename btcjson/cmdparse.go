@@ -12,6 +12,10 @@ import (
 	"strings"
 )
 
+// blockVerbosityType is the reflect type of BlockVerbosity, which assignField
+// also allows to be specified by the legacy boolean verbose flag.
+var blockVerbosityType = reflect.TypeOf(BlockVerbosity(0))
+
 // makeParams creates a slice of interface values for the given struct.
 func makeParams(rt reflect.Type, rv reflect.Value) []interface{} {
 	numFields := rt.NumField()
@@ -46,6 +50,32 @@ func MarshalCmd(id interface{}, cmd interface{}) ([]byte, error) {
 		return nil, makeError(ErrUnregisteredMethod, str)
 	}
 
+	return marshalCmd(id, method, cmd)
+}
+
+// MarshalCmdAs is the same as MarshalCmd except the request uses the passed
+// method name, which must be either the registered method of the command type
+// or an alias registered for it with RegisterCmdAlias.  This allows requests
+// to be sent to servers which only know a deprecated name of the method.
+func MarshalCmdAs(id interface{}, method string, cmd interface{}) ([]byte, error) {
+	rt := reflect.TypeOf(cmd)
+	registerLock.RLock()
+	cmdMethod, ok := concreteTypeToMethod[rt]
+	resolved := resolveMethod(method)
+	registerLock.RUnlock()
+	if !ok || resolved != cmdMethod {
+		str := fmt.Sprintf("%q is not registered for %v", method, rt)
+		return nil, makeError(ErrUnregisteredMethod, str)
+	}
+
+	return marshalCmd(id, method, cmd)
+}
+
+// marshalCmd marshals the passed registered command to a JSON-RPC request for
+// the passed method name.
+func marshalCmd(id interface{}, method string, cmd interface{}) ([]byte, error) {
+	rt := reflect.TypeOf(cmd)
+
 	// The provided command must not be nil.
 	rv := reflect.ValueOf(cmd)
 	if rv.IsNil() {
@@ -106,11 +136,13 @@ func populateDefaults(numParams int, info *methodInfo, rv reflect.Value) {
 
 // UnmarshalCmd unmarshals a JSON-RPC request into a suitable concrete command
 // so long as the method type contained within the marshalled request is
-// registered.
+// registered.  Methods registered as an alias with RegisterCmdAlias unmarshal
+// into the command type of the method they are an alias for.
 func UnmarshalCmd(r *Request) (interface{}, error) {
 	registerLock.RLock()
-	rtp, ok := methodToConcreteType[r.Method]
-	info := methodToInfo[r.Method]
+	method := resolveMethod(r.Method)
+	rtp, ok := methodToConcreteType[method]
+	info := methodToInfo[method]
 	registerLock.RUnlock()
 	if !ok {
 		str := fmt.Sprintf("%q is not registered", r.Method)
@@ -410,6 +442,16 @@ func assignField(paramNum int, fieldName string, dest reflect.Value, src reflect
 			reflect.Int64:
 
 			srcInt, err := strconv.ParseInt(src.String(), 0, 0)
+			if err != nil && destBaseType == blockVerbosityType {
+				// The getblock verbosity also accepts the legacy
+				// boolean verbose flag.
+				var verbose bool
+				verbose, err = strconv.ParseBool(src.String())
+				srcInt = int64(BlockVerbosityHex)
+				if verbose {
+					srcInt = int64(BlockVerbosityTxIDs)
+				}
+			}
 			if err != nil {
 				str := fmt.Sprintf("parameter #%d '%s' must "+
 					"parse to a %v", paramNum, fieldName,
@@ -512,8 +554,9 @@ func NewCmd(method string, args ...interface{}) (interface{}, error) {
 	// Look up details about the provided method.  Any methods that aren't
 	// registered are an error.
 	registerLock.RLock()
-	rtp, ok := methodToConcreteType[method]
-	info := methodToInfo[method]
+	resolved := resolveMethod(method)
+	rtp, ok := methodToConcreteType[resolved]
+	info := methodToInfo[resolved]
 	registerLock.RUnlock()
 	if !ok {
 		str := fmt.Sprintf("%q is not registered", method)
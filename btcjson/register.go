@@ -88,6 +88,10 @@ var (
 	methodToConcreteType = make(map[string]reflect.Type)
 	methodToInfo         = make(map[string]methodInfo)
 	concreteTypeToMethod = make(map[reflect.Type]string)
+
+	// methodAliases maps deprecated method names to the registered method
+	// they are an alias for.
+	methodAliases = make(map[string]string)
 )
 
 // baseKindString returns the base kind for a given reflect.Type after
@@ -162,6 +166,11 @@ func RegisterCmd(method string, cmd interface{}, flags UsageFlag) error {
 		str := fmt.Sprintf("method %q is already registered", method)
 		return makeError(ErrDuplicateMethod, str)
 	}
+	if _, ok := methodAliases[method]; ok {
+		str := fmt.Sprintf("method %q is already registered as an "+
+			"alias", method)
+		return makeError(ErrDuplicateMethod, str)
+	}
 
 	// Ensure that no unrecognized flag bits were specified.
 	if ^(highestUsageFlagBit-1)&flags != 0 {
@@ -276,6 +285,49 @@ func MustRegisterCmd(method string, cmd interface{}, flags UsageFlag) {
 	}
 }
 
+// RegisterCmdAlias registers the passed alias as an additional name for the
+// passed registered method.  This allows a method which has been renamed to
+// keep accepting its deprecated name.  UnmarshalCmd and NewCmd resolve the
+// alias to the command type of the method, while MarshalCmd continues to use
+// the method name unless MarshalCmdAs is used to emit the alias instead.
+// Aliases are not included in the methods returned by RegisteredCmdMethods.
+//
+// ErrUnregisteredMethod is returned when the method is not registered and
+// ErrDuplicateMethod is returned when the alias is already registered as
+// either a method or an alias.
+func RegisterCmdAlias(alias, method string) error {
+	registerLock.Lock()
+	defer registerLock.Unlock()
+
+	if _, ok := methodToConcreteType[method]; !ok {
+		str := fmt.Sprintf("%q is not registered", method)
+		return makeError(ErrUnregisteredMethod, str)
+	}
+	if _, ok := methodToConcreteType[alias]; ok {
+		str := fmt.Sprintf("method %q is already registered", alias)
+		return makeError(ErrDuplicateMethod, str)
+	}
+	if _, ok := methodAliases[alias]; ok {
+		str := fmt.Sprintf("method %q is already registered as an "+
+			"alias", alias)
+		return makeError(ErrDuplicateMethod, str)
+	}
+
+	methodAliases[alias] = method
+	return nil
+}
+
+// resolveMethod returns the registered method the passed method name refers
+// to, which is the method itself unless it is a registered alias.
+//
+// This function MUST be called with the register lock held (for reads).
+func resolveMethod(method string) string {
+	if aliasOf, ok := methodAliases[method]; ok {
+		return aliasOf
+	}
+	return method
+}
+
 // RegisteredCmdMethods returns a sorted list of methods for all registered
 // commands.
 func RegisteredCmdMethods() []string {
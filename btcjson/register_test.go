@@ -5,6 +5,7 @@
 package btcjson_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"sort"
 	"testing"
@@ -261,3 +262,100 @@ func TestRegisteredCmdMethods(t *testing.T) {
 		t.Fatal("RegisteredCmdMethods: methods are not sorted")
 	}
 }
+
+// TestRegisterCmdAlias ensures a command can be marshalled using an alias of
+// its method and unmarshalled from a request using the alias, and that invalid
+// aliases are rejected.
+func TestRegisterCmdAlias(t *testing.T) {
+	t.Parallel()
+
+	err := btcjson.RegisterCmdAlias("getblockhashold", "getblockhash")
+	if err != nil {
+		t.Fatalf("RegisterCmdAlias: unexpected error: %v", err)
+	}
+
+	// Round trip a command through its alias.
+	cmd := btcjson.NewGetBlockHashCmd(123)
+	marshalled, err := btcjson.MarshalCmdAs(1, "getblockhashold", cmd)
+	if err != nil {
+		t.Fatalf("MarshalCmdAs: unexpected error: %v", err)
+	}
+	want := `{"jsonrpc":"1.0","method":"getblockhashold","params":[123],"id":1}`
+	if string(marshalled) != want {
+		t.Fatalf("MarshalCmdAs: unexpected marshalled data - got %s, "+
+			"want %s", marshalled, want)
+	}
+	var request btcjson.Request
+	if err := json.Unmarshal(marshalled, &request); err != nil {
+		t.Fatalf("unexpected error unmarshalling request: %v", err)
+	}
+	unmarshalled, err := btcjson.UnmarshalCmd(&request)
+	if err != nil {
+		t.Fatalf("UnmarshalCmd: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(unmarshalled, cmd) {
+		t.Fatalf("UnmarshalCmd: unexpected command - got %v, want %v",
+			unmarshalled, cmd)
+	}
+
+	// NewCmd must also resolve the alias and MarshalCmd must keep using
+	// the registered method.
+	newCmd, err := btcjson.NewCmd("getblockhashold", 123)
+	if err != nil {
+		t.Fatalf("NewCmd: unexpected error: %v", err)
+	}
+	marshalled, err = btcjson.MarshalCmd(1, newCmd)
+	if err != nil {
+		t.Fatalf("MarshalCmd: unexpected error: %v", err)
+	}
+	want = `{"jsonrpc":"1.0","method":"getblockhash","params":[123],"id":1}`
+	if string(marshalled) != want {
+		t.Fatalf("MarshalCmd: unexpected marshalled data - got %s, "+
+			"want %s", marshalled, want)
+	}
+
+	tests := []struct {
+		name   string
+		alias  string
+		method string
+		err    btcjson.Error
+	}{
+		{
+			name:   "unregistered method",
+			alias:  "getnothingold",
+			method: "getnothing",
+			err:    btcjson.Error{ErrorCode: btcjson.ErrUnregisteredMethod},
+		},
+		{
+			name:   "alias is a registered method",
+			alias:  "getblockcount",
+			method: "getblockhash",
+			err:    btcjson.Error{ErrorCode: btcjson.ErrDuplicateMethod},
+		},
+		{
+			name:   "alias already registered",
+			alias:  "getblockhashold",
+			method: "getblockcount",
+			err:    btcjson.Error{ErrorCode: btcjson.ErrDuplicateMethod},
+		},
+	}
+	for _, test := range tests {
+		err := btcjson.RegisterCmdAlias(test.alias, test.method)
+		gotErrorCode := err.(btcjson.Error).ErrorCode
+		if gotErrorCode != test.err.ErrorCode {
+			t.Errorf("%s: mismatched error - got %v (%v), want %v",
+				test.name, gotErrorCode, err, test.err.ErrorCode)
+		}
+	}
+
+	// Marshalling a command using an alias of a different method must
+	// fail.
+	_, err = btcjson.MarshalCmdAs(1, "getblockhashold",
+		btcjson.NewGetBlockCountCmd())
+	if jerr, ok := err.(btcjson.Error); !ok ||
+		jerr.ErrorCode != btcjson.ErrUnregisteredMethod {
+
+		t.Errorf("MarshalCmdAs: mismatched error - got %v, want %v",
+			err, btcjson.ErrUnregisteredMethod)
+	}
+}
@@ -9,9 +9,10 @@ package btcjson
 
 // AddMultisigAddressCmd defines the addmutisigaddress JSON-RPC command.
 type AddMultisigAddressCmd struct {
-	NRequired int
-	Keys      []string
-	Account   *string
+	NRequired   int
+	Keys        []string
+	Label       *string
+	AddressType *string `jsonrpcdefault:"\"legacy\""`
 }
 
 // NewAddMultisigAddressCmd returns a new instance which can be used to issue a
@@ -19,11 +20,14 @@ type AddMultisigAddressCmd struct {
 //
 // The parameters which are pointers indicate they are optional.  Passing nil
 // for optional parameters will use the default value.
-func NewAddMultisigAddressCmd(nRequired int, keys []string, account *string) *AddMultisigAddressCmd {
+func NewAddMultisigAddressCmd(nRequired int, keys []string, label *string,
+	addressType *string) *AddMultisigAddressCmd {
+
 	return &AddMultisigAddressCmd{
-		NRequired: nRequired,
-		Keys:      keys,
-		Account:   account,
+		NRequired:   nRequired,
+		Keys:        keys,
+		Label:       label,
+		AddressType: addressType,
 	}
 }
 
@@ -42,16 +46,21 @@ func NewAddWitnessAddressCmd(address string) *AddWitnessAddressCmd {
 
 // CreateMultisigCmd defines the createmultisig JSON-RPC command.
 type CreateMultisigCmd struct {
-	NRequired int
-	Keys      []string
+	NRequired   int
+	Keys        []string
+	AddressType *string `jsonrpcdefault:"\"legacy\""`
 }
 
 // NewCreateMultisigCmd returns a new instance which can be used to issue a
 // createmultisig JSON-RPC command.
-func NewCreateMultisigCmd(nRequired int, keys []string) *CreateMultisigCmd {
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewCreateMultisigCmd(nRequired int, keys []string, addressType *string) *CreateMultisigCmd {
 	return &CreateMultisigCmd{
-		NRequired: nRequired,
-		Keys:      keys,
+		NRequired:   nRequired,
+		Keys:        keys,
+		AddressType: addressType,
 	}
 }
 
@@ -133,6 +142,19 @@ func NewGetAccountAddressCmd(account string) *GetAccountAddressCmd {
 	}
 }
 
+// GetAddressInfoCmd defines the getaddressinfo JSON-RPC command.
+type GetAddressInfoCmd struct {
+	Address string
+}
+
+// NewGetAddressInfoCmd returns a new instance which can be used to issue a
+// getaddressinfo JSON-RPC command.
+func NewGetAddressInfoCmd(address string) *GetAddressInfoCmd {
+	return &GetAddressInfoCmd{
+		Address: address,
+	}
+}
+
 // GetAddressesByAccountCmd defines the getaddressesbyaccount JSON-RPC command.
 type GetAddressesByAccountCmd struct {
 	Account string
@@ -667,6 +689,7 @@ func init() {
 	MustRegisterCmd("getaccount", (*GetAccountCmd)(nil), flags)
 	MustRegisterCmd("getaccountaddress", (*GetAccountAddressCmd)(nil), flags)
 	MustRegisterCmd("getaddressesbyaccount", (*GetAddressesByAccountCmd)(nil), flags)
+	MustRegisterCmd("getaddressinfo", (*GetAddressInfoCmd)(nil), flags)
 	MustRegisterCmd("getbalance", (*GetBalanceCmd)(nil), flags)
 	MustRegisterCmd("getnewaddress", (*GetNewAddressCmd)(nil), flags)
 	MustRegisterCmd("getrawchangeaddress", (*GetRawChangeAddressCmd)(nil), flags)
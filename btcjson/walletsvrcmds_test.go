@@ -36,29 +36,32 @@ func TestWalletSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				keys := []string{"031234", "035678"}
-				return btcjson.NewAddMultisigAddressCmd(2, keys, nil)
+				return btcjson.NewAddMultisigAddressCmd(2, keys, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"addmultisigaddress","params":[2,["031234","035678"]],"id":1}`,
 			unmarshalled: &btcjson.AddMultisigAddressCmd{
-				NRequired: 2,
-				Keys:      []string{"031234", "035678"},
-				Account:   nil,
+				NRequired:   2,
+				Keys:        []string{"031234", "035678"},
+				Label:       nil,
+				AddressType: btcjson.String("legacy"),
 			},
 		},
 		{
 			name: "addmultisigaddress optional",
 			newCmd: func() (interface{}, error) {
-				return btcjson.NewCmd("addmultisigaddress", 2, []string{"031234", "035678"}, "test")
+				return btcjson.NewCmd("addmultisigaddress", 2, []string{"031234", "035678"}, "test", "bech32")
 			},
 			staticCmd: func() interface{} {
 				keys := []string{"031234", "035678"}
-				return btcjson.NewAddMultisigAddressCmd(2, keys, btcjson.String("test"))
+				return btcjson.NewAddMultisigAddressCmd(2, keys,
+					btcjson.String("test"), btcjson.String("bech32"))
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"addmultisigaddress","params":[2,["031234","035678"],"test"],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"addmultisigaddress","params":[2,["031234","035678"],"test","bech32"],"id":1}`,
 			unmarshalled: &btcjson.AddMultisigAddressCmd{
-				NRequired: 2,
-				Keys:      []string{"031234", "035678"},
-				Account:   btcjson.String("test"),
+				NRequired:   2,
+				Keys:        []string{"031234", "035678"},
+				Label:       btcjson.String("test"),
+				AddressType: btcjson.String("bech32"),
 			},
 		},
 		{
@@ -81,12 +84,30 @@ func TestWalletSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				keys := []string{"031234", "035678"}
-				return btcjson.NewCreateMultisigCmd(2, keys)
+				return btcjson.NewCreateMultisigCmd(2, keys, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"createmultisig","params":[2,["031234","035678"]],"id":1}`,
 			unmarshalled: &btcjson.CreateMultisigCmd{
-				NRequired: 2,
-				Keys:      []string{"031234", "035678"},
+				NRequired:   2,
+				Keys:        []string{"031234", "035678"},
+				AddressType: btcjson.String("legacy"),
+			},
+		},
+		{
+			name: "createmultisig optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("createmultisig", 2, []string{"031234", "035678"}, "p2sh-segwit")
+			},
+			staticCmd: func() interface{} {
+				keys := []string{"031234", "035678"}
+				return btcjson.NewCreateMultisigCmd(2, keys,
+					btcjson.String("p2sh-segwit"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"createmultisig","params":[2,["031234","035678"],"p2sh-segwit"],"id":1}`,
+			unmarshalled: &btcjson.CreateMultisigCmd{
+				NRequired:   2,
+				Keys:        []string{"031234", "035678"},
+				AddressType: btcjson.String("p2sh-segwit"),
 			},
 		},
 		{
@@ -180,6 +201,19 @@ func TestWalletSvrCmds(t *testing.T) {
 				Account: "acct",
 			},
 		},
+		{
+			name: "getaddressinfo",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getaddressinfo", "1Address")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetAddressInfoCmd("1Address")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressinfo","params":["1Address"],"id":1}`,
+			unmarshalled: &btcjson.GetAddressInfoCmd{
+				Address: "1Address",
+			},
+		},
 		{
 			name: "getbalance",
 			newCmd: func() (interface{}, error) {
@@ -4,6 +4,53 @@
 
 package btcjson
 
+// AddMultisigAddressResult models the data returned from the addmultisigaddress
+// command.
+type AddMultisigAddressResult struct {
+	Address      string `json:"address"`
+	RedeemScript string `json:"redeemScript"`
+	Descriptor   string `json:"descriptor,omitempty"`
+}
+
+// EmbeddedAddressInfoResult models the information about an address returned
+// by the getaddressinfo command which does not depend on its relation to the
+// wallet.  It is also the type of the embedded field of GetAddressInfoResult,
+// which describes the address wrapped by a pay-to-script-hash address.
+//
+// Most of the fields only apply to certain types of addresses and are omitted
+// when they do not apply.
+type EmbeddedAddressInfoResult struct {
+	Address             string    `json:"address"`
+	ScriptPubKey        string    `json:"scriptPubKey"`
+	Descriptor          *string   `json:"desc,omitempty"`
+	IsScript            bool      `json:"isscript"`
+	IsChange            bool      `json:"ischange"`
+	IsWitness           bool      `json:"iswitness"`
+	WitnessVersion      *int32    `json:"witness_version,omitempty"`
+	WitnessProgram      *string   `json:"witness_program,omitempty"`
+	ScriptType          *string   `json:"script,omitempty"`
+	Hex                 *string   `json:"hex,omitempty"`
+	PubKeys             *[]string `json:"pubkeys,omitempty"`
+	SignaturesRequired  *int32    `json:"sigsrequired,omitempty"`
+	PubKey              *string   `json:"pubkey,omitempty"`
+	IsCompressed        *bool     `json:"iscompressed,omitempty"`
+	HDMasterFingerprint *string   `json:"hdmasterfingerprint,omitempty"`
+	Labels              []string  `json:"labels"`
+}
+
+// GetAddressInfoResult models the data returned from the getaddressinfo
+// command.
+type GetAddressInfoResult struct {
+	EmbeddedAddressInfoResult
+	IsMine      bool                       `json:"ismine"`
+	IsWatchOnly bool                       `json:"iswatchonly"`
+	Solvable    bool                       `json:"solvable"`
+	Timestamp   *int64                     `json:"timestamp,omitempty"`
+	HDKeyPath   *string                    `json:"hdkeypath,omitempty"`
+	HDSeedID    *string                    `json:"hdseedid,omitempty"`
+	Embedded    *EmbeddedAddressInfoResult `json:"embedded,omitempty"`
+}
+
 // GetTransactionDetailsResult models the details data from the gettransaction command.
 //
 // This models the "short" version of the ListTransactionsResult type, which
@@ -0,0 +1,159 @@
+// Copyright (c) 2014 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package btcjson_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/navcoin/navd/btcjson"
+)
+
+// TestWalletSvrAddressInfoResult ensures a getaddressinfo result for a
+// pay-to-witness-pubkey-hash address unmarshals as expected, including a
+// witness version of zero, and that fields which don't apply to the address
+// are omitted when it is marshalled again.
+func TestWalletSvrAddressInfoResult(t *testing.T) {
+	t.Parallel()
+
+	marshalled := `{"address":"bc1q0ht9tyks4vh7p5p904t340cr9nvahy7u3re7zg",` +
+		`"scriptPubKey":"00147dd65592d0ab2fe0d0257d571abf032cd9db93dc",` +
+		`"desc":"wpkh([d34db33f/84'/0'/0'/0/0]03a34b99f22c790c4e36b2b3c2c3` +
+		`5a36db06226e41c692fc82b8b56ac1c540c5bd)#8zxhl7uy",` +
+		`"isscript":false,"ischange":false,"iswitness":true,` +
+		`"witness_version":0,"witness_program":"7dd65592d0ab2fe0d0257d571a` +
+		`bf032cd9db93dc","pubkey":"03a34b99f22c790c4e36b2b3c2c35a36db0622` +
+		`6e41c692fc82b8b56ac1c540c5bd","iscompressed":true,` +
+		`"hdmasterfingerprint":"d34db33f","labels":[""],"ismine":true,` +
+		`"iswatchonly":false,"solvable":true,"timestamp":1600000000,` +
+		`"hdkeypath":"m/84'/0'/0'/0/0","hdseedid":"a4a5ad4c3dc9d3b2b75c3e` +
+		`9d8b3c37a87fa3e4b2"}`
+
+	desc := "wpkh([d34db33f/84'/0'/0'/0/0]03a34b99f22c790c4e36b2b3c2c35a36" +
+		"db06226e41c692fc82b8b56ac1c540c5bd)#8zxhl7uy"
+	witnessVersion := int32(0)
+	witnessProgram := "7dd65592d0ab2fe0d0257d571abf032cd9db93dc"
+	pubKey := "03a34b99f22c790c4e36b2b3c2c35a36db06226e41c692fc82b8b56ac1c" +
+		"540c5bd"
+	isCompressed := true
+	fingerprint := "d34db33f"
+	timestamp := int64(1600000000)
+	keyPath := "m/84'/0'/0'/0/0"
+	seedID := "a4a5ad4c3dc9d3b2b75c3e9d8b3c37a87fa3e4b2"
+	expected := btcjson.GetAddressInfoResult{
+		EmbeddedAddressInfoResult: btcjson.EmbeddedAddressInfoResult{
+			Address:             "bc1q0ht9tyks4vh7p5p904t340cr9nvahy7u3re7zg",
+			ScriptPubKey:        "00147dd65592d0ab2fe0d0257d571abf032cd9db93dc",
+			Descriptor:          &desc,
+			IsWitness:           true,
+			WitnessVersion:      &witnessVersion,
+			WitnessProgram:      &witnessProgram,
+			PubKey:              &pubKey,
+			IsCompressed:        &isCompressed,
+			HDMasterFingerprint: &fingerprint,
+			Labels:              []string{""},
+		},
+		IsMine:    true,
+		Solvable:  true,
+		Timestamp: &timestamp,
+		HDKeyPath: &keyPath,
+		HDSeedID:  &seedID,
+	}
+
+	var result btcjson.GetAddressInfoResult
+	if err := json.Unmarshal([]byte(marshalled), &result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("unexpected unmarshalled result - got %+v, want %+v",
+			result, expected)
+	}
+
+	// Ensure the result round trips back to the same JSON, which also
+	// ensures fields that only apply to other address types are omitted.
+	remarshalled, err := json.Marshal(&result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(remarshalled) != marshalled {
+		t.Fatalf("unexpected marshalled data - got %s, want %s",
+			remarshalled, marshalled)
+	}
+}
+
+// TestWalletSvrMultisigResults ensures the createmultisig and
+// addmultisigaddress results unmarshal as expected, including the descriptor,
+// and round trip back to the same JSON.
+func TestWalletSvrMultisigResults(t *testing.T) {
+	t.Parallel()
+
+	const (
+		address      = "bbhd4dgz59DaLzkQRsJay1KVNPvU8g4FpD"
+		redeemScript = "52210279be667ef9dcbbac55a06295ce870b07029bfcdb2dc" +
+			"e28d959f2815b16f817982102f9308a019258c31049344f85f89d5229" +
+			"b531c845836f99b08601f113bce036f952ae"
+		descriptor = "sh(multi(2,0279be667ef9dcbbac55a06295ce870b07029bf" +
+			"cdb2dce28d959f2815b16f81798,02f9308a019258c31049344f85f89" +
+			"d5229b531c845836f99b08601f113bce036f9))#hlfqt9ga"
+	)
+	marshalled := `{"address":"` + address + `","redeemScript":"` +
+		redeemScript + `","descriptor":"` + descriptor + `"}`
+
+	tests := []struct {
+		name     string
+		result   interface{}
+		expected interface{}
+	}{
+		{
+			name:   "createmultisig",
+			result: &btcjson.CreateMultiSigResult{},
+			expected: &btcjson.CreateMultiSigResult{
+				Address:      address,
+				RedeemScript: redeemScript,
+				Descriptor:   descriptor,
+			},
+		},
+		{
+			name:   "addmultisigaddress",
+			result: &btcjson.AddMultisigAddressResult{},
+			expected: &btcjson.AddMultisigAddressResult{
+				Address:      address,
+				RedeemScript: redeemScript,
+				Descriptor:   descriptor,
+			},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		err := json.Unmarshal([]byte(marshalled), test.result)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(test.result, test.expected) {
+			t.Errorf("Test #%d (%s) unexpected unmarshalled result "+
+				"- got %+v, want %+v", i, test.name, test.result,
+				test.expected)
+			continue
+		}
+
+		// Ensure the result round trips back to the same JSON.
+		remarshalled, err := json.Marshal(test.result)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if string(remarshalled) != marshalled {
+			t.Errorf("Test #%d (%s) unexpected marshalled data - "+
+				"got %s, want %s", i, test.name, remarshalled,
+				marshalled)
+			continue
+		}
+	}
+}
@@ -205,6 +205,10 @@ type Params struct {
 	// Mempool parameters
 	RelayNonStdTxs bool
 
+	// MessageMagic is the prefix hashed ahead of messages signed by the
+	// signmessage RPC so signatures can't be replayed as transactions.
+	MessageMagic string
+
 	// Human-readable part for Bech32 encoded segwit addresses, as defined
 	// in BIP 173.
 	Bech32HRPSegwit string
@@ -302,6 +306,9 @@ var MainNetParams = Params{
 	// Mempool parameters
 	RelayNonStdTxs: false,
 
+	// Signed message magic
+	MessageMagic: "NavCoin Signed Message:\n",
+
 	// Human-readable part for Bech32 encoded segwit addresses, as defined in
 	// BIP 173.
 	Bech32HRPSegwit: "nav", // always bc for main net
@@ -378,6 +385,9 @@ var RegressionNetParams = Params{
 	// Mempool parameters
 	RelayNonStdTxs: true,
 
+	// Signed message magic
+	MessageMagic: "NavCoin Signed Message:\n",
+
 	// Human-readable part for Bech32 encoded segwit addresses, as defined in
 	// BIP 173.
 	Bech32HRPSegwit: "tb", // always tb for test net
@@ -461,6 +471,9 @@ var TestNet3Params = Params{
 	// Mempool parameters
 	RelayNonStdTxs: true,
 
+	// Signed message magic
+	MessageMagic: "NavCoin Signed Message:\n",
+
 	// Human-readable part for Bech32 encoded segwit addresses, as defined in
 	// BIP 173.
 	Bech32HRPSegwit: "tb", // always tb for test net
@@ -541,6 +554,9 @@ var SimNetParams = Params{
 	// Mempool parameters
 	RelayNonStdTxs: true,
 
+	// Signed message magic
+	MessageMagic: "NavCoin Signed Message:\n",
+
 	// Human-readable part for Bech32 encoded segwit addresses, as defined in
 	// BIP 173.
 	Bech32HRPSegwit: "sb", // always sb for sim net
@@ -0,0 +1,177 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package coinselect
+
+import (
+	"math"
+	"sort"
+
+	"github.com/navcoin/navd/wire"
+)
+
+const (
+	// MaxBnBIterations is the maximum number of branches the branch and
+	// bound search will visit before giving up.
+	MaxBnBIterations = 100000
+
+	// ChangeOutputSize is the size in bytes of a pay-to-witness-pubkey-hash
+	// change output.  It is 8 bytes for the value, 1 byte for the script
+	// length and 22 bytes for the script itself.
+	ChangeOutputSize = 31
+
+	// ChangeSpendSize is the virtual size in bytes of an input spending a
+	// pay-to-witness-pubkey-hash change output.
+	ChangeSpendSize = 68
+)
+
+// UTXO describes an unspent transaction output available for selection.
+type UTXO struct {
+	// OutPoint is the outpoint which identifies the output.
+	OutPoint wire.OutPoint
+
+	// Value is the value of the output in satoshi.
+	Value int64
+
+	// SpendSize is the virtual size in bytes of the input required to
+	// spend the output.
+	SpendSize int64
+}
+
+// effectiveValue returns the value of the output less the fee, in satoshi,
+// required to spend it at the passed fee rate in satoshi per kilobyte.
+func (u *UTXO) effectiveValue(feeRate int64) int64 {
+	return u.Value - u.SpendSize*feeRate/1000
+}
+
+// costOfChange returns the fee, in satoshi, required to create a change
+// output and later spend it at the passed fee rate in satoshi per kilobyte.
+func costOfChange(feeRate int64) int64 {
+	return (ChangeOutputSize + ChangeSpendSize) * feeRate / 1000
+}
+
+// SelectCoinsBnB selects a subset of the passed outputs whose total effective
+// value, at the given fee rate in satoshi per kilobyte, is at least the target
+// but exceeds it by no more than the cost of creating and spending a change
+// output.  Such a subset funds a transaction paying target satoshi (including
+// the fee for everything other than the selected inputs) without requiring a
+// change output.
+//
+// The effective value of each output is its value less the fee required to
+// spend it, and outputs with no positive effective value are never selected.
+// Among the solutions found, the one with the least excess over the target is
+// returned.  The search is deterministic for a given set of outputs and is
+// bounded by MaxBnBIterations.
+//
+// The returned bool is false when no changeless solution was found, in which
+// case the caller should fall back to a selection strategy which creates
+// change.
+func SelectCoinsBnB(utxos []UTXO, target, feeRate int64) ([]UTXO, bool) {
+	return selectCoinsBnB(utxos, target, feeRate, MaxBnBIterations)
+}
+
+// selectCoinsBnB implements SelectCoinsBnB with a configurable bound on the
+// number of iterations.
+func selectCoinsBnB(utxos []UTXO, target, feeRate int64, maxIterations int) ([]UTXO, bool) {
+	if target <= 0 {
+		return nil, false
+	}
+
+	// Only consider outputs which contribute positive effective value and
+	// visit them from largest to smallest so the search reaches the target
+	// quickly.  A stable sort keeps the result deterministic.
+	type candidate struct {
+		utxo  UTXO
+		value int64
+	}
+	candidates := make([]candidate, 0, len(utxos))
+	var available int64
+	for _, utxo := range utxos {
+		value := utxo.effectiveValue(feeRate)
+		if value <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{utxo, value})
+		available += value
+	}
+	if available < target {
+		return nil, false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].value > candidates[j].value
+	})
+
+	// Perform a depth-first search where each level decides whether the
+	// candidate at that index is included.  The inclusion branch is
+	// always explored first.
+	upperBound := target + costOfChange(feeRate)
+	selection := make([]bool, 0, len(candidates))
+	var selected, remaining int64 = 0, available
+	var best []bool
+	bestExcess := int64(math.MaxInt64)
+	for i := 0; i < maxIterations; i++ {
+		backtrack := false
+		switch {
+		// The current branch either can no longer reach the target or
+		// already overshoots the window.
+		case selected+remaining < target || selected > upperBound:
+			backtrack = true
+
+		// The current branch is a solution, so record it when it has
+		// less excess than the best one seen so far.
+		case selected >= target:
+			if excess := selected - target; excess < bestExcess {
+				best = append(best[:0], selection...)
+				bestExcess = excess
+			}
+			backtrack = true
+		}
+
+		// An exact match can't be improved upon.
+		if bestExcess == 0 {
+			break
+		}
+
+		if backtrack {
+			// Walk back to the most recently included candidate
+			// and explore the branch which excludes it instead.
+			for len(selection) > 0 && !selection[len(selection)-1] {
+				remaining += candidates[len(selection)-1].value
+				selection = selection[:len(selection)-1]
+			}
+			if len(selection) == 0 {
+				break
+			}
+			idx := len(selection) - 1
+			selection[idx] = false
+			selected -= candidates[idx].value
+			continue
+		}
+
+		// Include the next candidate unless the previous candidate has
+		// the same effective value and was excluded, since that branch
+		// is equivalent to one which has already been explored.
+		idx := len(selection)
+		remaining -= candidates[idx].value
+		if idx > 0 && !selection[idx-1] &&
+			candidates[idx].value == candidates[idx-1].value {
+
+			selection = append(selection, false)
+			continue
+		}
+		selection = append(selection, true)
+		selected += candidates[idx].value
+	}
+
+	if best == nil {
+		return nil, false
+	}
+	result := make([]UTXO, 0, len(best))
+	for i, include := range best {
+		if include {
+			result = append(result, candidates[i].utxo)
+		}
+	}
+	return result, true
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package coinselect
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/navcoin/navd/wire"
+)
+
+// testUTXOs returns outputs with the passed values which each cost 68 bytes to
+// spend.  The outpoint index of each output is its position in the list.
+func testUTXOs(values ...int64) []UTXO {
+	utxos := make([]UTXO, 0, len(values))
+	for i, value := range values {
+		utxos = append(utxos, UTXO{
+			OutPoint:  wire.OutPoint{Index: uint32(i)},
+			Value:     value,
+			SpendSize: 68,
+		})
+	}
+	return utxos
+}
+
+// TestSelectCoinsBnB ensures the branch and bound selection finds changeless
+// solutions based on effective values and reports when none exist.
+func TestSelectCoinsBnB(t *testing.T) {
+	t.Parallel()
+
+	// At a fee rate of 1000 satoshi per kilobyte each test output costs 68
+	// satoshi to spend and the cost of change is 99 satoshi.
+	const feeRate = 1000
+
+	tests := []struct {
+		name    string
+		utxos   []UTXO
+		target  int64
+		feeRate int64
+		want    []uint32 // outpoint indexes of the selected outputs
+		found   bool
+	}{
+		{
+			name:    "exact match",
+			utxos:   testUTXOs(1068, 2068, 3068, 4068),
+			target:  5000,
+			feeRate: feeRate,
+			want:    []uint32{3, 0},
+			found:   true,
+		},
+		{
+			name:    "match within cost of change",
+			utxos:   testUTXOs(1068, 2068, 3068, 4068),
+			target:  4950,
+			feeRate: feeRate,
+			want:    []uint32{3, 0},
+			found:   true,
+		},
+		{
+			name:    "single output",
+			utxos:   testUTXOs(1068, 2068, 3068, 4068),
+			target:  3000,
+			feeRate: feeRate,
+			want:    []uint32{2},
+			found:   true,
+		},
+		{
+			name:    "no match",
+			utxos:   testUTXOs(1068, 2068, 3068, 4068),
+			target:  5500,
+			feeRate: feeRate,
+			found:   false,
+		},
+		{
+			name:    "insufficient funds",
+			utxos:   testUTXOs(1068, 2068, 3068, 4068),
+			target:  20000,
+			feeRate: feeRate,
+			found:   false,
+		},
+		{
+			name:    "raw value matches but effective value does not",
+			utxos:   testUTXOs(5000),
+			target:  5000,
+			feeRate: feeRate,
+			found:   false,
+		},
+		{
+			name:    "raw value matches at zero fee rate",
+			utxos:   testUTXOs(5000),
+			target:  5000,
+			feeRate: 0,
+			want:    []uint32{0},
+			found:   true,
+		},
+		{
+			name:    "uneconomical outputs skipped",
+			utxos:   testUTXOs(50, 68, 2068, 3068),
+			target:  5000,
+			feeRate: feeRate,
+			want:    []uint32{3, 2},
+			found:   true,
+		},
+		{
+			name:    "duplicate values",
+			utxos:   testUTXOs(1068, 1068, 1068, 1068, 1068),
+			target:  3000,
+			feeRate: feeRate,
+			want:    []uint32{0, 1, 2},
+			found:   true,
+		},
+		{
+			name:    "zero target",
+			utxos:   testUTXOs(1068),
+			target:  0,
+			feeRate: feeRate,
+			found:   false,
+		},
+		{
+			name:    "no outputs",
+			utxos:   nil,
+			target:  1000,
+			feeRate: feeRate,
+			found:   false,
+		},
+	}
+
+	for _, test := range tests {
+		selected, found := SelectCoinsBnB(test.utxos, test.target,
+			test.feeRate)
+		if found != test.found {
+			t.Errorf("%s: unexpected found flag - got %v, want %v",
+				test.name, found, test.found)
+			continue
+		}
+		if !found {
+			if selected != nil {
+				t.Errorf("%s: unexpected selection %v",
+					test.name, selected)
+			}
+			continue
+		}
+
+		var got []uint32
+		var total int64
+		for _, utxo := range selected {
+			got = append(got, utxo.OutPoint.Index)
+			total += utxo.effectiveValue(test.feeRate)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: unexpected selection - got %v, want %v",
+				test.name, got, test.want)
+			continue
+		}
+		upperBound := test.target + costOfChange(test.feeRate)
+		if total < test.target || total > upperBound {
+			t.Errorf("%s: selected effective value %d outside of "+
+				"[%d, %d]", test.name, total, test.target,
+				upperBound)
+			continue
+		}
+	}
+}
+
+// TestSelectCoinsBnBIterationBound ensures the search gives up once the
+// iteration bound is reached rather than exhaustively exploring every subset.
+func TestSelectCoinsBnBIterationBound(t *testing.T) {
+	t.Parallel()
+
+	// The only solution for the target is the two smallest values, which
+	// are visited last since larger values are explored first.
+	const feeRate = 0
+	const target = 3
+	values := []int64{1}
+	for i := int64(1); i <= 20; i++ {
+		values = append(values, i*2)
+	}
+	utxos := testUTXOs(values...)
+
+	if _, found := selectCoinsBnB(utxos, target, feeRate, 10); found {
+		t.Fatal("unexpected solution found within iteration bound")
+	}
+	selected, found := SelectCoinsBnB(utxos, target, feeRate)
+	if !found {
+		t.Fatal("expected solution not found")
+	}
+	want := []UTXO{utxos[1], utxos[0]}
+	if !reflect.DeepEqual(selected, want) {
+		t.Fatalf("unexpected selection - got %v, want %v", selected,
+			want)
+	}
+
+	// Ensure the full search terminates without a solution when every
+	// value is even and the target is odd.
+	if _, found := SelectCoinsBnB(utxos[1:], 201, feeRate); found {
+		t.Fatal("unexpected solution found for odd target")
+	}
+}
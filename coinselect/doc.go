@@ -0,0 +1,23 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package coinselect provides deterministic coin selection algorithms for
+choosing which unspent transaction outputs fund a transaction.
+
+SelectCoinsBnB implements the branch and bound algorithm which performs a
+depth-first search over the available outputs for a subset whose effective
+value lands within a small window above the target.  Since the excess within
+that window is less than the cost of creating and later spending a change
+output, such a subset can be spent without a change output at all, which
+minimizes the fees paid over the lifetime of the wallet.
+
+The effective value of an output is its value less the fee required to spend
+it at the requested fee rate, so outputs which cost more to spend than they are
+worth are never selected.  The search is bounded by a maximum number of
+iterations to avoid the exponential worst case.  When no changeless solution
+is found, callers are expected to fall back to another selection strategy that
+creates change.
+*/
+package coinselect
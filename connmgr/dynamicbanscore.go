@@ -98,6 +98,14 @@ func (s *DynamicBanScore) Increase(persistent, transient uint32) uint32 {
 	return r
 }
 
+// Exceeds returns whether the current ban score is above the passed threshold,
+// which indicates the peer has misbehaved enough to be banned.
+//
+// This function is safe for concurrent access.
+func (s *DynamicBanScore) Exceeds(threshold uint32) bool {
+	return s.Int() > threshold
+}
+
 // Reset set both persistent and decaying scores to zero.
 //
 // This function is safe for concurrent access.
@@ -119,7 +127,7 @@ func (s *DynamicBanScore) int(t time.Time) uint32 {
 	if s.transient < 1 || dt < 0 || Lifetime < dt {
 		return s.persistent
 	}
-	return s.persistent + uint32(s.transient*decayFactor(dt))
+	return clampedScore(s.persistent, s.transient*decayFactor(dt))
 }
 
 // increase increases the persistent, the decaying or both scores by the values
@@ -129,7 +137,12 @@ func (s *DynamicBanScore) int(t time.Time) uint32 {
 //
 // This function is not safe for concurrent access.
 func (s *DynamicBanScore) increase(persistent, transient uint32, t time.Time) uint32 {
-	s.persistent += persistent
+	// Saturate rather than wrap the persistent score on overflow.
+	if persistent > math.MaxUint32-s.persistent {
+		s.persistent = math.MaxUint32
+	} else {
+		s.persistent += persistent
+	}
 	tu := t.Unix()
 	dt := tu - s.lastUnix
 
@@ -142,5 +155,16 @@ func (s *DynamicBanScore) increase(persistent, transient uint32, t time.Time) ui
 		s.transient += float64(transient)
 		s.lastUnix = tu
 	}
-	return s.persistent + uint32(s.transient)
+	return clampedScore(s.persistent, s.transient)
+}
+
+// clampedScore returns the sum of the passed persistent and transient scores
+// clamped to the maximum value of a uint32 so large scores never wrap around
+// to small ones.
+func clampedScore(persistent uint32, transient float64) uint32 {
+	score := float64(persistent) + transient
+	if score >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(score)
 }
@@ -66,3 +66,59 @@ func TestDynamicBanScoreReset(t *testing.T) {
 		t.Errorf("Failed to reset ban score.")
 	}
 }
+
+// TestDynamicBanScoreOverflow tests that DynamicBanScore clamps scores at the
+// maximum uint32 value rather than wrapping around.
+func TestDynamicBanScoreOverflow(t *testing.T) {
+	var bs DynamicBanScore
+	base := time.Now()
+
+	r := bs.increase(math.MaxUint32-10, 0, base)
+	if r != math.MaxUint32-10 {
+		t.Errorf("Unexpected result %d after ban score increase.", r)
+	}
+	r = bs.increase(100, 0, base)
+	if r != math.MaxUint32 {
+		t.Errorf("Persistent overflow not clamped - %d", r)
+	}
+	r = bs.increase(0, 100, base)
+	if r != math.MaxUint32 {
+		t.Errorf("Transient overflow not clamped - %d", r)
+	}
+	r = bs.int(base.Add(time.Minute))
+	if r != math.MaxUint32 {
+		t.Errorf("Decayed score overflow not clamped - %d", r)
+	}
+}
+
+// TestDynamicBanScoreThreshold tests that crossing the ban threshold is
+// reported and that decay brings the score back below it.
+func TestDynamicBanScoreThreshold(t *testing.T) {
+	const threshold = 100
+
+	var bs DynamicBanScore
+	if bs.Exceeds(threshold) {
+		t.Errorf("Initial state exceeds threshold.")
+	}
+	bs.Increase(threshold, 0)
+	if bs.Exceeds(threshold) {
+		t.Errorf("Score equal to the threshold reported as exceeding it.")
+	}
+	bs.Increase(1, 0)
+	if !bs.Exceeds(threshold) {
+		t.Errorf("Score above the threshold not reported.")
+	}
+
+	// Simulate the clock to ensure a transient score over the threshold
+	// decays back below it after a halflife.
+	bs.Reset()
+	base := time.Now()
+	r := bs.increase(50, 60, base)
+	if r <= threshold {
+		t.Errorf("Unexpected result %d after ban score increase.", r)
+	}
+	r = bs.int(base.Add(Halflife * time.Second))
+	if r != 80 {
+		t.Errorf("Decay after halflife - %d instead of 80", r)
+	}
+}
@@ -0,0 +1,472 @@
+// Copyright (c) 2014-2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hdkeychain
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg"
+)
+
+// TestBIP0032Vectors tests the vectors provided by [BIP32] to ensure the
+// derivation works as intended.
+func TestBIP0032Vectors(t *testing.T) {
+	// The master seeds for each of the four test vectors in [BIP32].
+	testVec1MasterHex := "000102030405060708090a0b0c0d0e0f"
+	testVec2MasterHex := "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542"
+	testVec3MasterHex := "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be"
+	testVec4MasterHex := "3ddd5602285899a946114506157c7997e5444528f3003f6134712147db19b678"
+	hkStart := uint32(0x80000000)
+
+	tests := []struct {
+		name     string
+		master   string
+		path     []uint32
+		wantPub  string
+		wantPriv string
+	}{
+		// Test vector 1
+		{
+			name:     "test vector 1 chain m",
+			master:   testVec1MasterHex,
+			path:     []uint32{},
+			wantPub:  "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+			wantPriv: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+		},
+		{
+			name:     "test vector 1 chain m/0H",
+			master:   testVec1MasterHex,
+			path:     []uint32{hkStart},
+			wantPub:  "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+			wantPriv: "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+		},
+		{
+			name:     "test vector 1 chain m/0H/1",
+			master:   testVec1MasterHex,
+			path:     []uint32{hkStart, 1},
+			wantPub:  "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+			wantPriv: "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+		},
+		{
+			name:     "test vector 1 chain m/0H/1/2H",
+			master:   testVec1MasterHex,
+			path:     []uint32{hkStart, 1, hkStart + 2},
+			wantPub:  "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+			wantPriv: "xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+		},
+		{
+			name:     "test vector 1 chain m/0H/1/2H/2",
+			master:   testVec1MasterHex,
+			path:     []uint32{hkStart, 1, hkStart + 2, 2},
+			wantPub:  "xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+			wantPriv: "xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+		},
+		{
+			name:     "test vector 1 chain m/0H/1/2H/2/1000000000",
+			master:   testVec1MasterHex,
+			path:     []uint32{hkStart, 1, hkStart + 2, 2, 1000000000},
+			wantPub:  "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+			wantPriv: "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+		},
+
+		// Test vector 2
+		{
+			name:     "test vector 2 chain m",
+			master:   testVec2MasterHex,
+			path:     []uint32{},
+			wantPub:  "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+			wantPriv: "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+		},
+		{
+			name:     "test vector 2 chain m/0",
+			master:   testVec2MasterHex,
+			path:     []uint32{0},
+			wantPub:  "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+			wantPriv: "xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt",
+		},
+		{
+			name:     "test vector 2 chain m/0/2147483647H",
+			master:   testVec2MasterHex,
+			path:     []uint32{0, hkStart + 2147483647},
+			wantPub:  "xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+			wantPriv: "xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9",
+		},
+		{
+			name:     "test vector 2 chain m/0/2147483647H/1",
+			master:   testVec2MasterHex,
+			path:     []uint32{0, hkStart + 2147483647, 1},
+			wantPub:  "xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+			wantPriv: "xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef",
+		},
+		{
+			name:     "test vector 2 chain m/0/2147483647H/1/2147483646H",
+			master:   testVec2MasterHex,
+			path:     []uint32{0, hkStart + 2147483647, 1, hkStart + 2147483646},
+			wantPub:  "xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+			wantPriv: "xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc",
+		},
+		{
+			name:     "test vector 2 chain m/0/2147483647H/1/2147483646H/2",
+			master:   testVec2MasterHex,
+			path:     []uint32{0, hkStart + 2147483647, 1, hkStart + 2147483646, 2},
+			wantPub:  "xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+			wantPriv: "xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+		},
+
+		// Test vector 3
+		{
+			name:     "test vector 3 chain m",
+			master:   testVec3MasterHex,
+			path:     []uint32{},
+			wantPub:  "xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+			wantPriv: "xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6",
+		},
+		{
+			name:     "test vector 3 chain m/0H",
+			master:   testVec3MasterHex,
+			path:     []uint32{hkStart},
+			wantPub:  "xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+			wantPriv: "xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+		},
+
+		// Test vector 4
+		{
+			name:     "test vector 4 chain m",
+			master:   testVec4MasterHex,
+			path:     []uint32{},
+			wantPub:  "xpub661MyMwAqRbcGczjuMoRm6dXaLDEhW1u34gKenbeYqAix21mdUKJyuyu5F1rzYGVxyL6tmgBUAEPrEz92mBXjByMRiJdba9wpnN37RLLAXa",
+			wantPriv: "xprv9s21ZrQH143K48vGoLGRPxgo2JNkJ3J3fqkirQC2zVdk5Dgd5w14S7fRDyHH4dWNHUgkvsvNDCkvAwcSHNAQwhwgNMgZhLtQC63zxwhQmRv",
+		},
+		{
+			name:     "test vector 4 chain m/0H",
+			master:   testVec4MasterHex,
+			path:     []uint32{hkStart},
+			wantPub:  "xpub69AUMk3qDBi3uW1sXgjCmVjJ2G6WQoYSnNHyzkmdCHEhSZ4tBok37xfFEqHd2AddP56Tqp4o56AePAgCjYdvpW2PU2jbUPFKsav5ut6Ch1m",
+			wantPriv: "xprv9vB7xEWwNp9kh1wQRfCCQMnZUEG21LpbR9NPCNN1dwhiZkjjeGRnaALmPXCX7SgjFTiCTT6bXes17boXtjq3xLpcDjzEuGLQBM5ohqkao9G",
+		},
+		{
+			name:     "test vector 4 chain m/0H/1H",
+			master:   testVec4MasterHex,
+			path:     []uint32{hkStart, hkStart + 1},
+			wantPub:  "xpub6BJA1jSqiukeaesWfxe6sNK9CCGaujFFSJLomWHprUL9DePQ4JDkM5d88n49sMGJxrhpjazuXYWdMf17C9T5XnxkopaeS7jGk1GyyVziaMt",
+			wantPriv: "xprv9xJocDuwtYCMNAo3Zw76WENQeAS6WGXQ55RCy7tDJ8oALr4FWkuVoHJeHVAcAqiZLE7Je3vZJHxspZdFHfnBEjHqU5hG1Jaj32dVoS6XLT1",
+		},
+	}
+
+tests:
+	for i, test := range tests {
+		masterSeed, err := hex.DecodeString(test.master)
+		if err != nil {
+			t.Errorf("DecodeString #%d (%s): unexpected error: %v",
+				i, test.name, err)
+			continue
+		}
+
+		extKey, err := NewMaster(masterSeed, &chaincfg.MainNetParams)
+		if err != nil {
+			t.Errorf("NewMaster #%d (%s): unexpected error when "+
+				"creating new master key: %v", i, test.name,
+				err)
+			continue
+		}
+
+		for _, childNum := range test.path {
+			var err error
+			extKey, err = extKey.Child(childNum)
+			if err != nil {
+				t.Errorf("err: %v", err)
+				continue tests
+			}
+		}
+
+		if extKey.Depth() != uint8(len(test.path)) {
+			t.Errorf("Depth of key %d should match fixture path: %v",
+				extKey.Depth(), len(test.path))
+			continue
+		}
+
+		privStr := extKey.String()
+		if privStr != test.wantPriv {
+			t.Errorf("Serialize #%d (%s): mismatched serialized "+
+				"private extended key -- got: %s, want: %s", i,
+				test.name, privStr, test.wantPriv)
+			continue
+		}
+
+		pubKey, err := extKey.Neuter()
+		if err != nil {
+			t.Errorf("Neuter #%d (%s): unexpected error: %v ", i,
+				test.name, err)
+			continue
+		}
+
+		// Neutering a second time should have no effect.
+		pubKey, err = pubKey.Neuter()
+		if err != nil {
+			t.Errorf("Neuter #%d (%s): unexpected error: %v", i,
+				test.name, err)
+			return
+		}
+
+		pubStr := pubKey.String()
+		if pubStr != test.wantPub {
+			t.Errorf("Neuter #%d (%s): mismatched serialized "+
+				"public extended key -- got: %s, want: %s", i,
+				test.name, pubStr, test.wantPub)
+			continue
+		}
+	}
+}
+
+// TestPublicDerivation tests several vectors which derive public keys from
+// other public keys works as intended.
+func TestPublicDerivation(t *testing.T) {
+	// The public extended keys for test vectors in [BIP32].
+	testVec1MasterPubKey := "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8"
+	testVec2MasterPubKey := "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB"
+
+	tests := []struct {
+		name    string
+		master  string
+		path    []uint32
+		wantPub string
+	}{
+		{
+			name:    "test vector 1 chain m",
+			master:  testVec1MasterPubKey,
+			path:    []uint32{},
+			wantPub: "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		},
+		{
+			name:    "test vector 2 chain m/0",
+			master:  testVec2MasterPubKey,
+			path:    []uint32{0},
+			wantPub: "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+		},
+	}
+
+tests:
+	for i, test := range tests {
+		extKey, err := NewKeyFromString(test.master)
+		if err != nil {
+			t.Errorf("NewKeyFromString #%d (%s): unexpected error "+
+				"creating extended key: %v", i, test.name,
+				err)
+			continue
+		}
+
+		for _, childNum := range test.path {
+			var err error
+			extKey, err = extKey.Child(childNum)
+			if err != nil {
+				t.Errorf("err: %v", err)
+				continue tests
+			}
+		}
+
+		pubStr := extKey.String()
+		if pubStr != test.wantPub {
+			t.Errorf("Child #%d (%s): mismatched serialized "+
+				"public extended key -- got: %s, want: %s", i,
+				test.name, pubStr, test.wantPub)
+			continue
+		}
+	}
+}
+
+// TestExtendedKeyAPI ensures the API on the ExtendedKey type works as intended.
+func TestExtendedKeyAPI(t *testing.T) {
+	tests := []struct {
+		name       string
+		extKey     string
+		isPrivate  bool
+		parentFP   uint32
+		childIndex uint32
+		privKeyErr error
+		pubKey     string
+	}{
+		{
+			name:      "test vector 1 master node private",
+			extKey:    "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+			isPrivate: true,
+			parentFP:  0,
+			pubKey:    "0339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2",
+		},
+		{
+			name:       "test vector 1 chain m/0H/1/2H public",
+			extKey:     "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+			isPrivate:  false,
+			parentFP:   3203769081,
+			childIndex: HardenedKeyStart + 2,
+			privKeyErr: ErrNotPrivExtKey,
+			pubKey:     "0357bfe1e341d01c69fe5654309956cbea516822fba8a601743a012a7896ee8dc2",
+		},
+	}
+
+	for i, test := range tests {
+		key, err := NewKeyFromString(test.extKey)
+		if err != nil {
+			t.Errorf("NewKeyFromString #%d (%s): unexpected "+
+				"error: %v", i, test.name, err)
+			continue
+		}
+
+		if key.IsPrivate() != test.isPrivate {
+			t.Errorf("IsPrivate #%d (%s): mismatched key type -- "+
+				"want private %v, is private %v", i, test.name,
+				test.isPrivate, key.IsPrivate())
+			continue
+		}
+
+		parentFP := key.ParentFingerprint()
+		if parentFP != test.parentFP {
+			t.Errorf("ParentFingerprint #%d (%s): mismatched "+
+				"parent fingerprint -- want %d, got %d", i,
+				test.name, test.parentFP, parentFP)
+			continue
+		}
+
+		if key.ChildIndex() != test.childIndex {
+			t.Errorf("ChildIndex #%d (%s): mismatched child "+
+				"index -- want %d, got %d", i, test.name,
+				test.childIndex, key.ChildIndex())
+			continue
+		}
+
+		serializedKey := key.String()
+		if serializedKey != test.extKey {
+			t.Errorf("String #%d (%s): mismatched serialized key "+
+				"-- want %s, got %s", i, test.name, test.extKey,
+				serializedKey)
+			continue
+		}
+
+		_, err = key.ECPrivKey()
+		if err != test.privKeyErr {
+			t.Errorf("ECPrivKey #%d (%s): mismatched error: want "+
+				"%v, got %v", i, test.name, test.privKeyErr, err)
+			continue
+		}
+
+		pubKey, err := key.ECPubKey()
+		if err != nil {
+			t.Errorf("ECPubKey #%d (%s): unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		pubKeyStr := hex.EncodeToString(pubKey.SerializeCompressed())
+		if pubKeyStr != test.pubKey {
+			t.Errorf("ECPubKey #%d (%s): mismatched public key -- "+
+				"want %s, got %s", i, test.name, test.pubKey,
+				pubKeyStr)
+			continue
+		}
+	}
+}
+
+// TestErrors performs some negative tests for various invalid cases to ensure
+// the errors are handled properly.
+func TestErrors(t *testing.T) {
+	// Should get an error when seed has too few bytes.
+	net := &chaincfg.MainNetParams
+	_, err := NewMaster(make([]byte, MinSeedBytes-1), net)
+	if err != ErrInvalidSeedLen {
+		t.Fatalf("NewMaster: mismatched error -- got: %v, want: %v",
+			err, ErrInvalidSeedLen)
+	}
+
+	// Should get an error when seed has too many bytes.
+	_, err = NewMaster(make([]byte, MaxSeedBytes+1), net)
+	if err != ErrInvalidSeedLen {
+		t.Fatalf("NewMaster: mismatched error -- got: %v, want: %v",
+			err, ErrInvalidSeedLen)
+	}
+
+	// Generate a new key and neuter it to a public extended key.
+	seed, err := GenerateSeed(RecommendedSeedLen)
+	if err != nil {
+		t.Fatalf("GenerateSeed: unexpected error: %v", err)
+	}
+	extKey, err := NewMaster(seed, net)
+	if err != nil {
+		t.Fatalf("NewMaster: unexpected error: %v", err)
+	}
+	pubKey, err := extKey.Neuter()
+	if err != nil {
+		t.Fatalf("Neuter: unexpected error: %v", err)
+	}
+
+	// Deriving a hardened child extended key should fail from a public key.
+	_, err = pubKey.Child(HardenedKeyStart)
+	if err != ErrDeriveHardFromPublic {
+		t.Fatalf("Child: mismatched error -- got: %v, want: %v",
+			err, ErrDeriveHardFromPublic)
+	}
+
+	// NewKeyFromString failure tests.
+	tests := []struct {
+		name string
+		key  string
+		err  error
+	}{
+		{
+			name: "invalid key length",
+			key:  "xpub1234",
+			err:  ErrInvalidKeyLen,
+		},
+		{
+			name: "bad checksum",
+			key:  "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EBygr15",
+			err:  ErrBadChecksum,
+		},
+	}
+
+	for i, test := range tests {
+		_, err := NewKeyFromString(test.key)
+		if err != test.err {
+			t.Errorf("NewKeyFromString #%d (%s): mismatched error "+
+				"-- got: %v, want: %v", i, test.name, err,
+				test.err)
+			continue
+		}
+	}
+}
+
+// TestNet ensures the network related APIs work as intended.
+func TestNet(t *testing.T) {
+	extKey, err := NewKeyFromString("xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi")
+	if err != nil {
+		t.Fatalf("NewKeyFromString: unexpected error: %v", err)
+	}
+
+	if !extKey.IsForNet(&chaincfg.MainNetParams) {
+		t.Fatalf("IsForNet: key is not for main net")
+	}
+	if extKey.IsForNet(&chaincfg.SimNetParams) {
+		t.Fatalf("IsForNet: key is unexpectedly for simnet")
+	}
+
+	// Switching the network should be reflected in the serialized key and
+	// carried through to derived children.
+	extKey.SetNet(&chaincfg.SimNetParams)
+	if !extKey.IsForNet(&chaincfg.SimNetParams) {
+		t.Fatalf("SetNet: key is not for simnet")
+	}
+	child, err := extKey.Child(0)
+	if err != nil {
+		t.Fatalf("Child: unexpected error: %v", err)
+	}
+	if !child.IsForNet(&chaincfg.SimNetParams) {
+		t.Fatalf("Child: derived key is not for simnet")
+	}
+	pubKey, err := child.Neuter()
+	if err != nil {
+		t.Fatalf("Neuter: unexpected error: %v", err)
+	}
+	if !pubKey.IsForNet(&chaincfg.SimNetParams) {
+		t.Fatalf("Neuter: public key is not for simnet")
+	}
+}
@@ -133,6 +133,24 @@ type Policy struct {
 	// MinRelayTxFee defines the minimum transaction fee in BTC/kB to be
 	// considered a non-zero fee.
 	MinRelayTxFee navutil.Amount
+
+	// DustRelayFee defines the fee rate in BTC/kB used to determine
+	// whether an output is dust.  MinRelayTxFee is used when it is zero.
+	DustRelayFee navutil.Amount
+
+	// MaxStandardTxWeight is the maximum weight of a transaction for it
+	// to be considered standard.  A default of 400000 is used when it is
+	// zero.
+	MaxStandardTxWeight int64
+
+	// MaxDataCarrierSize is the maximum number of bytes a null data output
+	// may carry for it to be considered standard.  It may not exceed
+	// txscript.MaxDataCarrierSize, which is used when it is zero.
+	MaxDataCarrierSize int
+
+	// RejectBareMultisig defines whether to reject transactions with bare
+	// multi-signature outputs as non-standard.
+	RejectBareMultisig bool
 }
 
 // TxDesc is a descriptor containing a transaction in the mempool along with
@@ -679,8 +697,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *navutil.Tx, isNew, rateLimit, rejec
 	// forbid their acceptance.
 	if !mp.cfg.Policy.AcceptNonStd {
 		err = checkTransactionStandard(tx, nextBlockHeight,
-			medianTimePast, mp.cfg.Policy.MinRelayTxFee,
-			mp.cfg.Policy.MaxTxVersion)
+			medianTimePast, &mp.cfg.Policy)
 		if err != nil {
 			// Attempt to extract a reject code from the error so
 			// it can be retained.  When not possible, fall back to
@@ -1149,6 +1166,67 @@ func (mp *TxPool) MiningDescs() []*mining.TxDesc {
 	return descs
 }
 
+// txAncestors returns the hashes of all of the transactions in the pool the
+// passed transaction depends on, either directly or indirectly.  The sets of
+// ancestors are memoized in the provided map so the ancestors of each
+// transaction are only determined once when called for many transactions.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) txAncestors(tx *navutil.Tx, memo map[chainhash.Hash]map[chainhash.Hash]struct{}) map[chainhash.Hash]struct{} {
+	if ancestors, ok := memo[*tx.Hash()]; ok {
+		return ancestors
+	}
+
+	ancestors := make(map[chainhash.Hash]struct{})
+	for _, txIn := range tx.MsgTx().TxIn {
+		hash := txIn.PreviousOutPoint.Hash
+		if _, exists := ancestors[hash]; exists {
+			continue
+		}
+		parent, exists := mp.pool[hash]
+		if !exists {
+			continue
+		}
+		ancestors[hash] = struct{}{}
+		for ancestor := range mp.txAncestors(parent.Tx, memo) {
+			ancestors[ancestor] = struct{}{}
+		}
+	}
+	memo[*tx.Hash()] = ancestors
+	return ancestors
+}
+
+// txDescendants returns the hashes of all of the transactions in the pool that
+// depend on the passed transaction, either directly or indirectly.  The sets of
+// descendants are memoized in the provided map so the descendants of each
+// transaction are only determined once when called for many transactions.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) txDescendants(tx *navutil.Tx, memo map[chainhash.Hash]map[chainhash.Hash]struct{}) map[chainhash.Hash]struct{} {
+	if descendants, ok := memo[*tx.Hash()]; ok {
+		return descendants
+	}
+
+	descendants := make(map[chainhash.Hash]struct{})
+	prevOut := wire.OutPoint{Hash: *tx.Hash()}
+	for txOutIdx := range tx.MsgTx().TxOut {
+		prevOut.Index = uint32(txOutIdx)
+		txRedeemer, exists := mp.outpoints[prevOut]
+		if !exists {
+			continue
+		}
+		if _, exists := descendants[*txRedeemer.Hash()]; exists {
+			continue
+		}
+		descendants[*txRedeemer.Hash()] = struct{}{}
+		for descendant := range mp.txDescendants(txRedeemer, memo) {
+			descendants[descendant] = struct{}{}
+		}
+	}
+	memo[*tx.Hash()] = descendants
+	return descendants
+}
+
 // RawMempoolVerbose returns all of the entries in the mempool as a fully
 // populated btcjson result.
 //
@@ -1161,6 +1239,17 @@ func (mp *TxPool) RawMempoolVerbose() map[string]*btcjson.GetRawMempoolVerboseRe
 		len(mp.pool))
 	bestHeight := mp.cfg.BestHeight()
 
+	// The virtual sizes and the sets of ancestors and descendants are
+	// shared by the entries, so only determine them once per transaction.
+	vsizes := make(map[chainhash.Hash]int64, len(mp.pool))
+	for hash, desc := range mp.pool {
+		vsizes[hash] = GetTxVirtualSize(desc.Tx)
+	}
+	ancestorSets := make(map[chainhash.Hash]map[chainhash.Hash]struct{},
+		len(mp.pool))
+	descendantSets := make(map[chainhash.Hash]map[chainhash.Hash]struct{},
+		len(mp.pool))
+
 	for _, desc := range mp.pool {
 		// Calculate the current priority based on the inputs to
 		// the transaction.  Use zero if one or more of the
@@ -1173,22 +1262,64 @@ func (mp *TxPool) RawMempoolVerbose() map[string]*btcjson.GetRawMempoolVerboseRe
 				bestHeight+1)
 		}
 
+		vsize := vsizes[*tx.Hash()]
+		fee := navutil.Amount(desc.Fee).ToBTC()
 		mpd := &btcjson.GetRawMempoolVerboseResult{
 			Size:             int32(tx.MsgTx().SerializeSize()),
-			Vsize:            int32(GetTxVirtualSize(tx)),
-			Fee:              navutil.Amount(desc.Fee).ToBTC(),
+			Vsize:            int32(vsize),
+			Weight:           int32(blockchain.GetTransactionWeight(tx)),
+			Fee:              fee,
+			ModifiedFee:      fee,
 			Time:             desc.Added.Unix(),
 			Height:           int64(desc.Height),
 			StartingPriority: desc.StartingPriority,
 			CurrentPriority:  currentPriority,
+			DescendantCount:  1,
+			DescendantSize:   vsize,
+			AncestorCount:    1,
+			AncestorSize:     vsize,
 			Depends:          make([]string, 0),
+			SpentBy:          make([]string, 0),
 		}
+
+		// A transaction may spend several outputs of the same
+		// transaction, so only include each of the transactions it
+		// depends on and each of the transactions spending it once.
+		seen := make(map[chainhash.Hash]struct{})
 		for _, txIn := range tx.MsgTx().TxIn {
-			hash := &txIn.PreviousOutPoint.Hash
-			if mp.haveTransaction(hash) {
-				mpd.Depends = append(mpd.Depends,
-					hash.String())
+			hash := txIn.PreviousOutPoint.Hash
+			if _, ok := seen[hash]; ok {
+				continue
 			}
+			if mp.haveTransaction(&hash) {
+				seen[hash] = struct{}{}
+				mpd.Depends = append(mpd.Depends, hash.String())
+			}
+		}
+		prevOut := wire.OutPoint{Hash: *tx.Hash()}
+		for txOutIdx := range tx.MsgTx().TxOut {
+			prevOut.Index = uint32(txOutIdx)
+			txRedeemer, ok := mp.outpoints[prevOut]
+			if !ok {
+				continue
+			}
+			if _, ok := seen[*txRedeemer.Hash()]; ok {
+				continue
+			}
+			seen[*txRedeemer.Hash()] = struct{}{}
+			mpd.SpentBy = append(mpd.SpentBy,
+				txRedeemer.Hash().String())
+		}
+
+		// Include the totals of all in-pool ancestors and descendants
+		// of the transaction along with the transaction itself.
+		for ancestor := range mp.txAncestors(tx, ancestorSets) {
+			mpd.AncestorCount++
+			mpd.AncestorSize += vsizes[ancestor]
+		}
+		for descendant := range mp.txDescendants(tx, descendantSets) {
+			mpd.DescendantCount++
+			mpd.DescendantSize += vsizes[descendant]
 		}
 
 		result[tx.Hash().String()] = mpd
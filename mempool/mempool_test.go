@@ -794,3 +794,123 @@ func TestMultiInputOrphanDoubleSpend(t *testing.T) {
 	// was not moved to the transaction pool.
 	testPoolMembership(tc, doubleSpendTx, false, false)
 }
+
+// TestRawMempoolVerbose ensures the verbose mempool entries of a chain of
+// transactions report the expected dependencies along with the expected counts
+// and sizes of their in-pool ancestors and descendants.
+func TestRawMempoolVerbose(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	harness.txPool.cfg.Policy.MaxTxVersion = wire.TxVersion
+
+	// Create a chain of a parent, a child which spends both outputs of the
+	// parent, and a grandchild which spends the output of the child.
+	parent, err := harness.CreateSignedTx(spendableOuts[:1], 2)
+	if err != nil {
+		t.Fatalf("unable to create signed tx: %v", err)
+	}
+	child, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(parent, 0),
+		txOutToSpendableOut(parent, 1),
+	}, 1)
+	if err != nil {
+		t.Fatalf("unable to create signed tx: %v", err)
+	}
+	grandchild, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(child, 0),
+	}, 1)
+	if err != nil {
+		t.Fatalf("unable to create signed tx: %v", err)
+	}
+	for _, tx := range []*navutil.Tx{parent, child, grandchild} {
+		_, err := harness.txPool.ProcessTransaction(tx, false, false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid tx "+
+				"%v: %v", tx.Hash(), err)
+		}
+	}
+
+	parentSize := GetTxVirtualSize(parent)
+	childSize := GetTxVirtualSize(child)
+	grandchildSize := GetTxVirtualSize(grandchild)
+
+	tests := []struct {
+		name            string
+		tx              *navutil.Tx
+		depends         []string
+		spentBy         []string
+		ancestorCount   int64
+		ancestorSize    int64
+		descendantCount int64
+		descendantSize  int64
+	}{{
+		name:            "parent",
+		tx:              parent,
+		depends:         []string{},
+		spentBy:         []string{child.Hash().String()},
+		ancestorCount:   1,
+		ancestorSize:    parentSize,
+		descendantCount: 3,
+		descendantSize:  parentSize + childSize + grandchildSize,
+	}, {
+		name:            "child",
+		tx:              child,
+		depends:         []string{parent.Hash().String()},
+		spentBy:         []string{grandchild.Hash().String()},
+		ancestorCount:   2,
+		ancestorSize:    parentSize + childSize,
+		descendantCount: 2,
+		descendantSize:  childSize + grandchildSize,
+	}, {
+		name:            "grandchild",
+		tx:              grandchild,
+		depends:         []string{child.Hash().String()},
+		spentBy:         []string{},
+		ancestorCount:   3,
+		ancestorSize:    parentSize + childSize + grandchildSize,
+		descendantCount: 1,
+		descendantSize:  grandchildSize,
+	}}
+
+	entries := harness.txPool.RawMempoolVerbose()
+	if len(entries) != len(tests) {
+		t.Fatalf("RawMempoolVerbose: unexpected number of entries - "+
+			"got %d, want %d", len(entries), len(tests))
+	}
+	for _, test := range tests {
+		entry, ok := entries[test.tx.Hash().String()]
+		if !ok {
+			t.Errorf("%s: no entry for transaction %v", test.name,
+				test.tx.Hash())
+			continue
+		}
+		if !reflect.DeepEqual(entry.Depends, test.depends) {
+			t.Errorf("%s: unexpected depends - got %v, want %v",
+				test.name, entry.Depends, test.depends)
+		}
+		if !reflect.DeepEqual(entry.SpentBy, test.spentBy) {
+			t.Errorf("%s: unexpected spent by - got %v, want %v",
+				test.name, entry.SpentBy, test.spentBy)
+		}
+		if entry.AncestorCount != test.ancestorCount ||
+			entry.AncestorSize != test.ancestorSize {
+
+			t.Errorf("%s: unexpected ancestors - got count %d size "+
+				"%d, want count %d size %d", test.name,
+				entry.AncestorCount, entry.AncestorSize,
+				test.ancestorCount, test.ancestorSize)
+		}
+		if entry.DescendantCount != test.descendantCount ||
+			entry.DescendantSize != test.descendantSize {
+
+			t.Errorf("%s: unexpected descendants - got count %d "+
+				"size %d, want count %d size %d", test.name,
+				entry.DescendantCount, entry.DescendantSize,
+				test.descendantCount, test.descendantSize)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"fmt"
+
+	"github.com/navcoin/navd/blockchain"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+	"github.com/navcoin/navutil"
+)
+
+const (
+	// maxPackageCount is the maximum number of transactions permitted in a
+	// package.  Only packages of one parent and one child are currently
+	// supported.
+	maxPackageCount = 2
+
+	// maxPackageWeight is the maximum combined weight permitted for the
+	// transactions in a package.
+	maxPackageWeight = 404000
+)
+
+// ValidatePackage performs context-free checks on a package of one parent
+// and one child transaction so they can be evaluated for acceptance together.
+// The parent must be the first transaction and the child the second, and fees
+// must house the fee paid by each transaction in the same order.
+//
+// The package is rejected unless the child spends at least one output of the
+// parent, the transactions do not spend the same outputs, their combined weight
+// is within the package limit and their combined fee rate meets the minimum
+// relay fee.  This allows a child to pay for a parent whose own fee rate is
+// too low for it to be accepted on its own.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) ValidatePackage(txs []*wire.MsgTx, fees []int64) error {
+	if len(txs) != maxPackageCount {
+		str := fmt.Sprintf("package has %d transactions, but only "+
+			"packages of one parent and one child are supported",
+			len(txs))
+		return txRuleError(wire.RejectInvalid, str)
+	}
+	if len(fees) != len(txs) {
+		str := fmt.Sprintf("package has %d transactions, but %d fees",
+			len(txs), len(fees))
+		return txRuleError(wire.RejectInvalid, str)
+	}
+
+	// Ensure the transactions are distinct and do not spend the same
+	// outputs, while tallying their combined fee and weight.
+	hashes := make([]chainhash.Hash, len(txs))
+	spent := make(map[wire.OutPoint]struct{})
+	var totalFee, totalWeight int64
+	for i, msgTx := range txs {
+		if msgTx == nil {
+			str := fmt.Sprintf("package transaction %d is nil", i)
+			return txRuleError(wire.RejectInvalid, str)
+		}
+		hashes[i] = msgTx.TxHash()
+		if i > 0 && hashes[i] == hashes[0] {
+			str := fmt.Sprintf("package contains transaction %v "+
+				"more than once", hashes[i])
+			return txRuleError(wire.RejectInvalid, str)
+		}
+
+		for _, txIn := range msgTx.TxIn {
+			if _, ok := spent[txIn.PreviousOutPoint]; ok {
+				str := fmt.Sprintf("package transaction %v "+
+					"spends %v which is already spent by "+
+					"the package", hashes[i],
+					txIn.PreviousOutPoint)
+				return txRuleError(wire.RejectDuplicate, str)
+			}
+			spent[txIn.PreviousOutPoint] = struct{}{}
+		}
+
+		if fees[i] < 0 || fees[i] > navutil.MaxSatoshi {
+			str := fmt.Sprintf("package transaction %v has an "+
+				"invalid fee of %d", hashes[i], fees[i])
+			return txRuleError(wire.RejectInvalid, str)
+		}
+		totalFee += fees[i]
+		totalWeight += blockchain.GetTransactionWeight(navutil.NewTx(msgTx))
+	}
+
+	// Ensure the package is shaped as a child paying for its parent.  The
+	// child must spend an existing output of the parent and the parent
+	// may not spend the child.
+	parent, child := txs[0], txs[1]
+	for _, txIn := range parent.TxIn {
+		if txIn.PreviousOutPoint.Hash == hashes[1] {
+			str := fmt.Sprintf("package parent %v spends its child "+
+				"%v", hashes[0], hashes[1])
+			return txRuleError(wire.RejectInvalid, str)
+		}
+	}
+	spendsParent := false
+	for _, txIn := range child.TxIn {
+		prevOut := &txIn.PreviousOutPoint
+		if prevOut.Hash != hashes[0] {
+			continue
+		}
+		if prevOut.Index >= uint32(len(parent.TxOut)) {
+			str := fmt.Sprintf("package child %v spends "+
+				"nonexistent output %v of its parent",
+				hashes[1], prevOut)
+			return txRuleError(wire.RejectInvalid, str)
+		}
+		spendsParent = true
+	}
+	if !spendsParent {
+		str := fmt.Sprintf("package child %v does not spend its "+
+			"parent %v", hashes[1], hashes[0])
+		return txRuleError(wire.RejectInvalid, str)
+	}
+
+	if totalWeight > maxPackageWeight {
+		str := fmt.Sprintf("package weight of %d is larger than max "+
+			"allowed weight of %d", totalWeight, maxPackageWeight)
+		return txRuleError(wire.RejectNonstandard, str)
+	}
+
+	// Ensure the package as a whole pays at least the minimum relay fee
+	// for its combined virtual size.
+	totalSize := (totalWeight + (blockchain.WitnessScaleFactor - 1)) /
+		blockchain.WitnessScaleFactor
+	minFee := calcMinRequiredTxRelayFee(totalSize,
+		mp.cfg.Policy.MinRelayTxFee)
+	if totalFee < minFee {
+		str := fmt.Sprintf("package has %d fees which is under the "+
+			"required amount of %d", totalFee, minFee)
+		return txRuleError(wire.RejectInsufficientFee, str)
+	}
+
+	return nil
+}
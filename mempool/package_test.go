@@ -0,0 +1,172 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// TestValidatePackage ensures one parent one child packages are accepted
+// when the child pays for its parent and malformed packages are rejected with
+// the expected reject code.
+func TestValidatePackage(t *testing.T) {
+	// newTx returns a transaction which spends the passed outpoints and
+	// has the passed number of outputs paying to the given script.
+	newTx := func(prevOuts []wire.OutPoint, numOuts int, pkScript []byte) *wire.MsgTx {
+		tx := wire.NewMsgTx(wire.TxVersion)
+		for _, prevOut := range prevOuts {
+			tx.AddTxIn(wire.NewTxIn(&prevOut, nil, nil))
+		}
+		for i := 0; i < numOuts; i++ {
+			tx.AddTxOut(wire.NewTxOut(100000, pkScript))
+		}
+		return tx
+	}
+
+	confirmedHash, err := chainhash.NewHashFromStr("01")
+	if err != nil {
+		t.Fatalf("NewHashFromStr: unexpected error: %v", err)
+	}
+	confirmedOut := wire.OutPoint{Hash: *confirmedHash, Index: 0}
+	otherOut := wire.OutPoint{Hash: *confirmedHash, Index: 1}
+	pkScript := bytes.Repeat([]byte{0x51}, 25)
+
+	// The parent pays no fee, so it is only acceptable when its child pays
+	// enough for the both of them.
+	parent := newTx([]wire.OutPoint{confirmedOut}, 2, pkScript)
+	parentOut := wire.OutPoint{Hash: parent.TxHash(), Index: 0}
+	child := newTx([]wire.OutPoint{parentOut}, 1, pkScript)
+
+	unrelated := newTx([]wire.OutPoint{otherOut}, 1, pkScript)
+	conflicting := newTx([]wire.OutPoint{parentOut, confirmedOut}, 1,
+		pkScript)
+	missingOut := newTx([]wire.OutPoint{{Hash: parentOut.Hash, Index: 2}},
+		1, pkScript)
+	oversized := newTx([]wire.OutPoint{parentOut}, 1,
+		bytes.Repeat([]byte{0x51}, maxPackageWeight/4))
+
+	tests := []struct {
+		name    string
+		txs     []*wire.MsgTx
+		fees    []int64
+		isValid bool
+		code    wire.RejectCode
+	}{
+		{
+			name:    "child pays for parent",
+			txs:     []*wire.MsgTx{parent, child},
+			fees:    []int64{0, 1000},
+			isValid: true,
+		},
+		{
+			name: "single transaction",
+			txs:  []*wire.MsgTx{parent},
+			fees: []int64{1000},
+			code: wire.RejectInvalid,
+		},
+		{
+			name: "three transactions",
+			txs:  []*wire.MsgTx{parent, child, unrelated},
+			fees: []int64{0, 1000, 1000},
+			code: wire.RejectInvalid,
+		},
+		{
+			name: "mismatched fees",
+			txs:  []*wire.MsgTx{parent, child},
+			fees: []int64{1000},
+			code: wire.RejectInvalid,
+		},
+		{
+			name: "negative fee",
+			txs:  []*wire.MsgTx{parent, child},
+			fees: []int64{-1, 1000},
+			code: wire.RejectInvalid,
+		},
+		{
+			name: "duplicate transaction",
+			txs:  []*wire.MsgTx{parent, parent},
+			fees: []int64{0, 0},
+			code: wire.RejectInvalid,
+		},
+		{
+			name: "child listed first",
+			txs:  []*wire.MsgTx{child, parent},
+			fees: []int64{1000, 0},
+			code: wire.RejectInvalid,
+		},
+		{
+			name: "child does not spend parent",
+			txs:  []*wire.MsgTx{parent, unrelated},
+			fees: []int64{0, 1000},
+			code: wire.RejectInvalid,
+		},
+		{
+			name: "child spends nonexistent parent output",
+			txs:  []*wire.MsgTx{parent, missingOut},
+			fees: []int64{0, 1000},
+			code: wire.RejectInvalid,
+		},
+		{
+			name: "in-package conflict",
+			txs:  []*wire.MsgTx{parent, conflicting},
+			fees: []int64{0, 1000},
+			code: wire.RejectDuplicate,
+		},
+		{
+			name: "package too large",
+			txs:  []*wire.MsgTx{parent, oversized},
+			fees: []int64{0, 1000000},
+			code: wire.RejectNonstandard,
+		},
+		{
+			name: "insufficient package fee",
+			txs:  []*wire.MsgTx{parent, child},
+			fees: []int64{0, 100},
+			code: wire.RejectInsufficientFee,
+		},
+	}
+
+	mp := &TxPool{cfg: Config{Policy: Policy{
+		MinRelayTxFee: DefaultMinRelayTxFee,
+	}}}
+	for _, test := range tests {
+		err := mp.ValidatePackage(test.txs, test.fees)
+		if test.isValid {
+			if err != nil {
+				t.Errorf("ValidatePackage (%s): unexpected error: "+
+					"%v", test.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("ValidatePackage (%s): valid when it should "+
+				"not be", test.name)
+			continue
+		}
+
+		// Ensure error type is a TxRuleError inside of a RuleError.
+		rerr, ok := err.(RuleError)
+		if !ok {
+			t.Errorf("ValidatePackage (%s): unexpected error type - "+
+				"got %T", test.name, err)
+			continue
+		}
+		txrerr, ok := rerr.Err.(TxRuleError)
+		if !ok {
+			t.Errorf("ValidatePackage (%s): unexpected error type - "+
+				"got %T", test.name, rerr.Err)
+			continue
+		}
+		if txrerr.RejectCode != test.code {
+			t.Errorf("ValidatePackage (%s): unexpected error code - "+
+				"got %v, want %v (%v)", test.name,
+				txrerr.RejectCode, test.code, err)
+		}
+	}
+}
@@ -53,6 +53,20 @@ const (
 	// in a multi-signature transaction output script for it to be
 	// considered standard.
 	maxStandardMultiSigKeys = 3
+
+	// maxStandardWitnessStackItems is the maximum number of witness stack
+	// items, excluding the witness script or the taproot script and
+	// control block, allowed for an input to be considered standard.
+	maxStandardWitnessStackItems = 100
+
+	// maxStandardWitnessStackItemSize is the maximum size allowed for each
+	// of those witness stack items for an input to be considered standard.
+	maxStandardWitnessStackItemSize = 80
+
+	// maxStandardWitnessScriptSize is the maximum size allowed for the
+	// witness script of a version 0 witness for an input to be considered
+	// standard.
+	maxStandardWitnessScriptSize = 3600
 )
 
 // calcMinRequiredTxRelayFee returns the minimum transaction fee required for a
@@ -123,6 +137,64 @@ func checkInputsStandard(tx *navutil.Tx, utxoView *blockchain.UtxoViewpoint) err
 	return nil
 }
 
+// CheckWitnessLimits performs standardness checks on the size of the passed
+// witness of a transaction input.  The witness may house at most
+// maxStandardWitnessStackItems stack items of no more than
+// maxStandardWitnessStackItemSize bytes each.
+//
+// For version 0 witnesses, the final element is the witness script, which is
+// instead limited to maxStandardWitnessScriptSize bytes.  When isTaproot is
+// set, the annex is exempt from the limits, a key-path spend is not limited,
+// and a script-path spend must end with a well-formed control block.  Neither
+// the control block nor the tapscript preceding it count towards the stack
+// item limits.
+func CheckWitnessLimits(witness wire.TxWitness, isTaproot bool) error {
+	if isTaproot {
+		if _, hasAnnex := txscript.ExtractAnnex(witness); hasAnnex {
+			witness = witness[:len(witness)-1]
+		}
+		if len(witness) < 2 {
+			return nil
+		}
+
+		controlBlock := witness[len(witness)-1]
+		_, _, _, err := txscript.ParseControlBlock(controlBlock)
+		if err != nil {
+			str := fmt.Sprintf("witness has an invalid control "+
+				"block: %v", err)
+			return txRuleError(wire.RejectNonstandard, str)
+		}
+		witness = witness[:len(witness)-2]
+	} else if len(witness) > 0 {
+		witnessScript := witness[len(witness)-1]
+		if len(witnessScript) > maxStandardWitnessScriptSize {
+			str := fmt.Sprintf("witness script size of %d bytes "+
+				"is larger than max allowed size of %d bytes",
+				len(witnessScript), maxStandardWitnessScriptSize)
+			return txRuleError(wire.RejectNonstandard, str)
+		}
+		witness = witness[:len(witness)-1]
+	}
+
+	if len(witness) > maxStandardWitnessStackItems {
+		str := fmt.Sprintf("witness has %d stack items which is more "+
+			"than the allowed max of %d", len(witness),
+			maxStandardWitnessStackItems)
+		return txRuleError(wire.RejectNonstandard, str)
+	}
+	for i, item := range witness {
+		if len(item) > maxStandardWitnessStackItemSize {
+			str := fmt.Sprintf("witness stack item #%d size of %d "+
+				"bytes is larger than max allowed size of %d "+
+				"bytes", i, len(item),
+				maxStandardWitnessStackItemSize)
+			return txRuleError(wire.RejectNonstandard, str)
+		}
+	}
+
+	return nil
+}
+
 // checkPkScriptStandard performs a series of checks on a transaction output
 // script (public key script) to ensure it is a "standard" public key script.
 // A standard public key script is one that is a recognized form, and for
@@ -269,41 +341,148 @@ func isDust(txOut *wire.TxOut, minRelayTxFee navutil.Amount) bool {
 	return txOut.Value*1000/(3*int64(totalSize)) < int64(minRelayTxFee)
 }
 
-// checkTransactionStandard performs a series of checks on a transaction to
-// ensure it is a "standard" transaction.  A standard transaction is one that
-// conforms to several additional limiting cases over what is considered a
-// "sane" transaction such as having a version in the supported range, being
-// finalized, conforming to more stringent size constraints, having scripts
-// of recognized forms, and not containing "dust" outputs (those that are
-// so small it costs more to process them than they are worth).
-func checkTransactionStandard(tx *navutil.Tx, height int32,
-	medianTimePast time.Time, minRelayTxFee navutil.Amount,
-	maxTxVersion int32) error {
+// IsDust returns whether or not the passed transaction output is considered
+// dust at the passed relay fee rate, expressed in satoshi per kilobyte.
+// Unlike the standardness check, the size of the input required to spend the
+// output is derived from its script type rather than assuming a
+// pay-to-pubkey-hash input, so, for example, witness outputs benefit from the
+// discount applied to their witness data.
+//
+// Outputs which only carry data are never dust since they are provably
+// prunable, while all other unspendable outputs are always dust.
+func IsDust(txOut *wire.TxOut, relayFeePerKB int64) bool {
+	pkScript := txOut.PkScript
+	if len(pkScript) > 0 && pkScript[0] == txscript.OP_RETURN {
+		return false
+	}
+	if txscript.IsUnspendable(pkScript) {
+		return true
+	}
+
+	// Determine the typical serialized size of the input which spends the
+	// output per the breakdown in isDust.  All inputs share the 41 byte
+	// preamble which references the output being spent and holds the
+	// sequence number.  Script types without a well-known redeeming input
+	// fall back to the size of a pay-to-pubkey-hash input.
+	spendSize := 41
+	switch txscript.GetScriptClass(pkScript) {
+	case txscript.PubKeyTy:
+		spendSize += 73
+	case txscript.WitnessV0PubKeyHashTy, txscript.WitnessV0ScriptHashTy:
+		spendSize += 107 / blockchain.WitnessScaleFactor
+	default:
+		spendSize += 107
+	}
+	totalSize := int64(txOut.SerializeSize() + spendSize)
+
+	// The output is considered dust if the cost to the network to spend the
+	// coins is more than 1/3 of the relay fee, consistent with isDust.
+	return txOut.Value*1000/(3*totalSize) < relayFeePerKB
+}
+
+// dustRelayFee returns the fee rate used to determine whether an output is
+// dust under the policy.
+func (p *Policy) dustRelayFee() navutil.Amount {
+	if p.DustRelayFee != 0 {
+		return p.DustRelayFee
+	}
+	return p.MinRelayTxFee
+}
+
+// maxStandardTxWeight returns the maximum weight of a standard transaction
+// under the policy.
+func (p *Policy) maxStandardTxWeight() int64 {
+	if p.MaxStandardTxWeight != 0 {
+		return p.MaxStandardTxWeight
+	}
+	return maxStandardTxWeight
+}
+
+// maxDataCarrierSize returns the maximum number of bytes a standard null data
+// output may carry under the policy.
+func (p *Policy) maxDataCarrierSize() int {
+	if p.MaxDataCarrierSize != 0 {
+		return p.MaxDataCarrierSize
+	}
+	return txscript.MaxDataCarrierSize
+}
+
+// IsStandardOutput performs a series of checks on a transaction output to
+// ensure it is "standard" under the policy.  A standard output has a public
+// key script of a recognized form, is not a bare multi-signature script when
+// the policy rejects them, carries no more than the policy's data carrier
+// size when it is a null data script, and is otherwise not dust.
+func (p *Policy) IsStandardOutput(txOut *wire.TxOut) error {
+	scriptClass := txscript.GetScriptClass(txOut.PkScript)
+	err := checkPkScriptStandard(txOut.PkScript, scriptClass)
+	if err != nil {
+		return err
+	}
+
+	switch scriptClass {
+	case txscript.MultiSigTy:
+		if p.RejectBareMultisig {
+			return txRuleError(wire.RejectNonstandard,
+				"bare multi-signature script")
+		}
+
+	case txscript.NullDataTy:
+		// Null data scripts are never dust, but the amount of data
+		// they carry is limited.
+		pushes, err := txscript.PushedData(txOut.PkScript)
+		if err != nil {
+			return txRuleError(wire.RejectNonstandard, err.Error())
+		}
+		var dataSize int
+		for _, push := range pushes {
+			dataSize += len(push)
+		}
+		if dataSize > p.maxDataCarrierSize() {
+			str := fmt.Sprintf("null data script carries %d bytes "+
+				"which is more than the allowed max of %d",
+				dataSize, p.maxDataCarrierSize())
+			return txRuleError(wire.RejectNonstandard, str)
+		}
+		return nil
+	}
+
+	if isDust(txOut, p.dustRelayFee()) {
+		str := fmt.Sprintf("payment of %d is dust", txOut.Value)
+		return txRuleError(wire.RejectDust, str)
+	}
 
+	return nil
+}
+
+// IsStandardTx performs a series of checks on a transaction to ensure it is a
+// "standard" transaction under the policy.  A standard transaction is one that
+// conforms to several additional limiting cases over what is considered a
+// "sane" transaction such as having a version in the supported range,
+// conforming to more stringent size constraints, having scripts of recognized
+// forms, and not containing "dust" outputs (those that are so small it costs
+// more to process them than they are worth).
+//
+// The inputs are only checked when a utxo view containing the outputs they
+// spend is provided.  Whether the transaction is finalized depends on the
+// chain state and is not checked.
+func (p *Policy) IsStandardTx(tx *navutil.Tx, utxoView *blockchain.UtxoViewpoint) error {
 	// The transaction must be a currently supported version.
 	msgTx := tx.MsgTx()
-	if msgTx.Version > maxTxVersion || msgTx.Version < 1 {
+	if msgTx.Version > p.MaxTxVersion || msgTx.Version < 1 {
 		str := fmt.Sprintf("transaction version %d is not in the "+
 			"valid range of %d-%d", msgTx.Version, 1,
-			maxTxVersion)
+			p.MaxTxVersion)
 		return txRuleError(wire.RejectNonstandard, str)
 	}
 
-	// The transaction must be finalized to be standard and therefore
-	// considered for inclusion in a block.
-	if !blockchain.IsFinalizedTransaction(tx, height, medianTimePast) {
-		return txRuleError(wire.RejectNonstandard,
-			"transaction is not finalized")
-	}
-
 	// Since extremely large transactions with a lot of inputs can cost
 	// almost as much to process as the sender fees, limit the maximum
 	// size of a transaction.  This also helps mitigate CPU exhaustion
 	// attacks.
 	txWeight := blockchain.GetTransactionWeight(tx)
-	if txWeight > maxStandardTxWeight {
+	if txWeight > p.maxStandardTxWeight() {
 		str := fmt.Sprintf("weight of transaction %v is larger than max "+
-			"allowed weight of %v", txWeight, maxStandardTxWeight)
+			"allowed weight of %v", txWeight, p.maxStandardTxWeight())
 		return txRuleError(wire.RejectNonstandard, str)
 	}
 
@@ -329,13 +508,11 @@ func checkTransactionStandard(tx *navutil.Tx, height int32,
 		}
 	}
 
-	// None of the output public key scripts can be a non-standard script or
-	// be "dust" (except when the script is a null data script).
+	// None of the outputs can be non-standard or be "dust" (except when the
+	// script is a null data script).
 	numNullDataOutputs := 0
 	for i, txOut := range msgTx.TxOut {
-		scriptClass := txscript.GetScriptClass(txOut.PkScript)
-		err := checkPkScriptStandard(txOut.PkScript, scriptClass)
-		if err != nil {
+		if err := p.IsStandardOutput(txOut); err != nil {
 			// Attempt to extract a reject code from the error so
 			// it can be retained.  When not possible, fall back to
 			// a non standard error.
@@ -347,15 +524,9 @@ func checkTransactionStandard(tx *navutil.Tx, height int32,
 			return txRuleError(rejectCode, str)
 		}
 
-		// Accumulate the number of outputs which only carry data.  For
-		// all other script types, ensure the output value is not
-		// "dust".
-		if scriptClass == txscript.NullDataTy {
+		// Accumulate the number of outputs which only carry data.
+		if txscript.GetScriptClass(txOut.PkScript) == txscript.NullDataTy {
 			numNullDataOutputs++
-		} else if isDust(txOut, minRelayTxFee) {
-			str := fmt.Sprintf("transaction output %d: payment "+
-				"of %d is dust", i, txOut.Value)
-			return txRuleError(wire.RejectDust, str)
 		}
 	}
 
@@ -366,6 +537,30 @@ func checkTransactionStandard(tx *navutil.Tx, height int32,
 		return txRuleError(wire.RejectNonstandard, str)
 	}
 
+	if utxoView != nil {
+		return checkInputsStandard(tx, utxoView)
+	}
+	return nil
+}
+
+// checkTransactionStandard performs a series of checks on a transaction to
+// ensure it is a "standard" transaction under the passed policy as described
+// by IsStandardTx, and that it is finalized and therefore could be included
+// in the next block.  The inputs are not checked.
+func checkTransactionStandard(tx *navutil.Tx, height int32,
+	medianTimePast time.Time, policy *Policy) error {
+
+	if err := policy.IsStandardTx(tx, nil); err != nil {
+		return err
+	}
+
+	// The transaction must be finalized to be standard and therefore
+	// considered for inclusion in a block.
+	if !blockchain.IsFinalizedTransaction(tx, height, medianTimePast) {
+		return txRuleError(wire.RejectNonstandard,
+			"transaction is not finalized")
+	}
+
 	return nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/navcoin/navd/blockchain"
 	"github.com/navcoin/navd/btcec"
 	"github.com/navcoin/navd/chaincfg"
 	"github.com/navcoin/navd/chaincfg/chainhash"
@@ -277,6 +278,91 @@ func TestDust(t *testing.T) {
 	}
 }
 
+// TestIsDust tests the IsDust API.
+func TestIsDust(t *testing.T) {
+	p2pkh, err := txscript.NewScriptBuilder().AddOp(txscript.OP_DUP).
+		AddOp(txscript.OP_HASH160).AddData(make([]byte, 20)).
+		AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		t.Fatalf("unable to build p2pkh script: %v", err)
+	}
+	p2wpkh, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).
+		AddData(make([]byte, 20)).Script()
+	if err != nil {
+		t.Fatalf("unable to build p2wpkh script: %v", err)
+	}
+	nullData, err := txscript.NullDataScript([]byte{0x01, 0x02, 0x03, 0x04})
+	if err != nil {
+		t.Fatalf("unable to build null data script: %v", err)
+	}
+
+	tests := []struct {
+		name     string // test description
+		txOut    wire.TxOut
+		relayFee int64 // relay fee rate in satoshi per kilobyte.
+		isDust   bool
+	}{
+		{
+			// A 31 byte p2wpkh output is spent by a 67 byte input,
+			// so 1000 satoshi is worth spending at a low fee rate.
+			"small p2wpkh output at low fee rate",
+			wire.TxOut{Value: 1000, PkScript: p2wpkh},
+			1000,
+			false,
+		},
+		{
+			"small p2wpkh output at high fee rate",
+			wire.TxOut{Value: 1000, PkScript: p2wpkh},
+			10000,
+			true,
+		},
+		{
+			// A p2wpkh output is cheaper to spend than a p2pkh
+			// output, so the same value is dust for the latter.
+			"p2wpkh output just above threshold",
+			wire.TxOut{Value: 294, PkScript: p2wpkh},
+			1000,
+			false,
+		},
+		{
+			"p2pkh output with same value",
+			wire.TxOut{Value: 294, PkScript: p2pkh},
+			1000,
+			true,
+		},
+		{
+			"p2pkh output just above threshold",
+			wire.TxOut{Value: 546, PkScript: p2pkh},
+			1000,
+			false,
+		},
+		{
+			// Data carrier outputs are never dust.
+			"null data output with zero value",
+			wire.TxOut{Value: 0, PkScript: nullData},
+			1000,
+			false,
+		},
+		{
+			// Unspendable pkScript due to an invalid public key
+			// script.
+			"unspendable pkScript",
+			wire.TxOut{Value: 5000, PkScript: []byte{0x01}},
+			0,
+			true,
+		},
+	}
+	for _, test := range tests {
+		res := IsDust(&test.txOut, test.relayFee)
+		if res != test.isDust {
+			t.Errorf("IsDust test '%s' failed: want %v got %v",
+				test.name, test.isDust, res)
+			continue
+		}
+	}
+}
+
 // TestCheckTransactionStandard tests the checkTransactionStandard API.
 func TestCheckTransactionStandard(t *testing.T) {
 	// Create some dummy, but otherwise standard, data for transactions.
@@ -467,10 +553,11 @@ func TestCheckTransactionStandard(t *testing.T) {
 	}
 
 	pastMedianTime := time.Now()
+	policy := Policy{MaxTxVersion: 1, MinRelayTxFee: DefaultMinRelayTxFee}
 	for _, test := range tests {
 		// Ensure standardness is as expected.
 		err := checkTransactionStandard(navutil.NewTx(&test.tx),
-			test.height, pastMedianTime, DefaultMinRelayTxFee, 1)
+			test.height, pastMedianTime, &policy)
 		if err == nil && test.isStandard {
 			// Test passes since function returned standard for a
 			// transaction which is intended to be standard.
@@ -510,3 +597,411 @@ func TestCheckTransactionStandard(t *testing.T) {
 		}
 	}
 }
+
+// TestCheckWitnessLimits tests the CheckWitnessLimits API.
+func TestCheckWitnessLimits(t *testing.T) {
+	// repeat returns count witness stack items of size bytes each.
+	repeat := func(count, size int) wire.TxWitness {
+		witness := make(wire.TxWitness, count)
+		for i := range witness {
+			witness[i] = bytes.Repeat([]byte{0x01}, size)
+		}
+		return witness
+	}
+
+	// with returns a copy of the passed witness with the items appended.
+	with := func(witness wire.TxWitness, items ...[]byte) wire.TxWitness {
+		result := make(wire.TxWitness, 0, len(witness)+len(items))
+		result = append(result, witness...)
+		return append(result, items...)
+	}
+
+	sig := bytes.Repeat([]byte{0x30}, 72)
+	pubKey := bytes.Repeat([]byte{0x02}, 33)
+	witnessScript := bytes.Repeat([]byte{0x51}, maxStandardWitnessScriptSize)
+	schnorrSig := bytes.Repeat([]byte{0x01}, 64)
+	tapscript := bytes.Repeat([]byte{0x51}, 10000)
+	controlBlock := bytes.Repeat([]byte{0xc0}, 33+32*2)
+	annex := append([]byte{0x50}, bytes.Repeat([]byte{0x00}, 1000)...)
+
+	tests := []struct {
+		name       string
+		witness    wire.TxWitness
+		isTaproot  bool
+		isStandard bool
+	}{
+		{
+			name:       "empty witness",
+			witness:    nil,
+			isStandard: true,
+		},
+		{
+			name:       "pay-to-witness-pubkey-hash",
+			witness:    wire.TxWitness{sig, pubKey},
+			isStandard: true,
+		},
+		{
+			name: "pay-to-witness-script-hash at limits",
+			witness: with(repeat(maxStandardWitnessStackItems,
+				maxStandardWitnessStackItemSize), witnessScript),
+			isStandard: true,
+		},
+		{
+			name: "pay-to-witness-script-hash too many items",
+			witness: with(repeat(maxStandardWitnessStackItems+1, 1),
+				witnessScript),
+			isStandard: false,
+		},
+		{
+			name: "pay-to-witness-script-hash item too large",
+			witness: with(repeat(1, maxStandardWitnessStackItemSize+1),
+				witnessScript),
+			isStandard: false,
+		},
+		{
+			name: "pay-to-witness-script-hash script too large",
+			witness: with(repeat(1, 1), append(witnessScript,
+				txscript.OP_TRUE)),
+			isStandard: false,
+		},
+		{
+			name:       "taproot key path",
+			witness:    wire.TxWitness{schnorrSig},
+			isTaproot:  true,
+			isStandard: true,
+		},
+		{
+			name:       "taproot key path with annex",
+			witness:    wire.TxWitness{schnorrSig, annex},
+			isTaproot:  true,
+			isStandard: true,
+		},
+		{
+			name: "taproot script path at limits",
+			witness: with(repeat(maxStandardWitnessStackItems,
+				maxStandardWitnessStackItemSize), tapscript,
+				controlBlock),
+			isTaproot:  true,
+			isStandard: true,
+		},
+		{
+			name: "taproot script path with annex",
+			witness: with(repeat(1, 1), tapscript, controlBlock,
+				annex),
+			isTaproot:  true,
+			isStandard: true,
+		},
+		{
+			name: "taproot script path too many items",
+			witness: with(repeat(maxStandardWitnessStackItems+1, 1),
+				tapscript, controlBlock),
+			isTaproot:  true,
+			isStandard: false,
+		},
+		{
+			name: "taproot script path item too large",
+			witness: with(repeat(1, maxStandardWitnessStackItemSize+1),
+				tapscript, controlBlock),
+			isTaproot:  true,
+			isStandard: false,
+		},
+		{
+			name: "taproot control block with partial node",
+			witness: wire.TxWitness{tapscript,
+				bytes.Repeat([]byte{0xc0}, 34)},
+			isTaproot:  true,
+			isStandard: false,
+		},
+		{
+			name: "taproot control block too large",
+			witness: wire.TxWitness{tapscript,
+				bytes.Repeat([]byte{0xc0}, 33+32*129)},
+			isTaproot:  true,
+			isStandard: false,
+		},
+	}
+
+	for _, test := range tests {
+		err := CheckWitnessLimits(test.witness, test.isTaproot)
+		if err == nil && test.isStandard {
+			continue
+		}
+		if err == nil && !test.isStandard {
+			t.Errorf("CheckWitnessLimits (%s): standard when it "+
+				"should not be", test.name)
+			continue
+		}
+		if err != nil && test.isStandard {
+			t.Errorf("CheckWitnessLimits (%s): nonstandard when it "+
+				"should not be: %v", test.name, err)
+			continue
+		}
+
+		// Ensure error type is a TxRuleError inside of a RuleError with
+		// the nonstandard reject code.
+		rerr, ok := err.(RuleError)
+		if !ok {
+			t.Errorf("CheckWitnessLimits (%s): unexpected error "+
+				"type - got %T", test.name, err)
+			continue
+		}
+		txrerr, ok := rerr.Err.(TxRuleError)
+		if !ok {
+			t.Errorf("CheckWitnessLimits (%s): unexpected error "+
+				"type - got %T", test.name, rerr.Err)
+			continue
+		}
+		if txrerr.RejectCode != wire.RejectNonstandard {
+			t.Errorf("CheckWitnessLimits (%s): unexpected error "+
+				"code - got %v, want %v", test.name,
+				txrerr.RejectCode, wire.RejectNonstandard)
+		}
+	}
+}
+
+// TestPolicyIsStandardOutput ensures outputs are checked against each of the
+// output related policy fields.
+func TestPolicyIsStandardOutput(t *testing.T) {
+	pkHash := bytes.Repeat([]byte{0x01}, 20)
+	p2pkhScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_DUP).
+		AddOp(txscript.OP_HASH160).AddData(pkHash).
+		AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		t.Fatalf("unable to build pay-to-pubkey-hash script: %v", err)
+	}
+	pubKey := append([]byte{0x02}, bytes.Repeat([]byte{0x01}, 32)...)
+	multiSigScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_1).
+		AddData(pubKey).AddData(pubKey).AddOp(txscript.OP_2).
+		AddOp(txscript.OP_CHECKMULTISIG).Script()
+	if err != nil {
+		t.Fatalf("unable to build multisig script: %v", err)
+	}
+	nullData := func(size int) []byte {
+		script, err := txscript.NullDataScript(bytes.Repeat([]byte{0x01},
+			size))
+		if err != nil {
+			t.Fatalf("unable to build null data script: %v", err)
+		}
+		return script
+	}
+
+	defaultPolicy := Policy{MaxTxVersion: 1, MinRelayTxFee: DefaultMinRelayTxFee}
+	highDustFee := defaultPolicy
+	highDustFee.DustRelayFee = 100000
+	noBareMultisig := defaultPolicy
+	noBareMultisig.RejectBareMultisig = true
+	smallDataCarrier := defaultPolicy
+	smallDataCarrier.MaxDataCarrierSize = 40
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		txOut      wire.TxOut
+		isStandard bool
+		code       wire.RejectCode
+	}{
+		{
+			name:       "pay-to-pubkey-hash",
+			policy:     defaultPolicy,
+			txOut:      wire.TxOut{Value: 100000000, PkScript: p2pkhScript},
+			isStandard: true,
+		},
+		{
+			name:   "dust",
+			policy: defaultPolicy,
+			txOut:  wire.TxOut{Value: 1, PkScript: p2pkhScript},
+			code:   wire.RejectDust,
+		},
+		{
+			name:       "not dust at the relay fee",
+			policy:     defaultPolicy,
+			txOut:      wire.TxOut{Value: 1000, PkScript: p2pkhScript},
+			isStandard: true,
+		},
+		{
+			name:   "dust at a higher dust relay fee",
+			policy: highDustFee,
+			txOut:  wire.TxOut{Value: 1000, PkScript: p2pkhScript},
+			code:   wire.RejectDust,
+		},
+		{
+			name:   "non-standard script",
+			policy: defaultPolicy,
+			txOut: wire.TxOut{Value: 100000000,
+				PkScript: []byte{txscript.OP_TRUE}},
+			code: wire.RejectNonstandard,
+		},
+		{
+			name:       "bare multisig",
+			policy:     defaultPolicy,
+			txOut:      wire.TxOut{Value: 100000000, PkScript: multiSigScript},
+			isStandard: true,
+		},
+		{
+			name:   "bare multisig rejected",
+			policy: noBareMultisig,
+			txOut:  wire.TxOut{Value: 100000000, PkScript: multiSigScript},
+			code:   wire.RejectNonstandard,
+		},
+		{
+			name:       "zero value null data",
+			policy:     defaultPolicy,
+			txOut:      wire.TxOut{Value: 0, PkScript: nullData(80)},
+			isStandard: true,
+		},
+		{
+			name:       "null data at data carrier size",
+			policy:     smallDataCarrier,
+			txOut:      wire.TxOut{Value: 0, PkScript: nullData(40)},
+			isStandard: true,
+		},
+		{
+			name:   "null data over data carrier size",
+			policy: smallDataCarrier,
+			txOut:  wire.TxOut{Value: 0, PkScript: nullData(41)},
+			code:   wire.RejectNonstandard,
+		},
+	}
+
+	for _, test := range tests {
+		err := test.policy.IsStandardOutput(&test.txOut)
+		if test.isStandard {
+			if err != nil {
+				t.Errorf("IsStandardOutput (%s): nonstandard when "+
+					"it should not be: %v", test.name, err)
+			}
+			continue
+		}
+		code, ok := extractRejectCode(err)
+		if !ok {
+			t.Errorf("IsStandardOutput (%s): unexpected error %v",
+				test.name, err)
+			continue
+		}
+		if code != test.code {
+			t.Errorf("IsStandardOutput (%s): unexpected error code "+
+				"- got %v, want %v", test.name, code, test.code)
+		}
+	}
+}
+
+// TestPolicyIsStandardTx ensures transactions are checked against each of the
+// transaction related policy fields.
+func TestPolicyIsStandardTx(t *testing.T) {
+	// The transaction being checked spends a pay-to-pubkey-hash output of
+	// prevTx, which is added to the utxo view.
+	pkHash := bytes.Repeat([]byte{0x01}, 20)
+	p2pkhScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_DUP).
+		AddOp(txscript.OP_HASH160).AddData(pkHash).
+		AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		t.Fatalf("unable to build pay-to-pubkey-hash script: %v", err)
+	}
+	prevTx := wire.NewMsgTx(1)
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 0}, nil, nil))
+	prevTx.AddTxOut(wire.NewTxOut(200000000, p2pkhScript))
+	prevTx.AddTxOut(wire.NewTxOut(100000000, []byte{txscript.OP_TRUE}))
+	utxoView := blockchain.NewUtxoViewpoint()
+	utxoView.AddTxOuts(navutil.NewTx(prevTx), 100)
+
+	prevHash := prevTx.TxHash()
+	sigScript := bytes.Repeat([]byte{0x00}, 65)
+	newTx := func(prevIndex uint32, outputs ...*wire.TxOut) *navutil.Tx {
+		tx := wire.NewMsgTx(1)
+		prevOut := wire.NewOutPoint(&prevHash, prevIndex)
+		tx.AddTxIn(wire.NewTxIn(prevOut, sigScript, nil))
+		for _, txOut := range outputs {
+			tx.AddTxOut(txOut)
+		}
+		return navutil.NewTx(tx)
+	}
+	payment := wire.NewTxOut(100000000, p2pkhScript)
+	nullData, err := txscript.NullDataScript([]byte{0x01})
+	if err != nil {
+		t.Fatalf("unable to build null data script: %v", err)
+	}
+	dataOut := wire.NewTxOut(0, nullData)
+
+	defaultPolicy := Policy{MaxTxVersion: 1, MinRelayTxFee: DefaultMinRelayTxFee}
+	lowWeight := defaultPolicy
+	lowWeight.MaxStandardTxWeight = 100
+	highDustFee := defaultPolicy
+	highDustFee.DustRelayFee = navutil.MaxSatoshi
+
+	versionTwo := newTx(0, payment)
+	versionTwo.MsgTx().Version = 2
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		tx         *navutil.Tx
+		utxoView   *blockchain.UtxoViewpoint
+		isStandard bool
+	}{
+		{
+			name:       "standard transaction",
+			policy:     defaultPolicy,
+			tx:         newTx(0, payment, dataOut),
+			utxoView:   utxoView,
+			isStandard: true,
+		},
+		{
+			name:     "version above max",
+			policy:   defaultPolicy,
+			tx:       versionTwo,
+			utxoView: utxoView,
+		},
+		{
+			name:     "weight above max",
+			policy:   lowWeight,
+			tx:       newTx(0, payment),
+			utxoView: utxoView,
+		},
+		{
+			name:     "dust output",
+			policy:   highDustFee,
+			tx:       newTx(0, payment),
+			utxoView: utxoView,
+		},
+		{
+			name:     "multiple null data outputs",
+			policy:   defaultPolicy,
+			tx:       newTx(0, payment, dataOut, dataOut),
+			utxoView: utxoView,
+		},
+		{
+			name:     "non-standard input",
+			policy:   defaultPolicy,
+			tx:       newTx(1, payment),
+			utxoView: utxoView,
+		},
+		{
+			name:       "inputs unchecked without utxo view",
+			policy:     defaultPolicy,
+			tx:         newTx(1, payment),
+			isStandard: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := test.policy.IsStandardTx(test.tx, test.utxoView)
+		if err == nil && !test.isStandard {
+			t.Errorf("IsStandardTx (%s): standard when it should "+
+				"not be", test.name)
+			continue
+		}
+		if err != nil && test.isStandard {
+			t.Errorf("IsStandardTx (%s): nonstandard when it "+
+				"should not be: %v", test.name, err)
+			continue
+		}
+		if err != nil {
+			if _, ok := err.(RuleError); !ok {
+				t.Errorf("IsStandardTx (%s): unexpected error "+
+					"type - got %T", test.name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"fmt"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// BuildDependencyGraph returns the spend dependencies between the passed
+// transactions.  Every transaction is mapped to the hashes of the other passed
+// transactions it spends outputs from, in the order they are first referenced
+// by its inputs.  Transactions that only spend outputs of transactions which
+// are not in the set map to no hashes.
+//
+// An error is returned when the same transaction is passed more than once or
+// when the dependencies contain a cycle.  Cycles are not possible between
+// valid transactions since a transaction can't reference the hash of a
+// transaction that spends it, but they are reported rather than assumed away.
+func BuildDependencyGraph(txs []*wire.MsgTx) (map[chainhash.Hash][]chainhash.Hash, error) {
+	hashes := make([]chainhash.Hash, 0, len(txs))
+	edges := make(map[chainhash.Hash][]chainhash.Hash, len(txs))
+	for _, tx := range txs {
+		txHash := tx.TxHash()
+		if _, ok := edges[txHash]; ok {
+			return nil, fmt.Errorf("transaction %v is included more "+
+				"than once", txHash)
+		}
+		edges[txHash] = nil
+		hashes = append(hashes, txHash)
+	}
+
+	for i, tx := range txs {
+		txHash := hashes[i]
+		for _, txIn := range tx.TxIn {
+			parentHash := txIn.PreviousOutPoint.Hash
+			if _, ok := edges[parentHash]; !ok {
+				continue
+			}
+			if containsHash(edges[txHash], parentHash) {
+				continue
+			}
+			edges[txHash] = append(edges[txHash], parentHash)
+		}
+	}
+
+	if cycleHash, ok := findDependencyCycle(hashes, edges); ok {
+		return nil, fmt.Errorf("transaction %v depends on itself "+
+			"through its in-set ancestors", cycleHash)
+	}
+	return edges, nil
+}
+
+// containsHash returns whether or not the passed hash is in the passed slice.
+func containsHash(hashes []chainhash.Hash, hash chainhash.Hash) bool {
+	for i := range hashes {
+		if hashes[i] == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// findDependencyCycle performs a depth-first search of the passed dependency
+// graph starting from each of the passed hashes in turn and returns the hash of
+// a transaction which is part of a cycle, if any.
+func findDependencyCycle(hashes []chainhash.Hash, edges map[chainhash.Hash][]chainhash.Hash) (chainhash.Hash, bool) {
+	// Transactions are visiting while their ancestors are being searched
+	// and visited once the search of their ancestors is complete, so
+	// reaching a transaction that is still visiting means there is a cycle.
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[chainhash.Hash]int, len(edges))
+
+	var visit func(hash chainhash.Hash) (chainhash.Hash, bool)
+	visit = func(hash chainhash.Hash) (chainhash.Hash, bool) {
+		switch state[hash] {
+		case visiting:
+			return hash, true
+		case visited:
+			return chainhash.Hash{}, false
+		}
+
+		state[hash] = visiting
+		for _, parentHash := range edges[hash] {
+			if cycleHash, ok := visit(parentHash); ok {
+				return cycleHash, true
+			}
+		}
+		state[hash] = visited
+		return chainhash.Hash{}, false
+	}
+
+	for _, hash := range hashes {
+		if cycleHash, ok := visit(hash); ok {
+			return cycleHash, true
+		}
+	}
+	return chainhash.Hash{}, false
+}
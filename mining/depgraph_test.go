@@ -0,0 +1,91 @@
+// Copyright (c) 2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// TestBuildDependencyGraph ensures the dependencies between transactions in a
+// parent, child and grandchild chain are reported and that duplicate
+// transactions and cycles are rejected.
+func TestBuildDependencyGraph(t *testing.T) {
+	t.Parallel()
+
+	// spend returns a transaction with an input spending each of the
+	// passed outpoints and the passed number of outputs.
+	spend := func(numOutputs int, prevOuts ...wire.OutPoint) *wire.MsgTx {
+		tx := wire.NewMsgTx(1)
+		for i := range prevOuts {
+			tx.AddTxIn(wire.NewTxIn(&prevOuts[i], nil, nil))
+		}
+		for i := 0; i < numOutputs; i++ {
+			tx.AddTxOut(wire.NewTxOut(int64(1000+i), []byte{0x51}))
+		}
+		return tx
+	}
+
+	// The parent spends an output which is not in the set, the child
+	// spends both outputs of the parent, and the grandchild spends the
+	// child along with another output of the parent.
+	confirmed := wire.OutPoint{Hash: chainhash.Hash{0x01}, Index: 0}
+	parent := spend(3, confirmed)
+	parentHash := parent.TxHash()
+	child := spend(1, wire.OutPoint{Hash: parentHash, Index: 0},
+		wire.OutPoint{Hash: parentHash, Index: 1})
+	childHash := child.TxHash()
+	grandchild := spend(1, wire.OutPoint{Hash: childHash, Index: 0},
+		wire.OutPoint{Hash: parentHash, Index: 2})
+	grandchildHash := grandchild.TxHash()
+
+	want := map[chainhash.Hash][]chainhash.Hash{
+		parentHash:     nil,
+		childHash:      {parentHash},
+		grandchildHash: {childHash, parentHash},
+	}
+
+	// Ensure the graph does not depend on the order of the transactions.
+	orders := [][]*wire.MsgTx{
+		{parent, child, grandchild},
+		{grandchild, child, parent},
+	}
+	for _, txs := range orders {
+		edges, err := BuildDependencyGraph(txs)
+		if err != nil {
+			t.Fatalf("BuildDependencyGraph: unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(edges, want) {
+			t.Fatalf("BuildDependencyGraph: unexpected graph - got "+
+				"%v, want %v", edges, want)
+		}
+	}
+
+	// Ensure a transaction included more than once is rejected.
+	_, err := BuildDependencyGraph([]*wire.MsgTx{parent, child, parent})
+	if err == nil {
+		t.Fatal("BuildDependencyGraph: did not receive expected error " +
+			"for duplicate transaction")
+	}
+
+	// Cycles can't be created with real transactions, so ensure they are
+	// detected directly.
+	a, b, c := chainhash.Hash{0x0a}, chainhash.Hash{0x0b}, chainhash.Hash{0x0c}
+	acyclic := map[chainhash.Hash][]chainhash.Hash{
+		a: nil, b: {a}, c: {a, b},
+	}
+	if _, ok := findDependencyCycle([]chainhash.Hash{a, b, c}, acyclic); ok {
+		t.Fatal("findDependencyCycle: unexpected cycle in acyclic graph")
+	}
+	cyclic := map[chainhash.Hash][]chainhash.Hash{
+		a: {c}, b: {a}, c: {b},
+	}
+	if _, ok := findDependencyCycle([]chainhash.Hash{a, b, c}, cyclic); !ok {
+		t.Fatal("findDependencyCycle: did not detect cycle")
+	}
+}
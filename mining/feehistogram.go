@@ -0,0 +1,55 @@
+// Copyright (c) 2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"sort"
+
+	"github.com/navcoin/navd/blockchain"
+)
+
+// FeeHistogram returns the combined virtual size of the passed entries grouped
+// by fee rate.  The tiers are the lower bounds of the histogram buckets in
+// atoms per virtual byte and may be provided in any order.  Each entry is
+// counted towards the highest tier that does not exceed its fee rate, so the
+// returned map is keyed by tier and houses the total virtual size of the
+// entries in each bucket.
+//
+// Entries which pay a fee rate below the lowest tier are not counted.  Every
+// tier is present in the returned map, including those without any entries.
+func FeeHistogram(entries []MempoolEntry, tiers []int64) map[int64]int64 {
+	histogram := make(map[int64]int64, len(tiers))
+	for _, tier := range tiers {
+		histogram[tier] = 0
+	}
+	if len(tiers) == 0 {
+		return histogram
+	}
+
+	// Visit the tiers from highest to lowest so each entry is counted
+	// towards the first one its fee rate reaches.
+	sorted := make([]int64, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] > sorted[j]
+	})
+
+	for i := range entries {
+		entry := &entries[i]
+		virtualSize := (entry.Weight + blockchain.WitnessScaleFactor - 1) /
+			blockchain.WitnessScaleFactor
+
+		// The fee rate is compared by scaling the tier by the virtual
+		// size rather than dividing the fee to avoid truncation.
+		for _, tier := range sorted {
+			if entry.Fee >= tier*virtualSize {
+				histogram[tier] += virtualSize
+				break
+			}
+		}
+	}
+
+	return histogram
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestFeeHistogram ensures mempool entries are bucketed by fee rate tier.
+func TestFeeHistogram(t *testing.T) {
+	// The entries have the following fee rates and virtual sizes:
+	//
+	//   fee rate 1    vsize 100  -> below the lowest tier
+	//   fee rate 2    vsize 250  -> tier 2
+	//   fee rate 4.99 vsize 100  -> tier 2
+	//   fee rate 5    vsize 200  -> tier 5
+	//   fee rate 12   vsize 50   -> tier 10
+	//   fee rate 100  vsize 10   -> tier 50
+	//
+	// The 4.99 entry has a weight of 397 which rounds up to a virtual size
+	// of 100.
+	entries := []MempoolEntry{
+		{Hash: *newHashFromStr("01"), Fee: 100, Weight: 400},
+		{Hash: *newHashFromStr("02"), Fee: 500, Weight: 1000},
+		{Hash: *newHashFromStr("03"), Fee: 499, Weight: 397},
+		{Hash: *newHashFromStr("04"), Fee: 1000, Weight: 800},
+		{Hash: *newHashFromStr("05"), Fee: 600, Weight: 200},
+		{Hash: *newHashFromStr("06"), Fee: 1000, Weight: 40},
+	}
+
+	tests := []struct {
+		name    string
+		entries []MempoolEntry
+		tiers   []int64
+		want    map[int64]int64
+	}{
+		{
+			name:    "hand computed",
+			entries: entries,
+			tiers:   []int64{2, 5, 10, 20, 50},
+			want: map[int64]int64{
+				2: 350, 5: 200, 10: 50, 20: 0, 50: 10,
+			},
+		},
+		{
+			name:    "unsorted tiers",
+			entries: entries,
+			tiers:   []int64{50, 10, 2, 20, 5},
+			want: map[int64]int64{
+				2: 350, 5: 200, 10: 50, 20: 0, 50: 10,
+			},
+		},
+		{
+			name:    "zero tier counts everything",
+			entries: entries,
+			tiers:   []int64{0, 10},
+			want:    map[int64]int64{0: 650, 10: 60},
+		},
+		{
+			name:    "all below lowest tier",
+			entries: entries,
+			tiers:   []int64{1000},
+			want:    map[int64]int64{1000: 0},
+		},
+		{
+			name:    "empty mempool",
+			entries: nil,
+			tiers:   []int64{1, 10},
+			want:    map[int64]int64{1: 0, 10: 0},
+		},
+		{
+			name:    "no tiers",
+			entries: entries,
+			tiers:   nil,
+			want:    map[int64]int64{},
+		},
+	}
+
+	for _, test := range tests {
+		got := FeeHistogram(test.entries, test.tiers)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: unexpected histogram - got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
@@ -92,11 +92,10 @@ func calcInputValueAge(tx *wire.MsgTx, utxoView *blockchain.UtxoViewpoint, nextB
 	return totalInputAge
 }
 
-// CalcPriority returns a transaction priority given a transaction and the sum
-// of each of its input values multiplied by their age (# of confirmations).
-// Thus, the final formula for the priority is:
-// sum(inputValue * inputAge) / adjustedTxSize
-func CalcPriority(tx *wire.MsgTx, utxoView *blockchain.UtxoViewpoint, nextBlockHeight int32) float64 {
+// calcPriorityTxSize returns the size of the passed transaction as used by
+// the priority calculations, which is its serialized size less an allowance
+// for each of its inputs.
+func calcPriorityTxSize(tx *wire.MsgTx) int {
 	// In order to encourage spending multiple old unspent transaction
 	// outputs thereby reducing the total set, don't count the constant
 	// overhead for each input as well as enough bytes of the signature
@@ -125,9 +124,55 @@ func CalcPriority(tx *wire.MsgTx, utxoView *blockchain.UtxoViewpoint, nextBlockH
 
 	serializedTxSize := tx.SerializeSize()
 	if overhead >= serializedTxSize {
+		return 0
+	}
+	return serializedTxSize - overhead
+}
+
+// CalcPriority returns a transaction priority given a transaction and the sum
+// of each of its input values multiplied by their age (# of confirmations).
+// Thus, the final formula for the priority is:
+// sum(inputValue * inputAge) / adjustedTxSize
+func CalcPriority(tx *wire.MsgTx, utxoView *blockchain.UtxoViewpoint, nextBlockHeight int32) float64 {
+	txSize := calcPriorityTxSize(tx)
+	if txSize == 0 {
 		return 0.0
 	}
 
 	inputValueAge := calcInputValueAge(tx, utxoView, nextBlockHeight)
-	return inputValueAge / float64(serializedTxSize-overhead)
+	return inputValueAge / float64(txSize)
+}
+
+// CalcPriorityFromInputs returns a transaction priority in the same manner as
+// CalcPriority, except the value and number of confirmations of each input
+// are provided directly by the caller instead of being looked up in a utxo
+// view.  The input values and depths are expected to be in the same order as
+// the inputs of the transaction.
+//
+// Inputs which do not have a corresponding value and depth, as well as those
+// with no confirmations, contribute no input age to the transaction.  A
+// coinbase transaction does not spend any previous outputs and therefore
+// always has a priority of zero.
+func CalcPriorityFromInputs(tx *wire.MsgTx, inputValues []int64, inputDepths []int32) float64 {
+	if blockchain.IsCoinBaseTx(tx) {
+		return 0.0
+	}
+
+	txSize := calcPriorityTxSize(tx)
+	if txSize == 0 {
+		return 0.0
+	}
+
+	var totalInputAge float64
+	for i := range tx.TxIn {
+		if i >= len(inputValues) || i >= len(inputDepths) {
+			break
+		}
+		if inputDepths[i] <= 0 {
+			continue
+		}
+		totalInputAge += float64(inputValues[i] * int64(inputDepths[i]))
+	}
+
+	return totalInputAge / float64(txSize)
 }
@@ -166,3 +166,101 @@ func TestCalcPriority(t *testing.T) {
 		}
 	}
 }
+
+// TestCalcPriorityFromInputs ensures the priority calculations from
+// caller-provided input values and depths work as intended.
+func TestCalcPriorityFromInputs(t *testing.T) {
+	// redeemTx has two inputs with 10 byte signature scripts, which are
+	// entirely discounted, and a single 25 byte pay-to-pubkey-hash output.
+	// Its adjusted size is therefore the 15 bytes of fixed fields and
+	// counts plus the 34 byte output for a total of 49 bytes.
+	redeemTx := &wire.MsgTx{
+		Version: 1,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{
+				Hash:  *newHashFromStr("01"),
+				Index: 0,
+			},
+			SignatureScript: make([]byte, 10),
+			Sequence:        0xffffffff,
+		}, {
+			PreviousOutPoint: wire.OutPoint{
+				Hash:  *newHashFromStr("02"),
+				Index: 1,
+			},
+			SignatureScript: make([]byte, 10),
+			Sequence:        0xffffffff,
+		}},
+		TxOut: []*wire.TxOut{{
+			Value: 1000000000,
+			PkScript: hexToBytes("76a914000000000000000000000000" +
+				"000000000000000088ac"),
+		}},
+		LockTime: 0,
+	}
+
+	coinbaseTx := &wire.MsgTx{
+		Version: 1,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{
+				Hash:  chainhash.Hash{},
+				Index: wire.MaxPrevOutIndex,
+			},
+			SignatureScript: hexToBytes("04ffff001d0134"),
+			Sequence:        0xffffffff,
+		}},
+		TxOut: []*wire.TxOut{{
+			Value:    5000000000,
+			PkScript: hexToBytes("51"),
+		}},
+	}
+
+	tests := []struct {
+		name   string      // test description
+		tx     *wire.MsgTx // tx to calc priority for
+		values []int64     // input values
+		depths []int32     // input confirmations
+		want   float64     // expected priority
+	}{
+		{
+			// (9e8 * 1 + 4.5e8 * 2) / 49
+			name:   "two confirmed inputs",
+			tx:     redeemTx,
+			values: []int64{900000000, 450000000},
+			depths: []int32{1, 2},
+			want:   1.8e9 / 49,
+		},
+		{
+			// 9e8 * 1 / 49
+			name:   "one unconfirmed input",
+			tx:     redeemTx,
+			values: []int64{900000000, 450000000},
+			depths: []int32{1, 0},
+			want:   9e8 / 49,
+		},
+		{
+			name:   "missing input details",
+			tx:     redeemTx,
+			values: []int64{900000000},
+			depths: []int32{1},
+			want:   9e8 / 49,
+		},
+		{
+			name:   "coinbase",
+			tx:     coinbaseTx,
+			values: []int64{5000000000},
+			depths: []int32{100},
+			want:   0,
+		},
+	}
+
+	for i, test := range tests {
+		got := CalcPriorityFromInputs(test.tx, test.values, test.depths)
+		if got != test.want {
+			t.Errorf("CalcPriorityFromInputs #%d (%q): unexpected "+
+				"priority got %v want %v", i, test.name, got,
+				test.want)
+			continue
+		}
+	}
+}
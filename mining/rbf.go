@@ -0,0 +1,165 @@
+// Copyright (c) 2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"fmt"
+
+	"github.com/navcoin/navd/blockchain"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// MaxReplacementEvictions is the maximum number of transactions, including
+// descendants, that a single replacement transaction may evict from the
+// mempool as defined by rule 5 of BIP0125.
+const MaxReplacementEvictions = 100
+
+// ReplacementRule identifies one of the BIP0125 rules a replacement
+// transaction must satisfy.
+type ReplacementRule int
+
+// These constants identify the BIP0125 rules in the order they are defined.
+const (
+	// RuleSignalsReplacement indicates the replaced transactions must
+	// signal replaceability, either explicitly or through inheritance.
+	RuleSignalsReplacement ReplacementRule = iota + 1
+
+	// RuleNoNewUnconfirmedInputs indicates the replacement may only spend
+	// unconfirmed outputs that were already spent by the replaced
+	// transactions.
+	RuleNoNewUnconfirmedInputs
+
+	// RuleHigherAbsoluteFee indicates the replacement must pay at least
+	// the sum of the fees paid by the replaced transactions.
+	RuleHigherAbsoluteFee
+
+	// RulePaysForBandwidth indicates the additional fee paid by the
+	// replacement must cover its own relay at the minimum relay fee.
+	RulePaysForBandwidth
+
+	// RuleMaxReplacements indicates the replacement may not evict more
+	// than MaxReplacementEvictions transactions.
+	RuleMaxReplacements
+)
+
+// Map of ReplacementRule values back to their constant names for pretty
+// printing.
+var replacementRuleStrings = map[ReplacementRule]string{
+	RuleSignalsReplacement:     "RuleSignalsReplacement",
+	RuleNoNewUnconfirmedInputs: "RuleNoNewUnconfirmedInputs",
+	RuleHigherAbsoluteFee:      "RuleHigherAbsoluteFee",
+	RulePaysForBandwidth:       "RulePaysForBandwidth",
+	RuleMaxReplacements:        "RuleMaxReplacements",
+}
+
+// String returns the ReplacementRule as a human-readable name.
+func (r ReplacementRule) String() string {
+	if s := replacementRuleStrings[r]; s != "" {
+		return s
+	}
+	return fmt.Sprintf("Unknown ReplacementRule (%d)", int(r))
+}
+
+// ReplacementError identifies a replacement transaction which violates one
+// of the BIP0125 rules.  The caller can use type assertions to determine
+// whether a failure was due to a replacement rule and access the Rule field
+// to ascertain which rule was violated.
+type ReplacementError struct {
+	Rule        ReplacementRule // The rule that was violated
+	Description string          // Human readable description of the issue
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e ReplacementError) Error() string {
+	return e.Description
+}
+
+// replacementError creates a ReplacementError given a set of arguments.
+func replacementError(rule ReplacementRule, desc string) ReplacementError {
+	return ReplacementError{Rule: rule, Description: desc}
+}
+
+// CheckReplacement ensures newTx may replace the passed conflicts according to
+// the rules defined by BIP0125.  The conflicts must house every transaction
+// the replacement would evict from the mempool, which is the transactions
+// that spend the same outputs as newTx along with all of their descendants.
+// The Depends field of newTx must only house the hashes of transactions in
+// the mempool, while relayFee is the minimum relay fee in atoms per kilobyte.
+//
+// A ReplacementError identifying the violated rule is returned when the
+// replacement is not allowed.
+func CheckReplacement(newTx MempoolEntry, conflicts []MempoolEntry, relayFee int64) error {
+	// Conflicts which do not spend another conflict are spent directly by
+	// the replacement and must signal replaceability themselves, while the
+	// remaining ones are their descendants and inherit it.  Only the
+	// parents of the direct conflicts may be spent by the replacement since
+	// the parents of their descendants are not necessarily spent by them.
+	conflictSet := make(map[chainhash.Hash]struct{}, len(conflicts))
+	for i := range conflicts {
+		conflictSet[conflicts[i].Hash] = struct{}{}
+	}
+	parents := make(map[chainhash.Hash]struct{})
+	var conflictFees int64
+	for i := range conflicts {
+		conflict := &conflicts[i]
+		conflictFees += conflict.Fee
+
+		isDescendant := false
+		for _, parent := range conflict.Depends {
+			if _, ok := conflictSet[parent]; ok {
+				isDescendant = true
+				break
+			}
+		}
+		if isDescendant {
+			continue
+		}
+		if !conflict.SignalsReplacement {
+			str := fmt.Sprintf("replacement transaction %v spends "+
+				"outputs of %v which does not signal "+
+				"replaceability", newTx.Hash, conflict.Hash)
+			return replacementError(RuleSignalsReplacement, str)
+		}
+		for _, parent := range conflict.Depends {
+			parents[parent] = struct{}{}
+		}
+	}
+
+	for _, parent := range newTx.Depends {
+		if _, ok := parents[parent]; !ok {
+			str := fmt.Sprintf("replacement transaction %v spends "+
+				"new unconfirmed outputs of %v", newTx.Hash,
+				parent)
+			return replacementError(RuleNoNewUnconfirmedInputs, str)
+		}
+	}
+
+	if newTx.Fee < conflictFees {
+		str := fmt.Sprintf("replacement transaction %v has an "+
+			"insufficient absolute fee: needs %d, has %d",
+			newTx.Hash, conflictFees, newTx.Fee)
+		return replacementError(RuleHigherAbsoluteFee, str)
+	}
+
+	virtualSize := (newTx.Weight + blockchain.WitnessScaleFactor - 1) /
+		blockchain.WitnessScaleFactor
+	minFeeDelta := virtualSize * relayFee / 1000
+	if newTx.Fee-conflictFees < minFeeDelta {
+		str := fmt.Sprintf("replacement transaction %v has an "+
+			"insufficient fee delta to pay for relay: needs %d, "+
+			"has %d", newTx.Hash, minFeeDelta,
+			newTx.Fee-conflictFees)
+		return replacementError(RulePaysForBandwidth, str)
+	}
+
+	if len(conflicts) > MaxReplacementEvictions {
+		str := fmt.Sprintf("replacement transaction %v evicts %d "+
+			"transactions which exceeds the max allowed of %d",
+			newTx.Hash, len(conflicts), MaxReplacementEvictions)
+		return replacementError(RuleMaxReplacements, str)
+	}
+
+	return nil
+}
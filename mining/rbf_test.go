@@ -0,0 +1,149 @@
+// Copyright (c) 2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// TestCheckReplacement ensures replacement transactions are checked against
+// each of the BIP0125 rules.
+func TestCheckReplacement(t *testing.T) {
+	// original spends the unconfirmed mempoolParent and signals
+	// replaceability, while descendant spends original and inherits it.
+	// Together they pay a fee of 3000 which the replacement must exceed by
+	// enough to pay for its own relay at 1000 atoms per kilobyte.
+	mempoolParent := *newHashFromStr("01")
+	original := MempoolEntry{
+		Hash:               *newHashFromStr("02"),
+		Fee:                1000,
+		Weight:             800,
+		Depends:            []chainhash.Hash{mempoolParent},
+		SignalsReplacement: true,
+	}
+	descendant := MempoolEntry{
+		Hash:    *newHashFromStr("03"),
+		Fee:     2000,
+		Weight:  800,
+		Depends: []chainhash.Hash{original.Hash},
+	}
+	conflicts := []MempoolEntry{original, descendant}
+	const relayFee = 1000
+
+	// newReplacement returns a replacement with the given fee which spends
+	// the same unconfirmed output as the original and has a virtual size of
+	// 250 bytes.
+	newReplacement := func(fee int64) MempoolEntry {
+		return MempoolEntry{
+			Hash:    *newHashFromStr("04"),
+			Fee:     fee,
+			Weight:  1000,
+			Depends: []chainhash.Hash{mempoolParent},
+		}
+	}
+
+	nonSignaling := original
+	nonSignaling.SignalsReplacement = false
+
+	newUnconfirmed := newReplacement(5000)
+	newUnconfirmed.Depends = append(newUnconfirmed.Depends,
+		*newHashFromStr("05"))
+
+	// descendantParent is an unconfirmed parent of the descendant only, so
+	// the replacement may not spend it since the original does not.
+	descendantParent := *newHashFromStr("06")
+	descendantWithParent := descendant
+	descendantWithParent.Depends = []chainhash.Hash{original.Hash,
+		descendantParent}
+	spendsDescendantParent := newReplacement(5000)
+	spendsDescendantParent.Depends = append(spendsDescendantParent.Depends,
+		descendantParent)
+
+	tooMany := make([]MempoolEntry, MaxReplacementEvictions+1)
+	for i := range tooMany {
+		tooMany[i] = MempoolEntry{
+			Hash:               chainhash.Hash{byte(i), byte(i >> 8)},
+			Fee:                10,
+			Weight:             400,
+			SignalsReplacement: true,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		newTx     MempoolEntry
+		conflicts []MempoolEntry
+		wantRule  ReplacementRule // 0 when the replacement is allowed
+	}{
+		{
+			name:      "valid replacement",
+			newTx:     newReplacement(3250),
+			conflicts: conflicts,
+		},
+		{
+			name:      "original does not signal",
+			newTx:     newReplacement(5000),
+			conflicts: []MempoolEntry{nonSignaling, descendant},
+			wantRule:  RuleSignalsReplacement,
+		},
+		{
+			name:      "new unconfirmed input",
+			newTx:     newUnconfirmed,
+			conflicts: conflicts,
+			wantRule:  RuleNoNewUnconfirmedInputs,
+		},
+		{
+			name:      "valid replacement of descendant with new parent",
+			newTx:     newReplacement(5000),
+			conflicts: []MempoolEntry{original, descendantWithParent},
+		},
+		{
+			name:      "new unconfirmed input from descendant parent",
+			newTx:     spendsDescendantParent,
+			conflicts: []MempoolEntry{original, descendantWithParent},
+			wantRule:  RuleNoNewUnconfirmedInputs,
+		},
+		{
+			name:      "lower absolute fee",
+			newTx:     newReplacement(2999),
+			conflicts: conflicts,
+			wantRule:  RuleHigherAbsoluteFee,
+		},
+		{
+			name:      "fee delta below relay fee",
+			newTx:     newReplacement(3249),
+			conflicts: conflicts,
+			wantRule:  RulePaysForBandwidth,
+		},
+		{
+			name:      "too many evictions",
+			newTx:     MempoolEntry{Hash: *newHashFromStr("04"), Fee: 1e8, Weight: 1000},
+			conflicts: tooMany,
+			wantRule:  RuleMaxReplacements,
+		},
+	}
+
+	for _, test := range tests {
+		err := CheckReplacement(test.newTx, test.conflicts, relayFee)
+		if test.wantRule == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		rerr, ok := err.(ReplacementError)
+		if !ok {
+			t.Errorf("%s: did not receive expected ReplacementError "+
+				"- got %T (%v)", test.name, err, err)
+			continue
+		}
+		if rerr.Rule != test.wantRule {
+			t.Errorf("%s: unexpected rule - got %v, want %v",
+				test.name, rerr.Rule, test.wantRule)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// MempoolEntry describes a transaction that is a candidate for inclusion in a
+// block template along with the information needed to select it by ancestor
+// fee rate.
+type MempoolEntry struct {
+	// Hash is the hash of the transaction.
+	Hash chainhash.Hash
+
+	// Fee is the total fee the transaction pays.
+	Fee int64
+
+	// Weight is the weight of the transaction.
+	Weight int64
+
+	// Depends houses the hashes of the transactions the transaction spends
+	// outputs from.  Hashes that do not refer to another entry in the same
+	// selection are assumed to already be in the chain and are ignored.
+	Depends []chainhash.Hash
+
+	// SignalsReplacement indicates whether the transaction, or one of its
+	// unconfirmed ancestors, signals that it may be replaced as defined by
+	// BIP0125.  It is only consulted by CheckReplacement.
+	SignalsReplacement bool
+}
+
+// selectionPackage houses a candidate transaction along with its unselected
+// ancestors and their combined fee and weight.
+type selectionPackage struct {
+	entry     *MempoolEntry
+	ancestors []*MempoolEntry
+	fee       int64
+	weight    int64
+}
+
+// feeRate returns the fee rate of the package in fee per unit of weight.
+func (p *selectionPackage) feeRate() float64 {
+	if p.weight <= 0 {
+		return 0
+	}
+	return float64(p.fee) / float64(p.weight)
+}
+
+// betterThan returns whether the package should be selected before the
+// passed package.  Packages with a higher fee rate are preferred, and ties are
+// broken by transaction hash so the selection is deterministic.
+func (p *selectionPackage) betterThan(other *selectionPackage) bool {
+	pRate, otherRate := p.feeRate(), other.feeRate()
+	if pRate != otherRate {
+		return pRate > otherRate
+	}
+	return bytes.Compare(p.entry.Hash[:], other.entry.Hash[:]) < 0
+}
+
+// sortedHashes returns a copy of the passed hashes sorted in ascending byte
+// order.
+func sortedHashes(hashes []chainhash.Hash) []chainhash.Hash {
+	sorted := make([]chainhash.Hash, len(hashes))
+	copy(sorted, hashes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+	return sorted
+}
+
+// SelectBlockTransactions returns the hashes of the transactions from the
+// passed entries to include in a block template, in an order that is valid for
+// inclusion in a block, such that their combined weight does not exceed the
+// provided limit.
+//
+// Transactions are selected greedily by ancestor fee rate, which is the
+// combined fee of a transaction and all of its unselected ancestors divided by
+// their combined weight.  This allows a high fee child to pay for its low fee
+// parents (CPFP).  Whenever a transaction is selected, all of its ancestors are
+// selected along with it and every parent precedes its children in the
+// returned ordering.  Packages that do not fit in the remaining weight are
+// skipped, along with any transactions that depend on them.
+//
+// The selection is deterministic for a given set of entries regardless of
+// their order.  Duplicate entries are ignored.
+func SelectBlockTransactions(entries []MempoolEntry, weightLimit int64) []chainhash.Hash {
+	pool := make(map[chainhash.Hash]*MempoolEntry, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		if _, ok := pool[entry.Hash]; ok {
+			continue
+		}
+		pool[entry.Hash] = entry
+	}
+
+	selected := make(map[chainhash.Hash]struct{}, len(pool))
+	failed := make(map[chainhash.Hash]struct{})
+
+	// unselectedAncestors returns the unselected ancestors of the passed
+	// entry, parents first, along with whether any of them was already
+	// rejected for not fitting.  Parents are visited in hash order so the
+	// result is deterministic.
+	unselectedAncestors := func(entry *MempoolEntry) ([]*MempoolEntry, bool) {
+		var ancestors []*MempoolEntry
+		visited := make(map[chainhash.Hash]struct{})
+		blocked := false
+		var visit func(e *MempoolEntry)
+		visit = func(e *MempoolEntry) {
+			for _, parentHash := range sortedHashes(e.Depends) {
+				parent, ok := pool[parentHash]
+				if !ok {
+					continue
+				}
+				if _, ok := selected[parentHash]; ok {
+					continue
+				}
+				if _, ok := visited[parentHash]; ok {
+					continue
+				}
+				visited[parentHash] = struct{}{}
+				if _, ok := failed[parentHash]; ok {
+					blocked = true
+				}
+				visit(parent)
+				ancestors = append(ancestors, parent)
+			}
+		}
+		visited[entry.Hash] = struct{}{}
+		visit(entry)
+		return ancestors, blocked
+	}
+
+	var (
+		order      []chainhash.Hash
+		usedWeight int64
+	)
+	for {
+		// Find the unselected transaction with the best ancestor fee
+		// rate.
+		var best *selectionPackage
+		for hash, entry := range pool {
+			if _, ok := selected[hash]; ok {
+				continue
+			}
+			if _, ok := failed[hash]; ok {
+				continue
+			}
+
+			ancestors, blocked := unselectedAncestors(entry)
+			if blocked {
+				failed[hash] = struct{}{}
+				continue
+			}
+
+			pkg := &selectionPackage{
+				entry:     entry,
+				ancestors: ancestors,
+				fee:       entry.Fee,
+				weight:    entry.Weight,
+			}
+			for _, ancestor := range ancestors {
+				pkg.fee += ancestor.Fee
+				pkg.weight += ancestor.Weight
+			}
+			if best == nil || pkg.betterThan(best) {
+				best = pkg
+			}
+		}
+		if best == nil {
+			break
+		}
+
+		// Skip the package when it does not fit.  Its descendants are
+		// skipped as well since their packages include it.
+		if usedWeight+best.weight > weightLimit {
+			failed[best.entry.Hash] = struct{}{}
+			continue
+		}
+
+		usedWeight += best.weight
+		for _, ancestor := range best.ancestors {
+			selected[ancestor.Hash] = struct{}{}
+			order = append(order, ancestor.Hash)
+		}
+		selected[best.entry.Hash] = struct{}{}
+		order = append(order, best.entry.Hash)
+	}
+
+	return order
+}
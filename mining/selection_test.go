@@ -0,0 +1,149 @@
+// Copyright (c) 2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// TestSelectBlockTransactions ensures block template transactions are
+// selected by ancestor fee rate in a valid order and within the weight limit.
+func TestSelectBlockTransactions(t *testing.T) {
+	// parent pays a low fee but has a child paying a high fee, so the two
+	// are selected together ahead of the independent transactions.
+	// grandchild spends child and pays enough to keep the package rate
+	// high.  confirmedSpender depends on a transaction which is not part
+	// of the selection.
+	parent := MempoolEntry{Hash: *newHashFromStr("01"), Fee: 100, Weight: 400}
+	child := MempoolEntry{
+		Hash:    *newHashFromStr("02"),
+		Fee:     5000,
+		Weight:  400,
+		Depends: []chainhash.Hash{parent.Hash},
+	}
+	grandchild := MempoolEntry{
+		Hash:    *newHashFromStr("03"),
+		Fee:     3000,
+		Weight:  400,
+		Depends: []chainhash.Hash{child.Hash, parent.Hash},
+	}
+	independent := MempoolEntry{Hash: *newHashFromStr("04"), Fee: 2000, Weight: 400}
+	confirmedSpender := MempoolEntry{
+		Hash:    *newHashFromStr("05"),
+		Fee:     1000,
+		Weight:  400,
+		Depends: []chainhash.Hash{*newHashFromStr("ff")},
+	}
+
+	entries := []MempoolEntry{
+		grandchild, confirmedSpender, independent, child, parent,
+	}
+
+	tests := []struct {
+		name        string
+		entries     []MempoolEntry
+		weightLimit int64
+		want        []chainhash.Hash
+	}{
+		{
+			name:        "everything fits",
+			entries:     entries,
+			weightLimit: 2000,
+			want: []chainhash.Hash{
+				parent.Hash, child.Hash, grandchild.Hash,
+				independent.Hash, confirmedSpender.Hash,
+			},
+		},
+		{
+			name:        "low fee parent pulled in by child",
+			entries:     entries,
+			weightLimit: 1200,
+			want: []chainhash.Hash{
+				parent.Hash, child.Hash, grandchild.Hash,
+			},
+		},
+		{
+			name:        "remaining weight filled by lower rate",
+			entries:     entries,
+			weightLimit: 1600,
+			want: []chainhash.Hash{
+				parent.Hash, child.Hash, grandchild.Hash,
+				independent.Hash,
+			},
+		},
+		{
+			name:        "package too large for limit",
+			entries:     entries,
+			weightLimit: 799,
+			want: []chainhash.Hash{
+				independent.Hash,
+			},
+		},
+		{
+			name:        "nothing fits",
+			entries:     entries,
+			weightLimit: 399,
+			want:        nil,
+		},
+		{
+			name: "duplicate entries",
+			entries: []MempoolEntry{
+				independent, parent, independent,
+			},
+			weightLimit: 2000,
+			want: []chainhash.Hash{
+				independent.Hash, parent.Hash,
+			},
+		},
+		{
+			name:        "no entries",
+			entries:     nil,
+			weightLimit: 2000,
+			want:        nil,
+		},
+	}
+
+	for i, test := range tests {
+		got := SelectBlockTransactions(test.entries, test.weightLimit)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SelectBlockTransactions #%d (%q): unexpected "+
+				"selection got %v want %v", i, test.name, got,
+				test.want)
+			continue
+		}
+
+		// Ensure the weight limit is honored and every transaction
+		// appears after the transactions it depends on.
+		pool := make(map[chainhash.Hash]MempoolEntry)
+		for _, entry := range test.entries {
+			pool[entry.Hash] = entry
+		}
+		seen := make(map[chainhash.Hash]struct{})
+		var weight int64
+		for _, hash := range got {
+			entry := pool[hash]
+			for _, dep := range entry.Depends {
+				if _, ok := pool[dep]; !ok {
+					continue
+				}
+				if _, ok := seen[dep]; !ok {
+					t.Errorf("SelectBlockTransactions #%d (%q): "+
+						"%v selected before its parent %v", i,
+						test.name, hash, dep)
+				}
+			}
+			seen[hash] = struct{}{}
+			weight += entry.Weight
+		}
+		if weight > test.weightLimit {
+			t.Errorf("SelectBlockTransactions #%d (%q): selected "+
+				"weight %d exceeds limit %d", i, test.name,
+				weight, test.weightLimit)
+		}
+	}
+}
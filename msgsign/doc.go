@@ -0,0 +1,19 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package msgsign provides helpers for creating and verifying navcoin signed
+messages as used by the signmessage and verifymessage RPCs.
+
+Signed messages carry a 65-byte compact recoverable signature over the hash of
+the message.  The first byte of the signature encodes the recovery id along
+with a flag indicating whether the signing key was compressed, which allows
+the public key to be recovered from the signature alone and compared against
+the address the message claims to be signed by.
+
+The hash which is signed commits to a network specific magic string followed
+by the message itself.  MessageHash computes it given the MessageMagic from the
+chain parameters of the network in use.
+*/
+package msgsign
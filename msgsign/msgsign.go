@@ -0,0 +1,79 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package msgsign
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+const (
+	// CompactSigSize is the size of a compact recoverable signature.  It
+	// consists of a single header byte followed by the 32-byte R and S
+	// values.
+	CompactSigSize = 65
+
+	// compactSigMagicOffset is the value added to the recovery id to form
+	// the header byte of a compact signature.
+	compactSigMagicOffset = 27
+
+	// compactSigCompPubKey is the value added to the header byte of a
+	// compact signature when the signing key was compressed.
+	compactSigCompPubKey = 4
+)
+
+var (
+	// ErrInvalidSigLen describes an error in which the compact signature
+	// passed to RecoverCompact is not CompactSigSize bytes.
+	ErrInvalidSigLen = errors.New("compact signature must be 65 bytes")
+
+	// ErrInvalidRecoveryID describes an error in which the header byte of
+	// the compact signature passed to RecoverCompact does not encode a
+	// valid recovery id.
+	ErrInvalidRecoveryID = errors.New("invalid compact signature recovery id")
+)
+
+// RecoverCompact recovers the public key which produced the passed compact
+// recoverable signature over msgHash.  The returned boolean reports whether
+// the signature indicates the signing key was compressed, which determines the
+// serialization to use when deriving the address of the recovered key.
+//
+// ErrInvalidSigLen is returned for signatures which are not exactly
+// CompactSigSize bytes and ErrInvalidRecoveryID is returned when the header
+// byte does not encode a recovery id in the range [0, 3].
+func RecoverCompact(sig []byte, msgHash []byte) (*btcec.PublicKey, bool, error) {
+	if len(sig) != CompactSigSize {
+		return nil, false, ErrInvalidSigLen
+	}
+
+	// The header byte is 27 + the recovery id, plus 4 when the key is
+	// compressed, so the only valid values are 27 through 34 inclusive.
+	header := sig[0]
+	if header < compactSigMagicOffset ||
+		header >= compactSigMagicOffset+2*compactSigCompPubKey {
+
+		return nil, false, ErrInvalidRecoveryID
+	}
+
+	return btcec.RecoverCompact(btcec.S256(), sig, msgHash)
+}
+
+// MessageHash returns the double-SHA256 digest of the passed message which is
+// signed by the signmessage RPC and checked by verifymessage.  Both the magic
+// and the message are serialized as variable length strings, so each is
+// prefixed with its length encoded as a variable length integer.  The magic
+// for a network is provided by the MessageMagic field of its chain parameters.
+func MessageHash(magic string, message string) chainhash.Hash {
+	var buf bytes.Buffer
+
+	// Writing to a bytes.Buffer never fails, so the errors are ignored.
+	wire.WriteVarString(&buf, 0, magic)
+	wire.WriteVarString(&buf, 0, message)
+	return chainhash.DoubleHashH(buf.Bytes())
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package msgsign
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// hexToBytes converts the passed hex string into bytes and will panic if there
+// is an error.  This is only provided for the hard-coded constants so errors in
+// the source code can be detected.  It will only (and must only) be called with
+// hard-coded values.
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
+
+// TestRecoverCompact ensures signing a message hash with a compact signature
+// and recovering the public key from it round trips for both compressed and
+// uncompressed keys.
+func TestRecoverCompact(t *testing.T) {
+	t.Parallel()
+
+	privKey, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), hexToBytes(
+		"eaf02ca348c524e6392655ba4d29603cd1a7347d9d65cfe93ce1ebffdca22694"))
+	msgHash := chainhash.DoubleHashB([]byte("test message"))
+
+	for _, compressed := range []bool{false, true} {
+		sig, err := btcec.SignCompact(btcec.S256(), privKey, msgHash,
+			compressed)
+		if err != nil {
+			t.Fatalf("SignCompact (compressed %v): unexpected error: %v",
+				compressed, err)
+		}
+
+		gotKey, gotCompressed, err := RecoverCompact(sig, msgHash)
+		if err != nil {
+			t.Errorf("RecoverCompact (compressed %v): unexpected "+
+				"error: %v", compressed, err)
+			continue
+		}
+		if !gotKey.IsEqual(pubKey) {
+			t.Errorf("RecoverCompact (compressed %v): recovered key "+
+				"mismatch - got %x, want %x", compressed,
+				gotKey.SerializeCompressed(),
+				pubKey.SerializeCompressed())
+		}
+		if gotCompressed != compressed {
+			t.Errorf("RecoverCompact: compression flag mismatch - "+
+				"got %v, want %v", gotCompressed, compressed)
+		}
+
+		// Recovering against a different hash must not produce the
+		// original key.
+		otherHash := chainhash.DoubleHashB([]byte("other message"))
+		gotKey, _, err = RecoverCompact(sig, otherHash)
+		if err == nil && gotKey.IsEqual(pubKey) {
+			t.Errorf("RecoverCompact (compressed %v): recovered "+
+				"signing key from wrong hash", compressed)
+		}
+	}
+}
+
+// TestRecoverCompactErrors ensures malformed compact signatures are rejected
+// with the expected errors.
+func TestRecoverCompactErrors(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), hexToBytes(
+		"eaf02ca348c524e6392655ba4d29603cd1a7347d9d65cfe93ce1ebffdca22694"))
+	msgHash := chainhash.DoubleHashB([]byte("test message"))
+	sig, err := btcec.SignCompact(btcec.S256(), privKey, msgHash, true)
+	if err != nil {
+		t.Fatalf("SignCompact: unexpected error: %v", err)
+	}
+
+	withHeader := func(header byte) []byte {
+		s := make([]byte, len(sig))
+		copy(s, sig)
+		s[0] = header
+		return s
+	}
+
+	tests := []struct {
+		name string
+		sig  []byte
+		err  error
+	}{
+		{"empty", nil, ErrInvalidSigLen},
+		{"short", sig[:CompactSigSize-1], ErrInvalidSigLen},
+		{"long", append(append([]byte{}, sig...), 0x00), ErrInvalidSigLen},
+		{"header zero", withHeader(0), ErrInvalidRecoveryID},
+		{"header below range", withHeader(26), ErrInvalidRecoveryID},
+		{"header above range", withHeader(35), ErrInvalidRecoveryID},
+		{"header max", withHeader(0xff), ErrInvalidRecoveryID},
+	}
+
+	for _, test := range tests {
+		_, _, err := RecoverCompact(test.sig, msgHash)
+		if err != test.err {
+			t.Errorf("%s: mismatched error - got %v, want %v",
+				test.name, err, test.err)
+		}
+	}
+}
+
+// TestMessageHash ensures MessageHash produces the expected signed message
+// digests, including for messages long enough to require multi-byte length
+// prefixes.
+func TestMessageHash(t *testing.T) {
+	t.Parallel()
+
+	const navMagic = "NavCoin Signed Message:\n"
+	tests := []struct {
+		name    string
+		magic   string
+		message string
+		want    string
+	}{
+		{
+			name:    "bitcoin magic",
+			magic:   "Bitcoin Signed Message:\n",
+			message: "Hello World",
+			want:    "490f55c83e9311bce17647cc31f117eff3bc1a0d3a9bc67fb999aed5aa0bafa7",
+		},
+		{
+			name:    "navcoin magic",
+			magic:   navMagic,
+			message: "Hello World",
+			want:    "021605cc76bc1d9dcbfc6e54d75bd428f8e46499f2331423af6230edc79fa506",
+		},
+		{
+			name:    "empty message",
+			magic:   navMagic,
+			message: "",
+			want:    "cf4ebdc1bf2dd32a2288f1ed91757194f363b297a17f7c8431a37f1ff0105a23",
+		},
+		{
+			name:    "3-byte length prefix",
+			magic:   navMagic,
+			message: strings.Repeat("a", 253),
+			want:    "ec59f1e2a91f658a22c8f2c585e689d7d9d7bd29c0cf21060671e711dbda1994",
+		},
+		{
+			name:    "5-byte length prefix",
+			magic:   navMagic,
+			message: strings.Repeat("a", 65536),
+			want:    "0c4199d806ea85e04ada54f33163ea255c19b33e3a662749d1f803ff5bd04942",
+		},
+	}
+
+	for _, test := range tests {
+		got := MessageHash(test.magic, test.message)
+		if got.String() != test.want {
+			t.Errorf("%s: mismatched hash - got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
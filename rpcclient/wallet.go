@@ -757,21 +757,21 @@ type FutureAddMultisigAddressResult chan *response
 
 // Receive waits for the response promised by the future and returns the
 // multisignature address that requires the specified number of signatures for
-// the provided addresses.
-func (r FutureAddMultisigAddressResult) Receive() (navutil.Address, error) {
+// the provided addresses along with the script needed to redeem it.
+func (r FutureAddMultisigAddressResult) Receive() (*btcjson.AddMultisigAddressResult, error) {
 	res, err := receiveFuture(r)
 	if err != nil {
 		return nil, err
 	}
 
-	// Unmarshal result as a string.
-	var addr string
-	err = json.Unmarshal(res, &addr)
+	// Unmarshal result as an addmultisigaddress result object.
+	var multisigRes btcjson.AddMultisigAddressResult
+	err = json.Unmarshal(res, &multisigRes)
 	if err != nil {
 		return nil, err
 	}
 
-	return navutil.DecodeAddress(addr, &chaincfg.MainNetParams)
+	return &multisigRes, nil
 }
 
 // AddMultisigAddressAsync returns an instance of a type that can be used to get
@@ -779,21 +779,22 @@ func (r FutureAddMultisigAddressResult) Receive() (navutil.Address, error) {
 // the returned instance.
 //
 // See AddMultisigAddress for the blocking version and more details.
-func (c *Client) AddMultisigAddressAsync(requiredSigs int, addresses []navutil.Address, account string) FutureAddMultisigAddressResult {
+func (c *Client) AddMultisigAddressAsync(requiredSigs int, addresses []navutil.Address, label string) FutureAddMultisigAddressResult {
 	addrs := make([]string, 0, len(addresses))
 	for _, addr := range addresses {
 		addrs = append(addrs, addr.String())
 	}
 
-	cmd := btcjson.NewAddMultisigAddressCmd(requiredSigs, addrs, &account)
+	cmd := btcjson.NewAddMultisigAddressCmd(requiredSigs, addrs, &label, nil)
 	return c.sendCmd(cmd)
 }
 
 // AddMultisigAddress adds a multisignature address that requires the specified
-// number of signatures for the provided addresses to the wallet.
-func (c *Client) AddMultisigAddress(requiredSigs int, addresses []navutil.Address, account string) (navutil.Address, error) {
+// number of signatures for the provided addresses to the wallet.  It returns
+// the address along with the script needed to redeem it.
+func (c *Client) AddMultisigAddress(requiredSigs int, addresses []navutil.Address, label string) (*btcjson.AddMultisigAddressResult, error) {
 	return c.AddMultisigAddressAsync(requiredSigs, addresses,
-		account).Receive()
+		label).Receive()
 }
 
 // FutureCreateMultisigResult is a future promise to deliver the result of a
@@ -829,7 +830,7 @@ func (c *Client) CreateMultisigAsync(requiredSigs int, addresses []navutil.Addre
 		addrs = append(addrs, addr.String())
 	}
 
-	cmd := btcjson.NewCreateMultisigCmd(requiredSigs, addrs)
+	cmd := btcjson.NewCreateMultisigCmd(requiredSigs, addrs, nil)
 	return c.sendCmd(cmd)
 }
 
@@ -1019,6 +1020,45 @@ func (c *Client) GetAccount(address navutil.Address) (string, error) {
 	return c.GetAccountAsync(address).Receive()
 }
 
+// FutureGetAddressInfoResult is a future promise to deliver the result of a
+// GetAddressInfoAsync RPC invocation (or an applicable error).
+type FutureGetAddressInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// information about the requested address.
+func (r FutureGetAddressInfoResult) Receive() (*btcjson.GetAddressInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getaddressinfo result object.
+	var addressInfo btcjson.GetAddressInfoResult
+	err = json.Unmarshal(res, &addressInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &addressInfo, nil
+}
+
+// GetAddressInfoAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See GetAddressInfo for the blocking version and more details.
+func (c *Client) GetAddressInfoAsync(address navutil.Address) FutureGetAddressInfoResult {
+	addr := address.EncodeAddress()
+	cmd := btcjson.NewGetAddressInfoCmd(addr)
+	return c.sendCmd(cmd)
+}
+
+// GetAddressInfo returns information about the passed address, such as its
+// script and whether it belongs to the wallet.
+func (c *Client) GetAddressInfo(address navutil.Address) (*btcjson.GetAddressInfoResult, error) {
+	return c.GetAddressInfoAsync(address).Receive()
+}
+
 // FutureSetAccountResult is a future promise to deliver the result of a
 // SetAccountAsync RPC invocation (or an applicable error).
 type FutureSetAccountResult chan *response
@@ -38,6 +38,7 @@ import (
 	"github.com/navcoin/navd/mempool"
 	"github.com/navcoin/navd/mining"
 	"github.com/navcoin/navd/mining/cpuminer"
+	"github.com/navcoin/navd/msgsign"
 	"github.com/navcoin/navd/peer"
 	"github.com/navcoin/navd/txscript"
 	"github.com/navcoin/navd/wire"
@@ -128,6 +129,7 @@ type commandHandler func(*rpcServer, interface{}, <-chan struct{}) (interface{},
 var rpcHandlers map[string]commandHandler
 var rpcHandlersBeforeInit = map[string]commandHandler{
 	"addnode":               handleAddNode,
+	"createmultisig":        handleCreateMultisig,
 	"createrawtransaction":  handleCreateRawTransaction,
 	"debuglevel":            handleDebugLevel,
 	"decoderawtransaction":  handleDecodeRawTransaction,
@@ -182,13 +184,13 @@ var rpcAskWallet = map[string]struct{}{
 	"addmultisigaddress":     {},
 	"backupwallet":           {},
 	"createencryptedwallet":  {},
-	"createmultisig":         {},
 	"dumpprivkey":            {},
 	"dumpwallet":             {},
 	"encryptwallet":          {},
 	"getaccount":             {},
 	"getaccountaddress":      {},
 	"getaddressesbyaccount":  {},
+	"getaddressinfo":         {},
 	"getbalance":             {},
 	"getnewaddress":          {},
 	"getrawchangeaddress":    {},
@@ -251,6 +253,7 @@ var rpcLimited = map[string]struct{}{
 	"help": {},
 
 	// HTTP/S-only commands
+	"createmultisig":        {},
 	"createrawtransaction":  {},
 	"decoderawtransaction":  {},
 	"decodescript":          {},
@@ -508,6 +511,193 @@ func messageToHex(msg wire.Message) (string, error) {
 	return hex.EncodeToString(buf.Bytes()), nil
 }
 
+// descriptorInputCharset and descriptorChecksumCharset are the character sets
+// used to compute and encode output script descriptor checksums respectively.
+const (
+	descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// descriptorChecksum returns the 8 character checksum of the passed output
+// script descriptor as defined by BIP0380.  The descriptor must only consist
+// of characters in descriptorInputCharset.
+func descriptorChecksum(desc string) string {
+	polymod := func(c uint64, val int) uint64 {
+		c0 := c >> 35
+		c = (c&0x7ffffffff)<<5 ^ uint64(val)
+		generator := [5]uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d,
+			0x3706b1677a, 0x644d626ffd}
+		for i := uint(0); i < 5; i++ {
+			if (c0>>i)&1 == 1 {
+				c ^= generator[i]
+			}
+		}
+		return c
+	}
+
+	// Each character is split into the position within its group of 32
+	// characters and the group, where every three groups are combined
+	// into an additional symbol.
+	c := uint64(1)
+	var groups [3]int
+	numGroups := 0
+	for i := 0; i < len(desc); i++ {
+		pos := strings.IndexByte(descriptorInputCharset, desc[i])
+		c = polymod(c, pos&31)
+		groups[numGroups] = pos >> 5
+		numGroups++
+		if numGroups == 3 {
+			c = polymod(c, groups[0]*9+groups[1]*3+groups[2])
+			numGroups = 0
+		}
+	}
+	switch numGroups {
+	case 1:
+		c = polymod(c, groups[0])
+	case 2:
+		c = polymod(c, groups[0]*3+groups[1])
+	}
+	for i := 0; i < 8; i++ {
+		c = polymod(c, 0)
+	}
+	c ^= 1
+
+	checksum := make([]byte, 8)
+	for i := range checksum {
+		checksum[i] = descriptorChecksumCharset[(c>>(5*uint(7-i)))&31]
+	}
+	return string(checksum)
+}
+
+// handleCreateMultisig handles createmultisig commands.
+func handleCreateMultisig(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.CreateMultisigCmd)
+
+	// The number of required signatures must be satisfiable by the
+	// provided keys.
+	if c.NRequired < 1 {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "A multisignature address must require at least one key to redeem",
+		}
+	}
+	if c.NRequired > len(c.Keys) {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidParameter,
+			Message: fmt.Sprintf("Not enough keys supplied (got %d "+
+				"keys, but need at least %d to redeem)",
+				len(c.Keys), c.NRequired),
+		}
+	}
+	if len(c.Keys) > txscript.MaxPubKeysPerMultiSig {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidParameter,
+			Message: fmt.Sprintf("Number of keys involved in the "+
+				"multisignature address creation > %d",
+				txscript.MaxPubKeysPerMultiSig),
+		}
+	}
+
+	// Only hex-encoded public keys are supported since there is no wallet
+	// to look up the public keys of addresses.  Outputs paying to witness
+	// scripts with uncompressed public keys can't be spent by standard
+	// transactions, so only compressed public keys are allowed for the
+	// segwit address types.
+	isWitness := *c.AddressType == "p2sh-segwit" ||
+		*c.AddressType == "bech32"
+	pubKeys := make([]*navutil.AddressPubKey, 0, len(c.Keys))
+	descKeys := make([]string, 0, len(c.Keys))
+	for _, key := range c.Keys {
+		serializedKey, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, rpcDecodeHexError(key)
+		}
+		if isWitness &&
+			len(serializedKey) != btcec.PubKeyBytesLenCompressed {
+
+			return nil, &btcjson.RPCError{
+				Code: btcjson.ErrRPCInvalidAddressOrKey,
+				Message: fmt.Sprintf("Compressed public key "+
+					"required for address type %q: %s",
+					*c.AddressType, key),
+			}
+		}
+		pubKey, err := navutil.NewAddressPubKey(serializedKey,
+			s.cfg.ChainParams)
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidAddressOrKey,
+				Message: "Invalid public key: " + key,
+			}
+		}
+		pubKeys = append(pubKeys, pubKey)
+		descKeys = append(descKeys, hex.EncodeToString(serializedKey))
+	}
+
+	script, err := txscript.MultiSigScript(pubKeys, c.NRequired)
+	if err != nil {
+		context := "Failed to create multisignature script"
+		return nil, internalRPCError(err.Error(), context)
+	}
+	multi := fmt.Sprintf("multi(%d,%s)", c.NRequired,
+		strings.Join(descKeys, ","))
+
+	// Wrap the script according to the requested address type.
+	var addr navutil.Address
+	var desc string
+	witnessScriptHash := sha256.Sum256(script)
+	switch *c.AddressType {
+	case "legacy":
+		if len(script) > txscript.MaxScriptElementSize {
+			return nil, &btcjson.RPCError{
+				Code: btcjson.ErrRPCInvalidParameter,
+				Message: fmt.Sprintf("Redeem script exceeds size "+
+					"limit: %d > %d", len(script),
+					txscript.MaxScriptElementSize),
+			}
+		}
+		addr, err = navutil.NewAddressScriptHash(script,
+			s.cfg.ChainParams)
+		desc = "sh(" + multi + ")"
+
+	case "p2sh-segwit":
+		builder := txscript.NewScriptBuilder().AddOp(txscript.OP_0)
+		builder.AddData(witnessScriptHash[:])
+		var witnessProgram []byte
+		witnessProgram, err = builder.Script()
+		if err != nil {
+			break
+		}
+		addr, err = navutil.NewAddressScriptHash(witnessProgram,
+			s.cfg.ChainParams)
+		desc = "sh(wsh(" + multi + "))"
+
+	case "bech32":
+		addr, err = navutil.NewAddressWitnessScriptHash(
+			witnessScriptHash[:], s.cfg.ChainParams)
+		desc = "wsh(" + multi + ")"
+
+	default:
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidParameter,
+			Message: fmt.Sprintf("Unknown address type %q",
+				*c.AddressType),
+		}
+	}
+	if err != nil {
+		context := "Failed to create multisignature address"
+		return nil, internalRPCError(err.Error(), context)
+	}
+
+	return &btcjson.CreateMultiSigResult{
+		Address:      addr.EncodeAddress(),
+		RedeemScript: hex.EncodeToString(script),
+		Descriptor:   desc + "#" + descriptorChecksum(desc),
+	}, nil
+}
+
 // handleCreateRawTransaction handles createrawtransaction commands.
 func handleCreateRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*btcjson.CreateRawTransactionCmd)
@@ -1081,13 +1271,26 @@ func handleGetBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 		}
 	}
 
-	// When the verbose flag isn't set, simply return the serialized block
+	// Determine the requested verbosity level.  The legacy verbosetx flag
+	// upgrades the default level to include the full transactions.
+	verbosity := btcjson.BlockVerbosityTxIDs
+	if c.Verbosity != nil {
+		verbosity = *c.Verbosity
+	}
+	if verbosity == btcjson.BlockVerbosityTxIDs && c.VerboseTx != nil &&
+		*c.VerboseTx {
+
+		verbosity = btcjson.BlockVerbosityTxs
+	}
+
+	// When the verbosity level is hex, simply return the serialized block
 	// as a hex-encoded string.
-	if c.Verbose != nil && !*c.Verbose {
+	if verbosity <= btcjson.BlockVerbosityHex {
 		return hex.EncodeToString(blkBytes), nil
 	}
 
-	// The verbose flag is set, so generate the JSON object and return it.
+	// The verbosity level calls for a JSON object, so generate it and
+	// return it.
 
 	// Deserialize the block.
 	blk, err := navutil.NewBlockFromBytes(blkBytes)
@@ -1136,7 +1339,7 @@ func handleGetBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 		NextHash:      nextHashString,
 	}
 
-	if c.VerboseTx == nil || !*c.VerboseTx {
+	if verbosity == btcjson.BlockVerbosityTxIDs {
 		transactions := blk.Transactions()
 		txNames := make([]string, len(transactions))
 		for i, tx := range transactions {
@@ -1144,22 +1347,39 @@ func handleGetBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 		}
 
 		blockReply.Tx = txNames
-	} else {
-		txns := blk.Transactions()
-		rawTxns := make([]btcjson.TxRawResult, len(txns))
-		for i, tx := range txns {
-			rawTxn, err := createTxRawResult(params, tx.MsgTx(),
-				tx.Hash().String(), blockHeader, hash.String(),
-				blockHeight, best.Height)
-			if err != nil {
-				return nil, err
-			}
-			rawTxns[i] = *rawTxn
-		}
-		blockReply.RawTx = rawTxns
+		return blockReply, nil
 	}
 
-	return blockReply, nil
+	txns := blk.Transactions()
+	rawTxns := make([]btcjson.TxRawResult, len(txns))
+	for i, tx := range txns {
+		rawTxn, err := createTxRawResult(params, tx.MsgTx(),
+			tx.Hash().String(), blockHeader, hash.String(),
+			blockHeight, best.Height)
+		if err != nil {
+			return nil, err
+		}
+		rawTxns[i] = *rawTxn
+	}
+
+	return btcjson.GetBlockVerboseTxResult{
+		Hash:          blockReply.Hash,
+		Confirmations: blockReply.Confirmations,
+		StrippedSize:  blockReply.StrippedSize,
+		Size:          blockReply.Size,
+		Weight:        blockReply.Weight,
+		Height:        blockReply.Height,
+		Version:       blockReply.Version,
+		VersionHex:    blockReply.VersionHex,
+		MerkleRoot:    blockReply.MerkleRoot,
+		Tx:            rawTxns,
+		Time:          blockReply.Time,
+		Nonce:         blockReply.Nonce,
+		Bits:          blockReply.Bits,
+		Difficulty:    blockReply.Difficulty,
+		PreviousHash:  blockReply.PreviousHash,
+		NextHash:      blockReply.NextHash,
+	}, nil
 }
 
 // softForkStatus converts a ThresholdState state into a human readable string
@@ -1197,41 +1417,31 @@ func handleGetBlockChainInfo(s *rpcServer, cmd interface{}, closeChan <-chan str
 		Difficulty:    getDifficultyRatio(chainSnapshot.Bits, params),
 		MedianTime:    chainSnapshot.MedianTime.Unix(),
 		Pruned:        false,
+		SoftForks:     make(map[string]*btcjson.SoftForkDescription),
 		Bip9SoftForks: make(map[string]*btcjson.Bip9SoftForkDescription),
 	}
+	chainInfo.InitialBlockDownload = !s.cfg.SyncMgr.IsCurrent()
 
 	// Next, populate the response with information describing the current
 	// status of soft-forks deployed via the super-majority block
-	// signalling mechanism.
+	// signalling mechanism, which are now buried at their activation
+	// heights.
 	height := chainSnapshot.Height
-	chainInfo.SoftForks = []*btcjson.SoftForkDescription{
-		{
-			ID:      "bip34",
-			Version: 2,
-			Reject: struct {
-				Status bool `json:"status"`
-			}{
-				Status: height >= params.BIP0034Height,
-			},
-		},
-		{
-			ID:      "bip66",
-			Version: 3,
-			Reject: struct {
-				Status bool `json:"status"`
-			}{
-				Status: height >= params.BIP0066Height,
-			},
-		},
-		{
-			ID:      "bip65",
-			Version: 4,
-			Reject: struct {
-				Status bool `json:"status"`
-			}{
-				Status: height >= params.BIP0065Height,
-			},
-		},
+	buriedForks := []struct {
+		name   string
+		height int32
+	}{
+		{"bip34", params.BIP0034Height},
+		{"bip66", params.BIP0066Height},
+		{"bip65", params.BIP0065Height},
+	}
+	for _, fork := range buriedForks {
+		forkHeight := fork.height
+		chainInfo.SoftForks[fork.name] = &btcjson.SoftForkDescription{
+			Type:   "buried",
+			Height: &forkHeight,
+			Active: height >= forkHeight,
+		}
 	}
 
 	// Finally, query the BIP0009 version bits state for all currently
@@ -1280,11 +1490,18 @@ func handleGetBlockChainInfo(s *rpcServer, cmd interface{}, closeChan <-chan str
 
 		// Finally, populate the soft-fork description with all the
 		// information gathered above.
-		chainInfo.Bip9SoftForks[forkName] = &btcjson.Bip9SoftForkDescription{
-			Status:    strings.ToLower(statusString),
-			Bit:       deploymentDetails.BitNumber,
-			StartTime: int64(deploymentDetails.StartTime),
-			Timeout:   int64(deploymentDetails.ExpireTime),
+		bip9Desc := &btcjson.Bip9SoftForkDescription{
+			Status:     strings.ToLower(statusString),
+			Bit:        deploymentDetails.BitNumber,
+			StartTime1: int64(deploymentDetails.StartTime),
+			StartTime2: int64(deploymentDetails.StartTime),
+			Timeout:    int64(deploymentDetails.ExpireTime),
+		}
+		chainInfo.Bip9SoftForks[forkName] = bip9Desc
+		chainInfo.SoftForks[forkName] = &btcjson.SoftForkDescription{
+			Type:   "bip9",
+			Bip9:   bip9Desc,
+			Active: deploymentStatus == blockchain.ThresholdActive,
 		}
 	}
 
@@ -1721,7 +1938,8 @@ func (state *gbtWorkState) blockTemplateResult(useCoinbaseValue bool, submitOld
 		bTx := navutil.NewTx(tx)
 		resultTx := btcjson.GetBlockTemplateResultTx{
 			Data:    hex.EncodeToString(txBuf.Bytes()),
-			Hash:    txHash.String(),
+			TxID:    txHash.String(),
+			Hash:    tx.WitnessHash().String(),
 			Depends: depends,
 			Fee:     template.Fees[i],
 			SigOps:  template.SigOpCosts[i],
@@ -1787,7 +2005,8 @@ func (state *gbtWorkState) blockTemplateResult(useCoinbaseValue bool, submitOld
 
 		resultTx := btcjson.GetBlockTemplateResultTx{
 			Data:    hex.EncodeToString(txBuf.Bytes()),
-			Hash:    tx.TxHash().String(),
+			TxID:    tx.TxHash().String(),
+			Hash:    tx.WitnessHash().String(),
 			Depends: []int64{},
 			Fee:     template.Fees[0],
 			SigOps:  template.SigOpCosts[0],
@@ -2345,11 +2564,11 @@ func handleGetMiningInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 	if err != nil {
 		return nil, err
 	}
-	networkHashesPerSec, ok := networkHashesPerSecIface.(int64)
+	networkHashesPerSec, ok := networkHashesPerSecIface.(float64)
 	if !ok {
 		return nil, &btcjson.RPCError{
 			Code:    btcjson.ErrRPCInternal.Code,
-			Message: "networkHashesPerSec is not an int64",
+			Message: "networkHashesPerSec is not a float64",
 		}
 	}
 
@@ -2366,6 +2585,7 @@ func handleGetMiningInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 		NetworkHashPS:      networkHashesPerSec,
 		PooledTx:           uint64(s.cfg.TxMemPool.Count()),
 		TestNet:            cfg.TestNet3,
+		Chain:              s.cfg.ChainParams.Name,
 	}
 	return &result, nil
 }
@@ -2399,7 +2619,7 @@ func handleGetNetworkHashPS(s *rpcServer, cmd interface{}, closeChan <-chan stru
 		endHeight = int32(*c.Height)
 	}
 	if endHeight > best.Height || endHeight == 0 {
-		return int64(0), nil
+		return float64(0), nil
 	}
 	if endHeight < 0 {
 		endHeight = best.Height
@@ -2468,11 +2688,13 @@ func handleGetNetworkHashPS(s *rpcServer, cmd interface{}, closeChan <-chan stru
 	// time difference.
 	timeDiff := int64(maxTimestamp.Sub(minTimestamp) / time.Second)
 	if timeDiff == 0 {
-		return int64(0), nil
+		return float64(0), nil
 	}
 
-	hashesPerSec := new(big.Int).Div(totalWork, big.NewInt(timeDiff))
-	return hashesPerSec.Int64(), nil
+	hashesPerSec := new(big.Float).Quo(new(big.Float).SetInt(totalWork),
+		new(big.Float).SetInt64(timeDiff))
+	result, _ := hashesPerSec.Float64()
+	return result, nil
 }
 
 // handleGetPeerInfo implements the getpeerinfo command.
@@ -3560,12 +3782,10 @@ func handleVerifyMessage(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 
 	// Validate the signature - this just shows that it was valid at all.
 	// we will compare it with the key next.
-	var buf bytes.Buffer
-	wire.WriteVarString(&buf, 0, "NavCoin Signed Message:\n")
-	wire.WriteVarString(&buf, 0, c.Message)
-	expectedMessageHash := chainhash.DoubleHashB(buf.Bytes())
-	pk, wasCompressed, err := btcec.RecoverCompact(btcec.S256(), sig,
-		expectedMessageHash)
+	expectedMessageHash := msgsign.MessageHash(params.MessageMagic,
+		c.Message)
+	pk, wasCompressed, err := msgsign.RecoverCompact(sig,
+		expectedMessageHash[:])
 	if err != nil {
 		// Mirror NavCoin Core behavior, which treats error in
 		// RecoverCompact as invalid signature.
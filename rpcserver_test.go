@@ -0,0 +1,209 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/navcoin/navd/btcjson"
+	"github.com/navcoin/navd/chaincfg"
+	"github.com/navcoin/navutil"
+)
+
+// TestDescriptorChecksum ensures descriptorChecksum produces the checksums of
+// the test vectors in BIP0380.
+func TestDescriptorChecksum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		want string
+	}{
+		{"raw(deadbeef)", "89f8spxm"},
+	}
+
+	for _, test := range tests {
+		if got := descriptorChecksum(test.desc); got != test.want {
+			t.Errorf("descriptorChecksum(%q): got %q, want %q",
+				test.desc, got, test.want)
+		}
+	}
+}
+
+// TestHandleCreateMultisig ensures the createmultisig handler returns the
+// expected address, redeem script and descriptor for every address type and
+// rejects invalid parameters.
+func TestHandleCreateMultisig(t *testing.T) {
+	t.Parallel()
+
+	// The compressed public keys of the private keys 1 and 2 along with the
+	// uncompressed public key of the private key 1.
+	const (
+		pubKey1 = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d9" +
+			"59f2815b16f81798"
+		pubKey2 = "02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7" +
+			"abac09b95c709ee5"
+		uncompressedPubKey1 = "0479be667ef9dcbbac55a06295ce870b07029bfcd" +
+			"b2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e11" +
+			"08a8fd17b448a68554199c47d08ffb10d4b8"
+	)
+	params := &chaincfg.MainNetParams
+
+	// redeemScript returns the 1-of-2 multisig script for the passed
+	// public keys.
+	redeemScript := func(key1, key2 string) []byte {
+		script, _ := hex.DecodeString("51" +
+			hex.EncodeToString([]byte{byte(len(key1) / 2)}) + key1 +
+			hex.EncodeToString([]byte{byte(len(key2) / 2)}) + key2 +
+			"52ae")
+		return script
+	}
+	script := redeemScript(pubKey1, pubKey2)
+	witnessScriptHash := sha256.Sum256(script)
+	witnessProgram := append([]byte{0x00, 0x20}, witnessScriptHash[:]...)
+	uncompressedScript := redeemScript(uncompressedPubKey1, pubKey2)
+
+	legacyAddr, err := navutil.NewAddressScriptHash(script, params)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHash: unexpected error: %v", err)
+	}
+	nestedAddr, err := navutil.NewAddressScriptHash(witnessProgram, params)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHash: unexpected error: %v", err)
+	}
+	bech32Addr, err := navutil.NewAddressWitnessScriptHash(
+		witnessScriptHash[:], params)
+	if err != nil {
+		t.Fatalf("NewAddressWitnessScriptHash: unexpected error: %v", err)
+	}
+	uncompressedAddr, err := navutil.NewAddressScriptHash(
+		uncompressedScript, params)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHash: unexpected error: %v", err)
+	}
+
+	multi := "multi(1," + pubKey1 + "," + pubKey2 + ")"
+	uncompressedMulti := "multi(1," + uncompressedPubKey1 + "," +
+		pubKey2 + ")"
+
+	tests := []struct {
+		name     string
+		params   []interface{}
+		wantAddr string
+		wantDesc string
+		wantCode btcjson.RPCErrorCode
+		script   []byte
+	}{{
+		name:     "default address type",
+		params:   []interface{}{1, []string{pubKey1, pubKey2}},
+		wantAddr: legacyAddr.EncodeAddress(),
+		wantDesc: "sh(" + multi + ")",
+		script:   script,
+	}, {
+		name: "legacy",
+		params: []interface{}{1, []string{pubKey1, pubKey2},
+			"legacy"},
+		wantAddr: legacyAddr.EncodeAddress(),
+		wantDesc: "sh(" + multi + ")",
+		script:   script,
+	}, {
+		name: "p2sh-segwit",
+		params: []interface{}{1, []string{pubKey1, pubKey2},
+			"p2sh-segwit"},
+		wantAddr: nestedAddr.EncodeAddress(),
+		wantDesc: "sh(wsh(" + multi + "))",
+		script:   script,
+	}, {
+		name: "bech32",
+		params: []interface{}{1, []string{pubKey1, pubKey2},
+			"bech32"},
+		wantAddr: bech32Addr.EncodeAddress(),
+		wantDesc: "wsh(" + multi + ")",
+		script:   script,
+	}, {
+		name: "legacy with uncompressed key",
+		params: []interface{}{1, []string{uncompressedPubKey1, pubKey2},
+			"legacy"},
+		wantAddr: uncompressedAddr.EncodeAddress(),
+		wantDesc: "sh(" + uncompressedMulti + ")",
+		script:   uncompressedScript,
+	}, {
+		name: "p2sh-segwit with uncompressed key",
+		params: []interface{}{1, []string{uncompressedPubKey1, pubKey2},
+			"p2sh-segwit"},
+		wantCode: btcjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name: "bech32 with uncompressed key",
+		params: []interface{}{1, []string{pubKey2, uncompressedPubKey1},
+			"bech32"},
+		wantCode: btcjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:     "no required signatures",
+		params:   []interface{}{0, []string{pubKey1, pubKey2}},
+		wantCode: btcjson.ErrRPCInvalidParameter,
+	}, {
+		name:     "more required signatures than keys",
+		params:   []interface{}{3, []string{pubKey1, pubKey2}},
+		wantCode: btcjson.ErrRPCInvalidParameter,
+	}, {
+		name:     "key not hex",
+		params:   []interface{}{1, []string{pubKey1, "zz"}},
+		wantCode: btcjson.ErrRPCDecodeHexString,
+	}, {
+		name:     "invalid key",
+		params:   []interface{}{1, []string{pubKey1, "0102"}},
+		wantCode: btcjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name: "unknown address type",
+		params: []interface{}{1, []string{pubKey1, pubKey2},
+			"taproot"},
+		wantCode: btcjson.ErrRPCInvalidParameter,
+	}}
+
+	s := &rpcServer{cfg: rpcserverConfig{ChainParams: params}}
+	for _, test := range tests {
+		req, err := btcjson.NewRequest(1, "createmultisig", test.params)
+		if err != nil {
+			t.Errorf("%s: NewRequest: unexpected error: %v",
+				test.name, err)
+			continue
+		}
+		cmd, err := btcjson.UnmarshalCmd(req)
+		if err != nil {
+			t.Errorf("%s: UnmarshalCmd: unexpected error: %v",
+				test.name, err)
+			continue
+		}
+
+		result, err := handleCreateMultisig(s, cmd, nil)
+		if test.wantCode != 0 {
+			rpcErr, ok := err.(*btcjson.RPCError)
+			if !ok || rpcErr.Code != test.wantCode {
+				t.Errorf("%s: unexpected error - got %v, want "+
+					"code %d", test.name, err, test.wantCode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+
+		res := result.(*btcjson.CreateMultiSigResult)
+		wantDesc := test.wantDesc + "#" +
+			descriptorChecksum(test.wantDesc)
+		if res.Address != test.wantAddr ||
+			res.RedeemScript != hex.EncodeToString(test.script) ||
+			res.Descriptor != wantDesc {
+
+			t.Errorf("%s: unexpected result - got %+v, want "+
+				"address %s, redeem script %x, descriptor %s",
+				test.name, res, test.wantAddr, test.script,
+				wantDesc)
+		}
+	}
+}
@@ -40,6 +40,17 @@ var helpDescsEnUS = map[string]string{
 	"node-target":        "Either the IP address and port of the peer to operate on, or a valid peer ID.",
 	"node-connectsubcmd": "'perm' to make the connected peer a permanent one, 'temp' to try a single connect to a peer",
 
+	// CreateMultisigCmd help.
+	"createmultisig--synopsis":   "Creates a multisignature address which requires the specified number of the provided public keys to redeem.",
+	"createmultisig-nrequired":   "The number of signatures required to redeem the address; must not exceed the number of keys",
+	"createmultisig-keys":        "The hex-encoded public keys which may sign to redeem the address",
+	"createmultisig-addresstype": "The type of address to create: 'legacy', 'p2sh-segwit', or 'bech32' (the segwit types require compressed public keys)",
+
+	// CreateMultiSigResult help.
+	"createmultisigresult-address":      "The multisignature address",
+	"createmultisigresult-redeemScript": "The hex-encoded redeem script of the address",
+	"createmultisigresult-descriptor":   "The output script descriptor of the address",
+
 	// TransactionInput help.
 	"transactioninput-txid": "The hash of the input transaction",
 	"transactioninput-vout": "The specific output of the input transaction to redeem",
@@ -162,10 +173,11 @@ var helpDescsEnUS = map[string]string{
 	// GetBlockCmd help.
 	"getblock--synopsis":   "Returns information about a block given its hash.",
 	"getblock-hash":        "The hash of the block",
-	"getblock-verbose":     "Specifies the block is returned as a JSON object instead of hex-encoded string",
-	"getblock-verbosetx":   "Specifies that each transaction is returned as a JSON object and only applies if the verbose flag is true (navd extension)",
-	"getblock--condition0": "verbose=false",
-	"getblock--condition1": "verbose=true",
+	"getblock-verbosity":   "Specifies the block is returned as a hex-encoded string (0), a JSON object with transaction hashes (1), or a JSON object with transactions as JSON objects (2); the legacy boolean verbose flag is also accepted",
+	"getblock-verbosetx":   "Legacy flag that specifies each transaction is returned as a JSON object and only applies if the verbosity is 1 (navd extension)",
+	"getblock--condition0": "verbosity=0",
+	"getblock--condition1": "verbosity=1",
+	"getblock--condition2": "verbosity=2",
 	"getblock--result0":    "Hex-encoded bytes of the serialized block",
 
 	// GetBlockChainInfoCmd help.
@@ -179,21 +191,22 @@ var helpDescsEnUS = map[string]string{
 	"getblockchaininforesult-difficulty":            "The current chain difficulty",
 	"getblockchaininforesult-mediantime":            "The median time from the PoV of the best block in the chain",
 	"getblockchaininforesult-verificationprogress":  "An estimate for how much of the best chain we've verified",
+	"getblockchaininforesult-initialblockdownload":  "Whether the node is still downloading the initial block chain",
+	"getblockchaininforesult-chainwork":             "The total cumulative work in the best chain",
+	"getblockchaininforesult-size_on_disk":          "The estimated size of the block and undo files on disk (only present when known)",
 	"getblockchaininforesult-pruned":                "A bool that indicates if the node is pruned or not",
 	"getblockchaininforesult-pruneheight":           "The lowest block retained in the current pruned chain",
-	"getblockchaininforesult-chainwork":             "The total cumulative work in the best chain",
-	"getblockchaininforesult-softforks":             "The status of the super-majority soft-forks",
+	"getblockchaininforesult-automatic_pruning":     "Whether automatic pruning is enabled (only present if pruning is enabled)",
+	"getblockchaininforesult-prune_target_size":     "The target size used by pruning (only present if automatic pruning is enabled)",
+	"getblockchaininforesult-softforks":             "JSON object describing the known soft-fork deployments",
+	"getblockchaininforesult-softforks--key":        "softforks",
+	"getblockchaininforesult-softforks--value":      "An object describing a particular buried or BIP0009 deployment",
+	"getblockchaininforesult-softforks--desc":       "The status of all known soft-fork deployments",
 	"getblockchaininforesult-bip9_softforks":        "JSON object describing active BIP0009 deployments",
 	"getblockchaininforesult-bip9_softforks--key":   "bip9_softforks",
 	"getblockchaininforesult-bip9_softforks--value": "An object describing a particular BIP009 deployment",
 	"getblockchaininforesult-bip9_softforks--desc":  "The status of any defined BIP0009 soft-fork deployments",
 
-	// SoftForkDescription help.
-	"softforkdescription-reject":  "The current activation status of the softfork",
-	"softforkdescription-version": "The block version that signals enforcement of this softfork",
-	"softforkdescription-id":      "The string identifier for the soft fork",
-	"-status":                     "A bool which indicates if the soft fork is active",
-
 	// TxRawResult help.
 	"txrawresult-hex":           "Hex-encoded transaction",
 	"txrawresult-txid":          "The hash of the transaction",
@@ -232,8 +245,7 @@ var helpDescsEnUS = map[string]string{
 	"getblockverboseresult-version":           "The block version",
 	"getblockverboseresult-versionHex":        "The block version in hexidecimal",
 	"getblockverboseresult-merkleroot":        "Root hash of the merkle tree",
-	"getblockverboseresult-tx":                "The transaction hashes (only when verbosetx=false)",
-	"getblockverboseresult-rawtx":             "The transactions as JSON objects (only when verbosetx=true)",
+	"getblockverboseresult-tx":                "The transaction hashes",
 	"getblockverboseresult-time":              "The block time in seconds since 1 Jan 1970 GMT",
 	"getblockverboseresult-nonce":             "The block nonce",
 	"getblockverboseresult-bits":              "The bits which represent the block difficulty",
@@ -243,6 +255,24 @@ var helpDescsEnUS = map[string]string{
 	"getblockverboseresult-strippedsize":      "The size of the block without witness data",
 	"getblockverboseresult-weight":            "The weight of the block",
 
+	// GetBlockVerboseTxResult help.
+	"getblockverbosetxresult-hash":              "The hash of the block (same as provided)",
+	"getblockverbosetxresult-confirmations":     "The number of confirmations",
+	"getblockverbosetxresult-size":              "The size of the block",
+	"getblockverbosetxresult-height":            "The height of the block in the block chain",
+	"getblockverbosetxresult-version":           "The block version",
+	"getblockverbosetxresult-versionHex":        "The block version in hexidecimal",
+	"getblockverbosetxresult-merkleroot":        "Root hash of the merkle tree",
+	"getblockverbosetxresult-tx":                "The transactions as JSON objects",
+	"getblockverbosetxresult-time":              "The block time in seconds since 1 Jan 1970 GMT",
+	"getblockverbosetxresult-nonce":             "The block nonce",
+	"getblockverbosetxresult-bits":              "The bits which represent the block difficulty",
+	"getblockverbosetxresult-difficulty":        "The proof-of-work difficulty as a multiple of the minimum difficulty",
+	"getblockverbosetxresult-previousblockhash": "The hash of the previous block",
+	"getblockverbosetxresult-nextblockhash":     "The hash of the next block (only if there is one)",
+	"getblockverbosetxresult-strippedsize":      "The size of the block without witness data",
+	"getblockverbosetxresult-weight":            "The weight of the block",
+
 	// GetBlockCountCmd help.
 	"getblockcount--synopsis": "Returns the number of blocks in the longest block chain.",
 	"getblockcount--result0":  "The current block count",
@@ -277,6 +307,7 @@ var helpDescsEnUS = map[string]string{
 	// TemplateRequest help.
 	"templaterequest-mode":         "This is 'template', 'proposal', or omitted",
 	"templaterequest-capabilities": "List of capabilities",
+	"templaterequest-rules":        "List of rules supported by the client, such as 'segwit'",
 	"templaterequest-longpollid":   "The long poll ID of a job to monitor for expiration; required and valid only for long poll requests ",
 	"templaterequest-sigoplimit":   "Number of signature operations allowed in blocks (this parameter is ignored)",
 	"templaterequest-sizelimit":    "Number of bytes allowed in blocks (this parameter is ignored)",
@@ -287,7 +318,8 @@ var helpDescsEnUS = map[string]string{
 
 	// GetBlockTemplateResultTx help.
 	"getblocktemplateresulttx-data":    "Hex-encoded transaction data (byte-for-byte)",
-	"getblocktemplateresulttx-hash":    "Hex-encoded transaction hash (little endian if treated as a 256-bit number)",
+	"getblocktemplateresulttx-txid":    "Hex-encoded transaction id excluding witness data (little endian if treated as a 256-bit number)",
+	"getblocktemplateresulttx-hash":    "Hex-encoded transaction hash including witness data (little endian if treated as a 256-bit number)",
 	"getblocktemplateresulttx-depends": "Other transactions before this one (by 1-based index in the 'transactions'  list) that must be present in the final block if this one is",
 	"getblocktemplateresulttx-fee":     "Difference in value between transaction inputs and outputs (in Satoshi)",
 	"getblocktemplateresulttx-sigops":  "Total number of signature operations as counted for purposes of block limits",
@@ -416,6 +448,8 @@ var helpDescsEnUS = map[string]string{
 	"getmininginforesult-networkhashps":      "Estimated network hashes per second for the most recent blocks",
 	"getmininginforesult-pooledtx":           "Number of transactions in the memory pool",
 	"getmininginforesult-testnet":            "Whether or not server is using testnet",
+	"getmininginforesult-chain":              "The name of the chain the server is using",
+	"getmininginforesult-warnings":           "Any network and blockchain warnings",
 
 	// GetMiningInfoCmd help.
 	"getmininginfo--synopsis": "Returns a JSON object containing mining-related information.",
@@ -461,14 +495,23 @@ var helpDescsEnUS = map[string]string{
 	"getpeerinfo--synopsis": "Returns data about each connected network peer as an array of json objects.",
 
 	// GetRawMempoolVerboseResult help.
-	"getrawmempoolverboseresult-size":             "Transaction size in bytes",
-	"getrawmempoolverboseresult-fee":              "Transaction fee in navcoins",
-	"getrawmempoolverboseresult-time":             "Local time transaction entered pool in seconds since 1 Jan 1970 GMT",
-	"getrawmempoolverboseresult-height":           "Block height when transaction entered the pool",
-	"getrawmempoolverboseresult-startingpriority": "Priority when transaction entered the pool",
-	"getrawmempoolverboseresult-currentpriority":  "Current priority",
-	"getrawmempoolverboseresult-depends":          "Unconfirmed transactions used as inputs for this transaction",
-	"getrawmempoolverboseresult-vsize":            "The virtual size of a transaction",
+	"getrawmempoolverboseresult-size":               "Transaction size in bytes",
+	"getrawmempoolverboseresult-vsize":              "The virtual size of a transaction",
+	"getrawmempoolverboseresult-weight":             "The weight of the transaction as defined in BIP 141",
+	"getrawmempoolverboseresult-fee":                "Transaction fee in navcoins",
+	"getrawmempoolverboseresult-modifiedfee":        "Transaction fee with fee deltas used for mining priority in navcoins",
+	"getrawmempoolverboseresult-time":               "Local time transaction entered pool in seconds since 1 Jan 1970 GMT",
+	"getrawmempoolverboseresult-height":             "Block height when transaction entered the pool",
+	"getrawmempoolverboseresult-startingpriority":   "Priority when transaction entered the pool",
+	"getrawmempoolverboseresult-currentpriority":    "Current priority",
+	"getrawmempoolverboseresult-descendantcount":    "Number of in-mempool descendant transactions (including this one)",
+	"getrawmempoolverboseresult-descendantsize":     "Virtual size of in-mempool descendants (including this one)",
+	"getrawmempoolverboseresult-ancestorcount":      "Number of in-mempool ancestor transactions (including this one)",
+	"getrawmempoolverboseresult-ancestorsize":       "Virtual size of in-mempool ancestors (including this one)",
+	"getrawmempoolverboseresult-depends":            "Unconfirmed transactions used as inputs for this transaction",
+	"getrawmempoolverboseresult-spentby":            "Unconfirmed transactions spending outputs from this transaction",
+	"getrawmempoolverboseresult-bip125-replaceable": "Whether this transaction could be replaced due to BIP125 (replace-by-fee)",
+	"getrawmempoolverboseresult-unbroadcast":        "Whether this transaction is currently unbroadcast (initial broadcast not yet acknowledged by any peers)",
 
 	// GetRawMempoolCmd help.
 	"getrawmempool--synopsis":   "Returns information about all of the transactions currently in the memory pool.",
@@ -668,6 +711,7 @@ var helpDescsEnUS = map[string]string{
 // pointer to the type (or nil to indicate no return value).
 var rpcResultTypes = map[string][]interface{}{
 	"addnode":               nil,
+	"createmultisig":        {(*btcjson.CreateMultiSigResult)(nil)},
 	"createrawtransaction":  {(*string)(nil)},
 	"debuglevel":            {(*string)(nil), (*string)(nil)},
 	"decoderawtransaction":  {(*btcjson.TxRawDecodeResult)(nil)},
@@ -677,7 +721,7 @@ var rpcResultTypes = map[string][]interface{}{
 	"getaddednodeinfo":      {(*[]string)(nil), (*[]btcjson.GetAddedNodeInfoResult)(nil)},
 	"getbestblock":          {(*btcjson.GetBestBlockResult)(nil)},
 	"getbestblockhash":      {(*string)(nil)},
-	"getblock":              {(*string)(nil), (*btcjson.GetBlockVerboseResult)(nil)},
+	"getblock":              {(*string)(nil), (*btcjson.GetBlockVerboseResult)(nil), (*btcjson.GetBlockVerboseTxResult)(nil)},
 	"getblockcount":         {(*int64)(nil)},
 	"getblockhash":          {(*string)(nil)},
 	"getblockheader":        {(*string)(nil), (*btcjson.GetBlockHeaderVerboseResult)(nil)},
@@ -694,7 +738,7 @@ var rpcResultTypes = map[string][]interface{}{
 	"getmempoolinfo":        {(*btcjson.GetMempoolInfoResult)(nil)},
 	"getmininginfo":         {(*btcjson.GetMiningInfoResult)(nil)},
 	"getnettotals":          {(*btcjson.GetNetTotalsResult)(nil)},
-	"getnetworkhashps":      {(*int64)(nil)},
+	"getnetworkhashps":      {(*float64)(nil)},
 	"getpeerinfo":           {(*[]btcjson.GetPeerInfoResult)(nil)},
 	"getrawmempool":         {(*[]string)(nil), (*btcjson.GetRawMempoolVerboseResult)(nil)},
 	"getrawtransaction":     {(*string)(nil), (*btcjson.TxRawResult)(nil)},
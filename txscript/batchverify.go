@@ -0,0 +1,79 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"fmt"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// VerifyItem houses a signature along with the public key and signature hash
+// it is to be verified against.
+type VerifyItem struct {
+	SigHash chainhash.Hash
+	Sig     *btcec.Signature
+	PubKey  *btcec.PublicKey
+}
+
+// verifyItem verifies a single item, consulting and populating the passed
+// signature cache when it is not nil.
+func verifyItem(item *VerifyItem, cache *SigCache) error {
+	if item.Sig == nil || item.PubKey == nil {
+		return scriptError(ErrSigVerify, "batch verify item is missing "+
+			"its signature or public key")
+	}
+
+	if cache != nil && cache.Exists(item.SigHash, item.Sig, item.PubKey) {
+		return nil
+	}
+	if !item.Sig.Verify(item.SigHash[:], item.PubKey) {
+		str := fmt.Sprintf("signature is not valid for sighash %v",
+			item.SigHash)
+		return scriptError(ErrSigVerify, str)
+	}
+	if cache != nil {
+		cache.Add(item.SigHash, item.Sig, item.PubKey)
+	}
+	return nil
+}
+
+// BatchVerify verifies every one of the passed items and returns a slice of
+// errors which aligns with them, where a nil entry means the item's signature
+// is valid.  Items with an invalid signature have an error with the
+// ErrSigVerify code.
+//
+// The signature cache may be nil.  Otherwise, items which are already in the
+// cache are not verified again and the valid signatures are added to it.
+func BatchVerify(items []VerifyItem, cache *SigCache) []error {
+	errs := make([]error, len(items))
+	for i := range items {
+		errs[i] = verifyItem(&items[i], cache)
+	}
+	return errs
+}
+
+// BatchVerifyShortCircuit is the same as BatchVerify except verification stops
+// at the first item whose signature is invalid.  Every item after it has an
+// error with the ErrSigNotVerified code, so callers that only need to know
+// whether all of the signatures are valid avoid verifying the rest.
+func BatchVerifyShortCircuit(items []VerifyItem, cache *SigCache) []error {
+	errs := make([]error, len(items))
+	for i := range items {
+		errs[i] = verifyItem(&items[i], cache)
+		if errs[i] == nil {
+			continue
+		}
+
+		for j := i + 1; j < len(items); j++ {
+			str := fmt.Sprintf("item %d not verified due to the "+
+				"failure of item %d", j, i)
+			errs[j] = scriptError(ErrSigNotVerified, str)
+		}
+		break
+	}
+	return errs
+}
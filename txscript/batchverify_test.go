@@ -0,0 +1,114 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"testing"
+)
+
+// TestBatchVerify ensures the errors returned by the batch verification
+// functions align with the passed items, that valid signatures are added to
+// the signature cache, and that short circuit verification skips the items
+// after the first failure.
+func TestBatchVerify(t *testing.T) {
+	t.Parallel()
+
+	// Generate a few valid signatures along with invalid variants which
+	// use the wrong signature hash or the wrong public key.
+	var valid []VerifyItem
+	for i := 0; i < 3; i++ {
+		msg, sig, key, err := genRandomSig()
+		if err != nil {
+			t.Fatalf("unable to generate random signature test data")
+		}
+		valid = append(valid, VerifyItem{*msg, sig, key})
+	}
+	wrongHash := valid[0]
+	wrongHash.SigHash[0] ^= 0x01
+	wrongKey := valid[1]
+	wrongKey.PubKey = valid[2].PubKey
+	missingSig := VerifyItem{SigHash: valid[0].SigHash, PubKey: valid[0].PubKey}
+
+	items := []VerifyItem{
+		valid[0], wrongHash, valid[1], wrongKey, valid[2], missingSig,
+	}
+	wantCodes := []ErrorCode{
+		-1, ErrSigVerify, -1, ErrSigVerify, -1, ErrSigVerify,
+	}
+
+	// checkErrs ensures the passed errors align with the wanted codes,
+	// where a negative code means no error is expected.
+	checkErrs := func(name string, errs []error, wantCodes []ErrorCode) {
+		if len(errs) != len(items) {
+			t.Fatalf("%s: got %d errors for %d items", name, len(errs),
+				len(items))
+		}
+		for i, err := range errs {
+			if wantCodes[i] < 0 {
+				if err != nil {
+					t.Errorf("%s: item %d: unexpected error: %v",
+						name, i, err)
+				}
+				continue
+			}
+			if !IsErrorCode(err, wantCodes[i]) {
+				t.Errorf("%s: item %d: got error %v, want code %v",
+					name, i, err, wantCodes[i])
+			}
+		}
+	}
+
+	// Full evaluation must report every item and populate the cache with
+	// only the valid signatures.
+	sigCache := NewSigCache(10)
+	checkErrs("BatchVerify", BatchVerify(items, sigCache), wantCodes)
+	for i, item := range items {
+		if item.Sig == nil {
+			continue
+		}
+		inCache := sigCache.Exists(item.SigHash, item.Sig, item.PubKey)
+		if want := wantCodes[i] < 0; inCache != want {
+			t.Errorf("BatchVerify: item %d: in cache %v, want %v", i,
+				inCache, want)
+		}
+	}
+
+	// Verifying again with the populated cache must give the same results.
+	checkErrs("BatchVerify cached", BatchVerify(items, sigCache), wantCodes)
+
+	// A nil cache is allowed.
+	checkErrs("BatchVerify nil cache", BatchVerify(items, nil), wantCodes)
+
+	// Short circuit evaluation stops at the first failure, so only the
+	// items before it are verified and added to the cache.
+	sigCache = NewSigCache(10)
+	errs := BatchVerifyShortCircuit(items, sigCache)
+	checkErrs("BatchVerifyShortCircuit", errs, []ErrorCode{
+		-1, ErrSigVerify, ErrSigNotVerified, ErrSigNotVerified,
+		ErrSigNotVerified, ErrSigNotVerified,
+	})
+	if !sigCache.Exists(valid[0].SigHash, valid[0].Sig, valid[0].PubKey) {
+		t.Errorf("BatchVerifyShortCircuit: verified item not in cache")
+	}
+	if sigCache.Exists(valid[1].SigHash, valid[1].Sig, valid[1].PubKey) {
+		t.Errorf("BatchVerifyShortCircuit: unverified item in cache")
+	}
+
+	// All valid items must produce no errors in either mode.
+	for _, verify := range []func([]VerifyItem, *SigCache) []error{
+		BatchVerify, BatchVerifyShortCircuit,
+	} {
+		for i, err := range verify(valid, nil) {
+			if err != nil {
+				t.Errorf("item %d: unexpected error: %v", i, err)
+			}
+		}
+	}
+
+	// Empty batches return no errors.
+	if errs := BatchVerify(nil, nil); len(errs) != 0 {
+		t.Errorf("BatchVerify: got %d errors for empty batch", len(errs))
+	}
+}
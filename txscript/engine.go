@@ -112,6 +112,36 @@ const (
 	payToWitnessScriptHashDataSize = 32
 )
 
+const (
+	// OpStepCost is the number of steps of the step budget consumed by
+	// executing any opcode which does not check signatures.
+	OpStepCost = 1
+
+	// SigOpStepCost is the number of steps of the step budget consumed by
+	// executing an opcode which checks a single signature.  It mirrors the
+	// validation weight taproot charges for each signature operation.
+	SigOpStepCost = 50
+
+	// MultiSigOpStepCost is the number of steps of the step budget
+	// consumed by executing an opcode which checks multiple signatures.
+	// It is charged as the maximum number of public keys since the actual
+	// number is not known until the opcode executes, which is consistent
+	// with how legacy signature operations are counted.
+	MultiSigOpStepCost = SigOpStepCost * MaxPubKeysPerMultiSig
+)
+
+// opcodeStepCost returns the number of steps of the step budget consumed by
+// executing the passed opcode.
+func opcodeStepCost(pop *parsedOpcode) uint64 {
+	switch pop.opcode.value {
+	case OP_CHECKSIG, OP_CHECKSIGVERIFY:
+		return SigOpStepCost
+	case OP_CHECKMULTISIG, OP_CHECKMULTISIGVERIFY:
+		return MultiSigOpStepCost
+	}
+	return OpStepCost
+}
+
 // halforder is used to tame ECDSA malleability (see BIP0062).
 var halfOrder = new(big.Int).Rsh(btcec.S256().N, 1)
 
@@ -135,6 +165,8 @@ type Engine struct {
 	witnessVersion  int
 	witnessProgram  []byte
 	inputAmount     int64
+	stepBudget      uint64 // zero means no budget
+	stepsUsed       uint64
 }
 
 // hasFlag returns whether the script engine instance has the passed flag set.
@@ -447,6 +479,27 @@ func (vm *Engine) Step() (done bool, err error) {
 	opcode := &vm.scripts[vm.scriptIdx][vm.scriptOff]
 	vm.scriptOff++
 
+	// Charge the opcode against the step budget when one is set.  Every
+	// opcode is charged, including those in non-executing branches, since
+	// they still need to be processed.
+	if vm.stepBudget != 0 {
+		// The budget may have been lowered below the steps already
+		// used, in which case no steps remain.
+		var remaining uint64
+		if vm.stepsUsed < vm.stepBudget {
+			remaining = vm.stepBudget - vm.stepsUsed
+		}
+		cost := opcodeStepCost(opcode)
+		if cost > remaining {
+			str := fmt.Sprintf("executing opcode %s would exceed the "+
+				"step budget of %d [used %d, cost %d]",
+				opcode.opcode.name, vm.stepBudget, vm.stepsUsed,
+				cost)
+			return true, scriptError(ErrStepBudgetExceeded, str)
+		}
+		vm.stepsUsed += cost
+	}
+
 	// Execute the opcode while taking into account several things such as
 	// disabled opcodes, illegal opcodes, maximum allowed operations per
 	// script, maximum script element sizes, and conditionals.
@@ -526,7 +579,34 @@ func (vm *Engine) Step() (done bool, err error) {
 
 // Execute will execute all scripts in the script engine and return either nil
 // for successful validation or an error if one occurred.
-func (vm *Engine) Execute() (err error) {
+func (vm *Engine) Execute() error {
+	if err := vm.execute(); err != nil {
+		return err
+	}
+	return vm.CheckErrorCondition(true)
+}
+
+// ExecuteAndReturnStack executes all scripts in the script engine the same as
+// Execute and additionally returns the contents of the primary stack as they
+// were left by the scripts, where the last item in the array is the top of the
+// stack.  This allows callers to inspect the values a script left behind.
+//
+// The stack is captured before the final checks Execute performs, such as
+// ensuring the top item is true and, when ScriptVerifyCleanStack is set, that
+// it is the only item.  Thus, when execution completes but those checks fail,
+// the stack is still returned along with the resulting error.  No stack is
+// returned when execution itself fails.
+func (vm *Engine) ExecuteAndReturnStack() ([][]byte, error) {
+	if err := vm.execute(); err != nil {
+		return nil, err
+	}
+	stack := vm.GetStack()
+	return stack, vm.CheckErrorCondition(true)
+}
+
+// execute steps through all scripts in the script engine until they are done
+// or an error occurs, without performing the final checks of Execute.
+func (vm *Engine) execute() (err error) {
 	done := false
 	for !done {
 		log.Tracef("%v", newLogClosure(func() string {
@@ -556,7 +636,7 @@ func (vm *Engine) Execute() (err error) {
 		}))
 	}
 
-	return vm.CheckErrorCondition(true)
+	return nil
 }
 
 // subScript returns the script since the last OP_CODESEPARATOR.
@@ -797,6 +877,27 @@ func (vm *Engine) SetAltStack(data [][]byte) {
 	setStack(&vm.astack, data)
 }
 
+// SetStepBudget sets the maximum number of steps the engine may consume while
+// executing the scripts, which bounds the worst-case cost of execution.  Each
+// executed opcode consumes OpStepCost steps, except opcodes which check
+// signatures, which consume SigOpStepCost or MultiSigOpStepCost steps.
+// Execution fails with ErrStepBudgetExceeded once an opcode would consume more
+// steps than remain.
+//
+// The budget applies to all scripts executed by the engine, including any
+// pay-to-script-hash and witness scripts.  A budget of zero, which is the
+// default, disables the budget.  Setting a budget lower than the steps already
+// used leaves no steps remaining, so executing another opcode fails.
+func (vm *Engine) SetStepBudget(budget uint64) {
+	vm.stepBudget = budget
+}
+
+// StepsUsed returns the number of steps of the step budget consumed so far.
+// It is always zero when no step budget is set.
+func (vm *Engine) StepsUsed() uint64 {
+	return vm.stepsUsed
+}
+
 // NewEngine returns a new script engine for the provided public key script,
 // transaction, and input index.  The flags modify the behavior of the script
 // engine according to the description provided by each flag.
@@ -5,6 +5,8 @@
 package txscript
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/navcoin/navd/chaincfg/chainhash"
@@ -425,3 +427,374 @@ func TestCheckSignatureEncoding(t *testing.T) {
 		}
 	}
 }
+
+// TestStepBudget ensures the step budget charges each executed opcode its cost
+// and fails execution with ErrStepBudgetExceeded once the budget is exhausted.
+func TestStepBudget(t *testing.T) {
+	t.Parallel()
+
+	// manyOpsScript is a large script which executes many cheap opcodes.
+	manyOpsScript := "1" + strings.Repeat(" DUP DROP", 100)
+
+	tests := []struct {
+		name      string
+		pkScript  string
+		budget    uint64
+		wantErr   bool
+		wantSteps uint64
+	}{{
+		name:      "no budget",
+		pkScript:  manyOpsScript,
+		budget:    0,
+		wantSteps: 0,
+	}, {
+		name:      "many ops within budget",
+		pkScript:  manyOpsScript,
+		budget:    202,
+		wantSteps: 202,
+	}, {
+		name:     "many ops exceeding budget",
+		pkScript: manyOpsScript,
+		budget:   201,
+		wantErr:  true,
+	}, {
+		name:      "signature check within budget",
+		pkScript:  "0 0 CHECKSIG NOT",
+		budget:    3*OpStepCost + SigOpStepCost + 1,
+		wantSteps: 3*OpStepCost + SigOpStepCost + 1,
+	}, {
+		name:     "signature check exceeding budget",
+		pkScript: "0 0 CHECKSIG NOT",
+		budget:   3*OpStepCost + SigOpStepCost,
+		wantErr:  true,
+	}, {
+		name:     "multisig check exceeding budget",
+		pkScript: "0 0 0 CHECKMULTISIG NOT",
+		budget:   SigOpStepCost * 2,
+		wantErr:  true,
+	}}
+
+	// The signature script executes a single NOP which consumes one step.
+	tx := &wire.MsgTx{
+		Version: 1,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{},
+			SignatureScript:  mustParseShortForm("NOP"),
+			Sequence:         4294967295,
+		}},
+		TxOut: []*wire.TxOut{{Value: 1000000000}},
+	}
+
+	for _, test := range tests {
+		pkScript := mustParseShortForm(test.pkScript)
+		vm, err := NewEngine(pkScript, tx, 0, 0, nil, nil, -1)
+		if err != nil {
+			t.Errorf("%s: failed to create script: %v", test.name, err)
+			continue
+		}
+		vm.SetStepBudget(test.budget)
+
+		err = vm.Execute()
+		if test.wantErr {
+			if !IsErrorCode(err, ErrStepBudgetExceeded) {
+				t.Errorf("%s: unexpected error - got %v, want %v",
+					test.name, err, ErrStepBudgetExceeded)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if vm.StepsUsed() != test.wantSteps {
+			t.Errorf("%s: unexpected steps used - got %d, want %d",
+				test.name, vm.StepsUsed(), test.wantSteps)
+		}
+	}
+
+	// Ensure lowering the budget below the steps already used fails the
+	// next opcode rather than underflowing the remaining steps.
+	vm, err := NewEngine(mustParseShortForm(manyOpsScript), tx, 0, 0, nil,
+		nil, -1)
+	if err != nil {
+		t.Fatalf("failed to create script: %v", err)
+	}
+	vm.SetStepBudget(10)
+	for i := 0; i < 5; i++ {
+		if _, err := vm.Step(); err != nil {
+			t.Fatalf("unexpected error on step %d: %v", i, err)
+		}
+	}
+	vm.SetStepBudget(2)
+	if _, err := vm.Step(); !IsErrorCode(err, ErrStepBudgetExceeded) {
+		t.Fatalf("unexpected error after lowering budget - got %v, "+
+			"want %v", err, ErrStepBudgetExceeded)
+	}
+}
+
+// TestDiscourageUpgradableNops ensures the reserved NOP opcodes fail execution
+// when the ScriptDiscourageUpgradableNops flag is set while the NOP opcodes
+// repurposed as OP_CHECKLOCKTIMEVERIFY and OP_CHECKSEQUENCEVERIFY still
+// execute when their respective flags are set.
+func TestDiscourageUpgradableNops(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		pkScript string
+		flags    ScriptFlags
+		wantErr  bool
+	}{{
+		name:     "NOP4 without flag",
+		pkScript: "1 NOP4",
+		flags:    0,
+	}, {
+		name:     "NOP1 with flag",
+		pkScript: "1 NOP1",
+		flags:    ScriptDiscourageUpgradableNops,
+		wantErr:  true,
+	}, {
+		name:     "NOP4 with flag",
+		pkScript: "1 NOP4",
+		flags:    ScriptDiscourageUpgradableNops,
+		wantErr:  true,
+	}, {
+		name:     "NOP10 with flag",
+		pkScript: "1 NOP10",
+		flags:    ScriptDiscourageUpgradableNops,
+		wantErr:  true,
+	}, {
+		name:     "unexecuted NOP4 with flag",
+		pkScript: "1 0 IF NOP4 ENDIF",
+		flags:    ScriptDiscourageUpgradableNops,
+	}, {
+		name:     "NOP2 without CLTV flag",
+		pkScript: "1 NOP2",
+		flags:    ScriptDiscourageUpgradableNops,
+		wantErr:  true,
+	}, {
+		name:     "CLTV with flag",
+		pkScript: "0 CHECKLOCKTIMEVERIFY DROP 1",
+		flags: ScriptDiscourageUpgradableNops |
+			ScriptVerifyCheckLockTimeVerify,
+	}, {
+		name:     "NOP3 without CSV flag",
+		pkScript: "1 NOP3",
+		flags:    ScriptDiscourageUpgradableNops,
+		wantErr:  true,
+	}, {
+		name:     "CSV with flag",
+		pkScript: "0 CHECKSEQUENCEVERIFY DROP 1",
+		flags: ScriptDiscourageUpgradableNops |
+			ScriptVerifyCheckSequenceVerify,
+	}}
+
+	// The input sequence is not final so the lock time checks pass.
+	tx := &wire.MsgTx{
+		Version: 2,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{},
+			Sequence:         0,
+		}},
+		TxOut: []*wire.TxOut{{Value: 1000000000}},
+	}
+
+	for _, test := range tests {
+		pkScript := mustParseShortForm(test.pkScript)
+		vm, err := NewEngine(pkScript, tx, 0, test.flags, nil, nil, -1)
+		if err != nil {
+			t.Errorf("%s: failed to create script: %v", test.name, err)
+			continue
+		}
+
+		err = vm.Execute()
+		if test.wantErr {
+			if !IsErrorCode(err, ErrDiscourageUpgradableNOPs) {
+				t.Errorf("%s: unexpected error - got %v, want %v",
+					test.name, err, ErrDiscourageUpgradableNOPs)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+	}
+}
+
+// TestExecuteAndReturnStack ensures the stack left by the scripts is returned
+// along with the result of executing them.
+func TestExecuteAndReturnStack(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		pkScript  string
+		flags     ScriptFlags
+		wantStack [][]byte
+		wantErr   ErrorCode
+		isValid   bool
+	}{{
+		name:      "single result",
+		pkScript:  "2 3 ADD",
+		wantStack: [][]byte{{5}},
+		isValid:   true,
+	}, {
+		name:      "multiple items without clean stack",
+		pkScript:  "1 2 3",
+		wantStack: [][]byte{{1}, {2}, {3}},
+		isValid:   true,
+	}, {
+		name:      "multiple items with clean stack",
+		pkScript:  "1 2 3",
+		flags:     ScriptBip16 | ScriptVerifyCleanStack,
+		wantStack: [][]byte{{1}, {2}, {3}},
+		wantErr:   ErrCleanStack,
+	}, {
+		name:      "false result",
+		pkScript:  "5 0",
+		wantStack: [][]byte{{5}, nil},
+		wantErr:   ErrEvalFalse,
+	}, {
+		name:     "execution failure",
+		pkScript: "1 RETURN",
+		wantErr:  ErrEarlyReturn,
+	}}
+
+	tx := &wire.MsgTx{
+		Version: 1,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{},
+			Sequence:         wire.MaxTxInSequenceNum,
+		}},
+		TxOut: []*wire.TxOut{{Value: 1000000000}},
+	}
+
+	for _, test := range tests {
+		pkScript := mustParseShortForm(test.pkScript)
+		vm, err := NewEngine(pkScript, tx, 0, test.flags, nil, nil, -1)
+		if err != nil {
+			t.Errorf("%s: failed to create script: %v", test.name, err)
+			continue
+		}
+
+		stack, err := vm.ExecuteAndReturnStack()
+		if test.isValid && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !test.isValid && !IsErrorCode(err, test.wantErr) {
+			t.Errorf("%s: unexpected error - got %v, want %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if len(stack) != len(test.wantStack) {
+			t.Errorf("%s: unexpected stack - got %x, want %x",
+				test.name, stack, test.wantStack)
+			continue
+		}
+		for i := range stack {
+			if !bytes.Equal(stack[i], test.wantStack[i]) {
+				t.Errorf("%s: unexpected stack - got %x, want %x",
+					test.name, stack, test.wantStack)
+				break
+			}
+		}
+	}
+}
+
+// TestMinimalIf ensures the operand of OP_IF and OP_NOTIF must be an empty
+// byte array or [0x01] when executing a version 0 witness program with the
+// minimal if flag set, and that it is otherwise interpreted as a normal bool.
+func TestMinimalIf(t *testing.T) {
+	t.Parallel()
+
+	const (
+		witnessFlags = ScriptBip16 | ScriptVerifyWitness
+		minimalFlags = witnessFlags | ScriptVerifyMinimalIf
+	)
+
+	witnessScript := mustParseShortForm("IF 1 ELSE 1 ENDIF")
+	scriptHash := chainhash.HashB(witnessScript)
+	p2wsh, err := payToWitnessScriptHashScript(scriptHash)
+	if err != nil {
+		t.Fatalf("failed to create p2wsh script: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		pkScript []byte
+		witness  wire.TxWitness
+		flags    ScriptFlags
+		wantErr  ErrorCode
+		isValid  bool
+	}{{
+		name:     "empty operand with flag",
+		pkScript: p2wsh,
+		witness:  wire.TxWitness{nil, witnessScript},
+		flags:    minimalFlags,
+		isValid:  true,
+	}, {
+		name:     "0x01 operand with flag",
+		pkScript: p2wsh,
+		witness:  wire.TxWitness{{0x01}, witnessScript},
+		flags:    minimalFlags,
+		isValid:  true,
+	}, {
+		name:     "0x02 operand with flag",
+		pkScript: p2wsh,
+		witness:  wire.TxWitness{{0x02}, witnessScript},
+		flags:    minimalFlags,
+		wantErr:  ErrMinimalIf,
+	}, {
+		name:     "0x00 operand with flag",
+		pkScript: p2wsh,
+		witness:  wire.TxWitness{{0x00}, witnessScript},
+		flags:    minimalFlags,
+		wantErr:  ErrMinimalIf,
+	}, {
+		name:     "multi-byte operand with flag",
+		pkScript: p2wsh,
+		witness:  wire.TxWitness{{0x01, 0x00}, witnessScript},
+		flags:    minimalFlags,
+		wantErr:  ErrMinimalIf,
+	}, {
+		name:     "0x02 operand without flag",
+		pkScript: p2wsh,
+		witness:  wire.TxWitness{{0x02}, witnessScript},
+		flags:    witnessFlags,
+		isValid:  true,
+	}, {
+		name:     "non-minimal operand outside witness program",
+		pkScript: mustParseShortForm("2 IF 1 ELSE 1 ENDIF"),
+		flags:    minimalFlags,
+		isValid:  true,
+	}}
+
+	for _, test := range tests {
+		tx := &wire.MsgTx{
+			Version: 1,
+			TxIn: []*wire.TxIn{{
+				PreviousOutPoint: wire.OutPoint{},
+				Witness:          test.witness,
+				Sequence:         wire.MaxTxInSequenceNum,
+			}},
+			TxOut: []*wire.TxOut{{Value: 1000000000}},
+		}
+		vm, err := NewEngine(test.pkScript, tx, 0, test.flags, nil,
+			NewTxSigHashes(tx), 1000000000)
+		if err != nil {
+			t.Errorf("%s: failed to create script: %v", test.name, err)
+			continue
+		}
+
+		err = vm.Execute()
+		if test.isValid && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !test.isValid && !IsErrorCode(err, test.wantErr) {
+			t.Errorf("%s: unexpected error - got %v, want %v",
+				test.name, err, test.wantErr)
+		}
+	}
+}
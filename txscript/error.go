@@ -276,6 +276,45 @@ const (
 	// serialized in a compressed format.
 	ErrWitnessPubKeyType
 
+	// ---------------------------------
+	// Failures related to taproot.
+	// ---------------------------------
+
+	// ErrControlBlockTooSmall is returned when a taproot control block is
+	// smaller than the minimum size of a leaf version and parity byte
+	// followed by the internal key.
+	ErrControlBlockTooSmall
+
+	// ErrControlBlockInvalidLength is returned when the length of a taproot
+	// control block after the leaf version byte and internal key is not a
+	// multiple of 32 bytes.
+	ErrControlBlockInvalidLength
+
+	// ErrControlBlockTooLarge is returned when a taproot control block
+	// contains a merkle path that is deeper than the maximum depth of a
+	// taproot script tree.
+	ErrControlBlockTooLarge
+
+	// ---------------------------------
+	// Failures related to step budgets.
+	// ---------------------------------
+
+	// ErrStepBudgetExceeded is returned when executing an opcode would
+	// consume more steps than remain in the step budget set on the engine.
+	ErrStepBudgetExceeded
+
+	// ---------------------------------------
+	// Failures related to batch verification.
+	// ---------------------------------------
+
+	// ErrSigVerify is returned by BatchVerify for an item whose signature
+	// is not valid for its public key and signature hash.
+	ErrSigVerify
+
+	// ErrSigNotVerified is returned by BatchVerifyShortCircuit for the
+	// items which were not verified because an earlier item failed.
+	ErrSigNotVerified
+
 	// numErrorCodes is the maximum error code number used in tests.  This
 	// entry MUST be the last entry in the enum.
 	numErrorCodes
@@ -333,6 +372,12 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrMinimalIf:                          "ErrMinimalIf",
 	ErrWitnessPubKeyType:                  "ErrWitnessPubKeyType",
 	ErrDiscourageUpgradableWitnessProgram: "ErrDiscourageUpgradableWitnessProgram",
+	ErrControlBlockTooSmall:               "ErrControlBlockTooSmall",
+	ErrControlBlockInvalidLength:          "ErrControlBlockInvalidLength",
+	ErrControlBlockTooLarge:               "ErrControlBlockTooLarge",
+	ErrStepBudgetExceeded:                 "ErrStepBudgetExceeded",
+	ErrSigVerify:                          "ErrSigVerify",
+	ErrSigNotVerified:                     "ErrSigNotVerified",
 }
 
 // String returns the ErrorCode as a human-readable name.
@@ -66,6 +66,12 @@ func TestErrorCodeStringer(t *testing.T) {
 		{ErrMinimalIf, "ErrMinimalIf"},
 		{ErrWitnessPubKeyType, "ErrWitnessPubKeyType"},
 		{ErrDiscourageUpgradableWitnessProgram, "ErrDiscourageUpgradableWitnessProgram"},
+		{ErrControlBlockTooSmall, "ErrControlBlockTooSmall"},
+		{ErrControlBlockInvalidLength, "ErrControlBlockInvalidLength"},
+		{ErrControlBlockTooLarge, "ErrControlBlockTooLarge"},
+		{ErrStepBudgetExceeded, "ErrStepBudgetExceeded"},
+		{ErrSigVerify, "ErrSigVerify"},
+		{ErrSigNotVerified, "ErrSigNotVerified"},
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
 
@@ -0,0 +1,146 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// flatSigCacheBucketSize is the number of consecutive slots of the table
+// probed when looking up or inserting an entry in a FlatSigCache.
+const flatSigCacheBucketSize = 4
+
+// flatSigCacheSlot houses a single entry of a FlatSigCache.  A nil pubKey
+// marks an empty slot.
+type flatSigCacheSlot struct {
+	sigHash chainhash.Hash
+	sig     *btcec.Signature
+	pubKey  *btcec.PublicKey
+}
+
+// FlatSigCache implements an ECDSA signature verification cache with the same
+// semantics as SigCache, but backed by a fixed-size open-addressed table that
+// is allocated up front rather than a map.  This gives the cache a predictable
+// memory footprint and avoids the per-entry overhead of map buckets.
+//
+// Each signature hash maps to a bucket of consecutive slots in the table.
+// Adding an entry to a bucket with no free slots overwrites one of the
+// existing entries in the bucket, so eviction is effectively random and never
+// requires scanning the cache.  Signature hashes are public and can be ground
+// by anyone creating transactions, so rather than deriving the bucket from the
+// signature hash directly, it is derived from a SipHash of the signature hash
+// keyed by a random secret chosen when the cache is created.  This prevents an
+// adversary from choosing signature hashes that map to the bucket of an entry
+// in order to target its eviction.
+type FlatSigCache struct {
+	sync.RWMutex
+	slots []flatSigCacheSlot
+
+	// k0 and k1 form the secret SipHash key used to map signature hashes
+	// to slots.
+	k0, k1 uint64
+}
+
+// NewFlatSigCache creates and initializes a new instance of FlatSigCache with
+// room for 'capacity' entries.  The entire table is allocated up front, so the
+// memory used by the cache does not grow as entries are added.  A capacity of
+// zero results in a cache which never stores any entries.
+func NewFlatSigCache(capacity uint) *FlatSigCache {
+	// The key only needs to be unpredictable, so in the extremely unlikely
+	// event the system random number generator fails, the cache is still
+	// usable with whatever was read.
+	var key [16]byte
+	_, _ = rand.Read(key[:])
+
+	return &FlatSigCache{
+		slots: make([]flatSigCacheSlot, capacity),
+		k0:    binary.LittleEndian.Uint64(key[:8]),
+		k1:    binary.LittleEndian.Uint64(key[8:]),
+	}
+}
+
+// bucket returns the index of the first slot and the number of slots of the
+// bucket the passed signature hash maps to.
+func (s *FlatSigCache) bucket(sigHash *chainhash.Hash) (int, int) {
+	start := int(sipHash24(s.k0, s.k1, sigHash[:]) % uint64(len(s.slots)))
+	size := flatSigCacheBucketSize
+	if size > len(s.slots) {
+		size = len(s.slots)
+	}
+	return start, size
+}
+
+// Exists returns true if an existing entry of 'sig' over 'sigHash' for public
+// key 'pubKey' is found within the FlatSigCache. Otherwise, false is returned.
+//
+// NOTE: This function is safe for concurrent access. Readers won't be blocked
+// unless there exists a writer, adding an entry to the FlatSigCache.
+func (s *FlatSigCache) Exists(sigHash chainhash.Hash, sig *btcec.Signature, pubKey *btcec.PublicKey) bool {
+	if len(s.slots) == 0 {
+		return false
+	}
+
+	start, size := s.bucket(&sigHash)
+	s.RLock()
+	defer s.RUnlock()
+	for i := 0; i < size; i++ {
+		slot := &s.slots[(start+i)%len(s.slots)]
+		if slot.pubKey != nil && slot.sigHash == sigHash {
+			return slot.pubKey.IsEqual(pubKey) && slot.sig.IsEqual(sig)
+		}
+	}
+	return false
+}
+
+// Add adds an entry for a signature over 'sigHash' under public key 'pubKey'
+// to the signature cache.  An existing entry for the same signature hash is
+// replaced.  Otherwise the entry is stored in a free slot of its bucket, or
+// when the bucket is full, overwrites one of the entries in it.
+//
+// NOTE: This function is safe for concurrent access. Writers will block
+// simultaneous readers until function execution has concluded.
+func (s *FlatSigCache) Add(sigHash chainhash.Hash, sig *btcec.Signature, pubKey *btcec.PublicKey) {
+	if len(s.slots) == 0 {
+		return
+	}
+
+	start, size := s.bucket(&sigHash)
+	s.Lock()
+	defer s.Unlock()
+
+	// Prefer replacing an existing entry for the signature hash, followed
+	// by the first free slot in the bucket.
+	target := -1
+	for i := 0; i < size; i++ {
+		idx := (start + i) % len(s.slots)
+		slot := &s.slots[idx]
+		if slot.pubKey != nil && slot.sigHash == sigHash {
+			target = idx
+			break
+		}
+		if slot.pubKey == nil && target == -1 {
+			target = idx
+		}
+	}
+
+	// The bucket is full, so evict an entry chosen by a hash of the
+	// signature hash under the secret key with its halves swapped, which is
+	// independent of the hash used to select the bucket.
+	if target == -1 {
+		offset := int(sipHash24(s.k1, s.k0, sigHash[:]) % uint64(size))
+		target = (start + offset) % len(s.slots)
+	}
+
+	s.slots[target] = flatSigCacheSlot{
+		sigHash: sigHash,
+		sig:     sig,
+		pubKey:  pubKey,
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"testing"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// numFlatSigCacheEntries returns the number of occupied slots in the passed
+// FlatSigCache.
+func numFlatSigCacheEntries(sigCache *FlatSigCache) int {
+	var n int
+	for i := range sigCache.slots {
+		if sigCache.slots[i].pubKey != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// TestFlatSigCacheAddExists tests the ability to add, and later check the
+// existence of a signature triplet in the flat signature cache.
+func TestFlatSigCacheAddExists(t *testing.T) {
+	sigCache := NewFlatSigCache(200)
+
+	// Generate a random sigCache entry triplet.
+	msg1, sig1, key1, err := genRandomSig()
+	if err != nil {
+		t.Fatalf("unable to generate random signature test data")
+	}
+
+	// Add the triplet to the signature cache.
+	sigCache.Add(*msg1, sig1, key1)
+
+	// The previously added triplet should now be found within the sigcache.
+	sig1Copy, _ := btcec.ParseSignature(sig1.Serialize(), btcec.S256())
+	key1Copy, _ := btcec.ParsePubKey(key1.SerializeCompressed(), btcec.S256())
+	if !sigCache.Exists(*msg1, sig1Copy, key1Copy) {
+		t.Errorf("previously added item not found in signature cache")
+	}
+
+	// A different signature or public key for the same hash must not be
+	// reported as existing.
+	_, sig2, key2, err := genRandomSig()
+	if err != nil {
+		t.Fatalf("unable to generate random signature test data")
+	}
+	if sigCache.Exists(*msg1, sig2, key1Copy) {
+		t.Errorf("entry with mismatched signature found in signature " +
+			"cache")
+	}
+	if sigCache.Exists(*msg1, sig1Copy, key2) {
+		t.Errorf("entry with mismatched public key found in signature " +
+			"cache")
+	}
+
+	// Adding the same hash again must replace the entry rather than
+	// consume another slot.
+	sigCache.Add(*msg1, sig2, key2)
+	if !sigCache.Exists(*msg1, sig2, key2) {
+		t.Errorf("replaced item not found in signature cache")
+	}
+	if sigCache.Exists(*msg1, sig1Copy, key1Copy) {
+		t.Errorf("replaced item still found in signature cache")
+	}
+	if n := numFlatSigCacheEntries(sigCache); n != 1 {
+		t.Errorf("signature cache should have 1 entry, instead it has %v",
+			n)
+	}
+}
+
+// TestFlatSigCacheChurn tests that adding many more entries than the capacity
+// of the cache overwrites existing entries without growing the table, that the
+// most recently added entry is always found, and that adding entries does not
+// allocate.
+func TestFlatSigCacheChurn(t *testing.T) {
+	const sigCacheSize = 50
+	sigCache := NewFlatSigCache(sigCacheSize)
+	slots := &sigCache.slots[0]
+
+	for i := 0; i < sigCacheSize*4; i++ {
+		msg, sig, key, err := genRandomSig()
+		if err != nil {
+			t.Fatalf("unable to generate random signature test data")
+		}
+
+		allocs := testing.AllocsPerRun(1, func() {
+			sigCache.Add(*msg, sig, key)
+		})
+		if allocs != 0 {
+			t.Fatalf("Add allocated %v times, expected none", allocs)
+		}
+
+		if !sigCache.Exists(*msg, sig, key) {
+			t.Fatalf("most recently added item not found in " +
+				"signature cache")
+		}
+
+		// The table must never be reallocated or grow.
+		if len(sigCache.slots) != sigCacheSize ||
+			cap(sigCache.slots) != sigCacheSize ||
+			&sigCache.slots[0] != slots {
+
+			t.Fatalf("signature cache table changed size")
+		}
+		if n := numFlatSigCacheEntries(sigCache); n > sigCacheSize {
+			t.Fatalf("signature cache has %v entries which exceeds "+
+				"the capacity of %v", n, sigCacheSize)
+		}
+	}
+}
+
+// TestFlatSigCacheKeyedBuckets ensures the bucket a signature hash maps to is
+// determined by a secret key chosen when the cache is created, so signature
+// hashes an adversary grinds to share bits do not land in the same bucket.
+func TestFlatSigCacheKeyedBuckets(t *testing.T) {
+	sigCache := NewFlatSigCache(1024)
+	other := NewFlatSigCache(1024)
+	if sigCache.k0 == other.k0 && sigCache.k1 == other.k1 {
+		t.Fatalf("signature caches were created with the same key")
+	}
+
+	// Every hash shares its first 16 bytes, which would map them all to
+	// the same bucket and eviction slot if they were used directly.
+	buckets := make(map[int]struct{})
+	for i := 0; i < 64; i++ {
+		var sigHash chainhash.Hash
+		sigHash[16] = byte(i)
+		start, _ := sigCache.bucket(&sigHash)
+		buckets[start] = struct{}{}
+	}
+	if len(buckets) < 32 {
+		t.Fatalf("signature hashes sharing a prefix mapped to only %d "+
+			"buckets", len(buckets))
+	}
+}
+
+// TestFlatSigCacheZeroCapacity tests that if a FlatSigCache is created with a
+// capacity of zero, then no entries are added to the sigcache at all.
+func TestFlatSigCacheZeroCapacity(t *testing.T) {
+	sigCache := NewFlatSigCache(0)
+
+	// Generate a random sigCache entry triplet.
+	msg1, sig1, key1, err := genRandomSig()
+	if err != nil {
+		t.Fatalf("unable to generate random signature test data")
+	}
+
+	// Add the triplet to the signature cache.
+	sigCache.Add(*msg1, sig1, key1)
+
+	// The generated triplet should not be found.
+	if sigCache.Exists(*msg1, sig1, key1) {
+		t.Errorf("previously added signature found in sigcache, but " +
+			"shouldn't have been")
+	}
+}
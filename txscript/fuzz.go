@@ -0,0 +1,144 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build gofuzz
+
+package txscript
+
+// This file provides targets for go-fuzz (https://github.com/dvyukov/go-fuzz)
+// which exercise script parsing, disassembly, and signature hash calculation
+// with arbitrary input.  It is only built by go-fuzz-build, which sets the
+// gofuzz build tag.  A seed corpus is provided in testdata/fuzz/corpus, so the
+// targets may be run from the txscript directory with, for example:
+//
+//   go-fuzz-build -func FuzzParseScript github.com/navcoin/navd/txscript
+//   go-fuzz -bin txscript-fuzz.zip -workdir testdata/fuzz
+//
+// Each target panics when an invariant is violated so go-fuzz records the
+// input as a crasher.
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// FuzzParseScript parses the passed data as a script and ensures that scripts
+// which parse successfully serialize back to exactly the same bytes and that
+// the serialized script parses to the same opcodes.
+func FuzzParseScript(data []byte) int {
+	pops, err := parseScript(data)
+	if err != nil {
+		return 0
+	}
+
+	script, err := unparseScript(pops)
+	if err != nil {
+		panic(fmt.Sprintf("unable to serialize parsed script %x: %v",
+			data, err))
+	}
+	if !bytes.Equal(script, data) {
+		panic(fmt.Sprintf("parsed script %x serialized as %x", data,
+			script))
+	}
+
+	reparsed, err := parseScript(script)
+	if err != nil {
+		panic(fmt.Sprintf("unable to parse serialized script %x: %v",
+			script, err))
+	}
+	if len(reparsed) != len(pops) {
+		panic(fmt.Sprintf("serialized script %x parsed to %d opcodes, "+
+			"want %d", script, len(reparsed), len(pops)))
+	}
+	for i := range pops {
+		if reparsed[i].opcode.value != pops[i].opcode.value ||
+			!bytes.Equal(reparsed[i].data, pops[i].data) {
+
+			panic(fmt.Sprintf("serialized script %x opcode %d "+
+				"mismatch", script, i))
+		}
+	}
+	return 1
+}
+
+// FuzzDisasmString disassembles the passed data as a script and ensures the
+// disassembly reports an error, and is marked as such, exactly when the script
+// fails to parse.
+func FuzzDisasmString(data []byte) int {
+	disasm, err := DisasmString(data)
+	_, parseErr := parseScript(data)
+	if (err == nil) != (parseErr == nil) {
+		panic(fmt.Sprintf("disassembly error %v does not match parse "+
+			"error %v for script %x", err, parseErr, data))
+	}
+	if hasErr := strings.HasSuffix(disasm, "[error]"); hasErr != (err != nil) {
+		panic(fmt.Sprintf("disassembly %q of script %x does not match "+
+			"error %v", disasm, data, err))
+	}
+	if err != nil {
+		return 0
+	}
+	return 1
+}
+
+// fuzzSigHashTx is the transaction signature hashes are calculated for by
+// FuzzSignatureHash.  It has more inputs than outputs so the SigHashSingle
+// handling of inputs without a corresponding output is exercised.
+var fuzzSigHashTx = &wire.MsgTx{
+	Version: 1,
+	TxIn: []*wire.TxIn{{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{0x01},
+			Index: 0,
+		},
+		Sequence: wire.MaxTxInSequenceNum,
+	}, {
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{0x02},
+			Index: 1,
+		},
+		Sequence: wire.MaxTxInSequenceNum,
+	}},
+	TxOut: []*wire.TxOut{{
+		Value:    1000,
+		PkScript: []byte{OP_TRUE},
+	}},
+}
+
+// FuzzSignatureHash calculates the legacy and witness signature hashes of
+// fuzzSigHashTx.  The first byte of the passed data selects the hash type,
+// the second selects the input index, and the remaining bytes are the script.
+func FuzzSignatureHash(data []byte) int {
+	if len(data) < 2 {
+		return 0
+	}
+	hashType := SigHashType(data[0])
+	idx := int(data[1]) % len(fuzzSigHashTx.TxIn)
+	pops, err := parseScript(data[2:])
+	if err != nil {
+		return 0
+	}
+
+	hash := calcSignatureHash(pops, hashType, fuzzSigHashTx, idx)
+	if len(hash) != chainhash.HashSize {
+		panic(fmt.Sprintf("signature hash has length %d", len(hash)))
+	}
+
+	sigHashes := NewTxSigHashes(fuzzSigHashTx)
+	hash, err = calcWitnessSignatureHash(pops, sigHashes, hashType,
+		fuzzSigHashTx, idx, 1000)
+	if err != nil {
+		panic(fmt.Sprintf("unable to calculate witness signature hash "+
+			"for valid input index %d: %v", idx, err))
+	}
+	if len(hash) != chainhash.HashSize {
+		panic(fmt.Sprintf("witness signature hash has length %d",
+			len(hash)))
+	}
+	return 1
+}
@@ -888,7 +888,7 @@ func opcodePushData(op *parsedOpcode, vm *Engine) error {
 
 // opcode1Negate pushes -1, encoded as a number, to the data stack.
 func opcode1Negate(op *parsedOpcode, vm *Engine) error {
-	vm.dstack.PushInt(scriptNum(-1))
+	vm.dstack.PushInt(ScriptNum(-1))
 	return nil
 }
 
@@ -898,7 +898,7 @@ func opcode1Negate(op *parsedOpcode, vm *Engine) error {
 func opcodeN(op *parsedOpcode, vm *Engine) error {
 	// The opcodes are all defined consecutively, so the numeric value is
 	// the difference.
-	vm.dstack.PushInt(scriptNum((op.opcode.value - (OP_1 - 1))))
+	vm.dstack.PushInt(ScriptNum((op.opcode.value - (OP_1 - 1))))
 	return nil
 }
 
@@ -1137,8 +1137,8 @@ func opcodeCheckLockTimeVerify(op *parsedOpcode, vm *Engine) error {
 
 	// The current transaction locktime is a uint32 resulting in a maximum
 	// locktime of 2^32-1 (the year 2106).  However, scriptNums are signed
-	// and therefore a standard 4-byte scriptNum would only support up to a
-	// maximum of 2^31-1 (the year 2038).  Thus, a 5-byte scriptNum is used
+	// and therefore a standard 4-byte ScriptNum would only support up to a
+	// maximum of 2^31-1 (the year 2038).  Thus, a 5-byte ScriptNum is used
 	// here since it will support up to 2^39-1 which allows dates beyond the
 	// current locktime limit.
 	//
@@ -1148,7 +1148,8 @@ func opcodeCheckLockTimeVerify(op *parsedOpcode, vm *Engine) error {
 	if err != nil {
 		return err
 	}
-	lockTime, err := makeScriptNum(so, vm.dstack.verifyMinimalData, 5)
+	lockTime, err := MakeScriptNum(so, vm.dstack.verifyMinimalData,
+		LockTimeScriptNumLen)
 	if err != nil {
 		return err
 	}
@@ -1211,8 +1212,8 @@ func opcodeCheckSequenceVerify(op *parsedOpcode, vm *Engine) error {
 
 	// The current transaction sequence is a uint32 resulting in a maximum
 	// sequence of 2^32-1.  However, scriptNums are signed and therefore a
-	// standard 4-byte scriptNum would only support up to a maximum of
-	// 2^31-1.  Thus, a 5-byte scriptNum is used here since it will support
+	// standard 4-byte ScriptNum would only support up to a maximum of
+	// 2^31-1.  Thus, a 5-byte ScriptNum is used here since it will support
 	// up to 2^39-1 which allows sequences beyond the current sequence
 	// limit.
 	//
@@ -1222,7 +1223,8 @@ func opcodeCheckSequenceVerify(op *parsedOpcode, vm *Engine) error {
 	if err != nil {
 		return err
 	}
-	stackSequence, err := makeScriptNum(so, vm.dstack.verifyMinimalData, 5)
+	stackSequence, err := MakeScriptNum(so, vm.dstack.verifyMinimalData,
+		LockTimeScriptNumLen)
 	if err != nil {
 		return err
 	}
@@ -1368,7 +1370,7 @@ func opcodeIfDup(op *parsedOpcode, vm *Engine) error {
 // Example with 2 items: [x1 x2] -> [x1 x2 2]
 // Example with 3 items: [x1 x2 x3] -> [x1 x2 x3 3]
 func opcodeDepth(op *parsedOpcode, vm *Engine) error {
-	vm.dstack.PushInt(scriptNum(vm.dstack.Depth()))
+	vm.dstack.PushInt(ScriptNum(vm.dstack.Depth()))
 	return nil
 }
 
@@ -1462,7 +1464,7 @@ func opcodeSize(op *parsedOpcode, vm *Engine) error {
 		return err
 	}
 
-	vm.dstack.PushInt(scriptNum(len(so)))
+	vm.dstack.PushInt(ScriptNum(len(so)))
 	return nil
 }
 
@@ -1577,9 +1579,9 @@ func opcodeNot(op *parsedOpcode, vm *Engine) error {
 	}
 
 	if m == 0 {
-		vm.dstack.PushInt(scriptNum(1))
+		vm.dstack.PushInt(ScriptNum(1))
 	} else {
-		vm.dstack.PushInt(scriptNum(0))
+		vm.dstack.PushInt(ScriptNum(0))
 	}
 	return nil
 }
@@ -1661,9 +1663,9 @@ func opcodeBoolAnd(op *parsedOpcode, vm *Engine) error {
 	}
 
 	if v0 != 0 && v1 != 0 {
-		vm.dstack.PushInt(scriptNum(1))
+		vm.dstack.PushInt(ScriptNum(1))
 	} else {
-		vm.dstack.PushInt(scriptNum(0))
+		vm.dstack.PushInt(ScriptNum(0))
 	}
 
 	return nil
@@ -1688,9 +1690,9 @@ func opcodeBoolOr(op *parsedOpcode, vm *Engine) error {
 	}
 
 	if v0 != 0 || v1 != 0 {
-		vm.dstack.PushInt(scriptNum(1))
+		vm.dstack.PushInt(ScriptNum(1))
 	} else {
-		vm.dstack.PushInt(scriptNum(0))
+		vm.dstack.PushInt(ScriptNum(0))
 	}
 
 	return nil
@@ -1713,9 +1715,9 @@ func opcodeNumEqual(op *parsedOpcode, vm *Engine) error {
 	}
 
 	if v0 == v1 {
-		vm.dstack.PushInt(scriptNum(1))
+		vm.dstack.PushInt(ScriptNum(1))
 	} else {
-		vm.dstack.PushInt(scriptNum(0))
+		vm.dstack.PushInt(ScriptNum(0))
 	}
 
 	return nil
@@ -1754,9 +1756,9 @@ func opcodeNumNotEqual(op *parsedOpcode, vm *Engine) error {
 	}
 
 	if v0 != v1 {
-		vm.dstack.PushInt(scriptNum(1))
+		vm.dstack.PushInt(ScriptNum(1))
 	} else {
-		vm.dstack.PushInt(scriptNum(0))
+		vm.dstack.PushInt(ScriptNum(0))
 	}
 
 	return nil
@@ -1779,9 +1781,9 @@ func opcodeLessThan(op *parsedOpcode, vm *Engine) error {
 	}
 
 	if v1 < v0 {
-		vm.dstack.PushInt(scriptNum(1))
+		vm.dstack.PushInt(ScriptNum(1))
 	} else {
-		vm.dstack.PushInt(scriptNum(0))
+		vm.dstack.PushInt(ScriptNum(0))
 	}
 
 	return nil
@@ -1804,9 +1806,9 @@ func opcodeGreaterThan(op *parsedOpcode, vm *Engine) error {
 	}
 
 	if v1 > v0 {
-		vm.dstack.PushInt(scriptNum(1))
+		vm.dstack.PushInt(ScriptNum(1))
 	} else {
-		vm.dstack.PushInt(scriptNum(0))
+		vm.dstack.PushInt(ScriptNum(0))
 	}
 	return nil
 }
@@ -1828,9 +1830,9 @@ func opcodeLessThanOrEqual(op *parsedOpcode, vm *Engine) error {
 	}
 
 	if v1 <= v0 {
-		vm.dstack.PushInt(scriptNum(1))
+		vm.dstack.PushInt(ScriptNum(1))
 	} else {
-		vm.dstack.PushInt(scriptNum(0))
+		vm.dstack.PushInt(ScriptNum(0))
 	}
 	return nil
 }
@@ -1852,9 +1854,9 @@ func opcodeGreaterThanOrEqual(op *parsedOpcode, vm *Engine) error {
 	}
 
 	if v1 >= v0 {
-		vm.dstack.PushInt(scriptNum(1))
+		vm.dstack.PushInt(ScriptNum(1))
 	} else {
-		vm.dstack.PushInt(scriptNum(0))
+		vm.dstack.PushInt(ScriptNum(0))
 	}
 
 	return nil
@@ -1933,9 +1935,9 @@ func opcodeWithin(op *parsedOpcode, vm *Engine) error {
 	}
 
 	if x >= minVal && x < maxVal {
-		vm.dstack.PushInt(scriptNum(1))
+		vm.dstack.PushInt(ScriptNum(1))
 	} else {
-		vm.dstack.PushInt(scriptNum(0))
+		vm.dstack.PushInt(ScriptNum(0))
 	}
 	return nil
 }
@@ -867,3 +867,46 @@ func TestCalcSignatureHash(t *testing.T) {
 		}
 	}
 }
+
+// TestCalcSignatureHashSingleBug ensures the signature hash of an input signed
+// with SigHashSingle that does not have a corresponding output is the value
+// one, as required by consensus, regardless of the other hash type bits.
+func TestCalcSignatureHashSingleBug(t *testing.T) {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	for i := uint32(0); i < 3; i++ {
+		prevOut := wire.NewOutPoint(&chainhash.Hash{byte(i + 1)}, i)
+		tx.AddTxIn(wire.NewTxIn(prevOut, nil, nil))
+	}
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{OP_TRUE}))
+
+	subScript, err := parseScript([]byte{OP_TRUE})
+	if err != nil {
+		t.Fatalf("failed to parse sub-script: %v", err)
+	}
+
+	var one chainhash.Hash
+	one[0] = 0x01
+
+	tests := []struct {
+		name     string
+		hashType SigHashType
+		idx      int
+		isOne    bool
+	}{
+		{"single with output", SigHashSingle, 0, false},
+		{"single without output", SigHashSingle, 1, true},
+		{"single last input without output", SigHashSingle, 2, true},
+		{"single anyonecanpay without output",
+			SigHashSingle | SigHashAnyOneCanPay, 2, true},
+		{"all without output", SigHashAll, 2, false},
+		{"none without output", SigHashNone, 2, false},
+	}
+
+	for _, test := range tests {
+		hash := calcSignatureHash(subScript, test.hashType, tx, test.idx)
+		if isOne := bytes.Equal(hash, one[:]); isOne != test.isOne {
+			t.Errorf("%s: unexpected signature hash %x - want one: "+
+				"%v", test.name, hash, test.isOne)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/navcoin/navd/chaincfg/chainhash"
 	"github.com/navcoin/navd/wire"
+	"github.com/navcoin/navutil"
 )
 
 // Bip16Activation is the timestamp where BIP0016 is valid to use in the
@@ -140,6 +141,57 @@ func isWitnessProgram(pops []parsedOpcode) bool {
 		(len(pops[1].data) >= 2 && len(pops[1].data) <= 40)
 }
 
+// IsWrappedWitnessProgram returns true if the passed signature script of a
+// pay-to-script-hash input consists of exactly a single canonical data push of
+// the passed redeem script and the redeem script is a witness program, as
+// consensus requires for P2SH-wrapped witness programs.  Signature scripts with
+// any additional data are rejected since they would reintroduce malleability.
+func IsWrappedWitnessProgram(scriptSig, redeemScript []byte) bool {
+	if !IsWitnessProgram(redeemScript) {
+		return false
+	}
+
+	pops, err := parseScript(scriptSig)
+	if err != nil {
+		return false
+	}
+
+	return len(pops) == 1 && canonicalPush(pops[0]) &&
+		bytes.Equal(pops[0].data, redeemScript)
+}
+
+// ExtractRedeemScript returns the redeem script of the passed signature script
+// of a pay-to-script-hash input, which is the data pushed by its final opcode.
+// An error is returned when the signature script is empty, does not parse, or
+// is not push only, since consensus requires pay-to-script-hash signature
+// scripts to only push data.
+func ExtractRedeemScript(scriptSig []byte) ([]byte, error) {
+	pops, err := parseScript(scriptSig)
+	if err != nil {
+		return nil, err
+	}
+	if len(pops) == 0 {
+		return nil, scriptError(ErrEmptyStack,
+			"signature script does not push a redeem script")
+	}
+	if !isPushOnly(pops) {
+		return nil, scriptError(ErrNotPushOnly,
+			"pay-to-script-hash signature script is not push only")
+	}
+	return pops[len(pops)-1].data, nil
+}
+
+// P2SHScriptMatches returns whether the passed public key script is a
+// pay-to-script-hash script which commits to the hash160 of the passed redeem
+// script.  False is returned for scripts which are not pay-to-script-hash.
+func P2SHScriptMatches(scriptPubKey, redeemScript []byte) bool {
+	pops, err := parseScript(scriptPubKey)
+	if err != nil || !isScriptHash(pops) {
+		return false
+	}
+	return bytes.Equal(pops[1].data, navutil.Hash160(redeemScript))
+}
+
 // ExtractWitnessProgramInfo attempts to extract the witness program version,
 // as well as the witness program itself from the passed script.
 func ExtractWitnessProgramInfo(script []byte) (int, []byte, error) {
@@ -319,6 +371,67 @@ func DisasmString(buf []byte) (string, error) {
 	return disbuf.String(), err
 }
 
+// ValidateConditionals statically checks that the conditional opcodes in the
+// passed script are balanced without executing it.  Every OP_IF and OP_NOTIF
+// must be terminated by a matching OP_ENDIF, and OP_ELSE and OP_ENDIF may only
+// appear within such a conditional.  Conditionals may be nested and, as during
+// execution, OP_CODESEPARATOR has no effect on the conditional state, so a
+// conditional may begin before a code separator and end after it.
+//
+// An error is returned if the script fails to parse, and an error with the
+// ErrUnbalancedConditional code is returned if the conditionals are not
+// balanced.
+func ValidateConditionals(script []byte) error {
+	pops, err := parseScript(script)
+	if err != nil {
+		return err
+	}
+
+	var depth int
+	for _, pop := range pops {
+		switch pop.opcode.value {
+		case OP_IF, OP_NOTIF:
+			depth++
+
+		case OP_ELSE, OP_ENDIF:
+			if depth == 0 {
+				str := fmt.Sprintf("encountered opcode %s with no "+
+					"matching opcode to begin conditional "+
+					"execution", pop.opcode.name)
+				return scriptError(ErrUnbalancedConditional, str)
+			}
+			if pop.opcode.value == OP_ENDIF {
+				depth--
+			}
+		}
+	}
+	if depth != 0 {
+		str := fmt.Sprintf("end of script reached in conditional "+
+			"execution with %d unterminated conditionals", depth)
+		return scriptError(ErrUnbalancedConditional, str)
+	}
+	return nil
+}
+
+// ContainsDisabledOpcode returns the first disabled opcode, such as OP_CAT or
+// OP_MUL, found in the passed script and whether one was found.  As with
+// consensus, the mere presence of a disabled opcode makes the script invalid,
+// so opcodes in branches that would not be executed are also considered.
+//
+// Only the opcodes preceding the first parse failure, if any, are scanned
+// since execution fails at that point.
+func ContainsDisabledOpcode(script []byte) (byte, bool) {
+	// The parsed opcodes up to any parse failure are returned along with
+	// the error, which is intentionally ignored here.
+	pops, _ := parseScript(script)
+	for i := range pops {
+		if pops[i].isDisabled() {
+			return pops[i].opcode.value, true
+		}
+	}
+	return 0, false
+}
+
 // removeOpcode will remove any opcode matching ``opcode'' from the opcode
 // stream in pkscript
 func removeOpcode(pkscript []parsedOpcode, opcode byte) []parsedOpcode {
@@ -370,6 +483,54 @@ func removeOpcodeByData(pkscript []parsedOpcode, data []byte) []parsedOpcode {
 
 }
 
+// SignatureSubScript returns the portion of the passed script that is covered
+// by a legacy (non-segwit) signature when the OP_CODESEPARATOR at the provided
+// opcode index was the last one executed prior to the signature check.  A
+// negative codeSepIdx indicates no OP_CODESEPARATOR was executed, in which
+// case the entire script is used.  Matching the signature hash algorithm, any
+// OP_CODESEPARATOR opcodes remaining after the selected position are removed
+// from the returned script.
+//
+// An ErrInvalidIndex error is returned when codeSepIdx does not refer to an
+// OP_CODESEPARATOR within the script.
+func SignatureSubScript(script []byte, codeSepIdx int) ([]byte, error) {
+	pops, err := parseScript(script)
+	if err != nil {
+		return nil, err
+	}
+
+	if codeSepIdx >= 0 {
+		if codeSepIdx >= len(pops) {
+			str := fmt.Sprintf("code separator index %d is out of range "+
+				"for a script with %d opcodes", codeSepIdx, len(pops))
+			return nil, scriptError(ErrInvalidIndex, str)
+		}
+		if pops[codeSepIdx].opcode.value != OP_CODESEPARATOR {
+			str := fmt.Sprintf("opcode at index %d is %s, not "+
+				"OP_CODESEPARATOR", codeSepIdx,
+				pops[codeSepIdx].opcode.name)
+			return nil, scriptError(ErrInvalidIndex, str)
+		}
+		pops = pops[codeSepIdx+1:]
+	}
+
+	return unparseScript(removeOpcode(pops, OP_CODESEPARATOR))
+}
+
+// RemoveSignaturePush returns the passed script with every canonical data push
+// of the provided signature removed.  This mirrors the treatment legacy
+// signature checking applies to the subscript before computing the signature
+// hash, so pushes of the signature anywhere in the script, including in the
+// middle of it, are stripped.
+func RemoveSignaturePush(script, sig []byte) ([]byte, error) {
+	pops, err := parseScript(script)
+	if err != nil {
+		return nil, err
+	}
+
+	return unparseScript(removeOpcodeByData(pops, sig))
+}
+
 // calcHashPrevOuts calculates a single hash of all the previous outputs
 // (txid:index) referenced within the passed transaction. This calculated hash
 // can be re-used when validating all inputs spending segwit outputs, with a
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/navcoin/navd/wire"
+	"github.com/navcoin/navutil"
 )
 
 // TestParseOpcode tests for opcode parsing with bad data templates.
@@ -4165,6 +4166,173 @@ func TestRemoveOpcodeByData(t *testing.T) {
 	}
 }
 
+// TestSignatureSubScript ensures the subscript covered by legacy signatures is
+// computed correctly for scripts with and without code separators.
+func TestSignatureSubScript(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		script     string
+		codeSepIdx int
+		err        error
+		after      string
+	}{
+		{
+			name:       "no separator executed",
+			script:     "DUP HASH160 DATA_20 0x01020304050607080910111213141516171819 EQUALVERIFY CHECKSIG",
+			codeSepIdx: -1,
+			after:      "DUP HASH160 DATA_20 0x01020304050607080910111213141516171819 EQUALVERIFY CHECKSIG",
+		},
+		{
+			name:       "no separator executed strips separators",
+			script:     "NOP CODESEPARATOR DUP CODESEPARATOR CHECKSIG",
+			codeSepIdx: -1,
+			after:      "NOP DUP CHECKSIG",
+		},
+		{
+			name:       "single separator",
+			script:     "NOP CODESEPARATOR DUP CHECKSIG",
+			codeSepIdx: 1,
+			after:      "DUP CHECKSIG",
+		},
+		{
+			name:       "first of multiple separators",
+			script:     "NOP CODESEPARATOR DUP CODESEPARATOR CHECKSIG",
+			codeSepIdx: 1,
+			after:      "DUP CHECKSIG",
+		},
+		{
+			name:       "last of multiple separators",
+			script:     "NOP CODESEPARATOR DUP CODESEPARATOR CHECKSIG",
+			codeSepIdx: 3,
+			after:      "CHECKSIG",
+		},
+		{
+			name:       "separator is final opcode",
+			script:     "NOP CHECKSIG CODESEPARATOR",
+			codeSepIdx: 2,
+			after:      "",
+		},
+		{
+			name:       "index is not a separator",
+			script:     "NOP CODESEPARATOR CHECKSIG",
+			codeSepIdx: 0,
+			err:        scriptError(ErrInvalidIndex, ""),
+		},
+		{
+			name:       "index out of range",
+			script:     "NOP CODESEPARATOR CHECKSIG",
+			codeSepIdx: 3,
+			err:        scriptError(ErrInvalidIndex, ""),
+		},
+		{
+			name:       "unparsable script",
+			script:     "NOP DATA_2 0x01",
+			codeSepIdx: -1,
+			err:        scriptError(ErrMalformedPush, ""),
+		},
+	}
+
+	for _, test := range tests {
+		script := mustParseShortForm(test.script)
+		result, err := SignatureSubScript(script, test.codeSepIdx)
+		if e := tstCheckScriptError(err, test.err); e != nil {
+			t.Errorf("%s: %v", test.name, e)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		want := mustParseShortForm(test.after)
+		if !bytes.Equal(want, result) {
+			t.Errorf("%s: value does not equal expected: exp: %x "+
+				"got: %x", test.name, want, result)
+		}
+	}
+}
+
+// TestRemoveSignaturePush ensures pushes of a signature are removed from
+// scripts regardless of where they appear.
+func TestRemoveSignaturePush(t *testing.T) {
+	t.Parallel()
+
+	sig := bytes.Repeat([]byte{0x30}, 9)
+	otherSig := bytes.Repeat([]byte{0x31}, 9)
+	tests := []struct {
+		name   string
+		script string
+		err    error
+		after  string
+	}{
+		{
+			name:   "no signature present",
+			script: "DUP CHECKSIG",
+			after:  "DUP CHECKSIG",
+		},
+		{
+			name:   "signature at start",
+			script: "DATA_9 0x303030303030303030 CHECKSIG",
+			after:  "CHECKSIG",
+		},
+		{
+			name:   "signature mid-script",
+			script: "NOP DATA_9 0x303030303030303030 DROP CHECKSIG",
+			after:  "NOP DROP CHECKSIG",
+		},
+		{
+			name:   "multiple signature pushes",
+			script: "DATA_9 0x303030303030303030 CODESEPARATOR DATA_9 0x303030303030303030 CHECKSIG",
+			after:  "CODESEPARATOR CHECKSIG",
+		},
+		{
+			name:   "different signature kept",
+			script: "DATA_9 0x313131313131313131 DATA_9 0x303030303030303030 CHECKSIG",
+			after:  "DATA_9 0x313131313131313131 CHECKSIG",
+		},
+		{
+			name:   "non-canonical push kept",
+			script: "PUSHDATA1 0x09 0x303030303030303030 CHECKSIG",
+			after:  "PUSHDATA1 0x09 0x303030303030303030 CHECKSIG",
+		},
+		{
+			name:   "unparsable script",
+			script: "NOP DATA_2 0x01",
+			err:    scriptError(ErrMalformedPush, ""),
+		},
+	}
+
+	for _, test := range tests {
+		script := mustParseShortForm(test.script)
+		result, err := RemoveSignaturePush(script, sig)
+		if e := tstCheckScriptError(err, test.err); e != nil {
+			t.Errorf("%s: %v", test.name, e)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		want := mustParseShortForm(test.after)
+		if !bytes.Equal(want, result) {
+			t.Errorf("%s: value does not equal expected: exp: %x "+
+				"got: %x", test.name, want, result)
+		}
+	}
+
+	// Ensure the unrelated signature is untouched when removing it is not
+	// requested.
+	script := mustParseShortForm("DATA_9 0x313131313131313131 CHECKSIG")
+	result, err := RemoveSignaturePush(script, otherSig)
+	if err != nil {
+		t.Fatalf("RemoveSignaturePush: unexpected error: %v", err)
+	}
+	if want := mustParseShortForm("CHECKSIG"); !bytes.Equal(want, result) {
+		t.Fatalf("RemoveSignaturePush: exp: %x got: %x", want, result)
+	}
+}
+
 // TestIsPayToScriptHash ensures the IsPayToScriptHash function returns the
 // expected results for all the scripts in scriptClassTests.
 func TestIsPayToScriptHash(t *testing.T) {
@@ -4213,6 +4381,251 @@ func TestIsPayToWitnessPubKeyHash(t *testing.T) {
 	}
 }
 
+// TestIsWrappedWitnessProgram ensures the IsWrappedWitnessProgram function
+// only accepts signature scripts which are exactly a canonical push of a
+// witness program redeem script.
+func TestIsWrappedWitnessProgram(t *testing.T) {
+	t.Parallel()
+
+	const program = "0x0014 0x1d0f172a0ecb48aee1be1f2687d2963ae33f71a1"
+	redeemScript := mustParseShortForm("0 DATA_20 " +
+		"0x1d0f172a0ecb48aee1be1f2687d2963ae33f71a1")
+
+	tests := []struct {
+		name         string
+		scriptSig    string
+		redeemScript []byte
+		expected     bool
+	}{
+		{
+			name:         "canonical p2sh-p2wpkh",
+			scriptSig:    "DATA_22 " + program,
+			redeemScript: redeemScript,
+			expected:     true,
+		},
+		{
+			name:         "padded before push",
+			scriptSig:    "0 DATA_22 " + program,
+			redeemScript: redeemScript,
+			expected:     false,
+		},
+		{
+			name:         "padded after push",
+			scriptSig:    "DATA_22 " + program + " 0",
+			redeemScript: redeemScript,
+			expected:     false,
+		},
+		{
+			name:         "non-canonical push",
+			scriptSig:    "PUSHDATA1 0x16 " + program,
+			redeemScript: redeemScript,
+			expected:     false,
+		},
+		{
+			name:         "push of different program",
+			scriptSig:    "DATA_22 0x0014 0x0000000000000000000000000000000000000000",
+			redeemScript: redeemScript,
+			expected:     false,
+		},
+		{
+			name:         "redeem script not a witness program",
+			scriptSig:    "DATA_2 0x5151",
+			redeemScript: mustParseShortForm("1 1"),
+			expected:     false,
+		},
+		{
+			name:         "does not parse",
+			scriptSig:    "DATA_22 0x0014",
+			redeemScript: redeemScript,
+			expected:     false,
+		},
+	}
+
+	for _, test := range tests {
+		scriptSig := mustParseShortForm(test.scriptSig)
+		got := IsWrappedWitnessProgram(scriptSig, test.redeemScript)
+		if got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name,
+				test.expected, got)
+		}
+	}
+}
+
+// TestExtractRedeemScript ensures the redeem script is extracted from the
+// signature script of a pay-to-script-hash multisig input and matches the
+// hash committed to by the public key script.
+func TestExtractRedeemScript(t *testing.T) {
+	t.Parallel()
+
+	// A 1-of-2 multisig redeem script and a signature script spending it
+	// with a single signature.
+	redeemScript := mustParseShortForm("1 DATA_33 " +
+		"0x02f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9 " +
+		"DATA_33 " +
+		"0x03e60fce93b59e9ec53011aabc21c23e97b2a31369b87a5ae9c44ee89e2a6dec0a " +
+		"2 CHECKMULTISIG")
+	sig := mustParseShortForm("0x3044022049f4a4a9dba9be5e0b3aa08f0d72e" +
+		"1e25df5b3a3a37ad90fd7d1e37d5c2d4d7e022036ff6c6bb3cba3e4d1e44c5b5" +
+		"4e2d35a5b2a6db0eba8faec2e5e9a34d1bc34b101")
+	scriptSig, err := NewScriptBuilder().AddOp(OP_0).AddData(sig).
+		AddData(redeemScript).Script()
+	if err != nil {
+		t.Fatalf("unable to build signature script: %v", err)
+	}
+	scriptPubKey, err := payToScriptHashScript(navutil.Hash160(redeemScript))
+	if err != nil {
+		t.Fatalf("unable to build public key script: %v", err)
+	}
+
+	extracted, err := ExtractRedeemScript(scriptSig)
+	if err != nil {
+		t.Fatalf("ExtractRedeemScript: unexpected error: %v", err)
+	}
+	if !bytes.Equal(extracted, redeemScript) {
+		t.Fatalf("ExtractRedeemScript: mismatched redeem script - got "+
+			"%x, want %x", extracted, redeemScript)
+	}
+	if !P2SHScriptMatches(scriptPubKey, extracted) {
+		t.Fatalf("P2SHScriptMatches: redeem script does not match")
+	}
+
+	// A different redeem script and a public key script which is not
+	// pay-to-script-hash must not match.
+	if P2SHScriptMatches(scriptPubKey, extracted[1:]) {
+		t.Errorf("P2SHScriptMatches: altered redeem script matches")
+	}
+	if P2SHScriptMatches(redeemScript, extracted) {
+		t.Errorf("P2SHScriptMatches: non-p2sh script matches")
+	}
+
+	// Signature scripts which are empty, do not parse, or are not push
+	// only must be rejected.
+	tests := []struct {
+		name      string
+		scriptSig []byte
+		err       error
+	}{
+		{
+			name:      "empty",
+			scriptSig: nil,
+			err:       scriptError(ErrEmptyStack, ""),
+		},
+		{
+			name:      "does not parse",
+			scriptSig: mustParseShortForm("0x4c"),
+			err:       scriptError(ErrMalformedPush, ""),
+		},
+		{
+			name:      "not push only",
+			scriptSig: mustParseShortForm("0 DUP DATA_1 0x51"),
+			err:       scriptError(ErrNotPushOnly, ""),
+		},
+	}
+	for _, test := range tests {
+		_, err := ExtractRedeemScript(test.scriptSig)
+		if e := tstCheckScriptError(err, test.err); e != nil {
+			t.Errorf("%s: %v", test.name, e)
+		}
+	}
+}
+
+// TestValidateConditionals ensures ValidateConditionals accepts scripts with
+// balanced conditionals and rejects scripts with unbalanced ones.
+func TestValidateConditionals(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		script string
+		err    error
+	}{
+		{
+			name:   "empty script",
+			script: "",
+			err:    nil,
+		},
+		{
+			name: "no conditionals",
+			script: "DUP HASH160 DATA_20 0x01020304050607080910111213141516" +
+				"17181920 EQUALVERIFY CHECKSIG",
+			err: nil,
+		},
+		{
+			name:   "if endif",
+			script: "1 IF 2 ENDIF",
+			err:    nil,
+		},
+		{
+			name:   "notif else endif",
+			script: "0 NOTIF 2 ELSE 3 ENDIF",
+			err:    nil,
+		},
+		{
+			name:   "multiple elses",
+			script: "1 IF 2 ELSE 3 ELSE 4 ENDIF",
+			err:    nil,
+		},
+		{
+			name:   "nested conditionals",
+			script: "1 IF 1 NOTIF 2 ELSE 1 IF 3 ENDIF ENDIF ELSE 4 ENDIF",
+			err:    nil,
+		},
+		{
+			name:   "conditional split across code separator",
+			script: "1 IF CODESEPARATOR 2 ELSE CODESEPARATOR 3 ENDIF",
+			err:    nil,
+		},
+		{
+			name:   "conditional opcode pushed as data",
+			script: "DATA_1 0x68 1 IF DATA_1 0x63 ENDIF",
+			err:    nil,
+		},
+		{
+			name:   "unterminated if",
+			script: "1 IF 2",
+			err:    scriptError(ErrUnbalancedConditional, ""),
+		},
+		{
+			name:   "unterminated nested notif",
+			script: "1 IF 0 NOTIF 2 ENDIF",
+			err:    scriptError(ErrUnbalancedConditional, ""),
+		},
+		{
+			name:   "dangling endif",
+			script: "1 ENDIF",
+			err:    scriptError(ErrUnbalancedConditional, ""),
+		},
+		{
+			name:   "endif after balanced conditional",
+			script: "1 IF 2 ENDIF ENDIF",
+			err:    scriptError(ErrUnbalancedConditional, ""),
+		},
+		{
+			name:   "else outside conditional",
+			script: "1 ELSE 2",
+			err:    scriptError(ErrUnbalancedConditional, ""),
+		},
+		{
+			name:   "endif before if",
+			script: "ENDIF 1 IF",
+			err:    scriptError(ErrUnbalancedConditional, ""),
+		},
+		{
+			name:   "does not parse",
+			script: "1 IF DATA_2 0x01",
+			err:    scriptError(ErrMalformedPush, ""),
+		},
+	}
+
+	for _, test := range tests {
+		script := mustParseShortForm(test.script)
+		err := ValidateConditionals(script)
+		if e := tstCheckScriptError(err, test.err); e != nil {
+			t.Errorf("%s: %v", test.name, e)
+		}
+	}
+}
+
 // TestHasCanonicalPushes ensures the canonicalPush function properly determines
 // what is considered a canonical push for the purposes of removeOpcodeByData.
 func TestHasCanonicalPushes(t *testing.T) {
@@ -4312,3 +4725,58 @@ func TestIsUnspendable(t *testing.T) {
 		}
 	}
 }
+
+// TestContainsDisabledOpcode ensures the ContainsDisabledOpcode function
+// reports the first disabled opcode in a script, including those in branches
+// that would not be executed.
+func TestContainsDisabledOpcode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		script []byte
+		op     byte
+		found  bool
+	}{{
+		name:   "empty script",
+		script: nil,
+	}, {
+		name:   "no disabled opcodes",
+		script: mustParseShortForm("1 2 ADD 3 EQUAL"),
+	}, {
+		name:   "executed cat",
+		script: mustParseShortForm("1 2 CAT"),
+		op:     OP_CAT,
+		found:  true,
+	}, {
+		name:   "cat in unexecuted branch",
+		script: mustParseShortForm("0 IF CAT ENDIF 1"),
+		op:     OP_CAT,
+		found:  true,
+	}, {
+		name:   "first of several disabled opcodes",
+		script: mustParseShortForm("1 IF 2 ELSE SUBSTR ENDIF MUL"),
+		op:     OP_SUBSTR,
+		found:  true,
+	}, {
+		name:   "disabled opcode value in push data",
+		script: mustParseShortForm("DATA_2 0x7e95"),
+	}, {
+		name:   "disabled opcode before parse failure",
+		script: mustParseShortForm("2MUL DATA_2 0x01"),
+		op:     OP_2MUL,
+		found:  true,
+	}, {
+		name:   "disabled opcode after parse failure",
+		script: mustParseShortForm("DATA_3 0x7e"),
+	}}
+
+	for _, test := range tests {
+		op, found := ContainsDisabledOpcode(test.script)
+		if op != test.op || found != test.found {
+			t.Errorf("%s: unexpected result - got (%x, %v), want "+
+				"(%x, %v)", test.name, op, found, test.op,
+				test.found)
+		}
+	}
+}
@@ -322,9 +322,30 @@ func TestExceedMaxScriptSize(t *testing.T) {
 			"got len %d, want len %d", len(script), len(origScript))
 	}
 
+	// Ensure adding opcodes that would exceed the maximum size of the
+	// script does not add any of them.
+	builder.Reset().AddFullData(make([]byte, MaxScriptSize-5))
+	origScript, err = builder.Script()
+	if err != nil {
+		t.Fatalf("Unexpected error for near max size script: %v", err)
+	}
+	script, err = builder.AddOps([]byte{OP_0, OP_1, OP_2}).Script()
+	if _, ok := err.(ErrScriptNotCanonical); !ok || err == nil {
+		t.Fatalf("ScriptBuilder.AddOps allowed exceeding max script "+
+			"size: %v", len(script))
+	}
+	if !bytes.Equal(script, origScript) {
+		t.Fatalf("ScriptBuilder.AddOps unexpected modified script - "+
+			"got len %d, want len %d", len(script), len(origScript))
+	}
+
 	// Ensure adding an integer that would exceed the maximum size of the
 	// script does not add the data.
 	builder.Reset().AddFullData(make([]byte, MaxScriptSize-3))
+	origScript, err = builder.Script()
+	if err != nil {
+		t.Fatalf("Unexpected error for max size script: %v", err)
+	}
 	script, err = builder.AddInt64(0).Script()
 	if _, ok := err.(ErrScriptNotCanonical); !ok || err == nil {
 		t.Fatalf("ScriptBuilder.AddInt64 unexpected modified script - "+
@@ -393,6 +414,16 @@ func TestErroredScript(t *testing.T) {
 			"got len %d, want len %d", len(script), len(origScript))
 	}
 
+	// Ensure adding opcodes to a script that has errored doesn't succeed.
+	script, err = builder.AddOps([]byte{OP_0}).Script()
+	if _, ok := err.(ErrScriptNotCanonical); !ok || err == nil {
+		t.Fatal("ScriptBuilder.AddOps succeeded on errored script")
+	}
+	if !bytes.Equal(script, origScript) {
+		t.Fatalf("ScriptBuilder.AddOps unexpected modified script - "+
+			"got len %d, want len %d", len(script), len(origScript))
+	}
+
 	// Ensure adding an integer to a script that has errored doesn't
 	// succeed.
 	script, err = builder.AddInt64(0).Script()
@@ -0,0 +1,123 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// ScriptCache implements a script validation result cache with a randomized
+// entry eviction policy.  Unlike SigCache, which caches individual signature
+// verifications, ScriptCache records that the full script execution for a
+// specific transaction input succeeded, so re-validating the same input under
+// identical conditions is reduced to a single map lookup.  Only successful
+// validations should be added to the cache.
+//
+// Entries are keyed by the transaction hash, the input index, the script
+// verification flags, the public key script being spent and the signature
+// script and witness of the input.  Including the flags in the key ensures a
+// re-check under stricter (or simply different) flags never results in a
+// false hit.
+type ScriptCache struct {
+	sync.RWMutex
+	validScripts map[chainhash.Hash]struct{}
+	maxEntries   uint
+}
+
+// NewScriptCache creates and initializes a new instance of ScriptCache.  Its
+// sole parameter 'maxEntries' represents the maximum number of entries allowed
+// to exist in the ScriptCache at any particular moment.  Random entries are
+// evicted to make room for new entries that would cause the number of entries
+// in the cache to exceed the max.
+func NewScriptCache(maxEntries uint) *ScriptCache {
+	return &ScriptCache{
+		validScripts: make(map[chainhash.Hash]struct{}, maxEntries),
+		maxEntries:   maxEntries,
+	}
+}
+
+// scriptCacheKey returns the key which identifies the validation of the input
+// at index 'txIdx' of the passed transaction spending 'scriptPubKey' under the
+// passed flags.
+func scriptCacheKey(scriptPubKey []byte, tx *wire.MsgTx, txIdx int, flags ScriptFlags) chainhash.Hash {
+	txIn := tx.TxIn[txIdx]
+
+	// The key is the hash of the serialized tuple:
+	//   txid || input index || flags || pkScript || sigScript || witness
+	var buf bytes.Buffer
+	txHash := tx.TxHash()
+	buf.Write(txHash[:])
+	var scratch [4]byte
+	binary.LittleEndian.PutUint32(scratch[:], uint32(txIdx))
+	buf.Write(scratch[:])
+	binary.LittleEndian.PutUint32(scratch[:], uint32(flags))
+	buf.Write(scratch[:])
+	wire.WriteVarBytes(&buf, 0, scriptPubKey)
+	wire.WriteVarBytes(&buf, 0, txIn.SignatureScript)
+	wire.WriteVarInt(&buf, 0, uint64(len(txIn.Witness)))
+	for _, item := range txIn.Witness {
+		wire.WriteVarBytes(&buf, 0, item)
+	}
+
+	return chainhash.HashH(buf.Bytes())
+}
+
+// Exists returns true if a successful validation of the input at index
+// 'txIdx' of the passed transaction spending 'scriptPubKey' under the passed
+// flags is found within the ScriptCache.  Otherwise, false is returned.
+//
+// NOTE: This function is safe for concurrent access. Readers won't be blocked
+// unless there exists a writer, adding an entry to the ScriptCache.
+func (s *ScriptCache) Exists(scriptPubKey []byte, tx *wire.MsgTx, txIdx int, flags ScriptFlags) bool {
+	if txIdx < 0 || txIdx >= len(tx.TxIn) {
+		return false
+	}
+	key := scriptCacheKey(scriptPubKey, tx, txIdx, flags)
+
+	s.RLock()
+	_, ok := s.validScripts[key]
+	s.RUnlock()
+
+	return ok
+}
+
+// Add adds an entry for the successful validation of the input at index
+// 'txIdx' of the passed transaction spending 'scriptPubKey' under the passed
+// flags.  In the event that the ScriptCache is 'full', an existing entry is
+// randomly chosen to be evicted in order to make space for the new entry.
+//
+// NOTE: This function is safe for concurrent access. Writers will block
+// simultaneous readers until function execution has concluded.
+func (s *ScriptCache) Add(scriptPubKey []byte, tx *wire.MsgTx, txIdx int, flags ScriptFlags) {
+	if txIdx < 0 || txIdx >= len(tx.TxIn) {
+		return
+	}
+	key := scriptCacheKey(scriptPubKey, tx, txIdx, flags)
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.maxEntries <= 0 {
+		return
+	}
+
+	// If adding this new entry will put us over the max number of allowed
+	// entries, then evict a random entry.  See SigCache.Add for details on
+	// the safety of relying on Go's map iteration order.
+	if _, ok := s.validScripts[key]; !ok &&
+		uint(len(s.validScripts)+1) > s.maxEntries {
+
+		for scriptEntry := range s.validScripts {
+			delete(s.validScripts, scriptEntry)
+			break
+		}
+	}
+	s.validScripts[key] = struct{}{}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// newScriptCacheTestTx returns a transaction with two inputs for use in the
+// script cache tests.
+func newScriptCacheTestTx() *wire.MsgTx {
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0),
+		[]byte{OP_TRUE}, nil))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x02}, 1),
+		nil, [][]byte{{0x01, 0x02}, {0x03}}))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{OP_TRUE}))
+	return tx
+}
+
+// TestScriptCacheAddExists tests the ability to add, and later check the
+// existence of a script validation result in the script cache, and that a
+// change to any part of the key results in a miss.
+func TestScriptCacheAddExists(t *testing.T) {
+	scriptCache := NewScriptCache(100)
+	tx := newScriptCacheTestTx()
+	pkScript := []byte{OP_TRUE}
+	flags := ScriptBip16 | ScriptVerifyDERSignatures
+
+	scriptCache.Add(pkScript, tx, 0, flags)
+	scriptCache.Add(pkScript, tx, 1, flags)
+
+	if !scriptCache.Exists(pkScript, tx, 0, flags) {
+		t.Fatalf("previously added item not found in script cache")
+	}
+	if !scriptCache.Exists(pkScript, tx, 1, flags) {
+		t.Fatalf("previously added item not found in script cache")
+	}
+
+	// A stricter set of flags must not produce a hit.
+	if scriptCache.Exists(pkScript, tx, 0, flags|ScriptVerifyCleanStack) {
+		t.Fatalf("script cache hit with different flags")
+	}
+
+	// A different public key script must not produce a hit.
+	if scriptCache.Exists([]byte{OP_FALSE}, tx, 0, flags) {
+		t.Fatalf("script cache hit with different public key script")
+	}
+
+	// A different witness for the same input must not produce a hit.
+	modifiedTx := tx.Copy()
+	modifiedTx.TxIn[1].Witness[1] = []byte{0x04}
+	if scriptCache.Exists(pkScript, modifiedTx, 1, flags) {
+		t.Fatalf("script cache hit with different witness")
+	}
+
+	// Out of range input indexes must never produce a hit.
+	if scriptCache.Exists(pkScript, tx, 2, flags) {
+		t.Fatalf("script cache hit for out of range input index")
+	}
+}
+
+// TestScriptCacheAddEvictEntry tests the eviction case where a new entry is
+// added to a full script cache.
+func TestScriptCacheAddEvictEntry(t *testing.T) {
+	scriptCacheSize := uint(10)
+	scriptCache := NewScriptCache(scriptCacheSize)
+	tx := newScriptCacheTestTx()
+
+	for i := uint(0); i <= scriptCacheSize; i++ {
+		scriptCache.Add([]byte{byte(i)}, tx, 0, ScriptBip16)
+	}
+
+	if uint(len(scriptCache.validScripts)) != scriptCacheSize {
+		t.Fatalf("script cache should have %v entries, instead it "+
+			"has %v", scriptCacheSize, len(scriptCache.validScripts))
+	}
+	if !scriptCache.Exists([]byte{byte(scriptCacheSize)}, tx, 0, ScriptBip16) {
+		t.Fatalf("previously added item not found in script cache")
+	}
+}
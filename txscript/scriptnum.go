@@ -15,9 +15,20 @@ const (
 	// defaultScriptNumLen is the default number of bytes
 	// data being interpreted as an integer may be.
 	defaultScriptNumLen = 4
+
+	// LockTimeScriptNumLen is the number of bytes data being interpreted
+	// as an integer may be for the lock time and sequence values used by
+	// OP_CHECKLOCKTIMEVERIFY and OP_CHECKSEQUENCEVERIFY.  It is larger than
+	// the default so the full range of unsigned 32-bit values is supported.
+	LockTimeScriptNumLen = 5
+
+	// maxScriptNumLen is the maximum number of bytes data being interpreted
+	// as an integer may be.  Larger values can not be represented by the
+	// int64 a ScriptNum is backed by.
+	maxScriptNumLen = 8
 )
 
-// scriptNum represents a numeric value used in the scripting engine with
+// ScriptNum represents a numeric value used in the scripting engine with
 // special handling to deal with the subtle semantics required by consensus.
 //
 // All numbers are stored on the data and alternate stacks encoded as little
@@ -39,11 +50,11 @@ const (
 // method to get the serialized representation (including values that overflow).
 //
 // Then, whenever data is interpreted as an integer, it is converted to this
-// type by using the makeScriptNum function which will return an error if the
+// type by using the MakeScriptNum function which will return an error if the
 // number is out of range or not minimally encoded depending on parameters.
 // Since all numeric opcodes involve pulling data from the stack and
 // interpreting it as an integer, it provides the required behavior.
-type scriptNum int64
+type ScriptNum int64
 
 // checkMinimalDataEncoding returns whether or not the passed byte array adheres
 // to the minimal encoding requirements.
@@ -89,7 +100,7 @@ func checkMinimalDataEncoding(v []byte) error {
 //    -32767 -> [0xff 0xff]
 //     32768 -> [0x00 0x80 0x00]
 //    -32768 -> [0x00 0x80 0x80]
-func (n scriptNum) Bytes() []byte {
+func (n ScriptNum) Bytes() []byte {
 	// Zero encodes as an empty byte slice.
 	if n == 0 {
 		return nil
@@ -139,12 +150,12 @@ func (n scriptNum) Bytes() []byte {
 // provide this behavior.
 //
 // In practice, for most opcodes, the number should never be out of range since
-// it will have been created with makeScriptNum using the defaultScriptLen
+// it will have been created with MakeScriptNum using the defaultScriptLen
 // value, which rejects them.  In case something in the future ends up calling
 // this function against the result of some arithmetic, which IS allowed to be
 // out of range before being reinterpreted as an integer, this will provide the
 // correct behavior.
-func (n scriptNum) Int32() int32 {
+func (n ScriptNum) Int32() int32 {
 	if n > maxInt32 {
 		return maxInt32
 	}
@@ -156,7 +167,7 @@ func (n scriptNum) Int32() int32 {
 	return int32(n)
 }
 
-// makeScriptNum interprets the passed serialized bytes as an encoded integer
+// MakeScriptNum interprets the passed serialized bytes as an encoded integer
 // and returns the result as a script number.
 //
 // Since the consensus rules dictate that serialized bytes interpreted as ints
@@ -175,14 +186,23 @@ func (n scriptNum) Int32() int32 {
 // requireMinimal enabled.
 //
 // The scriptNumLen is the maximum number of bytes the encoded value can be
-// before an ErrStackNumberTooBig is returned.  This effectively limits the
-// range of allowed values.
+// before an ErrNumberTooBig is returned.  This effectively limits the
+// range of allowed values.  A scriptNumLen larger than 8 is rejected with
+// ErrNumberTooBig since such values can not be represented by a ScriptNum.
 // WARNING:  Great care should be taken if passing a value larger than
 // defaultScriptNumLen, which could lead to addition and multiplication
 // overflows.
 //
 // See the Bytes function documentation for example encodings.
-func makeScriptNum(v []byte, requireMinimal bool, scriptNumLen int) (scriptNum, error) {
+func MakeScriptNum(v []byte, requireMinimal bool, scriptNumLen int) (ScriptNum, error) {
+	// Values larger than 8 bytes would silently overflow the int64 they
+	// are decoded into.
+	if scriptNumLen > maxScriptNumLen {
+		str := fmt.Sprintf("script number length of %d bytes exceeds "+
+			"the max allowed of %d", scriptNumLen, maxScriptNumLen)
+		return 0, scriptError(ErrNumberTooBig, str)
+	}
+
 	// Interpreting data requires that it is not larger than
 	// the the passed scriptNumLen value.
 	if len(v) > scriptNumLen {
@@ -218,8 +238,8 @@ func makeScriptNum(v []byte, requireMinimal bool, scriptNumLen int) (scriptNum,
 		// above, so uint8 is enough to cover the max possible shift
 		// value of 24.
 		result &= ^(int64(0x80) << uint8(8*(len(v)-1)))
-		return scriptNum(-result), nil
+		return ScriptNum(-result), nil
 	}
 
-	return scriptNum(result), nil
+	return ScriptNum(result), nil
 }
@@ -28,7 +28,7 @@ func TestScriptNumBytes(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		num        scriptNum
+		num        ScriptNum
 		serialized []byte
 	}{
 		{0, nil},
@@ -98,7 +98,7 @@ func TestMakeScriptNum(t *testing.T) {
 
 	tests := []struct {
 		serialized      []byte
-		num             scriptNum
+		num             ScriptNum
 		numLen          int
 		minimalEncoding bool
 		err             error
@@ -137,10 +137,16 @@ func TestMakeScriptNum(t *testing.T) {
 		{hexToBytes("ffffffffff"), -549755813887, 5, true, nil},
 		{hexToBytes("ffffffffffffff7f"), 9223372036854775807, 8, true, nil},
 		{hexToBytes("ffffffffffffffff"), -9223372036854775807, 8, true, nil},
-		{hexToBytes("ffffffffffffffff7f"), -1, 9, true, nil},
-		{hexToBytes("ffffffffffffffffff"), 1, 9, true, nil},
-		{hexToBytes("ffffffffffffffffff7f"), -1, 10, true, nil},
-		{hexToBytes("ffffffffffffffffffff"), 1, 10, true, nil},
+
+		// Lengths which exceed the number of bytes that can be
+		// represented by a script number.  Should error and return 0
+		// regardless of the length of the data.
+		{hexToBytes("ffffffffffffffff7f"), 0, 9, true, errNumTooBig},
+		{hexToBytes("ffffffffffffffffff"), 0, 9, true, errNumTooBig},
+		{hexToBytes("ffffffffffffffffff7f"), 0, 10, true, errNumTooBig},
+		{hexToBytes("ffffffffffffffffffff"), 0, 10, true, errNumTooBig},
+		{hexToBytes("01"), 0, 9, true, errNumTooBig},
+		{nil, 0, 9, false, errNumTooBig},
 
 		// Minimally encoded values that are out of range for data that
 		// is interpreted as script numbers with the minimal encoding
@@ -195,15 +201,15 @@ func TestMakeScriptNum(t *testing.T) {
 	for _, test := range tests {
 		// Ensure the error code is of the expected type and the error
 		// code matches the value specified in the test instance.
-		gotNum, err := makeScriptNum(test.serialized, test.minimalEncoding,
+		gotNum, err := MakeScriptNum(test.serialized, test.minimalEncoding,
 			test.numLen)
 		if e := tstCheckScriptError(err, test.err); e != nil {
-			t.Errorf("makeScriptNum(%#x): %v", test.serialized, e)
+			t.Errorf("MakeScriptNum(%#x): %v", test.serialized, e)
 			continue
 		}
 
 		if gotNum != test.num {
-			t.Errorf("makeScriptNum(%#x): did not get expected "+
+			t.Errorf("MakeScriptNum(%#x): did not get expected "+
 				"number - got %d, want %d", test.serialized,
 				gotNum, test.num)
 			continue
@@ -211,13 +217,105 @@ func TestMakeScriptNum(t *testing.T) {
 	}
 }
 
+// TestScriptNumRoundTrip ensures that encoding script numbers at the boundaries
+// of each serialized length and decoding them again with minimal encoding
+// required produces the original number.
+func TestScriptNumRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		num    ScriptNum
+		numLen int
+	}{
+		{0, defaultScriptNumLen},
+		{1, defaultScriptNumLen},
+		{-1, defaultScriptNumLen},
+		{127, defaultScriptNumLen},
+		{-127, defaultScriptNumLen},
+		{128, defaultScriptNumLen},
+		{-128, defaultScriptNumLen},
+		{32767, defaultScriptNumLen},
+		{-32767, defaultScriptNumLen},
+		{32768, defaultScriptNumLen},
+		{-32768, defaultScriptNumLen},
+		{8388607, defaultScriptNumLen},
+		{-8388607, defaultScriptNumLen},
+		{8388608, defaultScriptNumLen},
+		{-8388608, defaultScriptNumLen},
+		{2147483647, defaultScriptNumLen},
+		{-2147483647, defaultScriptNumLen},
+
+		// Lock time values beyond the range of a 4-byte number which
+		// require the extended length.
+		{2147483648, LockTimeScriptNumLen},
+		{4294967295, LockTimeScriptNumLen},
+		{549755813887, LockTimeScriptNumLen},
+		{-549755813887, LockTimeScriptNumLen},
+	}
+
+	for _, test := range tests {
+		serialized := test.num.Bytes()
+		if len(serialized) > test.numLen {
+			t.Errorf("Bytes(%d): serialized length %d exceeds %d",
+				test.num, len(serialized), test.numLen)
+			continue
+		}
+
+		num, err := MakeScriptNum(serialized, true, test.numLen)
+		if err != nil {
+			t.Errorf("MakeScriptNum(%#x): unexpected error: %v",
+				serialized, err)
+			continue
+		}
+		if num != test.num {
+			t.Errorf("MakeScriptNum(%#x): got %d, want %d",
+				serialized, num, test.num)
+			continue
+		}
+	}
+
+	// Ensure numbers which require the extended lock time length are
+	// rejected with the default length.
+	serialized := ScriptNum(4294967295).Bytes()
+	_, err := MakeScriptNum(serialized, true, defaultScriptNumLen)
+	if e := tstCheckScriptError(err, scriptError(ErrNumberTooBig, "")); e != nil {
+		t.Errorf("MakeScriptNum(%#x): %v", serialized, e)
+	}
+
+	// Ensure non-minimal encodings of round-tripped numbers are rejected
+	// when minimal encoding is required and accepted otherwise.
+	nonMinimal := [][]byte{
+		hexToBytes("0100"),
+		hexToBytes("7f0000"),
+		hexToBytes("ff0000"),
+		hexToBytes("0000000000"),
+		hexToBytes("ffffffff0000"),
+	}
+	for _, serialized := range nonMinimal {
+		_, err := MakeScriptNum(serialized, true, len(serialized))
+		wantErr := scriptError(ErrMinimalData, "")
+		if e := tstCheckScriptError(err, wantErr); e != nil {
+			t.Errorf("MakeScriptNum(%#x): %v", serialized, e)
+			continue
+		}
+		if _, err := MakeScriptNum(serialized, false,
+			len(serialized)); err != nil {
+
+			t.Errorf("MakeScriptNum(%#x): unexpected error with "+
+				"minimal encoding not required: %v",
+				serialized, err)
+			continue
+		}
+	}
+}
+
 // TestScriptNumInt32 ensures that the Int32 function on script number behaves
 // as expected.
 func TestScriptNumInt32(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		in   scriptNum
+		in   ScriptNum
 		want int32
 	}{
 		// Values inside the valid int32 range are just the values
@@ -22,8 +22,9 @@ type sigCacheEntry struct {
 	pubKey *btcec.PublicKey
 }
 
-// SigCache implements an ECDSA signature verification cache with a randomized
-// entry eviction policy. Only valid signatures will be added to the cache. The
+// SigCache implements an ECDSA signature verification cache with a pluggable
+// entry eviction policy, which is randomized by default. Only valid signatures
+// will be added to the cache. The
 // benefits of SigCache are two fold. Firstly, usage of SigCache mitigates a DoS
 // attack wherein an attack causes a victim's client to hang due to worst-case
 // behavior triggered while processing attacker crafted invalid transactions. A
@@ -36,6 +37,9 @@ type SigCache struct {
 	sync.RWMutex
 	validSigs  map[chainhash.Hash]sigCacheEntry
 	maxEntries uint
+	evictBatch uint
+	policy     EvictionPolicy
+	metrics    SigCacheMetrics
 }
 
 // NewSigCache creates and initializes a new instance of SigCache. Its sole
@@ -44,9 +48,60 @@ type SigCache struct {
 // to make room for new entries that would cause the number of entries in the
 // cache to exceed the max.
 func NewSigCache(maxEntries uint) *SigCache {
+	return NewSigCacheBatched(maxEntries, 1)
+}
+
+// NewSigCacheBatched creates and initializes a new instance of SigCache which
+// evicts up to 'evictBatch' random entries at once whenever adding a new entry
+// would cause the number of entries in the cache to exceed 'maxEntries'.  This
+// allows the following evictBatch-1 additions to proceed without evicting, at
+// the cost of the cache holding fewer entries until it fills back up.  An
+// evictBatch of zero is treated as one.
+func NewSigCacheBatched(maxEntries, evictBatch uint) *SigCache {
+	return NewSigCacheWithPolicy(maxEntries, evictBatch,
+		NewRandomEvictionPolicy())
+}
+
+// NewSigCacheWithPolicy creates and initializes a new instance of SigCache
+// which consults the passed eviction policy to choose the entries to evict.
+// Like NewSigCacheBatched, up to 'evictBatch' entries are evicted at once
+// whenever adding a new entry would cause the number of entries in the cache to
+// exceed 'maxEntries', and an evictBatch of zero is treated as one.  A nil
+// policy is treated as the one returned by NewRandomEvictionPolicy.
+func NewSigCacheWithPolicy(maxEntries, evictBatch uint, policy EvictionPolicy) *SigCache {
+	return NewSigCacheWithMetrics(maxEntries, evictBatch, policy, nil)
+}
+
+// NewSigCacheWithMetrics creates and initializes a new instance of SigCache
+// which behaves like one created by NewSigCacheWithPolicy and additionally
+// notifies the passed metrics of cache hits, misses, evictions and the size of
+// the cache.  A nil metrics disables notifications.
+func NewSigCacheWithMetrics(maxEntries, evictBatch uint, policy EvictionPolicy,
+	metrics SigCacheMetrics) *SigCache {
+
+	if evictBatch == 0 {
+		evictBatch = 1
+	}
+	if policy == nil {
+		policy = NewRandomEvictionPolicy()
+	}
+	if metrics == nil {
+		metrics = noopSigCacheMetrics{}
+	}
+	validSigs := make(map[chainhash.Hash]sigCacheEntry, maxEntries)
+
+	// The random policy draws its victims from the entries of the cache
+	// rather than tracking them separately.
+	if random, ok := policy.(*randomEvictionPolicy); ok {
+		random.validSigs = validSigs
+	}
+
 	return &SigCache{
-		validSigs:  make(map[chainhash.Hash]sigCacheEntry, maxEntries),
+		validSigs:  validSigs,
 		maxEntries: maxEntries,
+		evictBatch: evictBatch,
+		policy:     policy,
+		metrics:    metrics,
 	}
 }
 
@@ -58,42 +113,58 @@ func NewSigCache(maxEntries uint) *SigCache {
 func (s *SigCache) Exists(sigHash chainhash.Hash, sig *btcec.Signature, pubKey *btcec.PublicKey) bool {
 	s.RLock()
 	entry, ok := s.validSigs[sigHash]
+	found := ok && entry.pubKey.IsEqual(pubKey) && entry.sig.IsEqual(sig)
+	if found {
+		s.policy.OnAccess(sigHash)
+	}
 	s.RUnlock()
 
-	return ok && entry.pubKey.IsEqual(pubKey) && entry.sig.IsEqual(sig)
+	// Notify the metrics without holding the lock.
+	if found {
+		s.metrics.ObserveHit()
+	} else {
+		s.metrics.ObserveMiss()
+	}
+	return found
 }
 
 // Add adds an entry for a signature over 'sigHash' under public key 'pubKey'
-// to the signature cache. In the event that the SigCache is 'full', an
-// existing entry is randomly chosen to be evicted in order to make space for
-// the new entry.
+// to the signature cache. In the event that the SigCache is 'full', existing
+// entries are chosen by the eviction policy to be evicted in order to make
+// space for the new entry.  The number of entries evicted is the eviction
+// batch size the cache was created with.
 //
 // NOTE: This function is safe for concurrent access. Writers will block
 // simultaneous readers until function execution has concluded.
 func (s *SigCache) Add(sigHash chainhash.Hash, sig *btcec.Signature, pubKey *btcec.PublicKey) {
 	s.Lock()
-	defer s.Unlock()
-
 	if s.maxEntries <= 0 {
+		s.Unlock()
 		return
 	}
 
 	// If adding this new entry will put us over the max number of allowed
-	// entries, then evict an entry.
-	if uint(len(s.validSigs)+1) > s.maxEntries {
-		// Remove a random entry from the map. Relying on the random
-		// starting point of Go's map iteration. It's worth noting that
-		// the random iteration starting point is not 100% guaranteed
-		// by the spec, however most Go compilers support it.
-		// Ultimately, the iteration order isn't important here because
-		// in order to manipulate which items are evicted, an adversary
-		// would need to be able to execute preimage attacks on the
-		// hashing function in order to start eviction at a specific
-		// entry.
-		for sigEntry := range s.validSigs {
-			delete(s.validSigs, sigEntry)
-			break
+	// entries, then evict a batch of entries.  Replacing an existing entry
+	// does not change the number of entries.
+	var evicted uint
+	_, exists := s.validSigs[sigHash]
+	if !exists && uint(len(s.validSigs)+1) > s.maxEntries {
+		for ; evicted < s.evictBatch; evicted++ {
+			victim, ok := s.policy.SelectVictim()
+			if !ok {
+				break
+			}
+			delete(s.validSigs, victim)
 		}
 	}
 	s.validSigs[sigHash] = sigCacheEntry{sig, pubKey}
+	s.policy.OnAdd(sigHash)
+	size := len(s.validSigs)
+	s.Unlock()
+
+	// Notify the metrics without holding the lock.
+	for i := uint(0); i < evicted; i++ {
+		s.metrics.ObserveEviction()
+	}
+	s.metrics.SetSize(size)
 }
@@ -6,6 +6,7 @@ package txscript
 
 import (
 	"crypto/rand"
+	"fmt"
 	"testing"
 
 	"github.com/navcoin/navd/btcec"
@@ -34,6 +35,33 @@ func genRandomSig() (*chainhash.Hash, *btcec.Signature, *btcec.PublicKey, error)
 	return &msgHash, sig, privKey.PubKey(), nil
 }
 
+// verifyInvariants returns an error if the signature cache is in an
+// inconsistent state.  The cache must never hold more than its maximum number
+// of entries, must not hold an entry keyed by the zero hash, and every entry
+// must have both a signature and a public key.  It is only defined for tests so
+// the checks carry no cost in production.
+func (s *SigCache) verifyInvariants() error {
+	s.RLock()
+	defer s.RUnlock()
+
+	if uint(len(s.validSigs)) > s.maxEntries {
+		return fmt.Errorf("sigcache holds %d entries which exceeds the "+
+			"max of %d", len(s.validSigs), s.maxEntries)
+	}
+	var zeroHash chainhash.Hash
+	for sigHash, entry := range s.validSigs {
+		if sigHash == zeroHash {
+			return fmt.Errorf("sigcache holds an entry keyed by the " +
+				"zero hash")
+		}
+		if entry.sig == nil || entry.pubKey == nil {
+			return fmt.Errorf("sigcache entry %v is missing its "+
+				"signature or public key", sigHash)
+		}
+	}
+	return nil
+}
+
 // TestSigCacheAddExists tests the ability to add, and later check the
 // existence of a signature triplet in the signature cache.
 func TestSigCacheAddExists(t *testing.T) {
@@ -138,3 +166,129 @@ func TestSigCacheAddMaxEntriesZeroOrNegative(t *testing.T) {
 			"been added", len(sigCache.validSigs))
 	}
 }
+
+// TestSigCacheBatchedEviction tests that a sigcache created with an eviction
+// batch size evicts that many entries at once when full, stays within its
+// maximum number of entries, and does not evict again until it refills.
+func TestSigCacheBatchedEviction(t *testing.T) {
+	const (
+		sigCacheSize = uint(100)
+		evictBatch   = uint(10)
+	)
+	sigCache := NewSigCacheBatched(sigCacheSize, evictBatch)
+
+	// Fill the sigcache up with some random sig triplets.
+	for i := uint(0); i < sigCacheSize; i++ {
+		msg, sig, key, err := genRandomSig()
+		if err != nil {
+			t.Fatalf("unable to generate random signature test data")
+		}
+		sigCache.Add(*msg, sig, key)
+	}
+	if uint(len(sigCache.validSigs)) != sigCacheSize {
+		t.Fatalf("sigcache should now have %v entries, instead it has %v",
+			sigCacheSize, len(sigCache.validSigs))
+	}
+
+	// Add enough entries to trigger several evictions and ensure the
+	// occupancy follows the expected sawtooth pattern: each eviction drops
+	// evictBatch entries before the new entry is added, and the following
+	// evictBatch-1 additions grow the cache back to capacity.
+	for i := uint(0); i < evictBatch*3; i++ {
+		msg, sig, key, err := genRandomSig()
+		if err != nil {
+			t.Fatalf("unable to generate random signature test data")
+		}
+		sigCache.Add(*msg, sig, key)
+
+		want := sigCacheSize - evictBatch + 1 + i%evictBatch
+		if got := uint(len(sigCache.validSigs)); got != want {
+			t.Fatalf("add %d: sigcache should have %v entries, "+
+				"instead it has %v", i, want, got)
+		}
+		if !sigCache.Exists(*msg, sig, key) {
+			t.Fatalf("add %d: previously added item not found in "+
+				"signature cache", i)
+		}
+	}
+
+	// An eviction batch larger than the cache must simply empty it before
+	// adding the new entry.
+	sigCache = NewSigCacheBatched(5, 50)
+	for i := 0; i < 6; i++ {
+		msg, sig, key, err := genRandomSig()
+		if err != nil {
+			t.Fatalf("unable to generate random signature test data")
+		}
+		sigCache.Add(*msg, sig, key)
+	}
+	if len(sigCache.validSigs) != 1 {
+		t.Fatalf("sigcache should have 1 entry, instead it has %v",
+			len(sigCache.validSigs))
+	}
+
+	// An eviction batch of zero must behave as a batch size of one.
+	if NewSigCacheBatched(5, 0).evictBatch != 1 {
+		t.Fatalf("zero eviction batch size not treated as one")
+	}
+}
+
+// TestSigCacheInvariants ensures the signature cache remains consistent across
+// randomized sequences of additions, replacements and evictions for every
+// combination of eviction policy and batch size.
+func TestSigCacheInvariants(t *testing.T) {
+	// Signatures are expensive to generate, so a handful are reused under
+	// random sighashes since the cache does not verify them.
+	_, sig, key, err := genRandomSig()
+	if err != nil {
+		t.Fatalf("unable to generate random signature test data")
+	}
+
+	policies := []struct {
+		name      string
+		newPolicy func() EvictionPolicy
+	}{
+		{"random", NewRandomEvictionPolicy},
+		{"lru", NewLRUEvictionPolicy},
+	}
+	for _, policy := range policies {
+		for _, maxEntries := range []uint{0, 1, 7, 50} {
+			for _, evictBatch := range []uint{0, 1, 3, 100} {
+				sigCache := NewSigCacheWithPolicy(maxEntries,
+					evictBatch, policy.newPolicy())
+
+				var added []chainhash.Hash
+				for i := 0; i < 500; i++ {
+					var sigHash chainhash.Hash
+					if _, err := rand.Read(sigHash[:]); err != nil {
+						t.Fatalf("unable to generate random "+
+							"sighash: %v", err)
+					}
+
+					// Replace a previously added entry about a
+					// quarter of the time.
+					if len(added) > 0 && sigHash[0]&3 == 0 {
+						sigHash = added[int(sigHash[1])%len(added)]
+					} else {
+						added = append(added, sigHash)
+					}
+					sigCache.Add(sigHash, sig, key)
+
+					// Look up an entry so access based policies
+					// reorder their entries.
+					if sigHash[2]&1 == 0 {
+						lookup := added[int(sigHash[3])%len(added)]
+						sigCache.Exists(lookup, sig, key)
+					}
+
+					if err := sigCache.verifyInvariants(); err != nil {
+						t.Fatalf("policy %s, max entries %d, "+
+							"evict batch %d, add %d: %v",
+							policy.name, maxEntries,
+							evictBatch, i, err)
+					}
+				}
+			}
+		}
+	}
+}
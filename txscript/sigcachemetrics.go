@@ -0,0 +1,57 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+// SigCacheMetrics is notified of the activity of a SigCache so it can be
+// exported to a metrics system without polling the cache.
+//
+// The SigCache invokes the methods after releasing its lock so slow
+// implementations do not cause contention on the cache.  As a result, the
+// methods may be called concurrently and implementations must provide their
+// own synchronization.  It also means the sizes reported by concurrent
+// additions may be observed out of order, so SetSize only reflects the size of
+// the cache at some point shortly before it is called.
+type SigCacheMetrics interface {
+	// ObserveHit is invoked when a lookup finds a matching entry.
+	ObserveHit()
+
+	// ObserveMiss is invoked when a lookup does not find a matching entry.
+	ObserveMiss()
+
+	// ObserveEviction is invoked once for every entry evicted to make room
+	// for a new entry.
+	ObserveEviction()
+
+	// SetSize is invoked with the number of entries in the cache after an
+	// entry is added.
+	SetSize(size int)
+}
+
+// noopSigCacheMetrics is a SigCacheMetrics which ignores all activity.  It is
+// used by caches which are not created with metrics.
+type noopSigCacheMetrics struct{}
+
+// Ensure noopSigCacheMetrics implements the SigCacheMetrics interface.
+var _ SigCacheMetrics = noopSigCacheMetrics{}
+
+// ObserveHit does nothing.
+//
+// This is part of the SigCacheMetrics interface.
+func (noopSigCacheMetrics) ObserveHit() {}
+
+// ObserveMiss does nothing.
+//
+// This is part of the SigCacheMetrics interface.
+func (noopSigCacheMetrics) ObserveMiss() {}
+
+// ObserveEviction does nothing.
+//
+// This is part of the SigCacheMetrics interface.
+func (noopSigCacheMetrics) ObserveEviction() {}
+
+// SetSize does nothing.
+//
+// This is part of the SigCacheMetrics interface.
+func (noopSigCacheMetrics) SetSize(int) {}
@@ -0,0 +1,99 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fakeSigCacheMetrics is a SigCacheMetrics used for testing which records the
+// events it observes along with whether the cache was locked at the time.
+type fakeSigCacheMetrics struct {
+	cache  *SigCache
+	events []string
+	sizes  []int
+	locked bool
+}
+
+// record records the passed event and whether the cache lock is held.
+func (m *fakeSigCacheMetrics) record(event string) {
+	m.events = append(m.events, event)
+	if m.cache.TryLock() {
+		m.cache.Unlock()
+	} else {
+		m.locked = true
+	}
+}
+
+func (m *fakeSigCacheMetrics) ObserveHit()      { m.record("hit") }
+func (m *fakeSigCacheMetrics) ObserveMiss()     { m.record("miss") }
+func (m *fakeSigCacheMetrics) ObserveEviction() { m.record("eviction") }
+
+func (m *fakeSigCacheMetrics) SetSize(size int) {
+	m.record("size")
+	m.sizes = append(m.sizes, size)
+}
+
+// TestSigCacheMetrics ensures the metrics a SigCache is created with observe
+// the expected events without the cache lock held.
+func TestSigCacheMetrics(t *testing.T) {
+	t.Parallel()
+
+	metrics := &fakeSigCacheMetrics{}
+	sigCache := NewSigCacheWithMetrics(2, 1, &fifoEvictionPolicy{}, metrics)
+	metrics.cache = sigCache
+
+	msg1, sig1, key1, err := genRandomSig()
+	if err != nil {
+		t.Fatalf("unable to generate random signature test data")
+	}
+	msg2, sig2, key2, err := genRandomSig()
+	if err != nil {
+		t.Fatalf("unable to generate random signature test data")
+	}
+	msg3, sig3, key3, err := genRandomSig()
+	if err != nil {
+		t.Fatalf("unable to generate random signature test data")
+	}
+
+	// Fill the cache, replace an existing entry, look up an entry that is
+	// cached and one that is not, and finally add an entry which evicts
+	// the oldest one so the subsequent lookup of it misses.
+	sigCache.Add(*msg1, sig1, key1)
+	sigCache.Add(*msg2, sig2, key2)
+	sigCache.Add(*msg2, sig2, key2)
+	sigCache.Exists(*msg1, sig1, key1)
+	sigCache.Exists(*msg3, sig3, key3)
+	sigCache.Add(*msg3, sig3, key3)
+	sigCache.Exists(*msg1, sig1, key1)
+
+	wantEvents := []string{"size", "size", "size", "hit", "miss",
+		"eviction", "size", "miss"}
+	if !reflect.DeepEqual(metrics.events, wantEvents) {
+		t.Fatalf("unexpected events - got %v, want %v", metrics.events,
+			wantEvents)
+	}
+	wantSizes := []int{1, 2, 2, 2}
+	if !reflect.DeepEqual(metrics.sizes, wantSizes) {
+		t.Fatalf("unexpected sizes - got %v, want %v", metrics.sizes,
+			wantSizes)
+	}
+	if metrics.locked {
+		t.Fatal("metrics observed an event while the cache was locked")
+	}
+
+	// Ensure a cache created without metrics and one that is disabled do
+	// not notify anything.
+	NewSigCacheWithPolicy(2, 1, &fifoEvictionPolicy{}).Add(*msg1, sig1, key1)
+	metrics = &fakeSigCacheMetrics{}
+	disabled := NewSigCacheWithMetrics(0, 1, &fifoEvictionPolicy{}, metrics)
+	metrics.cache = disabled
+	disabled.Add(*msg1, sig1, key1)
+	if len(metrics.events) != 0 {
+		t.Fatalf("unexpected events for disabled cache: %v",
+			metrics.events)
+	}
+}
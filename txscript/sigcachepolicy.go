@@ -0,0 +1,152 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// EvictionPolicy decides which entries a SigCache evicts once it is full.  The
+// cache notifies the policy of every entry it adds and every cache hit, and
+// asks it for a victim each time an entry must be evicted.
+//
+// The SigCache invokes OnAdd and SelectVictim while holding its write lock, so
+// they are never called concurrently with any other method.  OnAccess is
+// invoked while holding the read lock, so it may be called concurrently with
+// other OnAccess calls and implementations which mutate state in it must
+// provide their own synchronization for that case.
+type EvictionPolicy interface {
+	// OnAdd is invoked after an entry for the passed signature hash is
+	// added to the cache.  Adding an entry for a signature hash which is
+	// already cached replaces the existing entry.
+	OnAdd(sigHash chainhash.Hash)
+
+	// OnAccess is invoked when a lookup finds a matching entry for the
+	// passed signature hash.
+	OnAccess(sigHash chainhash.Hash)
+
+	// SelectVictim returns the signature hash of the entry to evict and
+	// stops tracking it.  False is returned when no entries are tracked.
+	SelectVictim() (chainhash.Hash, bool)
+}
+
+// randomEvictionPolicy is an EvictionPolicy which evicts random entries.
+// Rather than tracking the signature hashes itself, it draws its victims from
+// the entries of the SigCache it is installed in, which are bound to it when
+// the cache is created.
+type randomEvictionPolicy struct {
+	validSigs map[chainhash.Hash]sigCacheEntry
+}
+
+// Ensure randomEvictionPolicy implements the EvictionPolicy interface.
+var _ EvictionPolicy = (*randomEvictionPolicy)(nil)
+
+// NewRandomEvictionPolicy returns an EvictionPolicy which evicts random
+// entries.  It is the policy used by NewSigCache and NewSigCacheBatched.  Since
+// it evicts from the entries of the cache it is installed in, each cache must
+// be given its own instance.
+func NewRandomEvictionPolicy() EvictionPolicy {
+	return &randomEvictionPolicy{}
+}
+
+// OnAdd does nothing since the entries are tracked by the cache itself.
+//
+// This is part of the EvictionPolicy interface.
+func (p *randomEvictionPolicy) OnAdd(sigHash chainhash.Hash) {}
+
+// OnAccess does nothing since access patterns do not influence random
+// eviction.
+//
+// This is part of the EvictionPolicy interface.
+func (p *randomEvictionPolicy) OnAccess(sigHash chainhash.Hash) {}
+
+// SelectVictim returns the signature hash of a random entry of the cache.  The
+// entry stops being tracked once the cache removes it.
+//
+// This is part of the EvictionPolicy interface.
+func (p *randomEvictionPolicy) SelectVictim() (chainhash.Hash, bool) {
+	// Relying on the random starting point of Go's map iteration.  It's
+	// worth noting that the random iteration starting point is not 100%
+	// guaranteed by the spec, however most Go compilers support it.
+	// Ultimately, the iteration order isn't important here because in
+	// order to manipulate which items are evicted, an adversary would need
+	// to be able to execute preimage attacks on the hashing function in
+	// order to start eviction at a specific entry.
+	for sigHash := range p.validSigs {
+		return sigHash, true
+	}
+	return chainhash.Hash{}, false
+}
+
+// lruEvictionPolicy is an EvictionPolicy which evicts the least recently used
+// entry, where both adding an entry and a cache hit count as a use.
+type lruEvictionPolicy struct {
+	mtx     sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[chainhash.Hash]*list.Element
+}
+
+// Ensure lruEvictionPolicy implements the EvictionPolicy interface.
+var _ EvictionPolicy = (*lruEvictionPolicy)(nil)
+
+// NewLRUEvictionPolicy returns an EvictionPolicy which evicts the least
+// recently used entry.
+func NewLRUEvictionPolicy() EvictionPolicy {
+	return &lruEvictionPolicy{
+		order:   list.New(),
+		entries: make(map[chainhash.Hash]*list.Element),
+	}
+}
+
+// touch marks the passed signature hash as the most recently used, adding it
+// when it is not already tracked and add is true.
+//
+// This function MUST be called with the policy lock held (for writes).
+func (p *lruEvictionPolicy) touch(sigHash chainhash.Hash, add bool) {
+	if elem, ok := p.entries[sigHash]; ok {
+		p.order.MoveToFront(elem)
+		return
+	}
+	if add {
+		p.entries[sigHash] = p.order.PushFront(sigHash)
+	}
+}
+
+// OnAdd marks the passed signature hash as the most recently used.
+//
+// This is part of the EvictionPolicy interface.
+func (p *lruEvictionPolicy) OnAdd(sigHash chainhash.Hash) {
+	p.mtx.Lock()
+	p.touch(sigHash, true)
+	p.mtx.Unlock()
+}
+
+// OnAccess marks the passed signature hash as the most recently used.
+//
+// This is part of the EvictionPolicy interface.
+func (p *lruEvictionPolicy) OnAccess(sigHash chainhash.Hash) {
+	p.mtx.Lock()
+	p.touch(sigHash, false)
+	p.mtx.Unlock()
+}
+
+// SelectVictim returns the least recently used signature hash.
+//
+// This is part of the EvictionPolicy interface.
+func (p *lruEvictionPolicy) SelectVictim() (chainhash.Hash, bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	elem := p.order.Back()
+	if elem == nil {
+		return chainhash.Hash{}, false
+	}
+	sigHash := p.order.Remove(elem).(chainhash.Hash)
+	delete(p.entries, sigHash)
+	return sigHash, true
+}
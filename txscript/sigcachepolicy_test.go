@@ -0,0 +1,168 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"testing"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// fifoEvictionPolicy is a deterministic EvictionPolicy used for testing which
+// evicts entries in the order they were first added and records the accesses
+// it is notified of.
+type fifoEvictionPolicy struct {
+	order    []chainhash.Hash
+	accessed []chainhash.Hash
+}
+
+func (p *fifoEvictionPolicy) OnAdd(sigHash chainhash.Hash) {
+	for _, h := range p.order {
+		if h == sigHash {
+			return
+		}
+	}
+	p.order = append(p.order, sigHash)
+}
+
+func (p *fifoEvictionPolicy) OnAccess(sigHash chainhash.Hash) {
+	p.accessed = append(p.accessed, sigHash)
+}
+
+func (p *fifoEvictionPolicy) SelectVictim() (chainhash.Hash, bool) {
+	if len(p.order) == 0 {
+		return chainhash.Hash{}, false
+	}
+	victim := p.order[0]
+	p.order = p.order[1:]
+	return victim, true
+}
+
+// testSigEntry houses a random signature triplet for use in the eviction
+// policy tests.
+type testSigEntry struct {
+	sigHash chainhash.Hash
+	sig     *btcec.Signature
+	pubKey  *btcec.PublicKey
+}
+
+// genTestSigEntries returns the requested number of random signature triplets.
+func genTestSigEntries(t *testing.T, n int) []testSigEntry {
+	entries := make([]testSigEntry, 0, n)
+	for i := 0; i < n; i++ {
+		msg, sig, key, err := genRandomSig()
+		if err != nil {
+			t.Fatalf("unable to generate random signature test data")
+		}
+		entries = append(entries, testSigEntry{*msg, sig, key})
+	}
+	return entries
+}
+
+// TestSigCacheCustomPolicy ensures the SigCache consults its eviction policy
+// on additions, hits, and evictions and evicts the victim the policy selects.
+func TestSigCacheCustomPolicy(t *testing.T) {
+	policy := &fifoEvictionPolicy{}
+	sigCache := NewSigCacheWithPolicy(3, 1, policy)
+
+	entries := genTestSigEntries(t, 5)
+	for _, e := range entries[:3] {
+		sigCache.Add(e.sigHash, e.sig, e.pubKey)
+	}
+
+	// Only hits should be reported to the policy.
+	if !sigCache.Exists(entries[1].sigHash, entries[1].sig, entries[1].pubKey) {
+		t.Fatalf("previously added item not found in signature cache")
+	}
+	if sigCache.Exists(entries[3].sigHash, entries[3].sig, entries[3].pubKey) {
+		t.Fatalf("item found in signature cache before being added")
+	}
+	if len(policy.accessed) != 1 || policy.accessed[0] != entries[1].sigHash {
+		t.Fatalf("unexpected accesses reported to policy: %v",
+			policy.accessed)
+	}
+
+	// Adding to the full cache must evict the first entry added.
+	sigCache.Add(entries[3].sigHash, entries[3].sig, entries[3].pubKey)
+	if sigCache.Exists(entries[0].sigHash, entries[0].sig, entries[0].pubKey) {
+		t.Fatalf("entry selected as victim was not evicted")
+	}
+	for _, e := range entries[1:4] {
+		if !sigCache.Exists(e.sigHash, e.sig, e.pubKey) {
+			t.Fatalf("entry not selected as victim was evicted")
+		}
+	}
+
+	// The next addition must evict the second entry added even though it
+	// was accessed, since the policy disregards accesses.
+	sigCache.Add(entries[4].sigHash, entries[4].sig, entries[4].pubKey)
+	if sigCache.Exists(entries[1].sigHash, entries[1].sig, entries[1].pubKey) {
+		t.Fatalf("entry selected as victim was not evicted")
+	}
+	if uint(len(sigCache.validSigs)) != 3 {
+		t.Fatalf("sigcache should have 3 entries, instead it has %v",
+			len(sigCache.validSigs))
+	}
+}
+
+// TestSigCacheLRUPolicy ensures the LRU eviction policy evicts the entry which
+// was least recently added or accessed.
+func TestSigCacheLRUPolicy(t *testing.T) {
+	sigCache := NewSigCacheWithPolicy(3, 1, NewLRUEvictionPolicy())
+
+	entries := genTestSigEntries(t, 5)
+	for _, e := range entries[:3] {
+		sigCache.Add(e.sigHash, e.sig, e.pubKey)
+	}
+
+	// Access the oldest entry so the second one becomes the least recently
+	// used, then add a new entry.
+	if !sigCache.Exists(entries[0].sigHash, entries[0].sig, entries[0].pubKey) {
+		t.Fatalf("previously added item not found in signature cache")
+	}
+	sigCache.Add(entries[3].sigHash, entries[3].sig, entries[3].pubKey)
+	if sigCache.Exists(entries[1].sigHash, entries[1].sig, entries[1].pubKey) {
+		t.Fatalf("least recently used entry was not evicted")
+	}
+
+	// Re-adding an existing entry counts as a use, so the third entry is
+	// now the least recently used.
+	sigCache.Add(entries[0].sigHash, entries[0].sig, entries[0].pubKey)
+	sigCache.Add(entries[4].sigHash, entries[4].sig, entries[4].pubKey)
+	if sigCache.Exists(entries[2].sigHash, entries[2].sig, entries[2].pubKey) {
+		t.Fatalf("least recently used entry was not evicted")
+	}
+	for _, i := range []int{0, 3, 4} {
+		e := entries[i]
+		if !sigCache.Exists(e.sigHash, e.sig, e.pubKey) {
+			t.Fatalf("recently used entry %d was evicted", i)
+		}
+	}
+}
+
+// TestLRUEvictionPolicyEmpty ensures the LRU eviction policy reports when there
+// are no entries to evict.
+func TestLRUEvictionPolicyEmpty(t *testing.T) {
+	policy := NewLRUEvictionPolicy()
+	if _, ok := policy.SelectVictim(); ok {
+		t.Fatalf("victim selected from empty policy")
+	}
+
+	var sigHash chainhash.Hash
+	policy.OnAccess(sigHash)
+	if _, ok := policy.SelectVictim(); ok {
+		t.Fatalf("victim selected for entry which was never added")
+	}
+
+	policy.OnAdd(sigHash)
+	victim, ok := policy.SelectVictim()
+	if !ok || victim != sigHash {
+		t.Fatalf("unexpected victim %v (ok %v)", victim, ok)
+	}
+	if _, ok := policy.SelectVictim(); ok {
+		t.Fatalf("victim selected twice")
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import "sync"
+
+// sigCacheRegistry houses the named signature caches shared by the subsystems
+// of a process, such as the caches of each chain in a process which runs more
+// than one.
+var sigCacheRegistry = struct {
+	sync.Mutex
+	caches map[string]*SigCache
+}{
+	caches: make(map[string]*SigCache),
+}
+
+// GetOrCreateSigCache returns the signature cache registered under the passed
+// name, creating and registering a new one with room for maxEntries entries
+// when none exists yet.  This allows subsystems to share a single cache per
+// name instead of each allocating their own.
+//
+// When a cache is already registered under the name, it is returned as is even
+// when it was created with a different maximum number of entries, in which
+// case a warning is logged.
+//
+// This function is safe for concurrent access.
+func GetOrCreateSigCache(name string, maxEntries uint) *SigCache {
+	sigCacheRegistry.Lock()
+	defer sigCacheRegistry.Unlock()
+
+	if sigCache, ok := sigCacheRegistry.caches[name]; ok {
+		if sigCache.maxEntries != maxEntries {
+			log.Warnf("Signature cache %q requested with %d max "+
+				"entries, using existing cache with %d max entries",
+				name, maxEntries, sigCache.maxEntries)
+		}
+		return sigCache
+	}
+
+	sigCache := NewSigCache(maxEntries)
+	sigCacheRegistry.caches[name] = sigCache
+	return sigCache
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestGetOrCreateSigCache ensures the registry returns the same signature cache
+// for the same name, including when a different size is requested, and
+// distinct caches for distinct names.
+func TestGetOrCreateSigCache(t *testing.T) {
+	mainCache := GetOrCreateSigCache("registrytest-main", 100)
+	if mainCache == nil {
+		t.Fatalf("nil signature cache returned")
+	}
+	if mainCache.maxEntries != 100 {
+		t.Fatalf("unexpected max entries - got %d, want %d",
+			mainCache.maxEntries, 100)
+	}
+
+	if got := GetOrCreateSigCache("registrytest-main", 100); got != mainCache {
+		t.Fatalf("different signature cache returned for same name")
+	}
+
+	// Requesting an existing name with a different size must return the
+	// existing cache unchanged.
+	if got := GetOrCreateSigCache("registrytest-main", 50); got != mainCache {
+		t.Fatalf("different signature cache returned for same name " +
+			"with different size")
+	}
+	if mainCache.maxEntries != 100 {
+		t.Fatalf("existing cache resized - got %d max entries, want %d",
+			mainCache.maxEntries, 100)
+	}
+
+	testCache := GetOrCreateSigCache("registrytest-test", 100)
+	if testCache == mainCache {
+		t.Fatalf("same signature cache returned for different names")
+	}
+}
+
+// TestGetOrCreateSigCacheConcurrent ensures concurrent requests for the same
+// name all receive the same signature cache.
+func TestGetOrCreateSigCacheConcurrent(t *testing.T) {
+	const numRequests = 50
+
+	var wg sync.WaitGroup
+	caches := make([]*SigCache, numRequests)
+	for i := 0; i < numRequests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			caches[i] = GetOrCreateSigCache("registrytest-concurrent",
+				100)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, sigCache := range caches {
+		if sigCache != caches[0] {
+			t.Fatalf("request %d received a different signature "+
+				"cache", i)
+		}
+	}
+}
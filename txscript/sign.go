@@ -14,6 +14,45 @@ import (
 	"github.com/navcoin/navutil"
 )
 
+const (
+	// maxDERSignatureLen is the maximum length of a DER encoded signature,
+	// which is 6 bytes of sequence and integer headers followed by R and
+	// S values of up to 33 bytes each, including the leading zero byte
+	// required when their high bit is set.
+	maxDERSignatureLen = 72
+
+	// lowSDERSignatureLen is the maximum length of a DER encoded signature
+	// with a low S value as required by BIP0062, which never needs the
+	// leading zero byte for S.
+	lowSDERSignatureLen = maxDERSignatureLen - 1
+
+	// lowRDERSignatureLen is the maximum length of a DER encoded signature
+	// with both a low S value and an R value with its high bit clear, such
+	// as those produced by btcec.SignLowR.
+	lowRDERSignatureLen = lowSDERSignatureLen - 1
+)
+
+// MaxDERSignatureSize returns the worst case size of a DER encoded signature
+// with the signature hash type appended, as found in signature scripts and
+// witnesses.  It is suitable for estimating the size of inputs spent with
+// signatures from any signer.
+func MaxDERSignatureSize() int {
+	return maxDERSignatureLen + 1
+}
+
+// ExpectedDERSignatureSize returns the maximum size of a DER encoded signature
+// with the signature hash type appended which is produced by the signing
+// functions of this package, which always produce low S values.  The lowR flag
+// indicates whether the signer also grinds for a low R value, which saves
+// another byte.  It is suitable for estimating the size of inputs before they
+// are signed.
+func ExpectedDERSignatureSize(lowR bool) int {
+	if lowR {
+		return lowRDERSignatureLen + 1
+	}
+	return lowSDERSignatureLen + 1
+}
+
 // RawTxInWitnessSignature returns the serialized ECDA signature for the input
 // idx of the given transaction, with the hashType appended to it. This
 // function is identical to RawTxInSignature, however the signature generated
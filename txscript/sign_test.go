@@ -1714,3 +1714,65 @@ nexttest:
 		}
 	}
 }
+
+// TestDERSignatureSize ensures the predicted signature sizes bound the sizes
+// of real signatures, with the signature hash type appended, over many inputs
+// and that the bounds are reached.
+func TestDERSignatureSize(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), []byte{
+		0x2b, 0x8c, 0x52, 0xb7, 0x7b, 0x32, 0x7c, 0x75,
+		0x5b, 0x9b, 0x37, 0x55, 0x00, 0xd3, 0xf4, 0xb2,
+		0xda, 0x9b, 0x0a, 0x1f, 0xf6, 0x5f, 0x68, 0x91,
+		0xd3, 0x11, 0xfe, 0x94, 0x29, 0x5b, 0xc2, 0x6a,
+	})
+	pkScript := []byte{OP_TRUE}
+	pops, err := parseScript(pkScript)
+	if err != nil {
+		t.Fatalf("unable to parse script: %v", err)
+	}
+
+	var maxSize, maxLowRSize int
+	for i := 0; i < 128; i++ {
+		tx := wire.NewMsgTx(1)
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{Index: uint32(i)},
+			Sequence:         wire.MaxTxInSequenceNum,
+		})
+		tx.AddTxOut(wire.NewTxOut(1000, pkScript))
+
+		sig, err := RawTxInSignature(tx, 0, pkScript, SigHashAll,
+			privKey)
+		if err != nil {
+			t.Fatalf("RawTxInSignature #%d: unexpected error: %v", i,
+				err)
+		}
+		if len(sig) > maxSize {
+			maxSize = len(sig)
+		}
+
+		hash := calcSignatureHash(pops, SigHashAll, tx, 0)
+		lowRSig, err := btcec.SignLowR(privKey, hash)
+		if err != nil {
+			t.Fatalf("SignLowR #%d: unexpected error: %v", i, err)
+		}
+		if size := len(lowRSig.Serialize()) + 1; size > maxLowRSize {
+			maxLowRSize = size
+		}
+	}
+
+	if maxSize != ExpectedDERSignatureSize(false) {
+		t.Errorf("largest signature is %d bytes, want %d", maxSize,
+			ExpectedDERSignatureSize(false))
+	}
+	if maxLowRSize != ExpectedDERSignatureSize(true) {
+		t.Errorf("largest low R signature is %d bytes, want %d",
+			maxLowRSize, ExpectedDERSignatureSize(true))
+	}
+	if MaxDERSignatureSize() <= ExpectedDERSignatureSize(false) {
+		t.Errorf("worst case signature size %d does not exceed the "+
+			"expected size %d", MaxDERSignatureSize(),
+			ExpectedDERSignatureSize(false))
+	}
+}
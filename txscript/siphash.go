@@ -0,0 +1,68 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// sipHash24 returns the SipHash-2-4 of the passed data under the 128-bit key
+// formed by k0 and k1, where each is the little-endian interpretation of the
+// respective half of the key.  SipHash is a keyed pseudorandom function, so its
+// output can't be predicted without the key, which makes it suitable for
+// indexing hash tables that hold attacker-influenced keys.
+func sipHash24(k0, k1 uint64, data []byte) uint64 {
+	v0 := k0 ^ 0x736f6d6570736575
+	v1 := k1 ^ 0x646f72616e646f6d
+	v2 := k0 ^ 0x6c7967656e657261
+	v3 := k1 ^ 0x7465646279746573
+
+	sipRound := func() {
+		v0 += v1
+		v1 = bits.RotateLeft64(v1, 13)
+		v1 ^= v0
+		v0 = bits.RotateLeft64(v0, 32)
+		v2 += v3
+		v3 = bits.RotateLeft64(v3, 16)
+		v3 ^= v2
+		v0 += v3
+		v3 = bits.RotateLeft64(v3, 21)
+		v3 ^= v0
+		v2 += v1
+		v1 = bits.RotateLeft64(v1, 17)
+		v1 ^= v2
+		v2 = bits.RotateLeft64(v2, 32)
+	}
+
+	// Compress each full 8-byte word of the data.
+	n := len(data)
+	for len(data) >= 8 {
+		m := binary.LittleEndian.Uint64(data)
+		v3 ^= m
+		sipRound()
+		sipRound()
+		v0 ^= m
+		data = data[8:]
+	}
+
+	// The final word holds the remaining bytes along with the low byte of
+	// the data length in its most significant byte.
+	m := uint64(n) << 56
+	for i := range data {
+		m |= uint64(data[i]) << (8 * uint(i))
+	}
+	v3 ^= m
+	sipRound()
+	sipRound()
+	v0 ^= m
+
+	v2 ^= 0xff
+	sipRound()
+	sipRound()
+	sipRound()
+	sipRound()
+	return v0 ^ v1 ^ v2 ^ v3
+}
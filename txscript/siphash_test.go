@@ -0,0 +1,37 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import "testing"
+
+// TestSipHash24 ensures sipHash24 produces the expected results for the test
+// vectors from the reference implementation, which use the key 00 01 .. 0f and
+// the messages 00 01 .. of increasing lengths.
+func TestSipHash24(t *testing.T) {
+	t.Parallel()
+
+	const k0, k1 = 0x0706050403020100, 0x0f0e0d0c0b0a0908
+	tests := []struct {
+		msgLen int
+		want   uint64
+	}{
+		{0, 0x726fdb47dd0e0e31},
+		{1, 0x74f839c593dc67fd},
+		{7, 0xab0200f58b01d137},
+		{8, 0x93f5f5799a932462},
+		{15, 0xa129ca6149be45e5},
+	}
+
+	for _, test := range tests {
+		msg := make([]byte, test.msgLen)
+		for i := range msg {
+			msg[i] = byte(i)
+		}
+		if got := sipHash24(k0, k1, msg); got != test.want {
+			t.Errorf("sipHash24 (len %d): got %016x, want %016x",
+				test.msgLen, got, test.want)
+		}
+	}
+}
@@ -322,7 +322,7 @@ func TestStack(t *testing.T) {
 			"PushInt 0",
 			nil,
 			func(s *stack) error {
-				s.PushInt(scriptNum(0))
+				s.PushInt(ScriptNum(0))
 				return nil
 			},
 			nil,
@@ -332,7 +332,7 @@ func TestStack(t *testing.T) {
 			"PushInt 1",
 			nil,
 			func(s *stack) error {
-				s.PushInt(scriptNum(1))
+				s.PushInt(ScriptNum(1))
 				return nil
 			},
 			nil,
@@ -342,7 +342,7 @@ func TestStack(t *testing.T) {
 			"PushInt -1",
 			nil,
 			func(s *stack) error {
-				s.PushInt(scriptNum(-1))
+				s.PushInt(ScriptNum(-1))
 				return nil
 			},
 			nil,
@@ -352,7 +352,7 @@ func TestStack(t *testing.T) {
 			"PushInt two bytes",
 			nil,
 			func(s *stack) error {
-				s.PushInt(scriptNum(256))
+				s.PushInt(ScriptNum(256))
 				return nil
 			},
 			nil,
@@ -364,7 +364,7 @@ func TestStack(t *testing.T) {
 			nil,
 			func(s *stack) error {
 				// this will have the highbit set
-				s.PushInt(scriptNum(128))
+				s.PushInt(ScriptNum(128))
 				return nil
 			},
 			nil,
@@ -486,7 +486,7 @@ func TestStack(t *testing.T) {
 			"PushInt PopBool",
 			nil,
 			func(s *stack) error {
-				s.PushInt(scriptNum(1))
+				s.PushInt(ScriptNum(1))
 				val, err := s.PopBool()
 				if err != nil {
 					return err
@@ -504,7 +504,7 @@ func TestStack(t *testing.T) {
 			"PushInt PopBool 2",
 			nil,
 			func(s *stack) error {
-				s.PushInt(scriptNum(0))
+				s.PushInt(ScriptNum(0))
 				val, err := s.PopBool()
 				if err != nil {
 					return err
@@ -874,7 +874,7 @@ func TestStack(t *testing.T) {
 			"pop int",
 			nil,
 			func(s *stack) error {
-				s.PushInt(scriptNum(1))
+				s.PushInt(ScriptNum(1))
 				// Peek int is otherwise pretty well tested,
 				// just check it works.
 				val, err := s.PopInt()
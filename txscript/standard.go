@@ -7,6 +7,7 @@ package txscript
 import (
 	"fmt"
 
+	"github.com/navcoin/navd/btcec"
 	"github.com/navcoin/navd/chaincfg"
 	"github.com/navcoin/navd/wire"
 	"github.com/navcoin/navutil"
@@ -498,6 +499,46 @@ func MultiSigScript(pubkeys []*navutil.AddressPubKey, nrequired int) ([]byte, er
 	return builder.Script()
 }
 
+// MultiSigScriptFromPubKeys returns a valid script for a multisignature
+// redemption where nrequired of the passed public keys are required to have
+// signed the transaction for success.  It is the same as MultiSigScript except
+// it works with public keys directly instead of addresses, which allows
+// multisignature scripts to be built without a wallet.  The public keys are
+// serialized in the compressed format.
+//
+// An Error with the error code ErrInvalidPubKeyCount will be returned if more
+// than MaxPubKeysPerMultiSig keys are provided, ErrInvalidSignatureCount if
+// nrequired is less than one, and ErrTooManyRequiredSigs if nrequired is larger
+// than the number of keys provided.
+func MultiSigScriptFromPubKeys(pubKeys []*btcec.PublicKey, nrequired int) ([]byte, error) {
+	if len(pubKeys) > MaxPubKeysPerMultiSig {
+		str := fmt.Sprintf("unable to generate multisig script with "+
+			"%d public keys when the max allowed is %d",
+			len(pubKeys), MaxPubKeysPerMultiSig)
+		return nil, scriptError(ErrInvalidPubKeyCount, str)
+	}
+	if nrequired < 1 {
+		str := fmt.Sprintf("unable to generate multisig script with "+
+			"%d required signatures", nrequired)
+		return nil, scriptError(ErrInvalidSignatureCount, str)
+	}
+	if len(pubKeys) < nrequired {
+		str := fmt.Sprintf("unable to generate multisig script with "+
+			"%d required signatures when there are only %d public "+
+			"keys available", nrequired, len(pubKeys))
+		return nil, scriptError(ErrTooManyRequiredSigs, str)
+	}
+
+	builder := NewScriptBuilder().AddInt64(int64(nrequired))
+	for _, key := range pubKeys {
+		builder.AddData(key.SerializeCompressed())
+	}
+	builder.AddInt64(int64(len(pubKeys)))
+	builder.AddOp(OP_CHECKMULTISIG)
+
+	return builder.Script()
+}
+
 // PushedData returns an array of byte slices containing any pushed data found
 // in the passed script.  This includes OP_0, but not OP_1 - OP_16.
 func PushedData(script []byte) ([][]byte, error) {
@@ -682,7 +723,8 @@ func ExtractAtomicSwapDataPushes(version uint16, pkScript []byte) (*AtomicSwapDa
 	copy(pushes.RecipientHash160[:], pops[9].data)
 	copy(pushes.RefundHash160[:], pops[16].data)
 	if pops[2].data != nil {
-		locktime, err := makeScriptNum(pops[2].data, true, 5)
+		locktime, err := MakeScriptNum(pops[2].data, true,
+			LockTimeScriptNumLen)
 		if err != nil {
 			return nil, nil
 		}
@@ -693,7 +735,8 @@ func ExtractAtomicSwapDataPushes(version uint16, pkScript []byte) (*AtomicSwapDa
 		return nil, nil
 	}
 	if pops[11].data != nil {
-		locktime, err := makeScriptNum(pops[11].data, true, 5)
+		locktime, err := MakeScriptNum(pops[11].data, true,
+			LockTimeScriptNumLen)
 		if err != nil {
 			return nil, nil
 		}
@@ -705,3 +748,74 @@ func ExtractAtomicSwapDataPushes(version uint16, pkScript []byte) (*AtomicSwapDa
 	}
 	return pushes, nil
 }
+
+// IsMalleable returns whether or not the id of the passed transaction can be
+// changed by a third party without invalidating it.  This is the case when any
+// of its inputs is spent without witness data, since the signature script of
+// such an input is covered by the transaction id but not by its signatures.
+//
+// Transactions that only spend witness inputs, including those nested in
+// pay-to-script-hash, are not malleable because their signature scripts are
+// either empty or restricted to a single push of the witness program.
+func IsMalleable(tx *wire.MsgTx) bool {
+	for _, txIn := range tx.TxIn {
+		if len(txIn.Witness) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractSignatures returns the signatures and public keys pushed by the passed
+// signature script and witness of a signed input.  It is intended to aid in
+// debugging and works with any combination of the two, so either may be empty.
+//
+// Each pushed item is parsed as a DER signature with its trailing sighash type
+// byte removed, or otherwise as a public key.  Items which are neither, such as
+// the leading OP_0 of multisig signature scripts, are ignored.  Redeem and
+// witness scripts which are multisig scripts have their public keys extracted
+// as well.
+func ExtractSignatures(scriptSig []byte, witness wire.TxWitness) ([]*btcec.Signature, []*btcec.PublicKey, error) {
+	pushes, err := PushedData(scriptSig)
+	if err != nil {
+		return nil, nil, err
+	}
+	pushes = append(pushes, witness...)
+
+	var sigs []*btcec.Signature
+	var pubKeys []*btcec.PublicKey
+	for _, data := range pushes {
+		// Signatures always start with the DER sequence identifier and
+		// are followed by the sighash type, which is not part of the
+		// DER encoding.
+		if len(data) > 1 && data[0] == 0x30 {
+			sig, err := btcec.ParseDERSignature(data[:len(data)-1],
+				btcec.S256())
+			if err == nil {
+				sigs = append(sigs, sig)
+				continue
+			}
+		}
+
+		if pubKey, err := btcec.ParsePubKey(data, btcec.S256()); err == nil {
+			pubKeys = append(pubKeys, pubKey)
+			continue
+		}
+
+		// Extract the public keys from multisig redeem and witness
+		// scripts.
+		pops, err := parseScript(data)
+		if err != nil || !isMultiSig(pops) {
+			continue
+		}
+		for _, pop := range pops[1 : len(pops)-2] {
+			pubKey, err := btcec.ParsePubKey(pop.data, btcec.S256())
+			if err != nil {
+				continue
+			}
+			pubKeys = append(pubKeys, pubKey)
+		}
+	}
+
+	return sigs, pubKeys, nil
+}
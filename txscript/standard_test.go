@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/navcoin/navd/btcec"
 	"github.com/navcoin/navd/chaincfg"
 	"github.com/navcoin/navd/wire"
 	"github.com/navcoin/navutil"
@@ -817,6 +818,103 @@ func TestMultiSigScript(t *testing.T) {
 	}
 }
 
+// TestMultiSigScriptFromPubKeys ensures the MultiSigScriptFromPubKeys function
+// builds multisig scripts which classify as such with the expected threshold
+// and rejects invalid key and signature counts.
+func TestMultiSigScriptFromPubKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]*btcec.PublicKey, MaxPubKeysPerMultiSig+1)
+	for i := range keys {
+		privKey, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		keys[i] = privKey.PubKey()
+	}
+
+	// Build a 2-of-3 and ensure it classifies as a multisig script with
+	// the keys serialized in the compressed format.
+	script, err := MultiSigScriptFromPubKeys(keys[:3], 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, err := NewScriptBuilder().AddOp(OP_2).
+		AddData(keys[0].SerializeCompressed()).
+		AddData(keys[1].SerializeCompressed()).
+		AddData(keys[2].SerializeCompressed()).
+		AddOp(OP_3).AddOp(OP_CHECKMULTISIG).Script()
+	if err != nil {
+		t.Fatalf("failed to build expected script: %v", err)
+	}
+	if !bytes.Equal(script, expected) {
+		t.Fatalf("unexpected script - got: %x, want: %x", script,
+			expected)
+	}
+	if class := GetScriptClass(script); class != MultiSigTy {
+		t.Fatalf("unexpected script class - got: %v, want: %v", class,
+			MultiSigTy)
+	}
+	numPubKeys, numSigs, err := CalcMultiSigStats(script)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if numPubKeys != 3 || numSigs != 2 {
+		t.Fatalf("unexpected multisig stats - got: %d-of-%d, want: "+
+			"2-of-3", numSigs, numPubKeys)
+	}
+
+	// Ensure the maximum number of keys uses the largest small integer
+	// opcode which is not a data push.
+	script, err = MultiSigScriptFromPubKeys(keys[:16], 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if script[0] != OP_16 || script[len(script)-2] != OP_16 {
+		t.Fatalf("unexpected 16-of-16 threshold opcodes %x and %x",
+			script[0], script[len(script)-2])
+	}
+
+	tests := []struct {
+		name      string
+		keys      []*btcec.PublicKey
+		nrequired int
+		err       error
+	}{
+		{
+			name:      "more required than keys",
+			keys:      keys[:3],
+			nrequired: 4,
+			err:       scriptError(ErrTooManyRequiredSigs, ""),
+		},
+		{
+			name:      "no required signatures",
+			keys:      keys[:3],
+			nrequired: 0,
+			err:       scriptError(ErrInvalidSignatureCount, ""),
+		},
+		{
+			name:      "too many keys",
+			keys:      keys,
+			nrequired: 1,
+			err:       scriptError(ErrInvalidPubKeyCount, ""),
+		},
+		{
+			name:      "max keys",
+			keys:      keys[:MaxPubKeysPerMultiSig],
+			nrequired: MaxPubKeysPerMultiSig,
+			err:       nil,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := MultiSigScriptFromPubKeys(test.keys, test.nrequired)
+		if e := tstCheckScriptError(err, test.err); e != nil {
+			t.Errorf("%s: %v", test.name, e)
+		}
+	}
+}
+
 // TestCalcMultiSigStats ensures the CalcMutliSigStats function returns the
 // expected errors.
 func TestCalcMultiSigStats(t *testing.T) {
@@ -1213,3 +1311,194 @@ func TestNullDataScript(t *testing.T) {
 		}
 	}
 }
+
+// TestIsMalleable ensures transactions are classified as malleable when any of
+// their inputs are spent without witness data.
+func TestIsMalleable(t *testing.T) {
+	t.Parallel()
+
+	sigScript := mustParseShortForm("DATA_2 0x3044 DATA_1 0x02")
+	nestedSigScript := mustParseShortForm("DATA_22 0x0014" +
+		"0000000000000000000000000000000000000000")
+	witness := wire.TxWitness{{0x30, 0x44}, {0x02}}
+
+	legacyIn := &wire.TxIn{SignatureScript: sigScript}
+	witnessIn := &wire.TxIn{Witness: witness}
+	nestedIn := &wire.TxIn{
+		SignatureScript: nestedSigScript,
+		Witness:         witness,
+	}
+
+	tests := []struct {
+		name      string
+		txIns     []*wire.TxIn
+		malleable bool
+	}{
+		{
+			name:      "no inputs",
+			txIns:     nil,
+			malleable: false,
+		},
+		{
+			name:      "legacy input",
+			txIns:     []*wire.TxIn{legacyIn},
+			malleable: true,
+		},
+		{
+			name:      "native witness input",
+			txIns:     []*wire.TxIn{witnessIn},
+			malleable: false,
+		},
+		{
+			name:      "nested witness input",
+			txIns:     []*wire.TxIn{nestedIn},
+			malleable: false,
+		},
+		{
+			name:      "witness inputs only",
+			txIns:     []*wire.TxIn{witnessIn, nestedIn},
+			malleable: false,
+		},
+		{
+			name:      "mixed witness and legacy inputs",
+			txIns:     []*wire.TxIn{witnessIn, legacyIn},
+			malleable: true,
+		},
+		{
+			name:      "mixed legacy and witness inputs",
+			txIns:     []*wire.TxIn{legacyIn, nestedIn},
+			malleable: true,
+		},
+	}
+
+	for _, test := range tests {
+		tx := &wire.MsgTx{Version: 2, TxIn: test.txIns}
+		got := IsMalleable(tx)
+		if got != test.malleable {
+			t.Errorf("%s: unexpected result -- got %v, want %v",
+				test.name, got, test.malleable)
+			continue
+		}
+	}
+}
+
+// TestExtractSignatures ensures the signatures and public keys pushed by signed
+// signature scripts and witnesses are extracted.
+func TestExtractSignatures(t *testing.T) {
+	t.Parallel()
+
+	// Generate keys and signatures serialized with a trailing sighash type
+	// the same as they appear in signed inputs.
+	var hash [32]byte
+	keys := make([]*btcec.PrivateKey, 3)
+	sigs := make([]*btcec.Signature, 3)
+	serializedSigs := make([][]byte, 3)
+	for i := range keys {
+		key, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		sig, err := key.Sign(hash[:])
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		keys[i] = key
+		sigs[i] = sig
+		serializedSigs[i] = append(sig.Serialize(), byte(SigHashAll))
+	}
+	pubKeyBytes := func(i int) []byte {
+		return keys[i].PubKey().SerializeCompressed()
+	}
+
+	p2pkhScriptSig, err := NewScriptBuilder().AddData(serializedSigs[0]).
+		AddData(pubKeyBytes(0)).Script()
+	if err != nil {
+		t.Fatalf("failed to build p2pkh script: %v", err)
+	}
+	redeemScript, err := NewScriptBuilder().AddOp(OP_2).
+		AddData(pubKeyBytes(0)).AddData(pubKeyBytes(1)).
+		AddData(pubKeyBytes(2)).AddOp(OP_3).AddOp(OP_CHECKMULTISIG).
+		Script()
+	if err != nil {
+		t.Fatalf("failed to build multisig script: %v", err)
+	}
+	multiSigScriptSig, err := NewScriptBuilder().AddOp(OP_0).
+		AddData(serializedSigs[0]).AddData(serializedSigs[2]).
+		AddData(redeemScript).Script()
+	if err != nil {
+		t.Fatalf("failed to build multisig signature script: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		scriptSig []byte
+		witness   wire.TxWitness
+		sigs      []*btcec.Signature
+		pubKeys   []*btcec.PublicKey
+	}{
+		{
+			name:      "p2pkh",
+			scriptSig: p2pkhScriptSig,
+			sigs:      []*btcec.Signature{sigs[0]},
+			pubKeys:   []*btcec.PublicKey{keys[0].PubKey()},
+		},
+		{
+			name:      "p2sh 2-of-3 multisig",
+			scriptSig: multiSigScriptSig,
+			sigs:      []*btcec.Signature{sigs[0], sigs[2]},
+			pubKeys: []*btcec.PublicKey{keys[0].PubKey(),
+				keys[1].PubKey(), keys[2].PubKey()},
+		},
+		{
+			name:    "p2wpkh",
+			witness: wire.TxWitness{serializedSigs[1], pubKeyBytes(1)},
+			sigs:    []*btcec.Signature{sigs[1]},
+			pubKeys: []*btcec.PublicKey{keys[1].PubKey()},
+		},
+		{
+			name: "p2wsh 2-of-3 multisig",
+			witness: wire.TxWitness{nil, serializedSigs[1],
+				serializedSigs[2], redeemScript},
+			sigs: []*btcec.Signature{sigs[1], sigs[2]},
+			pubKeys: []*btcec.PublicKey{keys[0].PubKey(),
+				keys[1].PubKey(), keys[2].PubKey()},
+		},
+	}
+
+	for _, test := range tests {
+		gotSigs, gotPubKeys, err := ExtractSignatures(test.scriptSig,
+			test.witness)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if len(gotSigs) != len(test.sigs) {
+			t.Errorf("%s: unexpected number of signatures - got %d, "+
+				"want %d", test.name, len(gotSigs), len(test.sigs))
+			continue
+		}
+		for i, sig := range gotSigs {
+			if !sig.IsEqual(test.sigs[i]) {
+				t.Errorf("%s: unexpected signature #%d", test.name, i)
+			}
+		}
+		if len(gotPubKeys) != len(test.pubKeys) {
+			t.Errorf("%s: unexpected number of public keys - got "+
+				"%d, want %d", test.name, len(gotPubKeys),
+				len(test.pubKeys))
+			continue
+		}
+		for i, pubKey := range gotPubKeys {
+			if !pubKey.IsEqual(test.pubKeys[i]) {
+				t.Errorf("%s: unexpected public key #%d",
+					test.name, i)
+			}
+		}
+	}
+
+	// Ensure signature scripts which do not parse are rejected.
+	_, _, err = ExtractSignatures(mustParseShortForm("DATA_2 0x01"), nil)
+	if err == nil {
+		t.Errorf("unexpected success extracting from malformed script")
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// References:
+//   [BIP341]: Taproot: SegWit version 1 spending rules
+//   https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki
+
+const (
+	// BaseLeafVersion is the leaf version of tapscript leaves as defined by
+	// BIP342.
+	BaseLeafVersion = 0xc0
+
+	// TaprootLeafMask is the mask applied to the first byte of a control
+	// block to extract the leaf version.  The remaining bit holds the
+	// parity of the y coordinate of the output key.
+	TaprootLeafMask = 0xfe
+
+	// ControlBlockBaseSize is the size of a control block with an empty
+	// merkle path, which consists of the leaf version and parity byte
+	// followed by the x-only internal key.
+	ControlBlockBaseSize = 33
+
+	// ControlBlockNodeSize is the size of each hash in the merkle path of
+	// a control block.
+	ControlBlockNodeSize = 32
+
+	// ControlBlockMaxNodeCount is the maximum number of hashes in the
+	// merkle path of a control block, which bounds the depth of a taproot
+	// script tree.
+	ControlBlockMaxNodeCount = 128
+
+	// ControlBlockMaxSize is the maximum size of a control block.
+	ControlBlockMaxSize = ControlBlockBaseSize +
+		ControlBlockNodeSize*ControlBlockMaxNodeCount
+
+	// TaprootAnnexTag is the first byte of the annex, which is an optional
+	// final witness element reserved for future extensions.
+	TaprootAnnexTag = 0x50
+)
+
+// Tags used to domain separate the hashes that make up a taproot script tree
+// and output key.
+const (
+	tagTapLeaf    = "TapLeaf"
+	tagTapBranch  = "TapBranch"
+	tagTapTweak   = "TapTweak"
+	tagTapSighash = "TapSighash"
+)
+
+// TapNode is a node of a taproot script tree.  It is implemented by TapLeaf
+// and TapBranch, which together allow building trees of arbitrary shape.  The
+// merkle root of a tree is the TapHash of its root node.
+type TapNode interface {
+	// TapHash returns the tagged hash of the node.
+	TapHash() []byte
+}
+
+// TapLeaf is a leaf of a taproot script tree committing to a script along with
+// the leaf version it is to be executed under.
+type TapLeaf struct {
+	LeafVersion byte
+	Script      []byte
+}
+
+// NewBaseTapLeaf returns a leaf for the passed script with the tapscript leaf
+// version.
+func NewBaseTapLeaf(script []byte) TapLeaf {
+	return TapLeaf{LeafVersion: BaseLeafVersion, Script: script}
+}
+
+// TapHash returns the BIP341 leaf hash, which is the TapLeaf tagged hash of the
+// leaf version followed by the script serialized with its compact size length
+// prefix.
+//
+// This is part of the TapNode interface.
+func (l TapLeaf) TapHash() []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(l.LeafVersion)
+
+	// Writing to a bytes.Buffer never fails.
+	_ = wire.WriteVarBytes(&buf, 0, l.Script)
+	return btcec.TaggedHash(tagTapLeaf, buf.Bytes())
+}
+
+// TapBranch is an inner node of a taproot script tree.
+type TapBranch struct {
+	Left  TapNode
+	Right TapNode
+}
+
+// NewTapBranch returns a branch with the two passed children.
+func NewTapBranch(left, right TapNode) TapBranch {
+	return TapBranch{Left: left, Right: right}
+}
+
+// TapHash returns the BIP341 branch hash of the two children.
+//
+// This is part of the TapNode interface.
+func (b TapBranch) TapHash() []byte {
+	return TapBranchHash(b.Left.TapHash(), b.Right.TapHash())
+}
+
+// TapBranchHash returns the TapBranch tagged hash of the two passed child
+// hashes.  The children are ordered lexicographically before hashing so the
+// result does not depend on which side of the tree each is on, which allows
+// proofs to omit the direction of each step.
+func TapBranchHash(a, b []byte) []byte {
+	if bytes.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+	return btcec.TaggedHash(tagTapBranch, a, b)
+}
+
+// TaprootOutputKey returns the x-only taproot output key obtained by tweaking
+// the internal key with the script tree merkle root, along with the parity of
+// its y coordinate which script-path spends must reveal in the control block.
+// A nil merkle root produces the output key for a key-path only output as
+// recommended by BIP341, where the tweak commits to the internal key alone.
+//
+// Only the x coordinate of the internal key is used, so the parity of its y
+// coordinate does not affect the result.  A nil output key is returned in the
+// cryptographically negligible case the tweak is not a valid scalar or the
+// tweaked key is the point at infinity.
+func TaprootOutputKey(internalKey *btcec.PublicKey, merkleRoot []byte) (outputKey []byte, parity byte) {
+	xOnly := internalKey.XOnly()
+	pubKey, err := xOnly.PubKey()
+	if err != nil {
+		return nil, 0
+	}
+
+	curve := btcec.S256()
+	tweak := btcec.TaggedHash(tagTapTweak, xOnly[:], merkleRoot)
+	if new(big.Int).SetBytes(tweak).Cmp(curve.N) >= 0 {
+		return nil, 0
+	}
+
+	// Q = P + t*G
+	tx, ty := curve.ScalarBaseMult(tweak)
+	qx, qy := curve.Add(pubKey.X, pubKey.Y, tx, ty)
+	if qx.Sign() == 0 && qy.Sign() == 0 {
+		return nil, 0
+	}
+
+	outputKey = make([]byte, btcec.SchnorrPubKeyLen)
+	qxBytes := qx.Bytes()
+	copy(outputKey[btcec.SchnorrPubKeyLen-len(qxBytes):], qxBytes)
+	return outputKey, byte(qy.Bit(0))
+}
+
+// ParseControlBlock splits the control block revealed by a taproot script-path
+// spend into the leaf version of the script being spent, the x-only internal
+// key, and the merkle path proving the leaf is committed to by the output key.
+// The parity of the output key is held in the low bit of the first byte and is
+// masked off of the returned leaf version.
+//
+// The control block must be ControlBlockBaseSize bytes followed by at most
+// ControlBlockMaxNodeCount hashes of ControlBlockNodeSize bytes each.  Only
+// the length is validated, so callers must still ensure the internal key is a
+// valid point.  The returned internal key and path hashes reference the
+// passed control block rather than copies of it.
+func ParseControlBlock(controlBlock []byte) (leafVersion byte, internalKey []byte, path [][]byte, err error) {
+	switch {
+	case len(controlBlock) < ControlBlockBaseSize:
+		str := fmt.Sprintf("control block of %d bytes is less than the "+
+			"minimum of %d bytes", len(controlBlock),
+			ControlBlockBaseSize)
+		return 0, nil, nil, scriptError(ErrControlBlockTooSmall, str)
+
+	case len(controlBlock) > ControlBlockMaxSize:
+		str := fmt.Sprintf("control block of %d bytes exceeds the "+
+			"maximum of %d bytes", len(controlBlock),
+			ControlBlockMaxSize)
+		return 0, nil, nil, scriptError(ErrControlBlockTooLarge, str)
+
+	case (len(controlBlock)-ControlBlockBaseSize)%ControlBlockNodeSize != 0:
+		str := fmt.Sprintf("control block of %d bytes is not %d bytes "+
+			"plus a multiple of %d bytes", len(controlBlock),
+			ControlBlockBaseSize, ControlBlockNodeSize)
+		return 0, nil, nil, scriptError(ErrControlBlockInvalidLength,
+			str)
+	}
+
+	leafVersion = controlBlock[0] & TaprootLeafMask
+	internalKey = controlBlock[1:ControlBlockBaseSize]
+
+	rawPath := controlBlock[ControlBlockBaseSize:]
+	path = make([][]byte, 0, len(rawPath)/ControlBlockNodeSize)
+	for len(rawPath) > 0 {
+		path = append(path, rawPath[:ControlBlockNodeSize])
+		rawPath = rawPath[ControlBlockNodeSize:]
+	}
+
+	return leafVersion, internalKey, path, nil
+}
+
+// TaprootSpendType is an enumeration of the ways a taproot output can be spent.
+type TaprootSpendType byte
+
+// Kinds of taproot spends.
+const (
+	TaprootUnknownSpend    TaprootSpendType = iota // Not a recognized spend.
+	TaprootKeyPathSpend                            // Signature for the output key.
+	TaprootScriptPathSpend                         // Script revealed by a control block.
+)
+
+// taprootSpendTypeToName houses the human-readable strings which describe
+// each taproot spend type.
+var taprootSpendTypeToName = []string{
+	TaprootUnknownSpend:    "unknown",
+	TaprootKeyPathSpend:    "keypath",
+	TaprootScriptPathSpend: "scriptpath",
+}
+
+// String implements the Stringer interface by returning the name of the enum
+// taproot spend type.  If the enum is invalid then "Invalid" will be returned.
+func (t TaprootSpendType) String() string {
+	if int(t) >= len(taprootSpendTypeToName) {
+		return "Invalid"
+	}
+	return taprootSpendTypeToName[t]
+}
+
+// ClassifyTaprootWitness returns whether the passed witness of an input
+// spending a taproot output is a key-path or script-path spend, along with
+// whether it carries an annex.
+//
+// Per BIP341, when the witness has at least two elements and the last one
+// begins with TaprootAnnexTag, that element is the annex and is removed before
+// classifying the rest.  A single remaining element is a key-path spend when it
+// is a 64 or 65 byte signature, while two or more remaining elements are a
+// script-path spend consisting of the script inputs followed by the script and
+// the control block.  Any other witness is classified as TaprootUnknownSpend.
+// The contents of the script and control block are not validated.
+func ClassifyTaprootWitness(witness wire.TxWitness) (TaprootSpendType, bool) {
+	_, hasAnnex := ExtractAnnex(witness)
+	if hasAnnex {
+		witness = witness[:len(witness)-1]
+	}
+
+	switch {
+	case len(witness) == 1:
+		sigLen := len(witness[0])
+		if sigLen == btcec.SchnorrSigLen || sigLen == btcec.SchnorrSigLen+1 {
+			return TaprootKeyPathSpend, hasAnnex
+		}
+
+	case len(witness) >= 2:
+		return TaprootScriptPathSpend, hasAnnex
+	}
+
+	return TaprootUnknownSpend, hasAnnex
+}
+
+// ExtractAnnex returns the annex of the passed taproot witness along with
+// whether it has one.  Per BIP341, the annex is the last witness element when
+// there are at least two elements and that element begins with
+// TaprootAnnexTag.  The returned annex references the witness element rather
+// than a copy of it.
+func ExtractAnnex(witness wire.TxWitness) ([]byte, bool) {
+	if len(witness) < 2 {
+		return nil, false
+	}
+
+	lastElem := witness[len(witness)-1]
+	if len(lastElem) == 0 || lastElem[0] != TaprootAnnexTag {
+		return nil, false
+	}
+	return lastElem, true
+}
+
+// SigHashDefault is the taproot only signature hash type which commits to the
+// same data as SigHashAll and is implied by a 64 byte signature.
+const SigHashDefault SigHashType = 0x00
+
+// isValidTaprootSigHashType returns whether the passed signature hash type is
+// one of the types permitted by BIP341.
+func isValidTaprootSigHashType(hashType SigHashType) bool {
+	switch hashType {
+	case SigHashDefault, SigHashAll, SigHashNone, SigHashSingle,
+		SigHashAll | SigHashAnyOneCanPay,
+		SigHashNone | SigHashAnyOneCanPay,
+		SigHashSingle | SigHashAnyOneCanPay:
+
+		return true
+	}
+	return false
+}
+
+// CalcTaprootSignatureHash computes the BIP341 signature hash for the
+// specified input of the passed transaction observing the desired sig hash
+// type.  Since taproot signatures commit to the amounts and scripts of every
+// output being spent, the previous outputs referenced by each input of the
+// transaction must be provided in order.
+//
+// A nil leaf hash produces the digest for a key-path spend, while the TapHash
+// of the leaf being executed produces the digest for a tapscript script-path
+// spend as extended by BIP342, under the assumption no OP_CODESEPARATOR has
+// been executed.  When the witness of the input carries an annex, as
+// determined by ExtractAnnex, the digest commits to it as well.
+func CalcTaprootSignatureHash(hashType SigHashType, tx *wire.MsgTx, idx int,
+	prevOuts []*wire.TxOut, leafHash []byte) ([]byte, error) {
+
+	if !isValidTaprootSigHashType(hashType) {
+		return nil, fmt.Errorf("invalid taproot sighash type 0x%x",
+			uint32(hashType))
+	}
+	if idx < 0 || idx > len(tx.TxIn)-1 {
+		return nil, fmt.Errorf("idx %d but %d txins", idx, len(tx.TxIn))
+	}
+	if len(prevOuts) != len(tx.TxIn) {
+		return nil, fmt.Errorf("%d previous outputs but %d txins",
+			len(prevOuts), len(tx.TxIn))
+	}
+	if leafHash != nil && len(leafHash) != chainhash.HashSize {
+		return nil, fmt.Errorf("leaf hash of %d bytes is not %d bytes",
+			len(leafHash), chainhash.HashSize)
+	}
+
+	baseType := hashType & sigHashMask
+	anyoneCanPay := hashType&SigHashAnyOneCanPay != 0
+	if baseType == SigHashSingle && idx >= len(tx.TxOut) {
+		return nil, fmt.Errorf("idx %d but %d txouts for SigHashSingle",
+			idx, len(tx.TxOut))
+	}
+
+	var buf [8]byte
+	var sigMsg bytes.Buffer
+
+	// The message starts with the sighash epoch followed by the hash type
+	// and the transaction level fields.
+	sigMsg.WriteByte(0x00)
+	sigMsg.WriteByte(byte(hashType))
+	binary.LittleEndian.PutUint32(buf[:4], uint32(tx.Version))
+	sigMsg.Write(buf[:4])
+	binary.LittleEndian.PutUint32(buf[:4], tx.LockTime)
+	sigMsg.Write(buf[:4])
+
+	// Unless anyone can pay, commit to the outpoints, amounts, scripts and
+	// sequence numbers of all inputs.  Unlike BIP0143, these are single
+	// rather than double SHA256 hashes.
+	if !anyoneCanPay {
+		var prevOutsBuf, amounts, scripts, sequences bytes.Buffer
+		for i, txIn := range tx.TxIn {
+			prevOutsBuf.Write(txIn.PreviousOutPoint.Hash[:])
+			binary.LittleEndian.PutUint32(buf[:4],
+				txIn.PreviousOutPoint.Index)
+			prevOutsBuf.Write(buf[:4])
+
+			binary.LittleEndian.PutUint64(buf[:],
+				uint64(prevOuts[i].Value))
+			amounts.Write(buf[:])
+
+			// Writing to a bytes.Buffer never fails.
+			_ = wire.WriteVarBytes(&scripts, 0, prevOuts[i].PkScript)
+
+			binary.LittleEndian.PutUint32(buf[:4], txIn.Sequence)
+			sequences.Write(buf[:4])
+		}
+		sigMsg.Write(chainhash.HashB(prevOutsBuf.Bytes()))
+		sigMsg.Write(chainhash.HashB(amounts.Bytes()))
+		sigMsg.Write(chainhash.HashB(scripts.Bytes()))
+		sigMsg.Write(chainhash.HashB(sequences.Bytes()))
+	}
+	if baseType != SigHashNone && baseType != SigHashSingle {
+		var outputs bytes.Buffer
+		for _, txOut := range tx.TxOut {
+			wire.WriteTxOut(&outputs, 0, 0, txOut)
+		}
+		sigMsg.Write(chainhash.HashB(outputs.Bytes()))
+	}
+
+	// The spend type indicates whether the tapscript extension is appended
+	// and whether the annex is committed to.
+	txIn := tx.TxIn[idx]
+	annex, hasAnnex := ExtractAnnex(txIn.Witness)
+	var spendType byte
+	if leafHash != nil {
+		spendType |= 0x02
+	}
+	if hasAnnex {
+		spendType |= 0x01
+	}
+	sigMsg.WriteByte(spendType)
+
+	// Commit to the input being signed, either in full when anyone can pay
+	// or by its index otherwise.
+	if anyoneCanPay {
+		sigMsg.Write(txIn.PreviousOutPoint.Hash[:])
+		binary.LittleEndian.PutUint32(buf[:4], txIn.PreviousOutPoint.Index)
+		sigMsg.Write(buf[:4])
+		binary.LittleEndian.PutUint64(buf[:], uint64(prevOuts[idx].Value))
+		sigMsg.Write(buf[:])
+		_ = wire.WriteVarBytes(&sigMsg, 0, prevOuts[idx].PkScript)
+		binary.LittleEndian.PutUint32(buf[:4], txIn.Sequence)
+		sigMsg.Write(buf[:4])
+	} else {
+		binary.LittleEndian.PutUint32(buf[:4], uint32(idx))
+		sigMsg.Write(buf[:4])
+	}
+
+	// The annex is committed to by the hash of its serialization with a
+	// compact size length prefix.
+	if hasAnnex {
+		var annexBuf bytes.Buffer
+		_ = wire.WriteVarBytes(&annexBuf, 0, annex)
+		sigMsg.Write(chainhash.HashB(annexBuf.Bytes()))
+	}
+
+	if baseType == SigHashSingle {
+		var output bytes.Buffer
+		wire.WriteTxOut(&output, 0, 0, tx.TxOut[idx])
+		sigMsg.Write(chainhash.HashB(output.Bytes()))
+	}
+
+	// The tapscript extension commits to the leaf being executed, the key
+	// version, and the position of the last executed OP_CODESEPARATOR.
+	if leafHash != nil {
+		sigMsg.Write(leafHash)
+		sigMsg.WriteByte(0x00)
+		binary.LittleEndian.PutUint32(buf[:4], 0xffffffff)
+		sigMsg.Write(buf[:4])
+	}
+
+	return btcec.TaggedHash(tagTapSighash, sigMsg.Bytes()), nil
+}
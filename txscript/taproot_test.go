@@ -0,0 +1,510 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// TestTaprootOutputKey ensures the taproot script tree hashes and output key
+// tweaks reproduce the BIP341 wallet test vectors for key-path only outputs as
+// well as outputs committing to script trees.
+func TestTaprootOutputKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		internalKey string
+		tree        TapNode
+		leafHashes  []string
+		merkleRoot  string
+		outputKey   string
+		parity      byte
+	}{
+		{
+			name:        "key-path only",
+			internalKey: "d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+			outputKey:   "53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+			parity:      1,
+		},
+		{
+			name:        "single leaf",
+			internalKey: "187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+			tree: NewBaseTapLeaf(hexToBytes("20d85a959b0290bf19bb89ed43c9" +
+				"16be835475d013da4b362117393e25a48229b8ac")),
+			leafHashes: []string{
+				"5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+			},
+			merkleRoot: "5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+			outputKey:  "147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+			parity:     1,
+		},
+		{
+			name:        "single leaf even parity",
+			internalKey: "93478e9488f956df2396be2ce6c5cced75f900dfa18e7dabd2428aae78451820",
+			tree: NewBaseTapLeaf(hexToBytes("20b617298552a72ade070667e86c" +
+				"a63b8f5789a9fe8731ef91202a91c9f3459007ac")),
+			leafHashes: []string{
+				"c525714a7f49c28aedbbba78c005931a81c234b2f6c99a73e4d06082adc8bf2b",
+			},
+			merkleRoot: "c525714a7f49c28aedbbba78c005931a81c234b2f6c99a73e4d06082adc8bf2b",
+			outputKey:  "e4d810fd50586274face62b8a807eb9719cef49c04177cc6b76a9a4251d5450e",
+			parity:     0,
+		},
+		{
+			name:        "two leaves with distinct leaf versions",
+			internalKey: "ee4fe085983462a184015d1f782d6a5f8b9c2b60130aff050ce221ecf3786592",
+			tree: NewTapBranch(
+				NewBaseTapLeaf(hexToBytes("20387671353e273264c495656e27e3"+
+					"9ba899ea8fee3bb69fb2a680e22093447d48ac")),
+				TapLeaf{
+					LeafVersion: 0xfa,
+					Script:      hexToBytes("06424950333431"),
+				},
+			),
+			leafHashes: []string{
+				"8ad69ec7cf41c2a4001fd1f738bf1e505ce2277acdcaa63fe4765192497f47a7",
+				"f224a923cd0021ab202ab139cc56802ddb92dcfc172b9212261a539df79a112a",
+			},
+			merkleRoot: "6c2dc106ab816b73f9d07e3cd1ef2c8c1256f519748e0813e4edd2405d277bef",
+			outputKey:  "712447206d7a5238acc7ff53fbe94a3b64539ad291c7cdbc490b7577e4b17df5",
+			parity:     0,
+		},
+	}
+
+	for _, test := range tests {
+		var merkleRoot []byte
+		if test.tree != nil {
+			// Ensure the leaf hashes match.
+			var leaves []TapNode
+			if branch, ok := test.tree.(TapBranch); ok {
+				leaves = []TapNode{branch.Left, branch.Right}
+			} else {
+				leaves = []TapNode{test.tree}
+			}
+			for i, leaf := range leaves {
+				got := hex.EncodeToString(leaf.TapHash())
+				if got != test.leafHashes[i] {
+					t.Errorf("%s: mismatched leaf hash %d - got "+
+						"%s, want %s", test.name, i, got,
+						test.leafHashes[i])
+				}
+			}
+
+			merkleRoot = test.tree.TapHash()
+			if got := hex.EncodeToString(merkleRoot); got != test.merkleRoot {
+				t.Errorf("%s: mismatched merkle root - got %s, "+
+					"want %s", test.name, got, test.merkleRoot)
+				continue
+			}
+		}
+
+		// Both parities of the internal key must produce the same output
+		// key since only its x coordinate is committed to.
+		for _, prefix := range []byte{0x02, 0x03} {
+			serialized := append([]byte{prefix},
+				hexToBytes(test.internalKey)...)
+			internalKey, err := btcec.ParsePubKey(serialized,
+				btcec.S256())
+			if err != nil {
+				t.Fatalf("%s: unexpected error parsing internal "+
+					"key: %v", test.name, err)
+			}
+
+			outputKey, parity := TaprootOutputKey(internalKey,
+				merkleRoot)
+			if !bytes.Equal(outputKey, hexToBytes(test.outputKey)) {
+				t.Errorf("%s: mismatched output key - got %x, "+
+					"want %s", test.name, outputKey,
+					test.outputKey)
+			}
+			if parity != test.parity {
+				t.Errorf("%s: mismatched parity - got %d, want %d",
+					test.name, parity, test.parity)
+			}
+		}
+	}
+}
+
+// TestTapBranchHash ensures branch hashes do not depend on the order of the
+// children.
+func TestTapBranchHash(t *testing.T) {
+	t.Parallel()
+
+	a := NewBaseTapLeaf([]byte{OP_TRUE})
+	b := NewBaseTapLeaf([]byte{OP_FALSE})
+	ab := NewTapBranch(a, b).TapHash()
+	ba := NewTapBranch(b, a).TapHash()
+	if !bytes.Equal(ab, ba) {
+		t.Fatalf("branch hash depends on child order: %x != %x", ab, ba)
+	}
+
+	// Changing the leaf version must change the leaf hash.
+	c := TapLeaf{LeafVersion: 0xc2, Script: []byte{OP_TRUE}}
+	if bytes.Equal(a.TapHash(), c.TapHash()) {
+		t.Fatalf("leaf hash does not commit to the leaf version")
+	}
+}
+
+// TestParseControlBlock ensures control blocks are split into their parts and
+// malformed control blocks are rejected.
+func TestParseControlBlock(t *testing.T) {
+	t.Parallel()
+
+	// Build the control block for spending the first leaf of the two leaf
+	// BIP341 test vector and ensure the parsed parts prove the leaf is
+	// committed to by the merkle root.
+	internalKey := hexToBytes("ee4fe085983462a184015d1f782d6a5f8b9c2b60130a" +
+		"ff050ce221ecf3786592")
+	leaf := NewBaseTapLeaf(hexToBytes("20387671353e273264c495656e27e39ba89" +
+		"9ea8fee3bb69fb2a680e22093447d48ac"))
+	sibling := TapLeaf{LeafVersion: 0xfa, Script: hexToBytes("06424950333431")}
+	merkleRoot := NewTapBranch(leaf, sibling).TapHash()
+
+	controlBlock := append([]byte{BaseLeafVersion | 0x01}, internalKey...)
+	controlBlock = append(controlBlock, sibling.TapHash()...)
+
+	leafVersion, gotKey, path, err := ParseControlBlock(controlBlock)
+	if err != nil {
+		t.Fatalf("ParseControlBlock: unexpected error: %v", err)
+	}
+	if leafVersion != BaseLeafVersion {
+		t.Fatalf("ParseControlBlock: mismatched leaf version - got %#x, "+
+			"want %#x", leafVersion, BaseLeafVersion)
+	}
+	if !bytes.Equal(gotKey, internalKey) {
+		t.Fatalf("ParseControlBlock: mismatched internal key - got %x, "+
+			"want %x", gotKey, internalKey)
+	}
+	if len(path) != 1 {
+		t.Fatalf("ParseControlBlock: unexpected path length %d", len(path))
+	}
+	if root := TapBranchHash(leaf.TapHash(), path[0]); !bytes.Equal(root,
+		merkleRoot) {
+
+		t.Fatalf("ParseControlBlock: path does not prove leaf - got "+
+			"root %x, want %x", root, merkleRoot)
+	}
+
+	tests := []struct {
+		name      string
+		size      int
+		pathDepth int
+		err       error
+	}{
+		{"empty", 0, 0, scriptError(ErrControlBlockTooSmall, "")},
+		{"missing key byte", ControlBlockBaseSize - 1, 0,
+			scriptError(ErrControlBlockTooSmall, "")},
+		{"key-path depth", ControlBlockBaseSize, 0, nil},
+		{"one extra byte", ControlBlockBaseSize + 1, 0,
+			scriptError(ErrControlBlockInvalidLength, "")},
+		{"partial node", ControlBlockBaseSize + ControlBlockNodeSize + 31, 0,
+			scriptError(ErrControlBlockInvalidLength, "")},
+		{"max depth", ControlBlockMaxSize, ControlBlockMaxNodeCount, nil},
+		{"max depth plus one", ControlBlockMaxSize + ControlBlockNodeSize, 0,
+			scriptError(ErrControlBlockTooLarge, "")},
+		{"max depth plus one byte", ControlBlockMaxSize + 1, 0,
+			scriptError(ErrControlBlockTooLarge, "")},
+	}
+
+	for _, test := range tests {
+		_, _, path, err := ParseControlBlock(make([]byte, test.size))
+		if e := tstCheckScriptError(err, test.err); e != nil {
+			t.Errorf("%s: %v", test.name, e)
+			continue
+		}
+		if err == nil && len(path) != test.pathDepth {
+			t.Errorf("%s: unexpected path length - got %d, want %d",
+				test.name, len(path), test.pathDepth)
+		}
+	}
+}
+
+// TestClassifyTaprootWitness ensures taproot witnesses are classified as key
+// or script-path spends and that an annex is detected.
+func TestClassifyTaprootWitness(t *testing.T) {
+	t.Parallel()
+
+	sig := bytes.Repeat([]byte{0x01}, 64)
+	sigWithHashType := append(bytes.Repeat([]byte{0x01}, 64), 0x81)
+	script := hexToBytes("20387671353e273264c495656e27e39ba899ea8fee3bb69f" +
+		"b2a680e22093447d48ac")
+	controlBlock := append([]byte{BaseLeafVersion}, make([]byte, 32)...)
+	annex := []byte{TaprootAnnexTag, 0x01, 0x02}
+
+	tests := []struct {
+		name      string
+		witness   wire.TxWitness
+		spendType TaprootSpendType
+		hasAnnex  bool
+	}{
+		{"empty", nil, TaprootUnknownSpend, false},
+		{"key path", wire.TxWitness{sig}, TaprootKeyPathSpend, false},
+		{"key path with hash type", wire.TxWitness{sigWithHashType},
+			TaprootKeyPathSpend, false},
+		{"key path bad sig length", wire.TxWitness{sig[:63]},
+			TaprootUnknownSpend, false},
+		{"key path with annex", wire.TxWitness{sig, annex},
+			TaprootKeyPathSpend, true},
+		{"script path", wire.TxWitness{sig, script, controlBlock},
+			TaprootScriptPathSpend, false},
+		{"script path no inputs", wire.TxWitness{script, controlBlock},
+			TaprootScriptPathSpend, false},
+		{"script path with annex",
+			wire.TxWitness{sig, script, controlBlock, annex},
+			TaprootScriptPathSpend, true},
+		{"lone annex tagged element is not an annex",
+			wire.TxWitness{annex}, TaprootUnknownSpend, false},
+		{"annex only", wire.TxWitness{annex, annex},
+			TaprootUnknownSpend, true},
+	}
+
+	for _, test := range tests {
+		spendType, hasAnnex := ClassifyTaprootWitness(test.witness)
+		if spendType != test.spendType {
+			t.Errorf("%s: unexpected spend type - got %v, want %v",
+				test.name, spendType, test.spendType)
+			continue
+		}
+		if hasAnnex != test.hasAnnex {
+			t.Errorf("%s: unexpected annex flag - got %v, want %v",
+				test.name, hasAnnex, test.hasAnnex)
+		}
+	}
+}
+
+// TestExtractAnnex ensures only the last element of a witness with more than
+// one element is treated as the annex, and only when it has the annex tag.
+func TestExtractAnnex(t *testing.T) {
+	t.Parallel()
+
+	sig := bytes.Repeat([]byte{0x01}, 64)
+	annex := []byte{TaprootAnnexTag, 0x01, 0x02}
+
+	tests := []struct {
+		name     string
+		witness  wire.TxWitness
+		annex    []byte
+		hasAnnex bool
+	}{
+		{"empty", nil, nil, false},
+		{"single annex tagged element", wire.TxWitness{annex}, nil, false},
+		{"no annex", wire.TxWitness{sig, sig}, nil, false},
+		{"empty last element", wire.TxWitness{sig, nil}, nil, false},
+		{"annex", wire.TxWitness{sig, annex}, annex, true},
+		{"annex tag not last", wire.TxWitness{annex, sig}, nil, false},
+		{"bare annex tag", wire.TxWitness{sig, {TaprootAnnexTag}},
+			[]byte{TaprootAnnexTag}, true},
+	}
+
+	for _, test := range tests {
+		gotAnnex, hasAnnex := ExtractAnnex(test.witness)
+		if hasAnnex != test.hasAnnex {
+			t.Errorf("%s: unexpected annex flag - got %v, want %v",
+				test.name, hasAnnex, test.hasAnnex)
+			continue
+		}
+		if !bytes.Equal(gotAnnex, test.annex) {
+			t.Errorf("%s: unexpected annex - got %x, want %x",
+				test.name, gotAnnex, test.annex)
+		}
+	}
+}
+
+// TestCalcTaprootSignatureHash ensures the taproot signature hash commits to
+// the annex and the spend path and rejects invalid parameters.
+func TestCalcTaprootSignatureHash(t *testing.T) {
+	t.Parallel()
+
+	sig := bytes.Repeat([]byte{0x01}, 64)
+	annex := []byte{TaprootAnnexTag, 0x01, 0x02}
+	pkScript := append([]byte{OP_1, OP_DATA_32}, make([]byte, 32)...)
+
+	newTx := func(witness wire.TxWitness) *wire.MsgTx {
+		return &wire.MsgTx{
+			Version: 2,
+			TxIn: []*wire.TxIn{{
+				PreviousOutPoint: wire.OutPoint{Index: 1},
+				Witness:          wire.TxWitness{sig},
+				Sequence:         0xffffffff,
+			}, {
+				PreviousOutPoint: wire.OutPoint{Index: 2},
+				Witness:          witness,
+				Sequence:         0xfffffffe,
+			}},
+			TxOut: []*wire.TxOut{{
+				Value:    90000,
+				PkScript: pkScript,
+			}},
+		}
+	}
+	prevOuts := []*wire.TxOut{
+		{Value: 50000, PkScript: pkScript},
+		{Value: 60000, PkScript: pkScript},
+	}
+	leafHash := NewBaseTapLeaf([]byte{OP_TRUE}).TapHash()
+
+	hashTypes := []SigHashType{SigHashDefault, SigHashAll, SigHashNone,
+		SigHashAll | SigHashAnyOneCanPay,
+		SigHashNone | SigHashAnyOneCanPay}
+	for _, hashType := range hashTypes {
+		for _, leaf := range [][]byte{nil, leafHash} {
+			plain, err := CalcTaprootSignatureHash(hashType,
+				newTx(wire.TxWitness{sig}), 1, prevOuts, leaf)
+			if err != nil {
+				t.Fatalf("0x%x: unexpected error: %v", hashType,
+					err)
+			}
+			withAnnex, err := CalcTaprootSignatureHash(hashType,
+				newTx(wire.TxWitness{sig, annex}), 1, prevOuts,
+				leaf)
+			if err != nil {
+				t.Fatalf("0x%x: unexpected error: %v", hashType,
+					err)
+			}
+			if len(plain) != chainhash.HashSize {
+				t.Fatalf("0x%x: unexpected sighash length %d",
+					hashType, len(plain))
+			}
+			if bytes.Equal(plain, withAnnex) {
+				t.Errorf("0x%x: sighash does not commit to annex",
+					hashType)
+			}
+		}
+	}
+
+	// The key and script paths must not produce the same digest.
+	tx := newTx(wire.TxWitness{sig})
+	keyPath, err := CalcTaprootSignatureHash(SigHashDefault, tx, 1,
+		prevOuts, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	scriptPath, err := CalcTaprootSignatureHash(SigHashDefault, tx, 1,
+		prevOuts, leafHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Equal(keyPath, scriptPath) {
+		t.Errorf("key and script path sighashes are equal")
+	}
+
+	// Ensure invalid parameters are rejected.
+	invalidTests := []struct {
+		name     string
+		hashType SigHashType
+		idx      int
+		prevOuts []*wire.TxOut
+		leafHash []byte
+	}{
+		{"invalid hash type", 0x04, 0, prevOuts, nil},
+		{"anyone can pay default", SigHashAnyOneCanPay, 0, prevOuts, nil},
+		{"index out of range", SigHashDefault, 2, prevOuts, nil},
+		{"missing previous output", SigHashDefault, 0, prevOuts[:1], nil},
+		{"single without output", SigHashSingle, 1, prevOuts, nil},
+		{"short leaf hash", SigHashDefault, 0, prevOuts, leafHash[:31]},
+	}
+	for _, test := range invalidTests {
+		_, err := CalcTaprootSignatureHash(test.hashType, tx, test.idx,
+			test.prevOuts, test.leafHash)
+		if err == nil {
+			t.Errorf("%s: did not receive expected error", test.name)
+		}
+	}
+}
+
+// TestCalcTaprootSignatureHashVectors ensures the taproot signature hash
+// reproduces the key-path spending test vectors from the BIP341 wallet test
+// vectors.
+func TestCalcTaprootSignatureHashVectors(t *testing.T) {
+	t.Parallel()
+
+	// The unsigned transaction of the test vectors in its Bitcoin
+	// serialization.  The Navcoin serialization additionally carries a zero
+	// time after the version and an empty strdzeel after the lock time,
+	// neither of which is committed to by the signature hash.
+	const rawUnsignedTx = "02000000097de20cbff686da83a54981d2b9bab3586f4c" +
+		"a7e48f57f5b55963115f3b334e9c010000000000000000d7b7cab57b1393ace2" +
+		"d064f4d4a2cb8af6def61273e127517d44759b6dafdd990000000000ffffffff" +
+		"f8e1f583384333689228c5d28eac13366be082dc57441760d957275419a41842" +
+		"0000000000fffffffff0689180aa63b30cb162a73c6d2a38b7eeda2a83ece743" +
+		"10fda0843ad604853b0100000000feffffffaa5202bdf6d8ccd2ee0f0202afbb" +
+		"b7461d9264a25e5bfd3c5a52ee1239e0ba6c0000000000feffffff956149bdc6" +
+		"6faa968eb2be2d2faa29718acbfe3941215893a2a3446d32acd0500000000000" +
+		"00000000e664b9773b88c09c32cb70a2a3e4da0ced63b7ba3b22f848531bbb1d" +
+		"5d5f4c94010000000000000000e9aa6b8e6c9de67619e6a3924ae25696bb7b69" +
+		"4bb677a632a74ef7eadfd4eabf0000000000ffffffffa778eb6a263dc090464c" +
+		"d125c466b5a99667720b1c110468831d058aa1b82af10100000000ffffffff02" +
+		"00ca9a3b000000001976a91406afd46bcdfd22ef94ac122aa11f241244a37ecc" +
+		"88ac807840cb0000000020ac9a87f5594be208f8532db38cff670c450ed2fea8" +
+		"fcdefcc9a663f78bab962b0065cd1d"
+	rawTx := hexToBytes(rawUnsignedTx[:8] + "00000000" +
+		rawUnsignedTx[8:] + "00")
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+		t.Fatalf("Deserialize: unexpected error: %v", err)
+	}
+
+	prevOuts := []*wire.TxOut{
+		{Value: 420000000, PkScript: hexToBytes("512053a1f6e454df1aa27" +
+			"76a2814a721372d6258050de330b3c6d10ee8f4e0dda343")},
+		{Value: 462000000, PkScript: hexToBytes("5120147c9c57132f6e7ec" +
+			"ddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3")},
+		{Value: 294000000, PkScript: hexToBytes("76a914751e76e8199196d" +
+			"454941c45d1b3a323f1433bd688ac")},
+		{Value: 504000000, PkScript: hexToBytes("5120e4d810fd50586274f" +
+			"ace62b8a807eb9719cef49c04177cc6b76a9a4251d5450e")},
+		{Value: 630000000, PkScript: hexToBytes("512091b64d5324723a985" +
+			"170e4dc5a0f84c041804f2cd12660fa5dec09fc21783605")},
+		{Value: 378000000, PkScript: hexToBytes("00147dd65592d0ab2fe0d" +
+			"0257d571abf032cd9db93dc")},
+		{Value: 672000000, PkScript: hexToBytes("512075169f4001aa68f15" +
+			"bbed28b218df1d0a62cbbcf1188c6665110c293c907b831")},
+		{Value: 546000000, PkScript: hexToBytes("5120712447206d7a5238a" +
+			"cc7ff53fbe94a3b64539ad291c7cdbc490b7577e4b17df5")},
+		{Value: 588000000, PkScript: hexToBytes("512077e30a5522dd9f894" +
+			"c3f8b8bd4c4b2cf82ca7da8a3ea6a239655c39c050ab220")},
+	}
+
+	tests := []struct {
+		idx      int
+		hashType SigHashType
+		sigHash  string
+	}{
+		{0, SigHashSingle, "2514a6272f85cfa0f45eb907fcb0d121b808ed37c6" +
+			"ea160a5a9046ed5526d555"},
+		{1, SigHashSingle | SigHashAnyOneCanPay, "325a644af47e8a5a2591" +
+			"cda0ab0723978537318f10e6a63d4eed783b96a71a4d"},
+		{3, SigHashAll, "bf013ea93474aa67815b1b6cc441d23b64fa310911d99" +
+			"1e713cd34c7f5d46669"},
+		{4, SigHashDefault, "4f900a0bae3f1446fd48490c2958b5a023228f0166" +
+			"1cda3496a11da502a7f7ef"},
+		{6, SigHashNone, "15f25c298eb5cdc7eb1d638dd2d45c97c4c59dcaec66" +
+			"79cfc16ad84f30876b85"},
+		{7, SigHashNone | SigHashAnyOneCanPay, "cd292de50313804dabe468" +
+			"5e83f923d2969577191a3e1d2882220dca88cbeb10"},
+		{8, SigHashAll | SigHashAnyOneCanPay, "cccb739eca6c13a8a89e6e5" +
+			"cd317ffe55669bbda23f2fd37b0f18755e008edd2"},
+	}
+
+	for _, test := range tests {
+		sigHash, err := CalcTaprootSignatureHash(test.hashType, &tx,
+			test.idx, prevOuts, nil)
+		if err != nil {
+			t.Errorf("input %d: unexpected error: %v", test.idx, err)
+			continue
+		}
+		if hex.EncodeToString(sigHash) != test.sigHash {
+			t.Errorf("input %d: unexpected sighash - got %x, want %s",
+				test.idx, sigHash, test.sigHash)
+		}
+	}
+}
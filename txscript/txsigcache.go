@@ -0,0 +1,134 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"sync"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// txSigCacheEntry represents an entry in the TxSigCache.  In addition to the
+// signature and public key stored by a sigCacheEntry, it records the hash of
+// the transaction the signature was verified for so all of the entries that
+// belong to a transaction can be located and removed at once.
+type txSigCacheEntry struct {
+	sig    *btcec.Signature
+	pubKey *btcec.PublicKey
+	txid   chainhash.Hash
+}
+
+// TxSigCache is a variant of SigCache which associates each cached signature
+// with the transaction that owns it.  This trades a small amount of additional
+// memory for the ability to precisely invalidate every cached signature of a
+// transaction via RemoveTx, for example when the transaction is evicted from
+// the mempool or is replaced by a conflicting transaction.
+//
+// Like SigCache, only valid signatures should be added to the cache and random
+// entries are evicted when the cache is full.
+type TxSigCache struct {
+	sync.RWMutex
+	validSigs  map[chainhash.Hash]txSigCacheEntry
+	txSigs     map[chainhash.Hash]map[chainhash.Hash]struct{}
+	maxEntries uint
+}
+
+// NewTxSigCache creates and initializes a new instance of TxSigCache.  Its
+// sole parameter 'maxEntries' represents the maximum number of entries allowed
+// to exist in the TxSigCache at any particular moment.  Random entries are
+// evicted to make room for new entries that would cause the number of entries
+// in the cache to exceed the max.
+func NewTxSigCache(maxEntries uint) *TxSigCache {
+	return &TxSigCache{
+		validSigs:  make(map[chainhash.Hash]txSigCacheEntry, maxEntries),
+		txSigs:     make(map[chainhash.Hash]map[chainhash.Hash]struct{}),
+		maxEntries: maxEntries,
+	}
+}
+
+// Exists returns true if an existing entry of 'sig' over 'sigHash' for public
+// key 'pubKey' is found within the TxSigCache. Otherwise, false is returned.
+//
+// NOTE: This function is safe for concurrent access. Readers won't be blocked
+// unless there exists a writer, adding an entry to the TxSigCache.
+func (s *TxSigCache) Exists(sigHash chainhash.Hash, sig *btcec.Signature, pubKey *btcec.PublicKey) bool {
+	s.RLock()
+	entry, ok := s.validSigs[sigHash]
+	s.RUnlock()
+
+	return ok && entry.pubKey.IsEqual(pubKey) && entry.sig.IsEqual(sig)
+}
+
+// Add adds an entry for a signature over 'sigHash' under public key 'pubKey'
+// which was verified as part of the transaction identified by 'txid'.  In the
+// event that the TxSigCache is 'full', an existing entry is randomly chosen to
+// be evicted in order to make space for the new entry.
+//
+// NOTE: This function is safe for concurrent access. Writers will block
+// simultaneous readers until function execution has concluded.
+func (s *TxSigCache) Add(sigHash chainhash.Hash, sig *btcec.Signature, pubKey *btcec.PublicKey, txid chainhash.Hash) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.maxEntries <= 0 {
+		return
+	}
+
+	// When the sighash is already cached, the new entry replaces it, so
+	// detach the old entry from its owning transaction first.  Otherwise,
+	// if adding this new entry will put us over the max number of allowed
+	// entries, then evict a random entry.  See SigCache.Add for details on
+	// the safety of relying on Go's map iteration order.
+	if _, ok := s.validSigs[sigHash]; ok {
+		s.removeEntry(sigHash)
+	} else if uint(len(s.validSigs)+1) > s.maxEntries {
+		for sigEntry := range s.validSigs {
+			s.removeEntry(sigEntry)
+			break
+		}
+	}
+
+	s.validSigs[sigHash] = txSigCacheEntry{sig, pubKey, txid}
+	sigHashes, ok := s.txSigs[txid]
+	if !ok {
+		sigHashes = make(map[chainhash.Hash]struct{})
+		s.txSigs[txid] = sigHashes
+	}
+	sigHashes[sigHash] = struct{}{}
+}
+
+// RemoveTx removes all of the entries owned by the transaction identified by
+// 'txid' from the TxSigCache.  It is not an error if the transaction does not
+// own any entries.
+//
+// NOTE: This function is safe for concurrent access. Writers will block
+// simultaneous readers until function execution has concluded.
+func (s *TxSigCache) RemoveTx(txid chainhash.Hash) {
+	s.Lock()
+	for sigHash := range s.txSigs[txid] {
+		delete(s.validSigs, sigHash)
+	}
+	delete(s.txSigs, txid)
+	s.Unlock()
+}
+
+// removeEntry removes the entry for the passed sighash from the cache along
+// with the reference to it from its owning transaction.
+//
+// This function MUST be called with the cache lock held (for writes).
+func (s *TxSigCache) removeEntry(sigHash chainhash.Hash) {
+	entry, ok := s.validSigs[sigHash]
+	if !ok {
+		return
+	}
+	delete(s.validSigs, sigHash)
+
+	sigHashes := s.txSigs[entry.txid]
+	delete(sigHashes, sigHash)
+	if len(sigHashes) == 0 {
+		delete(s.txSigs, entry.txid)
+	}
+}
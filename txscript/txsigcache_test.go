@@ -0,0 +1,104 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"testing"
+
+	"github.com/navcoin/navd/btcec"
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// TestTxSigCacheRemoveTx tests that removing a transaction from the cache
+// purges all of the entries it owns while leaving the entries of other
+// transactions intact.
+func TestTxSigCacheRemoveTx(t *testing.T) {
+	sigCache := NewTxSigCache(100)
+
+	type sigTriplet struct {
+		msg *chainhash.Hash
+		sig *btcec.Signature
+		key *btcec.PublicKey
+	}
+
+	// Add a few signatures for each of two transactions.
+	txid1 := chainhash.Hash{0x01}
+	txid2 := chainhash.Hash{0x02}
+	var tx1Sigs, tx2Sigs []sigTriplet
+	for i := 0; i < 3; i++ {
+		msg, sig, key, err := genRandomSig()
+		if err != nil {
+			t.Fatalf("unable to generate random signature test data")
+		}
+		sigCache.Add(*msg, sig, key, txid1)
+		tx1Sigs = append(tx1Sigs, sigTriplet{msg, sig, key})
+
+		msg, sig, key, err = genRandomSig()
+		if err != nil {
+			t.Fatalf("unable to generate random signature test data")
+		}
+		sigCache.Add(*msg, sig, key, txid2)
+		tx2Sigs = append(tx2Sigs, sigTriplet{msg, sig, key})
+	}
+
+	// Remove the first transaction.  Only the entries belonging to the
+	// second transaction should remain.
+	sigCache.RemoveTx(txid1)
+	for _, e := range tx1Sigs {
+		if sigCache.Exists(*e.msg, e.sig, e.key) {
+			t.Fatalf("entry for removed transaction found in " +
+				"signature cache")
+		}
+	}
+	for _, e := range tx2Sigs {
+		sigCopy, _ := btcec.ParseSignature(e.sig.Serialize(), btcec.S256())
+		keyCopy, _ := btcec.ParsePubKey(e.key.SerializeCompressed(),
+			btcec.S256())
+		if !sigCache.Exists(*e.msg, sigCopy, keyCopy) {
+			t.Fatalf("entry for remaining transaction not found " +
+				"in signature cache")
+		}
+	}
+	if len(sigCache.validSigs) != len(tx2Sigs) {
+		t.Fatalf("sigcache should have %v entries, instead it has %v",
+			len(tx2Sigs), len(sigCache.validSigs))
+	}
+	if _, ok := sigCache.txSigs[txid1]; ok {
+		t.Fatalf("removed transaction still tracked by sigcache")
+	}
+
+	// Removing an unknown transaction must be a no-op.
+	sigCache.RemoveTx(chainhash.Hash{0x03})
+	if len(sigCache.validSigs) != len(tx2Sigs) {
+		t.Fatalf("sigcache should have %v entries, instead it has %v",
+			len(tx2Sigs), len(sigCache.validSigs))
+	}
+}
+
+// TestTxSigCacheEvictEntry tests that randomly evicting an entry from a full
+// cache also removes the reference to it held by its owning transaction.
+func TestTxSigCacheEvictEntry(t *testing.T) {
+	sigCacheSize := uint(10)
+	sigCache := NewTxSigCache(sigCacheSize)
+
+	// Fill the cache with entries, each owned by a distinct transaction,
+	// then add one more to trigger an eviction.
+	for i := uint(0); i <= sigCacheSize; i++ {
+		msg, sig, key, err := genRandomSig()
+		if err != nil {
+			t.Fatalf("unable to generate random signature test data")
+		}
+		sigCache.Add(*msg, sig, key, chainhash.Hash{byte(i)})
+	}
+
+	if uint(len(sigCache.validSigs)) != sigCacheSize {
+		t.Fatalf("sigcache should have %v entries, instead it has %v",
+			sigCacheSize, len(sigCache.validSigs))
+	}
+	if uint(len(sigCache.txSigs)) != sigCacheSize {
+		t.Fatalf("sigcache should track %v transactions, instead it "+
+			"tracks %v", sigCacheSize, len(sigCache.txSigs))
+	}
+}
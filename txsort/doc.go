@@ -0,0 +1,25 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package txsort sorts the inputs and outputs of a transaction into the
+canonical order defined by BIP0069.
+
+Wallets which order inputs and outputs in an implementation specific way, such
+as placing the change output last, leak information about which outputs belong
+to them.  Sorting deterministically removes that fingerprint and allows
+participants of multi-party transactions to construct identical transactions
+without further coordination.
+
+Inputs are sorted by the hash of the transaction they spend, compared in the
+byte order the hash is displayed in, and then by output index.  Outputs are
+sorted by amount and then by the bytes of their public key script.  Since the
+witness of an input is part of the input, it moves along with the input it
+belongs to.
+
+Sorting changes the hash of the transaction and invalidates any signatures
+which commit to the positions of the inputs or outputs, so transactions must
+be sorted before they are signed.
+*/
+package txsort
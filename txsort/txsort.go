@@ -0,0 +1,88 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txsort
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// SortInputsOutputs sorts the inputs and outputs of the passed transaction in
+// place into the canonical order defined by BIP0069.  The witness of each input
+// is reordered along with the input it belongs to.
+func SortInputsOutputs(tx *wire.MsgTx) {
+	sort.Sort(sortableInputSlice(tx.TxIn))
+	sort.Sort(sortableOutputSlice(tx.TxOut))
+}
+
+// IsSorted returns whether the inputs and outputs of the passed transaction are
+// in the canonical order defined by BIP0069.
+func IsSorted(tx *wire.MsgTx) bool {
+	return sort.IsSorted(sortableInputSlice(tx.TxIn)) &&
+		sort.IsSorted(sortableOutputSlice(tx.TxOut))
+}
+
+// reversedHash returns the passed hash with its bytes reversed, which is the
+// byte order the hash is displayed in.
+func reversedHash(hash *chainhash.Hash) chainhash.Hash {
+	var reversed chainhash.Hash
+	for i := range hash {
+		reversed[chainhash.HashSize-1-i] = hash[i]
+	}
+	return reversed
+}
+
+// sortableInputSlice is a slice of transaction inputs that satisfies the
+// sort.Interface interface to sort them into the order defined by BIP0069.
+type sortableInputSlice []*wire.TxIn
+
+// Len returns the number of inputs in the slice.  It is part of the
+// sort.Interface implementation.
+func (s sortableInputSlice) Len() int { return len(s) }
+
+// Swap swaps the inputs at the passed indices.  It is part of the
+// sort.Interface implementation.
+func (s sortableInputSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// Less returns whether the input with index i should sort before the input
+// with index j.  Inputs are ordered by the hash of the transaction they spend,
+// compared in displayed byte order, and then by the index of the output they
+// spend.  It is part of the sort.Interface implementation.
+func (s sortableInputSlice) Less(i, j int) bool {
+	iOutPoint := &s[i].PreviousOutPoint
+	jOutPoint := &s[j].PreviousOutPoint
+	if iOutPoint.Hash == jOutPoint.Hash {
+		return iOutPoint.Index < jOutPoint.Index
+	}
+
+	iHash := reversedHash(&iOutPoint.Hash)
+	jHash := reversedHash(&jOutPoint.Hash)
+	return bytes.Compare(iHash[:], jHash[:]) < 0
+}
+
+// sortableOutputSlice is a slice of transaction outputs that satisfies the
+// sort.Interface interface to sort them into the order defined by BIP0069.
+type sortableOutputSlice []*wire.TxOut
+
+// Len returns the number of outputs in the slice.  It is part of the
+// sort.Interface implementation.
+func (s sortableOutputSlice) Len() int { return len(s) }
+
+// Swap swaps the outputs at the passed indices.  It is part of the
+// sort.Interface implementation.
+func (s sortableOutputSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// Less returns whether the output with index i should sort before the output
+// with index j.  Outputs are ordered by amount and then by the bytes of their
+// public key script.  It is part of the sort.Interface implementation.
+func (s sortableOutputSlice) Less(i, j int) bool {
+	if s[i].Value == s[j].Value {
+		return bytes.Compare(s[i].PkScript, s[j].PkScript) < 0
+	}
+	return s[i].Value < s[j].Value
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txsort
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// hashFromStr converts the passed big-endian hex string into a hash and will
+// panic if there is an error.  This is only provided for the hard-coded
+// constants so errors in the source code can be detected.
+func hashFromStr(s string) chainhash.Hash {
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		panic("invalid hash in source file: " + s)
+	}
+	return *hash
+}
+
+// TestSortInputsOutputs ensures shuffled inputs and outputs are sorted into
+// the order defined by BIP0069 and that witnesses move with their inputs.
+func TestSortInputsOutputs(t *testing.T) {
+	t.Parallel()
+
+	// The hashes are chosen so that comparing them in their internal
+	// byte order would produce the opposite order to comparing them in
+	// the displayed byte order required by BIP0069.
+	hashA := hashFromStr("00000000000000000000000000000000000000000000" +
+		"000000000000000000ff")
+	hashB := hashFromStr("35288d269cee1941eaebb2ea85e32b42cdb2b04284a56d" +
+		"8b14dcc3f5c65d6055")
+	hashC := hashFromStr("ff00000000000000000000000000000000000000000000" +
+		"000000000000000000")
+
+	// newTxIn returns an input spending the passed outpoint whose witness
+	// identifies the outpoint so its alignment can be checked.
+	newTxIn := func(hash chainhash.Hash, index uint32) *wire.TxIn {
+		witness := wire.TxWitness{append(hash[:], byte(index))}
+		return &wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{Hash: hash, Index: index},
+			Witness:          witness,
+			Sequence:         wire.MaxTxInSequenceNum,
+		}
+	}
+
+	wantIns := []*wire.TxIn{
+		newTxIn(hashA, 0),
+		newTxIn(hashA, 1),
+		newTxIn(hashB, 0),
+		newTxIn(hashB, 1),
+		newTxIn(hashC, 0),
+	}
+	wantOuts := []*wire.TxOut{
+		wire.NewTxOut(0, []byte{0x6a}),
+		wire.NewTxOut(100000000, []byte{0x51}),
+		wire.NewTxOut(100000000, []byte{0x51, 0x51}),
+		wire.NewTxOut(100000000, []byte{0x52}),
+		wire.NewTxOut(2400000000, []byte{0x00}),
+	}
+
+	tx := wire.NewMsgTx(1)
+	for _, i := range []int{3, 4, 1, 2, 0} {
+		tx.AddTxIn(wantIns[i])
+	}
+	for _, i := range []int{4, 3, 1, 0, 2} {
+		tx.AddTxOut(wantOuts[i])
+	}
+	if IsSorted(tx) {
+		t.Fatalf("IsSorted: shuffled transaction reported as sorted")
+	}
+
+	SortInputsOutputs(tx)
+	if !IsSorted(tx) {
+		t.Fatalf("IsSorted: sorted transaction reported as unsorted")
+	}
+	for i, txIn := range tx.TxIn {
+		if txIn != wantIns[i] {
+			t.Errorf("input %d: mismatched outpoint - got %v, want %v",
+				i, txIn.PreviousOutPoint, wantIns[i].PreviousOutPoint)
+			continue
+		}
+		op := txIn.PreviousOutPoint
+		wantWitness := append(op.Hash[:], byte(op.Index))
+		if !bytes.Equal(txIn.Witness[0], wantWitness) {
+			t.Errorf("input %d: witness not aligned with input", i)
+		}
+	}
+	for i, txOut := range tx.TxOut {
+		if txOut != wantOuts[i] {
+			t.Errorf("output %d: mismatched output - got %d %x, "+
+				"want %d %x", i, txOut.Value, txOut.PkScript,
+				wantOuts[i].Value, wantOuts[i].PkScript)
+		}
+	}
+
+	// Sorting an already sorted transaction must not change it.
+	hash := tx.TxHash()
+	SortInputsOutputs(tx)
+	if got := tx.TxHash(); got != hash {
+		t.Errorf("SortInputsOutputs: sorted transaction changed - got "+
+			"%v, want %v", got, hash)
+	}
+}
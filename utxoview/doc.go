@@ -0,0 +1,23 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package utxoview provides an in-memory view of unspent transaction outputs
+which tracks the outputs created and spent while connecting blocks and can
+undo those changes when the blocks are disconnected during a reorganization.
+
+Unlike the view in the blockchain package, entries are keyed by individual
+outpoint and the view does not depend on a database, which makes it suitable
+for tooling and tests which need to follow the effects of blocks on the set of
+unspent outputs.
+
+Connecting a block with ConnectBlock spends the outputs referenced by the
+inputs of each transaction and adds the outputs of the transaction, in block
+order, so a transaction may spend an output created earlier in the same block.
+It returns the outputs it spent, in the order they were spent, which is the
+undo data required to disconnect the block again with DisconnectBlock.
+Disconnecting the most recently connected block with its undo data returns the
+view to exactly the state it was in before the block was connected.
+*/
+package utxoview
@@ -0,0 +1,178 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package utxoview
+
+import (
+	"fmt"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// Entry houses details about an unspent transaction output such as its amount,
+// public key script, and the height and kind of the transaction which created
+// it.
+type Entry struct {
+	Amount      int64
+	PkScript    []byte
+	BlockHeight int32
+	IsCoinBase  bool
+}
+
+// SpentOutput describes an output spent while connecting a block along with the
+// entry it had before it was spent.  A slice of them in the order the outputs
+// were spent is the undo data needed to disconnect the block.
+type SpentOutput struct {
+	OutPoint wire.OutPoint
+	Entry    *Entry
+}
+
+// View is a set of unspent transaction outputs keyed by outpoint.
+//
+// NOTE: A view is not safe for concurrent access.
+type View struct {
+	entries map[wire.OutPoint]*Entry
+}
+
+// New returns a new empty view.
+func New() *View {
+	return &View{
+		entries: make(map[wire.OutPoint]*Entry),
+	}
+}
+
+// isCoinBase returns whether the passed transaction is a coinbase, which has a
+// single input that does not reference a previous output.
+func isCoinBase(tx *wire.MsgTx) bool {
+	if len(tx.TxIn) != 1 {
+		return false
+	}
+	prevOut := &tx.TxIn[0].PreviousOutPoint
+	return prevOut.Index == wire.MaxPrevOutIndex &&
+		prevOut.Hash == (chainhash.Hash{})
+}
+
+// Len returns the number of unspent outputs in the view.
+func (v *View) Len() int {
+	return len(v.entries)
+}
+
+// FetchEntry returns the entry for the passed outpoint, or nil when the output
+// does not exist in the view or has been spent.
+func (v *View) FetchEntry(outPoint wire.OutPoint) *Entry {
+	return v.entries[outPoint]
+}
+
+// AddTxOuts adds all outputs of the passed transaction, which is contained in a
+// block at the passed height, to the view.  Existing entries for the outputs
+// are overwritten.
+func (v *View) AddTxOuts(tx *wire.MsgTx, blockHeight int32) {
+	txHash := tx.TxHash()
+	coinBase := isCoinBase(tx)
+	for i, txOut := range tx.TxOut {
+		outPoint := wire.OutPoint{Hash: txHash, Index: uint32(i)}
+		v.entries[outPoint] = &Entry{
+			Amount:      txOut.Value,
+			PkScript:    txOut.PkScript,
+			BlockHeight: blockHeight,
+			IsCoinBase:  coinBase,
+		}
+	}
+}
+
+// SpendOutpoint removes the passed outpoint from the view and returns the entry
+// it had.  An error is returned when the output does not exist in the view or
+// has already been spent.
+func (v *View) SpendOutpoint(outPoint wire.OutPoint) (*Entry, error) {
+	entry, ok := v.entries[outPoint]
+	if !ok {
+		return nil, fmt.Errorf("output %v does not exist or has already "+
+			"been spent", outPoint)
+	}
+	delete(v.entries, outPoint)
+	return entry, nil
+}
+
+// ConnectBlock updates the view for the passed block at the passed height by
+// spending the outputs referenced by the inputs of each transaction and adding
+// the outputs of each transaction in block order.  It returns the spent outputs
+// in the order they were spent, which must be passed to DisconnectBlock to undo
+// the changes.
+//
+// The view is left unmodified when an input references an output which does
+// not exist.
+func (v *View) ConnectBlock(block *wire.MsgBlock, blockHeight int32) ([]SpentOutput, error) {
+	var undo []SpentOutput
+	for i, tx := range block.Transactions {
+		if !isCoinBase(tx) {
+			txUndoStart := len(undo)
+			for _, txIn := range tx.TxIn {
+				outPoint := txIn.PreviousOutPoint
+				entry, err := v.SpendOutpoint(outPoint)
+				if err != nil {
+					// Restore the outputs already spent by
+					// this transaction and undo the prior
+					// transactions.
+					for _, spent := range undo[txUndoStart:] {
+						v.entries[spent.OutPoint] = spent.Entry
+					}
+					v.undoTransactions(block.Transactions[:i],
+						undo[:txUndoStart])
+					return nil, err
+				}
+				undo = append(undo, SpentOutput{outPoint, entry})
+			}
+		}
+		v.AddTxOuts(tx, blockHeight)
+	}
+	return undo, nil
+}
+
+// DisconnectBlock undoes the changes made to the view by connecting the passed
+// block, which must be the most recently connected block, given the undo data
+// returned when it was connected.  The outputs created by the block are
+// removed and the outputs it spent are restored.
+func (v *View) DisconnectBlock(block *wire.MsgBlock, undo []SpentOutput) error {
+	var numSpent int
+	for _, tx := range block.Transactions {
+		if !isCoinBase(tx) {
+			numSpent += len(tx.TxIn)
+		}
+	}
+	if numSpent != len(undo) {
+		return fmt.Errorf("block %v spends %d outputs but the undo data "+
+			"contains %d", block.BlockHash(), numSpent, len(undo))
+	}
+
+	v.undoTransactions(block.Transactions, undo)
+	return nil
+}
+
+// undoTransactions reverses the effects of connecting the passed transactions
+// in reverse order given the outputs they spent.  The outputs of each
+// transaction are removed before the outputs it spent are restored so outputs
+// spent within the same block are removed again when the transaction which
+// created them is undone.
+func (v *View) undoTransactions(txns []*wire.MsgTx, undo []SpentOutput) {
+	for i := len(txns) - 1; i >= 0; i-- {
+		tx := txns[i]
+		txHash := tx.TxHash()
+		for index := range tx.TxOut {
+			delete(v.entries, wire.OutPoint{Hash: txHash,
+				Index: uint32(index)})
+		}
+		if isCoinBase(tx) {
+			continue
+		}
+
+		// Restore the outputs spent by this transaction, which are the
+		// last entries of the undo data.
+		txUndo := undo[len(undo)-len(tx.TxIn):]
+		for _, spent := range txUndo {
+			v.entries[spent.OutPoint] = spent.Entry
+		}
+		undo = undo[:len(undo)-len(tx.TxIn)]
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package utxoview
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+	"github.com/navcoin/navd/wire"
+)
+
+// newCoinBase returns a coinbase transaction paying the passed amount which is
+// unique to the passed height.
+func newCoinBase(height int32, amount int64) *wire.MsgTx {
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{},
+			wire.MaxPrevOutIndex),
+		SignatureScript: []byte{0x01, byte(height)},
+		Sequence:        wire.MaxTxInSequenceNum,
+	})
+	tx.AddTxOut(wire.NewTxOut(amount, []byte{0x51}))
+	return tx
+}
+
+// newSpend returns a transaction spending the passed outpoints into outputs
+// with the passed amounts.
+func newSpend(outPoints []wire.OutPoint, amounts ...int64) *wire.MsgTx {
+	tx := wire.NewMsgTx(1)
+	for i := range outPoints {
+		tx.AddTxIn(wire.NewTxIn(&outPoints[i], nil, nil))
+	}
+	for _, amount := range amounts {
+		tx.AddTxOut(wire.NewTxOut(amount, []byte{0x52}))
+	}
+	return tx
+}
+
+// snapshot returns a copy of the entries of the passed view.
+func snapshot(v *View) map[wire.OutPoint]Entry {
+	entries := make(map[wire.OutPoint]Entry, len(v.entries))
+	for outPoint, entry := range v.entries {
+		entries[outPoint] = *entry
+	}
+	return entries
+}
+
+// TestConnectDisconnectBlock ensures connecting a block, including a
+// transaction spending an output created earlier in the same block, updates
+// the view as expected and that disconnecting it returns the view to its prior
+// state.
+func TestConnectDisconnectBlock(t *testing.T) {
+	t.Parallel()
+
+	// Create a view holding the outputs of a prior block.
+	view := New()
+	prevCoinBase := newCoinBase(1, 5000)
+	prevSpend := newSpend([]wire.OutPoint{{Index: 7}}, 1000, 2000)
+	view.AddTxOuts(prevCoinBase, 1)
+	view.AddTxOuts(prevSpend, 1)
+	prevCoinBaseOut := wire.OutPoint{Hash: prevCoinBase.TxHash()}
+	if entry := view.FetchEntry(prevCoinBaseOut); entry == nil ||
+		!entry.IsCoinBase || entry.Amount != 5000 ||
+		entry.BlockHeight != 1 {
+
+		t.Fatalf("FetchEntry: unexpected coinbase entry %+v", entry)
+	}
+	before := snapshot(view)
+
+	// Create a block which spends the prior coinbase and one output of the
+	// prior spend, then spends one of the resulting outputs again in the
+	// same block.
+	txA := newSpend([]wire.OutPoint{prevCoinBaseOut,
+		{Hash: prevSpend.TxHash(), Index: 1}}, 4000, 2500)
+	txB := newSpend([]wire.OutPoint{{Hash: txA.TxHash(), Index: 0}}, 3900)
+	block := &wire.MsgBlock{
+		Transactions: []*wire.MsgTx{newCoinBase(2, 5000), txA, txB},
+	}
+
+	undo, err := view.ConnectBlock(block, 2)
+	if err != nil {
+		t.Fatalf("ConnectBlock: unexpected error: %v", err)
+	}
+	if len(undo) != 3 {
+		t.Fatalf("ConnectBlock: unexpected undo data length %d",
+			len(undo))
+	}
+	if undo[2].OutPoint != txB.TxIn[0].PreviousOutPoint ||
+		undo[2].Entry.Amount != 4000 || undo[2].Entry.BlockHeight != 2 {
+
+		t.Fatalf("ConnectBlock: unexpected same block undo entry %+v",
+			undo[2])
+	}
+
+	// The spent outputs, including the one created and spent within the
+	// block, must be gone while the unspent ones remain.
+	spent := []wire.OutPoint{prevCoinBaseOut, txA.TxIn[1].PreviousOutPoint,
+		txB.TxIn[0].PreviousOutPoint}
+	for _, outPoint := range spent {
+		if entry := view.FetchEntry(outPoint); entry != nil {
+			t.Errorf("FetchEntry: spent output %v still exists",
+				outPoint)
+		}
+	}
+	unspent := []wire.OutPoint{{Hash: prevSpend.TxHash(), Index: 0},
+		{Hash: txA.TxHash(), Index: 1}, {Hash: txB.TxHash(), Index: 0},
+		{Hash: block.Transactions[0].TxHash(), Index: 0}}
+	for _, outPoint := range unspent {
+		if entry := view.FetchEntry(outPoint); entry == nil {
+			t.Errorf("FetchEntry: unspent output %v does not exist",
+				outPoint)
+		}
+	}
+	if view.Len() != len(unspent) {
+		t.Errorf("Len: unexpected number of entries - got %d, want %d",
+			view.Len(), len(unspent))
+	}
+
+	// Undo data with the wrong number of spent outputs must be rejected.
+	if err := view.DisconnectBlock(block, undo[:2]); err == nil {
+		t.Fatalf("DisconnectBlock: did not reject short undo data")
+	}
+
+	if err := view.DisconnectBlock(block, undo); err != nil {
+		t.Fatalf("DisconnectBlock: unexpected error: %v", err)
+	}
+	if after := snapshot(view); !reflect.DeepEqual(after, before) {
+		t.Fatalf("DisconnectBlock: view not restored - got %v, want %v",
+			after, before)
+	}
+}
+
+// TestConnectBlockMissingOutput ensures connecting a block which spends an
+// output that does not exist fails and leaves the view unmodified.
+func TestConnectBlockMissingOutput(t *testing.T) {
+	t.Parallel()
+
+	view := New()
+	prevCoinBase := newCoinBase(1, 5000)
+	view.AddTxOuts(prevCoinBase, 1)
+	before := snapshot(view)
+
+	prevCoinBaseOut := wire.OutPoint{Hash: prevCoinBase.TxHash()}
+	txA := newSpend([]wire.OutPoint{prevCoinBaseOut}, 4000)
+	txB := newSpend([]wire.OutPoint{{Hash: txA.TxHash(), Index: 0},
+		{Hash: txA.TxHash(), Index: 1}}, 3900)
+	block := &wire.MsgBlock{
+		Transactions: []*wire.MsgTx{newCoinBase(2, 5000), txA, txB},
+	}
+
+	if _, err := view.ConnectBlock(block, 2); err == nil {
+		t.Fatalf("ConnectBlock: did not reject missing output")
+	}
+	if after := snapshot(view); !reflect.DeepEqual(after, before) {
+		t.Fatalf("ConnectBlock: view modified - got %v, want %v",
+			after, before)
+	}
+
+	// Spending the same output twice must fail the second time.
+	if _, err := view.SpendOutpoint(prevCoinBaseOut); err != nil {
+		t.Fatalf("SpendOutpoint: unexpected error: %v", err)
+	}
+	if _, err := view.SpendOutpoint(prevCoinBaseOut); err == nil {
+		t.Fatalf("SpendOutpoint: did not reject spent output")
+	}
+}
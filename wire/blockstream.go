@@ -0,0 +1,106 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// BlockStreamReader decodes a serialized block from an io.Reader one
+// transaction at a time.  Unlike MsgBlock.Deserialize, it never materializes
+// the full slice of transactions, which makes it suitable for tools that only
+// need the block header or a handful of transactions from large blocks.
+//
+// The block header is decoded when the reader is created and is available via
+// the Header field.  Transactions are then decoded on demand by calling Next
+// until it returns io.EOF.
+type BlockStreamReader struct {
+	// Header is the header of the block being streamed.
+	Header BlockHeader
+
+	r       io.Reader
+	pver    uint32
+	enc     MessageEncoding
+	txCount uint64
+	txRead  uint64
+	err     error
+}
+
+// NewBlockStreamReader returns a new block stream reader which decodes a block
+// from r using the same long-term storage format as MsgBlock.Deserialize.  The
+// block header and transaction count are read immediately, so an error is
+// returned if either of them is malformed.
+func NewBlockStreamReader(r io.Reader) (*BlockStreamReader, error) {
+	// At the current time, there is no difference between the wire encoding
+	// at protocol version 0 and the stable long-term storage format.  As
+	// a result, make use of the wire protocol functions just as
+	// MsgBlock.Deserialize does.
+	const pver = 0
+	s := BlockStreamReader{r: r, pver: pver, enc: WitnessEncoding}
+	err := readBlockHeader(r, pver, &s.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	txCount, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prevent more transactions than could possibly fit into a block.
+	// It would be possible to cause memory exhaustion and panics without
+	// a sane upper bound on this count.
+	if txCount > maxTxPerBlock {
+		str := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", txCount, maxTxPerBlock)
+		return nil, messageError("NewBlockStreamReader", str)
+	}
+	s.txCount = txCount
+
+	return &s, nil
+}
+
+// TxCount returns the total number of transactions in the block as declared
+// by its serialized transaction count.
+func (s *BlockStreamReader) TxCount() uint64 {
+	return s.txCount
+}
+
+// Remaining returns the number of transactions which have not been read yet.
+func (s *BlockStreamReader) Remaining() uint64 {
+	return s.txCount - s.txRead
+}
+
+// Next decodes and returns the next transaction of the block.  io.EOF is
+// returned once all of the transactions declared by the transaction count have
+// been read.  Should the underlying reader end before a transaction is fully
+// decoded, io.ErrUnexpectedEOF is returned instead.
+//
+// Once an error other than io.EOF has been returned, the stream is no longer
+// usable and all subsequent calls return the same error.
+func (s *BlockStreamReader) Next() (*MsgTx, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.txRead == s.txCount {
+		return nil, io.EOF
+	}
+
+	tx := MsgTx{}
+	err := tx.BtcDecode(s.r, s.pver, s.enc)
+	if err != nil {
+		// A clean EOF in the middle of the declared transactions
+		// means the block was truncated.
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		s.err = err
+		return nil, err
+	}
+	s.txRead++
+
+	return &tx, nil
+}
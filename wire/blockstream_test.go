@@ -0,0 +1,143 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// TestBlockStreamReader tests streaming the transactions of a serialized
+// multi-transaction block and ensures each transaction matches the one
+// produced by fully decoding the block.
+func TestBlockStreamReader(t *testing.T) {
+	block := NewMsgBlock(&blockOne.Header)
+	block.AddTransaction(blockOne.Transactions[0])
+	block.AddTransaction(multiTx)
+	block.AddTransaction(multiWitnessTx)
+
+	var buf bytes.Buffer
+	if err := block.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	serialized := buf.Bytes()
+
+	var fullBlock MsgBlock
+	err := fullBlock.Deserialize(bytes.NewReader(serialized))
+	if err != nil {
+		t.Fatalf("Deserialize: unexpected error: %v", err)
+	}
+
+	stream, err := NewBlockStreamReader(bytes.NewReader(serialized))
+	if err != nil {
+		t.Fatalf("NewBlockStreamReader: unexpected error: %v", err)
+	}
+	if stream.Header != fullBlock.Header {
+		t.Fatalf("NewBlockStreamReader: mismatched header - got %v, "+
+			"want %v", stream.Header, fullBlock.Header)
+	}
+	if stream.TxCount() != uint64(len(fullBlock.Transactions)) {
+		t.Fatalf("TxCount: got %d, want %d", stream.TxCount(),
+			len(fullBlock.Transactions))
+	}
+
+	for i, wantTx := range fullBlock.Transactions {
+		tx, err := stream.Next()
+		if err != nil {
+			t.Fatalf("Next #%d: unexpected error: %v", i, err)
+		}
+		if !TxEqual(tx, wantTx, true) {
+			t.Fatalf("Next #%d: mismatched transaction - got %v, "+
+				"want %v", i, tx, wantTx)
+		}
+		wantRemaining := uint64(len(fullBlock.Transactions) - i - 1)
+		if stream.Remaining() != wantRemaining {
+			t.Fatalf("Remaining #%d: got %d, want %d", i,
+				stream.Remaining(), wantRemaining)
+		}
+	}
+
+	// All transactions have been read, so the stream must report EOF and
+	// keep doing so.
+	for i := 0; i < 2; i++ {
+		if _, err := stream.Next(); err != io.EOF {
+			t.Fatalf("Next: got error %v, want %v", err, io.EOF)
+		}
+	}
+}
+
+// TestBlockStreamReaderErrors performs negative tests against streaming
+// truncated and malformed blocks.
+func TestBlockStreamReaderErrors(t *testing.T) {
+	block := NewMsgBlock(&blockOne.Header)
+	block.AddTransaction(multiTx)
+	block.AddTransaction(multiTx)
+
+	var buf bytes.Buffer
+	if err := block.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	serialized := buf.Bytes()
+
+	// Truncating within the header or the transaction count must cause
+	// the creation of the stream to fail.
+	for _, n := range []int{0, blockHeaderLen - 1, blockHeaderLen} {
+		_, err := NewBlockStreamReader(bytes.NewReader(serialized[:n]))
+		if err == nil {
+			t.Errorf("NewBlockStreamReader: did not receive error "+
+				"for block truncated to %d bytes", n)
+		}
+	}
+
+	// A transaction count larger than can possibly fit into a block must
+	// be rejected.
+	overflow := make([]byte, blockHeaderLen, blockHeaderLen+9)
+	copy(overflow, serialized)
+	overflow = append(overflow, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff)
+	_, err := NewBlockStreamReader(bytes.NewReader(overflow))
+	if _, ok := err.(*MessageError); !ok {
+		t.Errorf("NewBlockStreamReader: got error %v, want %T", err,
+			&MessageError{})
+	}
+
+	// Truncating the block at the boundary of the second transaction must
+	// yield the first transaction followed by an unexpected EOF.
+	var txBuf bytes.Buffer
+	if err := multiTx.Serialize(&txBuf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	txLen := txBuf.Len()
+	truncated := serialized[:blockHeaderLen+1+txLen]
+	stream, err := NewBlockStreamReader(bytes.NewReader(truncated))
+	if err != nil {
+		t.Fatalf("NewBlockStreamReader: unexpected error: %v", err)
+	}
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("Next: unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := stream.Next(); err != io.ErrUnexpectedEOF {
+			t.Fatalf("Next: got error %v, want %v", err,
+				io.ErrUnexpectedEOF)
+		}
+	}
+
+	// Truncating in the middle of the second transaction must also be
+	// reported as an error.
+	truncated = serialized[:len(serialized)-1]
+	stream, err = NewBlockStreamReader(bytes.NewReader(truncated))
+	if err != nil {
+		t.Fatalf("NewBlockStreamReader: unexpected error: %v", err)
+	}
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("Next: unexpected error: %v", err)
+	}
+	if _, err := stream.Next(); err == nil {
+		t.Fatalf("Next: did not receive error for truncated " +
+			"transaction")
+	}
+}
@@ -533,6 +533,19 @@ func ReadVarInt(r io.Reader, pver uint32) (uint64, error) {
 	return rv, nil
 }
 
+// ReadVarIntStrict reads a variable length integer from r and returns it as a
+// uint64.  A MessageError is returned if the value is not canonically encoded,
+// that is, if it could have been encoded using fewer bytes (for example, a
+// value which fits in a single byte but is encoded using three).
+//
+// NOTE: ReadVarInt already rejects non-canonical encodings for all protocol
+// versions, so this is equivalent to calling it with a protocol version of 0.
+// It is provided so code which audits serialized data can make its reliance on
+// strict decoding explicit without needing a protocol version.
+func ReadVarIntStrict(r io.Reader) (uint64, error) {
+	return ReadVarInt(r, 0)
+}
+
 // WriteVarInt serializes val to w using a variable number of bytes depending
 // on its value.
 func WriteVarInt(w io.Writer, pver uint32, val uint64) error {
@@ -410,6 +410,90 @@ func TestVarIntNonCanonical(t *testing.T) {
 	}
 }
 
+// TestReadVarIntStrict ensures ReadVarIntStrict accepts canonically encoded
+// variable length integers at each encoding boundary and rejects every
+// non-minimal form.
+func TestReadVarIntStrict(t *testing.T) {
+	tests := []struct {
+		name      string // Test name for easier identification
+		in        []byte // Value to decode
+		out       uint64 // Expected decoded value
+		canonical bool   // Whether the encoding is canonical
+	}{
+		// Canonical encodings.
+		{"single byte 0", []byte{0x00}, 0, true},
+		{"max single byte", []byte{0xfc}, 0xfc, true},
+		{"min 3 bytes", []byte{0xfd, 0xfd, 0x00}, 0xfd, true},
+		{"max 3 bytes", []byte{0xfd, 0xff, 0xff}, 0xffff, true},
+		{"min 5 bytes", []byte{0xfe, 0x00, 0x00, 0x01, 0x00}, 0x10000, true},
+		{"max 5 bytes", []byte{0xfe, 0xff, 0xff, 0xff, 0xff}, 0xffffffff, true},
+		{
+			"min 9 bytes",
+			[]byte{0xff, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00},
+			0x100000000, true,
+		},
+		{
+			"max 9 bytes",
+			[]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			0xffffffffffffffff, true,
+		},
+
+		// Non-minimal encodings.
+		{"max single byte in 3 bytes", []byte{0xfd, 0xfc, 0x00}, 0, false},
+		{"max single byte in 5 bytes", []byte{0xfe, 0xfc, 0x00, 0x00, 0x00}, 0, false},
+		{"max 3 bytes in 5 bytes", []byte{0xfe, 0xff, 0xff, 0x00, 0x00}, 0, false},
+		{
+			"max single byte in 9 bytes",
+			[]byte{0xff, 0xfc, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			0, false,
+		},
+		{
+			"max 3 bytes in 9 bytes",
+			[]byte{0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			0, false,
+		},
+		{
+			"max 5 bytes in 9 bytes",
+			[]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00},
+			0, false,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		val, err := ReadVarIntStrict(bytes.NewReader(test.in))
+		if !test.canonical {
+			if _, ok := err.(*MessageError); !ok {
+				t.Errorf("ReadVarIntStrict #%d (%s) unexpected "+
+					"error %v", i, test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ReadVarIntStrict #%d (%s) error %v", i,
+				test.name, err)
+			continue
+		}
+		if val != test.out {
+			t.Errorf("ReadVarIntStrict #%d (%s)\n got: %d want: %d",
+				i, test.name, val, test.out)
+			continue
+		}
+
+		// Canonical encodings must round trip to the same bytes.
+		var buf bytes.Buffer
+		if err := WriteVarInt(&buf, 0, val); err != nil {
+			t.Errorf("WriteVarInt #%d (%s) error %v", i, test.name,
+				err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.in) {
+			t.Errorf("WriteVarInt #%d (%s)\n got: %x want: %x", i,
+				test.name, buf.Bytes(), test.in)
+		}
+	}
+}
+
 // TestVarIntWire tests the serialize size for variable length integers.
 func TestVarIntSerializeSize(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,70 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build gofuzz
+
+package wire
+
+// This file provides a target for go-fuzz (https://github.com/dvyukov/go-fuzz)
+// which decodes arbitrary bytes as a transaction.  It is only built by
+// go-fuzz-build, which sets the gofuzz build tag.  A seed corpus is provided in
+// testdata/fuzz/corpus, so the target may be run from the wire directory with,
+// for example:
+//
+//   go-fuzz-build -func FuzzMsgTx github.com/navcoin/navd/wire
+//   go-fuzz -bin wire-fuzz.zip -workdir testdata/fuzz
+//
+// The seed corpus holds the genesis coinbase, real transactions from mainnet
+// block 277647 (blockchain/testdata/277647.dat.bz2) encoded with the time and
+// strdzeel fields, and synthetic transactions which exercise edge cases such as
+// witness data, strdzeel payloads and malformed counts.
+//
+// The target panics when an invariant is violated so go-fuzz records the input
+// as a crasher.
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// FuzzMsgTx decodes the passed data as a transaction using the witness
+// encoding and ensures that any transaction which decodes successfully
+// re-encodes to exactly the bytes it was decoded from, so the decoder accepts
+// no non-canonical encodings.  It also ensures the serialized sizes reported
+// for the transaction agree with its encodings.
+func FuzzMsgTx(data []byte) int {
+	r := bytes.NewReader(data)
+	var msg MsgTx
+	if err := msg.BtcDecode(r, ProtocolVersion, WitnessEncoding); err != nil {
+		return 0
+	}
+	consumed := data[:len(data)-r.Len()]
+
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, ProtocolVersion, WitnessEncoding); err != nil {
+		panic(fmt.Sprintf("unable to encode decoded transaction %x: %v",
+			consumed, err))
+	}
+	if !bytes.Equal(buf.Bytes(), consumed) {
+		panic(fmt.Sprintf("transaction %x re-encoded as %x", consumed,
+			buf.Bytes()))
+	}
+	if msg.SerializeSize() != buf.Len() {
+		panic(fmt.Sprintf("serialize size %d of transaction %x does not "+
+			"match encoded length %d", msg.SerializeSize(), consumed,
+			buf.Len()))
+	}
+
+	buf.Reset()
+	if err := msg.SerializeNoWitness(&buf); err != nil {
+		panic(fmt.Sprintf("unable to encode transaction %x without "+
+			"witness data: %v", consumed, err))
+	}
+	if msg.SerializeSizeStripped() != buf.Len() {
+		panic(fmt.Sprintf("stripped serialize size %d of transaction %x "+
+			"does not match encoded length %d",
+			msg.SerializeSizeStripped(), consumed, buf.Len()))
+	}
+	return 1
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+// InvBatcher accumulates inventory vectors to announce and groups them into
+// inv messages of at most MaxInvPerMsg inventory vectors each.  Duplicate
+// inventory vectors are only included once per message, where inventory
+// vectors are considered duplicates when both their type and hash match.
+//
+// An InvBatcher is not safe for concurrent access.
+type InvBatcher struct {
+	full    []*MsgInv
+	current *MsgInv
+	seen    map[InvVect]struct{}
+}
+
+// NewInvBatcher returns a new inventory batcher with no pending inventory.
+func NewInvBatcher() *InvBatcher {
+	return &InvBatcher{
+		seen: make(map[InvVect]struct{}),
+	}
+}
+
+// Add queues the passed inventory vector to be announced.  It is ignored when
+// the same inventory vector is already queued in the message being built.
+// Once the message reaches MaxInvPerMsg inventory vectors it is set aside to
+// be returned by Flush and a new message is started.
+func (b *InvBatcher) Add(iv InvVect) {
+	if _, ok := b.seen[iv]; ok {
+		return
+	}
+	if b.current == nil {
+		b.current = NewMsgInvSizeHint(defaultInvListAlloc)
+	}
+
+	// AddInvVect can't fail since full messages are set aside below.
+	b.seen[iv] = struct{}{}
+	_ = b.current.AddInvVect(&iv)
+
+	if len(b.current.InvList) == MaxInvPerMsg {
+		b.full = append(b.full, b.current)
+		b.current = nil
+		b.seen = make(map[InvVect]struct{})
+	}
+}
+
+// Len returns the number of inventory vectors queued across all pending
+// messages.
+func (b *InvBatcher) Len() int {
+	n := len(b.full) * MaxInvPerMsg
+	if b.current != nil {
+		n += len(b.current.InvList)
+	}
+	return n
+}
+
+// Flush returns the pending inv messages in the order their inventory vectors
+// were added and resets the batcher.  Every message except for the last one
+// holds exactly MaxInvPerMsg inventory vectors.  No messages are returned when
+// nothing is pending.
+func (b *InvBatcher) Flush() []*MsgInv {
+	msgs := b.full
+	if b.current != nil {
+		msgs = append(msgs, b.current)
+	}
+
+	b.full = nil
+	b.current = nil
+	b.seen = make(map[InvVect]struct{})
+	return msgs
+}
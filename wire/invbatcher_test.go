@@ -0,0 +1,121 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// TestInvBatcher tests the InvBatcher API.
+func TestInvBatcher(t *testing.T) {
+	// invVect returns an inventory vector of the given type with a hash
+	// derived from the passed number.
+	invVect := func(typ InvType, n int) InvVect {
+		var hash chainhash.Hash
+		binary.LittleEndian.PutUint32(hash[:], uint32(n))
+		return InvVect{Type: typ, Hash: hash}
+	}
+
+	// Ensure nothing is returned when nothing was added.
+	b := NewInvBatcher()
+	if msgs := b.Flush(); len(msgs) != 0 {
+		t.Fatalf("Flush: got %d messages from empty batcher", len(msgs))
+	}
+
+	// Ensure duplicates are removed while inventory vectors of different
+	// types with the same hash are kept in the order they were added.
+	tx, block := invVect(InvTypeTx, 1), invVect(InvTypeBlock, 1)
+	other := invVect(InvTypeTx, 2)
+	for _, iv := range []InvVect{tx, block, tx, other, block} {
+		b.Add(iv)
+	}
+	if b.Len() != 3 {
+		t.Fatalf("Len: got %d, want 3", b.Len())
+	}
+	msgs := b.Flush()
+	want := []*InvVect{&tx, &block, &other}
+	if len(msgs) != 1 || !reflect.DeepEqual(msgs[0].InvList, want) {
+		t.Fatalf("Flush: unexpected messages %v, want single message "+
+			"with %v", msgs, want)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("Len: got %d after flush, want 0", b.Len())
+	}
+
+	// Ensure a flushed inventory vector may be queued again.
+	b.Add(tx)
+	if msgs := b.Flush(); len(msgs) != 1 || len(msgs[0].InvList) != 1 {
+		t.Fatalf("Flush: unexpected messages after re-adding %v: %v",
+			tx, msgs)
+	}
+
+	tests := []struct {
+		name    string
+		numInvs int
+		want    []int // number of inventory vectors in each message
+	}{
+		{"one below limit", MaxInvPerMsg - 1, []int{MaxInvPerMsg - 1}},
+		{"exactly at limit", MaxInvPerMsg, []int{MaxInvPerMsg}},
+		{"one over limit", MaxInvPerMsg + 1, []int{MaxInvPerMsg, 1}},
+		{"two full batches", MaxInvPerMsg * 2,
+			[]int{MaxInvPerMsg, MaxInvPerMsg}},
+	}
+
+	for _, test := range tests {
+		// Alternate between transaction and block inventory so both
+		// are present in each batch.
+		b := NewInvBatcher()
+		for i := 0; i < test.numInvs; i++ {
+			typ := InvTypeTx
+			if i%2 == 1 {
+				typ = InvTypeBlock
+			}
+			b.Add(invVect(typ, i))
+		}
+		if b.Len() != test.numInvs {
+			t.Errorf("%s: Len: got %d, want %d", test.name, b.Len(),
+				test.numInvs)
+			continue
+		}
+
+		msgs := b.Flush()
+		got := make([]int, len(msgs))
+		for i, msg := range msgs {
+			got[i] = len(msg.InvList)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: unexpected batch sizes - got %v, want %v",
+				test.name, got, test.want)
+			continue
+		}
+
+		// Ensure the inventory vectors were batched in order.
+		if len(msgs) > 1 {
+			first := *msgs[1].InvList[0]
+			if want := invVect(first.Type, MaxInvPerMsg); first != want {
+				t.Errorf("%s: unexpected first inventory in "+
+					"second batch - got %v, want %v",
+					test.name, first, want)
+			}
+		}
+	}
+
+	// Ensure dedup only applies within a batch, so an inventory vector
+	// queued in a full batch may be queued again in the next one.
+	b = NewInvBatcher()
+	for i := 0; i < MaxInvPerMsg; i++ {
+		b.Add(invVect(InvTypeTx, i))
+	}
+	b.Add(invVect(InvTypeTx, 0))
+	msgs = b.Flush()
+	if len(msgs) != 2 || len(msgs[1].InvList) != 1 {
+		t.Fatalf("Flush: inventory from a full batch was not queued " +
+			"in the next batch")
+	}
+}
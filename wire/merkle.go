@@ -0,0 +1,87 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// CalcMerkleRoot returns the root of the merkle tree of the passed transaction
+// hashes as committed to by the merkle root of a block header.  The last hash
+// of a level with an odd number of hashes is paired with itself, so the root of
+// a single transaction is its hash.  An all zero hash is returned when no
+// hashes are provided.
+//
+// Since the last hash is duplicated, a list of hashes ending with a duplicated
+// pair has the same root as the list without the duplicate (CVE-2012-2459).
+// Callers validating blocks should use CalcMerkleRootMutated to detect this.
+func CalcMerkleRoot(txHashes []chainhash.Hash) chainhash.Hash {
+	root, _ := CalcMerkleRootMutated(txHashes)
+	return root
+}
+
+// CalcMerkleRootMutated returns the root of the merkle tree of the passed
+// transaction hashes along with whether the tree is mutated.  A tree is mutated
+// when any level contains two identical adjacent hashes which are paired with
+// each other, which is the case for a block containing a duplicated
+// transaction that produces the same merkle root as a valid block
+// (CVE-2012-2459).  A block whose tree is mutated must be rejected without
+// marking its header invalid, since the same header commits to a valid block.
+func CalcMerkleRootMutated(txHashes []chainhash.Hash) (chainhash.Hash, bool) {
+	if len(txHashes) == 0 {
+		return chainhash.Hash{}, false
+	}
+
+	hashes := make([]chainhash.Hash, len(txHashes))
+	copy(hashes, txHashes)
+	return calcMerkleRoot(hashes)
+}
+
+// calcMerkleRoot reduces the passed non-empty slice of hashes to the root of
+// their merkle tree, overwriting the slice in the process, and reports whether
+// two identical hashes were paired with each other before any odd hash was
+// duplicated.
+func calcMerkleRoot(hashes []chainhash.Hash) (chainhash.Hash, bool) {
+	var mutated bool
+	var buf [chainhash.HashSize * 2]byte
+	for len(hashes) > 1 {
+		for i := 0; i+1 < len(hashes); i += 2 {
+			if hashes[i] == hashes[i+1] {
+				mutated = true
+			}
+		}
+		if len(hashes)%2 != 0 {
+			hashes = append(hashes, hashes[len(hashes)-1])
+		}
+		for i := 0; i < len(hashes)/2; i++ {
+			copy(buf[:chainhash.HashSize], hashes[i*2][:])
+			copy(buf[chainhash.HashSize:], hashes[i*2+1][:])
+			hashes[i] = chainhash.DoubleHashH(buf[:])
+		}
+		hashes = hashes[:len(hashes)/2]
+	}
+	return hashes[0], mutated
+}
+
+// CalcWitnessMerkleRoot returns the root of the merkle tree of the witness
+// hashes (wtxids) of the passed transactions as committed to by the witness
+// commitment of a segwit block.  The first transaction is taken to be the
+// coinbase, whose wtxid is defined to be all zeros by BIP0141, so a block with
+// only a coinbase transaction has an all zero witness merkle root.  As with
+// the transaction merkle root, the last hash of a level with an odd number of
+// hashes is paired with itself.  An all zero hash is returned when no
+// transactions are provided.
+func CalcWitnessMerkleRoot(txs []*MsgTx) chainhash.Hash {
+	if len(txs) == 0 {
+		return chainhash.Hash{}
+	}
+
+	hashes := make([]chainhash.Hash, len(txs))
+	for i := 1; i < len(txs); i++ {
+		hashes[i] = txs[i].WitnessHash()
+	}
+	root, _ := calcMerkleRoot(hashes)
+	return root
+}
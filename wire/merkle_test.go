@@ -0,0 +1,179 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// TestCalcWitnessMerkleRoot ensures the witness merkle root is calculated
+// over the witness hashes of the transactions with the coinbase hash zeroed.
+func TestCalcWitnessMerkleRoot(t *testing.T) {
+	t.Parallel()
+
+	// newTx returns a transaction spending the passed outpoint index with
+	// the passed witness.
+	newTx := func(index uint32, witness TxWitness) *MsgTx {
+		tx := NewMsgTx(1)
+		tx.AddTxIn(&TxIn{
+			PreviousOutPoint: OutPoint{Index: index},
+			Witness:          witness,
+			Sequence:         MaxTxInSequenceNum,
+		})
+		tx.AddTxOut(NewTxOut(1000, []byte{0x51}))
+		return tx
+	}
+
+	// hashPair returns the double sha256 of the concatenation of the two
+	// passed hashes.
+	hashPair := func(a, b chainhash.Hash) chainhash.Hash {
+		return chainhash.DoubleHashH(append(a[:], b[:]...))
+	}
+
+	coinbase := newTx(MaxPrevOutIndex, TxWitness{make([]byte, 32)})
+	tx1 := newTx(1, TxWitness{{0x01, 0x02}, {0x03}})
+	tx2 := newTx(2, TxWitness{{0x04}})
+	var zero chainhash.Hash
+
+	// The witness hash of a transaction with witness data differs from its
+	// transaction hash, so ensure the witness hash is the one committed.
+	if tx1.WitnessHash() == tx1.TxHash() {
+		t.Fatalf("test transaction witness hash matches its hash")
+	}
+
+	tests := []struct {
+		name string
+		txs  []*MsgTx
+		want chainhash.Hash
+	}{
+		{
+			name: "no transactions",
+			txs:  nil,
+			want: zero,
+		},
+		{
+			name: "coinbase only",
+			txs:  []*MsgTx{coinbase},
+			want: zero,
+		},
+		{
+			name: "coinbase and one segwit transaction",
+			txs:  []*MsgTx{coinbase, tx1},
+			want: hashPair(zero, tx1.WitnessHash()),
+		},
+		{
+			name: "odd number of transactions",
+			txs:  []*MsgTx{coinbase, tx1, tx2},
+			want: hashPair(hashPair(zero, tx1.WitnessHash()),
+				hashPair(tx2.WitnessHash(), tx2.WitnessHash())),
+		},
+	}
+
+	for _, test := range tests {
+		got := CalcWitnessMerkleRoot(test.txs)
+		if got != test.want {
+			t.Errorf("%s: mismatched root - got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestCalcMerkleRoot ensures the merkle root of a known block is calculated
+// from its transaction hashes and that trees with a duplicated pair of hashes
+// are reported as mutated.
+func TestCalcMerkleRoot(t *testing.T) {
+	t.Parallel()
+
+	// Transaction hashes and merkle root of block 100000 of the bitcoin
+	// main network.
+	hashStrs := []string{
+		"8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87",
+		"fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4",
+		"6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec4",
+		"e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d",
+	}
+	hashes := make([]chainhash.Hash, 0, len(hashStrs))
+	for _, hashStr := range hashStrs {
+		hash, err := chainhash.NewHashFromStr(hashStr)
+		if err != nil {
+			t.Fatalf("NewHashFromStr: unexpected error: %v", err)
+		}
+		hashes = append(hashes, *hash)
+	}
+	wantRoot, err := chainhash.NewHashFromStr("f3e94742aca4b5ef85488dc37c0" +
+		"6c3282295ffec960994b2c0d5ac2a25a95766")
+	if err != nil {
+		t.Fatalf("NewHashFromStr: unexpected error: %v", err)
+	}
+
+	sixHashes := []chainhash.Hash{hashes[0], hashes[1], hashes[2],
+		hashes[3], hashes[0], hashes[2]}
+
+	tests := []struct {
+		name    string
+		hashes  []chainhash.Hash
+		want    chainhash.Hash
+		mutated bool
+	}{
+		{
+			name:   "no hashes",
+			hashes: nil,
+			want:   chainhash.Hash{},
+		},
+		{
+			name:   "single transaction",
+			hashes: hashes[:1],
+			want:   hashes[0],
+		},
+		{
+			name:   "block 100000",
+			hashes: hashes,
+			want:   *wantRoot,
+		},
+		{
+			// The odd last hash is duplicated, so appending a copy of
+			// it produces the same root but is a mutated tree.
+			name:    "duplicated last transaction",
+			hashes:  append(hashes[:3:3], hashes[2]),
+			want:    CalcMerkleRoot(hashes[:3]),
+			mutated: true,
+		},
+		{
+			// Identical hashes which are not paired with each other
+			// do not mutate the tree.
+			name:   "unpaired duplicate transactions",
+			hashes: sixHashes,
+			want:   CalcMerkleRoot(sixHashes),
+		},
+		{
+			// The odd last pair of the second level is duplicated,
+			// so appending a copy of the last two hashes produces
+			// the same root but is a mutated tree.
+			name: "duplicated last pair",
+			hashes: append(sixHashes[:6:6], sixHashes[4],
+				sixHashes[5]),
+			want:    CalcMerkleRoot(sixHashes),
+			mutated: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, mutated := CalcMerkleRootMutated(test.hashes)
+		if got != test.want {
+			t.Errorf("%s: mismatched root - got %v, want %v",
+				test.name, got, test.want)
+		}
+		if mutated != test.mutated {
+			t.Errorf("%s: mismatched mutated flag - got %v, want %v",
+				test.name, mutated, test.mutated)
+		}
+		if root := CalcMerkleRoot(test.hashes); root != got {
+			t.Errorf("%s: CalcMerkleRoot mismatched root - got %v, "+
+				"want %v", test.name, root, got)
+		}
+	}
+}
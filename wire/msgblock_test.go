@@ -557,6 +557,7 @@ var blockOneBytes = []byte{
 	0x01, 0xe3, 0x62, 0x99, // Nonce
 	0x01,                   // TxnCount
 	0x01, 0x00, 0x00, 0x00, // Version
+	0x00, 0x00, 0x00, 0x00, // Time
 	0x01, // Varint for number of transaction inputs
 	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
@@ -581,9 +582,10 @@ var blockOneBytes = []byte{
 	0xee,                   // 65-byte uncompressed public key
 	0xac,                   // OP_CHECKSIG
 	0x00, 0x00, 0x00, 0x00, // Lock time
+	0x00, // Varint for length of strdzeel
 }
 
 // Transaction location information for block one transactions.
 var blockOneTxLocs = []TxLoc{
-	{TxStart: 81, TxLen: 134},
+	{TxStart: 81, TxLen: 139},
 }
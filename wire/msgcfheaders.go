@@ -165,3 +165,48 @@ func NewMsgCFHeaders() *MsgCFHeaders {
 		HeaderHashes: make([]*chainhash.Hash, 0, MaxCFHeadersPerMsg),
 	}
 }
+
+// MakeFilterHeader returns the committed filter header for a filter with the
+// passed hash, which is the double sha256 of the filter hash followed by the
+// header of the filter for the previous block.  The previous header of the
+// filter for the genesis block is the zero hash.
+func MakeFilterHeader(filterHash, prevHeader *chainhash.Hash) chainhash.Hash {
+	var filterTip [2 * chainhash.HashSize]byte
+	copy(filterTip[:], filterHash[:])
+	copy(filterTip[chainhash.HashSize:], prevHeader[:])
+	return chainhash.DoubleHashH(filterTip[:])
+}
+
+// VerifyFilterHeaders ensures the passed committed filter headers, such as those
+// received in a cfheaders message, commit to the filters with the passed
+// hashes.  The filter hashes and headers must be for consecutive blocks, with
+// the first of them chained from prevHeader, which is the zero hash when the
+// first block is the genesis block.
+//
+// The headers computed from the filter hashes are returned, so the last of them
+// may be used as the previous header when verifying the headers which follow.
+// An error is returned when the number of filter hashes and headers differ or
+// a header does not match the one computed for its filter.
+func VerifyFilterHeaders(filterHashes []chainhash.Hash, headers []*chainhash.Hash,
+	prevHeader chainhash.Hash) ([]chainhash.Hash, error) {
+
+	if len(filterHashes) != len(headers) {
+		str := fmt.Sprintf("%d filter hashes provided for %d filter "+
+			"headers", len(filterHashes), len(headers))
+		return nil, messageError("VerifyFilterHeaders", str)
+	}
+
+	computed := make([]chainhash.Hash, len(filterHashes))
+	for i := range filterHashes {
+		computed[i] = MakeFilterHeader(&filterHashes[i], &prevHeader)
+		if headers[i] == nil || !headers[i].IsEqual(&computed[i]) {
+			str := fmt.Sprintf("filter header %d is %v, but the "+
+				"filter commits to %v", i, headers[i],
+				computed[i])
+			return nil, messageError("VerifyFilterHeaders", str)
+		}
+		prevHeader = computed[i]
+	}
+
+	return computed, nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// TestVerifyFilterHeaders tests computing and verifying a chain of committed
+// filter headers.
+func TestVerifyFilterHeaders(t *testing.T) {
+	filterHashes := []chainhash.Hash{
+		chainhash.DoubleHashH([]byte("filter 0")),
+		chainhash.DoubleHashH([]byte("filter 1")),
+		chainhash.DoubleHashH([]byte("filter 2")),
+	}
+
+	// Compute the expected headers by hand starting from the zero hash
+	// which precedes the genesis filter header.
+	var prevHeader chainhash.Hash
+	want := make([]chainhash.Hash, len(filterHashes))
+	headers := make([]*chainhash.Hash, len(filterHashes))
+	for i, filterHash := range filterHashes {
+		want[i] = chainhash.DoubleHashH(append(filterHash[:],
+			prevHeader[:]...))
+		headers[i] = &want[i]
+		prevHeader = want[i]
+	}
+
+	// Ensure a valid chain of headers from the genesis block is accepted.
+	got, err := VerifyFilterHeaders(filterHashes, headers, chainhash.Hash{})
+	if err != nil {
+		t.Fatalf("VerifyFilterHeaders: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("VerifyFilterHeaders: unexpected headers - got %v, "+
+			"want %v", got, want)
+	}
+
+	// Ensure a chain continuing from a previous header is accepted.
+	got, err = VerifyFilterHeaders(filterHashes[1:], headers[1:], want[0])
+	if err != nil {
+		t.Fatalf("VerifyFilterHeaders: unexpected error continuing "+
+			"chain: %v", err)
+	}
+	if !reflect.DeepEqual(got, want[1:]) {
+		t.Fatalf("VerifyFilterHeaders: unexpected headers continuing "+
+			"chain - got %v, want %v", got, want[1:])
+	}
+
+	// Ensure no headers are accepted.
+	if _, err := VerifyFilterHeaders(nil, nil, want[2]); err != nil {
+		t.Fatalf("VerifyFilterHeaders: unexpected error with no "+
+			"headers: %v", err)
+	}
+
+	wrongHeader := want[1]
+	wrongHeader[0] ^= 0x01
+	tests := []struct {
+		name         string
+		filterHashes []chainhash.Hash
+		headers      []*chainhash.Hash
+		prevHeader   chainhash.Hash
+	}{
+		{
+			name:         "more filter hashes than headers",
+			filterHashes: filterHashes,
+			headers:      headers[:2],
+		},
+		{
+			name:         "more headers than filter hashes",
+			filterHashes: filterHashes[:2],
+			headers:      headers,
+		},
+		{
+			name:         "wrong previous header",
+			filterHashes: filterHashes,
+			headers:      headers,
+			prevHeader:   want[0],
+		},
+		{
+			name:         "wrong header",
+			filterHashes: filterHashes,
+			headers:      []*chainhash.Hash{headers[0], &wrongHeader, headers[2]},
+		},
+		{
+			name:         "nil header",
+			filterHashes: filterHashes,
+			headers:      []*chainhash.Hash{headers[0], nil, headers[2]},
+		},
+	}
+
+	for _, test := range tests {
+		_, err := VerifyFilterHeaders(test.filterHashes, test.headers,
+			test.prevHeader)
+		if _, ok := err.(*MessageError); !ok {
+			t.Errorf("VerifyFilterHeaders (%s): did not receive "+
+				"expected MessageError - got %T (%v)", test.name,
+				err, err)
+		}
+	}
+
+	// Ensure MakeFilterHeader agrees with the verified headers.
+	if header := MakeFilterHeader(&filterHashes[2], &want[1]); header != want[2] {
+		t.Errorf("MakeFilterHeader: unexpected header - got %v, want %v",
+			header, want[2])
+	}
+}
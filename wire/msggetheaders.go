@@ -134,3 +134,21 @@ func NewMsgGetHeaders() *MsgGetHeaders {
 			MaxBlockLocatorsPerMsg),
 	}
 }
+
+// NewMsgGetHeadersFromLocator returns a new navcoin getheaders message which
+// requests the headers after the passed block locator hashes up to and
+// including the passed stop hash.  The stop hash may be nil to request as many
+// headers as allowed.  An error is returned when the locator has more than
+// MaxBlockLocatorsPerMsg hashes.
+func NewMsgGetHeadersFromLocator(locator []*chainhash.Hash, hashStop *chainhash.Hash) (*MsgGetHeaders, error) {
+	msg := NewMsgGetHeaders()
+	for _, hash := range locator {
+		if err := msg.AddBlockLocatorHash(hash); err != nil {
+			return nil, err
+		}
+	}
+	if hashStop != nil {
+		msg.HashStop = *hashStop
+	}
+	return msg, nil
+}
@@ -67,6 +67,47 @@ func TestGetHeaders(t *testing.T) {
 	}
 }
 
+// TestNewMsgGetHeadersFromLocator tests creating a getheaders message from a
+// block locator.
+func TestNewMsgGetHeadersFromLocator(t *testing.T) {
+	hashStop := chainhash.Hash{0x01}
+	locator := []*chainhash.Hash{{0x04}, {0x03}, {0x02}}
+
+	msg, err := NewMsgGetHeadersFromLocator(locator, &hashStop)
+	if err != nil {
+		t.Fatalf("NewMsgGetHeadersFromLocator: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(msg.BlockLocatorHashes, locator) {
+		t.Errorf("NewMsgGetHeadersFromLocator: wrong locator - got %v, "+
+			"want %v", spew.Sdump(msg.BlockLocatorHashes),
+			spew.Sdump(locator))
+	}
+	if msg.HashStop != hashStop {
+		t.Errorf("NewMsgGetHeadersFromLocator: wrong stop hash - got "+
+			"%v, want %v", msg.HashStop, hashStop)
+	}
+
+	// Ensure a nil stop hash requests as many headers as allowed.
+	msg, err = NewMsgGetHeadersFromLocator(locator, nil)
+	if err != nil {
+		t.Fatalf("NewMsgGetHeadersFromLocator: unexpected error: %v", err)
+	}
+	if msg.HashStop != (chainhash.Hash{}) {
+		t.Errorf("NewMsgGetHeadersFromLocator: wrong stop hash - got "+
+			"%v, want zero hash", msg.HashStop)
+	}
+
+	// Ensure a locator with more than the max allowed hashes is rejected.
+	tooLong := make([]*chainhash.Hash, MaxBlockLocatorsPerMsg+1)
+	for i := range tooLong {
+		tooLong[i] = &hashStop
+	}
+	if _, err := NewMsgGetHeadersFromLocator(tooLong, nil); err == nil {
+		t.Errorf("NewMsgGetHeadersFromLocator: expected error on too " +
+			"many block locator hashes not received")
+	}
+}
+
 // TestGetHeadersWire tests the MsgGetHeaders wire encode and decode for various
 // numbers of block locator hashes and protocol versions.
 func TestGetHeadersWire(t *testing.T) {
@@ -134,3 +134,40 @@ func NewMsgHeaders() *MsgHeaders {
 		Headers: make([]*BlockHeader, 0, MaxBlockHeadersPerMsg),
 	}
 }
+
+// ValidateHeaderChain ensures the passed headers, such as those received in a
+// headers message, form a contiguous chain and do not exceed the maximum
+// number of headers allowed in a message.  Each header must reference the
+// hash of the header preceding it as its previous block.  An empty list of
+// headers, which a peer sends when it has no more headers to provide, is
+// valid.
+//
+// Only the linkage of the headers is checked.  Their proof of work and how
+// they connect to the known chain must be validated separately.
+func ValidateHeaderChain(headers []*BlockHeader) error {
+	if len(headers) > MaxBlockHeadersPerMsg {
+		str := fmt.Sprintf("too many block headers [count %v, max %v]",
+			len(headers), MaxBlockHeadersPerMsg)
+		return messageError("ValidateHeaderChain", str)
+	}
+
+	for i, header := range headers {
+		if header == nil {
+			str := fmt.Sprintf("block header %d is nil", i)
+			return messageError("ValidateHeaderChain", str)
+		}
+		if i == 0 {
+			continue
+		}
+
+		prevHash := headers[i-1].BlockHash()
+		if !header.PrevBlock.IsEqual(&prevHash) {
+			str := fmt.Sprintf("block header %d references previous "+
+				"block %v instead of %v", i, header.PrevBlock,
+				prevHash)
+			return messageError("ValidateHeaderChain", str)
+		}
+	}
+
+	return nil
+}
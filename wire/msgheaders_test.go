@@ -57,6 +57,59 @@ func TestHeaders(t *testing.T) {
 	}
 }
 
+// TestValidateHeaderChain tests the ValidateHeaderChain function.
+func TestValidateHeaderChain(t *testing.T) {
+	// newChain returns the given number of headers which each reference
+	// the previous one.
+	newChain := func(numHeaders int) []*BlockHeader {
+		headers := make([]*BlockHeader, numHeaders)
+		prevHash := blockOne.Header.PrevBlock
+		for i := range headers {
+			header := blockOne.Header
+			header.PrevBlock = prevHash
+			header.Nonce = uint32(i)
+			headers[i] = &header
+			prevHash = header.BlockHash()
+		}
+		return headers
+	}
+
+	brokenLink := newChain(3)
+	brokenLink[2].PrevBlock = brokenLink[0].BlockHash()
+	withNil := newChain(3)
+	withNil[1] = nil
+
+	tests := []struct {
+		name    string
+		headers []*BlockHeader
+		isValid bool
+	}{
+		{"no headers", nil, true},
+		{"single header", newChain(1), true},
+		{"contiguous chain", newChain(10), true},
+		{"max headers", newChain(MaxBlockHeadersPerMsg), true},
+		{"too many headers", newChain(MaxBlockHeadersPerMsg + 1), false},
+		{"broken link", brokenLink, false},
+		{"nil header", withNil, false},
+	}
+
+	for _, test := range tests {
+		err := ValidateHeaderChain(test.headers)
+		if test.isValid && err != nil {
+			t.Errorf("ValidateHeaderChain (%s): unexpected error: %v",
+				test.name, err)
+			continue
+		}
+		if !test.isValid {
+			if _, ok := err.(*MessageError); !ok {
+				t.Errorf("ValidateHeaderChain (%s): did not "+
+					"receive expected MessageError - got %T "+
+					"(%v)", test.name, err, err)
+			}
+		}
+	}
+}
+
 // TestHeadersWire tests the MsgHeaders wire encode and decode for various
 // numbers of headers and protocol versions.
 func TestHeadersWire(t *testing.T) {
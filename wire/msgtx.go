@@ -412,6 +412,64 @@ func (msg *MsgTx) Copy() *MsgTx {
 // See Deserialize for decoding transactions stored to disk, such as in a
 // database, as opposed to decoding transactions from the wire.
 func (msg *MsgTx) BtcDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	return msg.BtcDecodeWithOptions(r, pver, enc, nil)
+}
+
+// TxDecodeOptions houses options which control how a transaction is decoded.
+type TxDecodeOptions struct {
+	// MaxTxSize is the maximum number of bytes the encoded transaction may
+	// occupy.  Decoding is aborted as soon as the declared number of inputs,
+	// outputs, witness items, or script bytes could not possibly fit into
+	// the bytes that remain.  A value of zero disables the bound, which
+	// leaves only the per-message limits that BtcDecode enforces.
+	MaxTxSize uint32
+}
+
+// txSizeLimitReader wraps a reader to track the number of bytes which may
+// still be read for the transaction being decoded and fail any read beyond
+// that.
+type txSizeLimitReader struct {
+	r         io.Reader
+	maxSize   uint32
+	remaining uint64
+}
+
+// Read reads up to the number of remaining bytes into p from the underlying
+// reader.  An error is returned once no bytes remain.
+func (l *txSizeLimitReader) Read(p []byte) (int, error) {
+	if l.remaining == 0 {
+		str := fmt.Sprintf("transaction is larger than the max allowed "+
+			"size [max %d]", l.maxSize)
+		return 0, messageError("MsgTx.BtcDecode", str)
+	}
+	if uint64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= uint64(n)
+	return n, err
+}
+
+// BtcDecodeWithOptions decodes r using the navcoin protocol encoding into the
+// receiver the same as BtcDecode, except the passed options bound the size of
+// the transaction.  The declared input, output, and witness item counts and
+// script lengths are validated against the bytes remaining within that bound
+// before anything is allocated for them, which protects against memory
+// exhaustion through malicious counts.  Passing nil options is equivalent to
+// calling BtcDecode.
+func (msg *MsgTx) BtcDecodeWithOptions(r io.Reader, pver uint32,
+	enc MessageEncoding, opts *TxDecodeOptions) error {
+
+	// Only wrap the reader when a size bound is requested so the common
+	// case of decoding without one does not pay for the extra allocation
+	// and indirection.
+	var lr *txSizeLimitReader
+	if opts != nil && opts.MaxTxSize != 0 {
+		lr = &txSizeLimitReader{r: r, maxSize: opts.MaxTxSize,
+			remaining: uint64(opts.MaxTxSize)}
+		r = lr
+	}
+
 	version, err := binarySerializer.Uint32(r, littleEndian)
 	if err != nil {
 		return err
@@ -463,6 +521,15 @@ func (msg *MsgTx) BtcDecode(r io.Reader, pver uint32, enc MessageEncoding) error
 		return messageError("MsgTx.BtcDecode", str)
 	}
 
+	// Prevent more input transactions than could possibly fit into the
+	// remaining bytes of the transaction.
+	if lr != nil && count > lr.remaining/minTxInPayload {
+		str := fmt.Sprintf("too many input transactions to fit into "+
+			"max transaction size [count %d, max %d]", count,
+			lr.remaining/minTxInPayload)
+		return messageError("MsgTx.BtcDecode", str)
+	}
+
 	// returnScriptBuffers is a closure that returns any script buffers that
 	// were borrowed from the pool when there are any deserialization
 	// errors.  This is only valid to call before the final step which
@@ -526,6 +593,16 @@ func (msg *MsgTx) BtcDecode(r io.Reader, pver uint32, enc MessageEncoding) error
 		return messageError("MsgTx.BtcDecode", str)
 	}
 
+	// Prevent more output transactions than could possibly fit into the
+	// remaining bytes of the transaction.
+	if lr != nil && count > lr.remaining/minTxOutPayload {
+		returnScriptBuffers()
+		str := fmt.Sprintf("too many output transactions to fit into "+
+			"max transaction size [count %d, max %d]", count,
+			lr.remaining/minTxOutPayload)
+		return messageError("MsgTx.BtcDecode", str)
+	}
+
 	// Deserialize the outputs.
 	txOuts := make([]TxOut, count)
 	msg.TxOut = make([]*TxOut, count)
@@ -565,6 +642,17 @@ func (msg *MsgTx) BtcDecode(r io.Reader, pver uint32, enc MessageEncoding) error
 				return messageError("MsgTx.BtcDecode", str)
 			}
 
+			// Each witness item is prefixed by at least a single
+			// byte for its length, so more items than remaining
+			// bytes could not possibly fit into the transaction.
+			if lr != nil && witCount > lr.remaining {
+				returnScriptBuffers()
+				str := fmt.Sprintf("too many witness items to fit "+
+					"into max transaction size [count %d, max %d]",
+					witCount, lr.remaining)
+				return messageError("MsgTx.BtcDecode", str)
+			}
+
 			// Then for witCount number of stack items, each item
 			// has a varint length prefix, followed by the witness
 			// item itself.
@@ -579,6 +667,16 @@ func (msg *MsgTx) BtcDecode(r io.Reader, pver uint32, enc MessageEncoding) error
 				totalScriptSize += uint64(len(txin.Witness[j]))
 			}
 		}
+
+		// The marker and flag bytes are only written when at least one
+		// input has witness data, so reject a transaction that claims
+		// to have witness data without any since it could not have
+		// been encoded that way.
+		if !msg.HasWitness() {
+			returnScriptBuffers()
+			str := "witness flag set for transaction without witness data"
+			return messageError("MsgTx.BtcDecode", str)
+		}
 	}
 
 	msg.LockTime, err = binarySerializer.Uint32(r, littleEndian)
@@ -589,18 +687,31 @@ func (msg *MsgTx) BtcDecode(r io.Reader, pver uint32, enc MessageEncoding) error
 	
 	count_str, err := ReadVarInt(r, pver)
 	if err != nil {
+		returnScriptBuffers()
 		return err
 	}
 
-	// Prevent byte array larger than the max message size.  It would
-	// be possible to cause memory exhaustion and panics without a sane
-	// upper bound on this count.
-	if count_str > 0 || msg.Version > 1 {
-		var string []byte
-		if _, err = io.ReadFull(r, string[:]); err != nil {
-                        return err
-                }
-		msg.Strdzeel = string
+	// Prevent byte array larger than the max message size or the remaining
+	// bytes of a transaction which is being decoded with a size bound.  It
+	// would be possible to cause memory exhaustion and panics without a
+	// sane upper bound on this count.
+	maxStrdzeel := uint64(MaxMessagePayload)
+	if lr != nil {
+		maxStrdzeel = lr.remaining
+	}
+	if count_str > maxStrdzeel {
+		returnScriptBuffers()
+		str := fmt.Sprintf("strdzeel is larger than the max allowed "+
+			"size [count %d, max %d]", count_str, maxStrdzeel)
+		return messageError("MsgTx.BtcDecode", str)
+	}
+	msg.Strdzeel = nil
+	if count_str > 0 {
+		msg.Strdzeel = make([]byte, count_str)
+		if _, err = io.ReadFull(r, msg.Strdzeel); err != nil {
+			returnScriptBuffers()
+			return err
+		}
 	}
 
 	// Create a single allocation to house all of the scripts and set each
@@ -779,7 +890,8 @@ func (msg *MsgTx) BtcEncode(w io.Writer, pver uint32, enc MessageEncoding) error
 }
 
 // HasWitness returns false if none of the inputs within the transaction
-// contain witness data, true false otherwise.
+// contain witness data, true otherwise.  Inputs with an empty witness do not
+// count as having witness data.
 func (msg *MsgTx) HasWitness() bool {
 	for _, txIn := range msg.TxIn {
 		if len(txIn.Witness) != 0 {
@@ -790,6 +902,60 @@ func (msg *MsgTx) HasWitness() bool {
 	return false
 }
 
+// TxEqual returns whether or not the two passed transactions are semantically
+// equal.  The comparison is performed field by field, so the ordering of the
+// inputs, outputs, and witness stack items is significant.  When
+// includeWitness is false, the witness data of the inputs is ignored entirely,
+// meaning a transaction and its witness-stripped form compare as equal.
+//
+// Two nil transactions are considered equal, while a nil transaction is never
+// equal to a non-nil one.
+func TxEqual(a, b *MsgTx, includeWitness bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if a.Version != b.Version || a.Time != b.Time ||
+		a.LockTime != b.LockTime || !bytes.Equal(a.Strdzeel, b.Strdzeel) {
+		return false
+	}
+
+	if len(a.TxIn) != len(b.TxIn) || len(a.TxOut) != len(b.TxOut) {
+		return false
+	}
+
+	for i, aTxIn := range a.TxIn {
+		bTxIn := b.TxIn[i]
+		if aTxIn.PreviousOutPoint != bTxIn.PreviousOutPoint ||
+			aTxIn.Sequence != bTxIn.Sequence ||
+			!bytes.Equal(aTxIn.SignatureScript, bTxIn.SignatureScript) {
+			return false
+		}
+
+		if !includeWitness {
+			continue
+		}
+		if len(aTxIn.Witness) != len(bTxIn.Witness) {
+			return false
+		}
+		for j, item := range aTxIn.Witness {
+			if !bytes.Equal(item, bTxIn.Witness[j]) {
+				return false
+			}
+		}
+	}
+
+	for i, aTxOut := range a.TxOut {
+		bTxOut := b.TxOut[i]
+		if aTxOut.Value != bTxOut.Value ||
+			!bytes.Equal(aTxOut.PkScript, bTxOut.PkScript) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Serialize encodes the transaction to w using a format that suitable for
 // long-term storage such as a database while respecting the Version field in
 // the transaction.  This function differs from BtcEncode in that BtcEncode
@@ -824,10 +990,11 @@ func (msg *MsgTx) SerializeNoWitness(w io.Writer) error {
 func (msg *MsgTx) baseSize() int {
 	// Version 4 bytes + Time 4 bytes + LockTime 4 bytes + Serialized varint
 	// size for the number of transaction inputs and outputs + Serialized
-	// varint size of strdzeel.
-	n := 8 + VarIntSerializeSize(uint64(len(msg.TxIn))) +
+	// varint size of strdzeel + strdzeel bytes.
+	n := 12 + VarIntSerializeSize(uint64(len(msg.TxIn))) +
 		VarIntSerializeSize(uint64(len(msg.TxOut))) +
-		VarIntSerializeSize(uint64(len(msg.Strdzeel))) 
+		VarIntSerializeSize(uint64(len(msg.Strdzeel))) +
+		len(msg.Strdzeel)
 
 	for _, txIn := range msg.TxIn {
 		n += txIn.SerializeSize()
@@ -897,8 +1064,10 @@ func (msg *MsgTx) PkScriptLocs() []int {
 		VarIntSerializeSize(uint64(numTxOut))
 
 	// If this transaction has a witness input, the an additional two bytes
-	// for the marker, and flag byte need to be taken into account.
-	if len(msg.TxIn) > 0 && msg.TxIn[0].Witness != nil {
+	// for the marker, and flag byte need to be taken into account.  This
+	// must agree with BtcEncode, which only writes them when any input has
+	// a non-empty witness.
+	if msg.HasWitness() {
 		n += 2
 	}
 
@@ -979,6 +1148,14 @@ func readScript(r io.Reader, pver uint32, maxAllowed uint32, fieldName string) (
 		return nil, messageError("readScript", str)
 	}
 
+	// Prevent byte array larger than the remaining bytes of a transaction
+	// which is being decoded with a size bound.
+	if lr, ok := r.(*txSizeLimitReader); ok && count > lr.remaining {
+		str := fmt.Sprintf("%s is larger than the remaining transaction "+
+			"size [count %d, max %d]", fieldName, count, lr.remaining)
+		return nil, messageError("readScript", str)
+	}
+
 	b := scriptPool.Borrow(count)
 	_, err = io.ReadFull(r, b)
 	if err != nil {
@@ -721,6 +721,348 @@ func TestTxOverflowErrors(t *testing.T) {
 	}
 }
 
+// TestTxDecodeMaxSize ensures decoding transactions with a size bound rejects
+// transactions which declare more inputs, outputs, or script bytes than could
+// fit into the bound before allocating anything for them, and otherwise
+// decodes transactions which fit.
+func TestTxDecodeMaxSize(t *testing.T) {
+	pver := ProtocolVersion
+
+	// smallTx is a minimal encoded transaction with a single input and
+	// output and empty scripts.
+	smallTx := []byte{
+		0x01, 0x00, 0x00, 0x00, // Version
+		0x00, 0x00, 0x00, 0x00, // Time
+		0x01, // Varint for number of input transactions
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // Previous output hash
+		0xff, 0xff, 0xff, 0xff, // Prevous output index
+		0x00,                   // Varint for length of signature script
+		0xff, 0xff, 0xff, 0xff, // Sequence
+		0x01,                                           // Varint for number of output transactions
+		0x00, 0xe1, 0xf5, 0x05, 0x00, 0x00, 0x00, 0x00, // Transaction amount
+		0x00,                   // Varint for length of pk script
+		0x00, 0x00, 0x00, 0x00, // Lock time
+		0x00, // Varint for length of strdzeel
+	}
+
+	tests := []struct {
+		name      string
+		buf       []byte // Wire encoding
+		maxTxSize uint32 // Max transaction size
+		err       error  // Expected error
+	}{
+		{
+			name: "input count exceeds remaining size",
+			buf: []byte{
+				0x01, 0x00, 0x00, 0x00, // Version
+				0x00, 0x00, 0x00, 0x00, // Time
+				0xfd, 0xe8, 0x03, // Varint for number of input transactions
+			},
+			maxTxSize: 1000,
+			err:       &MessageError{},
+		},
+		{
+			name: "witness input count exceeds remaining size",
+			buf: []byte{
+				0x01, 0x00, 0x00, 0x00, // Version
+				0x00, 0x00, 0x00, 0x00, // Time
+				0x00, 0x01, // Witness marker and flag
+				0xfd, 0xe8, 0x03, // Varint for number of input transactions
+			},
+			maxTxSize: 1000,
+			err:       &MessageError{},
+		},
+		{
+			name: "output count exceeds remaining size",
+			buf: []byte{
+				0x01, 0x00, 0x00, 0x00, // Version
+				0x00, 0x00, 0x00, 0x00, // Time
+				0x00,             // Varint for number of input transactions
+				0xfd, 0xe8, 0x03, // Varint for number of output transactions
+			},
+			maxTxSize: 1000,
+			err:       &MessageError{},
+		},
+		{
+			name: "signature script exceeds remaining size",
+			buf: []byte{
+				0x01, 0x00, 0x00, 0x00, // Version
+				0x00, 0x00, 0x00, 0x00, // Time
+				0x01, // Varint for number of input transactions
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // Previous output hash
+				0xff, 0xff, 0xff, 0xff, // Prevous output index
+				0xfd, 0xe8, 0x03, // Varint for length of signature script
+			},
+			maxTxSize: 1000,
+			err:       &MessageError{},
+		},
+		{
+			name:      "transaction larger than max size",
+			buf:       smallTx,
+			maxTxSize: uint32(len(smallTx) - 1),
+			err:       &MessageError{},
+		},
+		{
+			name:      "transaction fits max size",
+			buf:       smallTx,
+			maxTxSize: uint32(len(smallTx)),
+			err:       nil,
+		},
+		{
+			name:      "transaction without max size",
+			buf:       smallTx,
+			maxTxSize: 0,
+			err:       nil,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for _, test := range tests {
+		var msg MsgTx
+		r := bytes.NewReader(test.buf)
+		opts := &TxDecodeOptions{MaxTxSize: test.maxTxSize}
+		err := msg.BtcDecodeWithOptions(r, pver, WitnessEncoding, opts)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.err) {
+			t.Errorf("%s: wrong error got: %v, want: %v", test.name,
+				err, reflect.TypeOf(test.err))
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if len(msg.TxIn) != 1 || len(msg.TxOut) != 1 {
+			t.Errorf("%s: unexpected decoded transaction %v",
+				test.name, spew.Sdump(msg))
+		}
+	}
+}
+
+// TestTxDecodeStrdzeel ensures the strdzeel payload which follows the lock time
+// is read in full, so the transaction encodes back to exactly the bytes it was
+// decoded from and no bytes of the payload are left unread.
+func TestTxDecodeStrdzeel(t *testing.T) {
+	pver := ProtocolVersion
+
+	// txPrefix is the encoding of a transaction with a single input and
+	// output and empty scripts up to and including its lock time.
+	txPrefix := []byte{
+		0x02, 0x00, 0x00, 0x00, // Version
+		0x00, 0x00, 0x00, 0x00, // Time
+		0x01, // Varint for number of input transactions
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // Previous output hash
+		0xff, 0xff, 0xff, 0xff, // Prevous output index
+		0x00,                   // Varint for length of signature script
+		0xff, 0xff, 0xff, 0xff, // Sequence
+		0x01,                                           // Varint for number of output transactions
+		0x00, 0xe1, 0xf5, 0x05, 0x00, 0x00, 0x00, 0x00, // Transaction amount
+		0x00,                   // Varint for length of pk script
+		0x00, 0x00, 0x00, 0x00, // Lock time
+	}
+	withSuffix := func(suffix ...byte) []byte {
+		buf := make([]byte, 0, len(txPrefix)+len(suffix))
+		buf = append(buf, txPrefix...)
+		return append(buf, suffix...)
+	}
+
+	tests := []struct {
+		name     string
+		buf      []byte          // Wire encoding
+		enc      MessageEncoding // Message encoding format
+		strdzeel []byte          // Expected strdzeel
+		err      error           // Expected error
+	}{
+		{
+			name:     "empty strdzeel",
+			buf:      withSuffix(0x00),
+			enc:      WitnessEncoding,
+			strdzeel: nil,
+		},
+		{
+			name:     "strdzeel",
+			buf:      withSuffix(0x03, 'a', 'b', 'c'),
+			enc:      WitnessEncoding,
+			strdzeel: []byte("abc"),
+		},
+		{
+			name:     "strdzeel without witness encoding",
+			buf:      withSuffix(0x03, 'a', 'b', 'c'),
+			enc:      BaseEncoding,
+			strdzeel: []byte("abc"),
+		},
+		{
+			name: "missing strdzeel length",
+			buf:  withSuffix(),
+			enc:  WitnessEncoding,
+			err:  io.EOF,
+		},
+		{
+			name: "truncated strdzeel",
+			buf:  withSuffix(0x03, 'a', 'b'),
+			enc:  WitnessEncoding,
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			name: "strdzeel exceeds max size",
+			buf:  withSuffix(0xfe, 0xff, 0xff, 0xff, 0xff),
+			enc:  WitnessEncoding,
+			err:  &MessageError{},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for _, test := range tests {
+		// Follow valid transactions with a trailing byte to ensure the
+		// decoder stops reading at the end of the strdzeel.
+		encoded := test.buf
+		if test.err == nil {
+			encoded = append(encoded[:len(encoded):len(encoded)], 0xff)
+		}
+		r := bytes.NewReader(encoded)
+		var msg MsgTx
+		err := msg.BtcDecode(r, pver, test.enc)
+		if _, ok := test.err.(*MessageError); ok {
+			if _, ok := err.(*MessageError); !ok {
+				t.Errorf("%s: wrong error got: %v, want: %v",
+					test.name, err, reflect.TypeOf(test.err))
+				continue
+			}
+		} else if err != test.err {
+			t.Errorf("%s: wrong error got: %v, want: %v", test.name,
+				err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if !bytes.Equal(msg.Strdzeel, test.strdzeel) {
+			t.Errorf("%s: unexpected strdzeel got: %q, want: %q",
+				test.name, msg.Strdzeel, test.strdzeel)
+			continue
+		}
+		if r.Len() != 1 {
+			t.Errorf("%s: unexpected number of unread bytes got: %d, "+
+				"want: 1", test.name, r.Len())
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := msg.BtcEncode(&buf, pver, test.enc); err != nil {
+			t.Errorf("%s: BtcEncode error %v", test.name, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("%s: re-encoded transaction mismatch got: %x, "+
+				"want: %x", test.name, buf.Bytes(), test.buf)
+			continue
+		}
+	}
+}
+
+// TestTxDecodeWitnessFlag ensures transactions encoded with the witness marker
+// and flag bytes are only decoded when at least one of their inputs has witness
+// data since the bytes are never written otherwise.
+func TestTxDecodeWitnessFlag(t *testing.T) {
+	pver := ProtocolVersion
+
+	// witnessTx returns the encoding of a transaction with the witness
+	// marker and flag bytes and two inputs with the passed witnesses.
+	witnessTx := func(witness1, witness2 []byte) []byte {
+		buf := []byte{
+			0x01, 0x00, 0x00, 0x00, // Version
+			0x00, 0x00, 0x00, 0x00, // Time
+			0x00, 0x01, // Witness marker and flag
+			0x02, // Varint for number of input transactions
+		}
+		for i := byte(0); i < 2; i++ {
+			buf = append(buf, bytes.Repeat([]byte{i + 1}, 32)...) // Previous output hash
+			buf = append(buf,
+				0x00, 0x00, 0x00, 0x00, // Prevous output index
+				0x00,                   // Varint for length of signature script
+				0xff, 0xff, 0xff, 0xff, // Sequence
+			)
+		}
+		buf = append(buf, 0x00) // Varint for number of output transactions
+		buf = append(buf, witness1...)
+		buf = append(buf, witness2...)
+		return append(buf,
+			0x00, 0x00, 0x00, 0x00, // Lock time
+			0x00, // Varint for length of strdzeel
+		)
+	}
+
+	// emptyWitness is an encoded witness without any items while
+	// oneItemWitness is an encoded witness with a single item.
+	emptyWitness := []byte{0x00}
+	oneItemWitness := []byte{0x01, 0x01, 0x51}
+
+	tests := []struct {
+		name string
+		buf  []byte // Wire encoding
+		err  error  // Expected error
+	}{
+		{
+			name: "witness data on the first input",
+			buf:  witnessTx(oneItemWitness, emptyWitness),
+		},
+		{
+			name: "witness data on the second input",
+			buf:  witnessTx(emptyWitness, oneItemWitness),
+		},
+		{
+			name: "witness data on every input",
+			buf:  witnessTx(oneItemWitness, oneItemWitness),
+		},
+		{
+			name: "witness flag without witness data",
+			buf:  witnessTx(emptyWitness, emptyWitness),
+			err:  &MessageError{},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for _, test := range tests {
+		var msg MsgTx
+		err := msg.BtcDecode(bytes.NewReader(test.buf), pver,
+			WitnessEncoding)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.err) {
+			t.Errorf("%s: wrong error got: %v, want: %v", test.name,
+				err, reflect.TypeOf(test.err))
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if !msg.HasWitness() {
+			t.Errorf("%s: decoded transaction has no witness data",
+				test.name)
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := msg.BtcEncode(&buf, pver, WitnessEncoding); err != nil {
+			t.Errorf("%s: BtcEncode error %v", test.name, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("%s: re-encoded transaction mismatch got: %x, "+
+				"want: %x", test.name, buf.Bytes(), test.buf)
+			continue
+		}
+	}
+}
+
 // TestTxSerializeSizeStripped performs tests to ensure the serialize size for
 // various transactions is accurate.
 func TestTxSerializeSizeStripped(t *testing.T) {
@@ -733,15 +1075,15 @@ func TestTxSerializeSizeStripped(t *testing.T) {
 		size int    // Expected serialized size
 	}{
 		// No inputs or outpus.
-		{noTx, 10},
+		{noTx, 15},
 
 		// Transcaction with an input and an output.
-		{multiTx, 210},
+		{multiTx, 215},
 
 		// Transaction with an input which includes witness data, and
 		// one output. Note that this uses SerializeSizeStripped which
 		// excludes the additional bytes due to witness data encoding.
-		{multiWitnessTx, 82},
+		{multiWitnessTx, 87},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -755,6 +1097,109 @@ func TestTxSerializeSizeStripped(t *testing.T) {
 	}
 }
 
+// TestTxSerializeSizeMatchesEncoding ensures the serialize sizes and public key
+// script locations of transactions agree with their actual encodings, in
+// particular that the witness marker and flag bytes are only accounted for
+// when an input has a non-empty witness.
+func TestTxSerializeSizeMatchesEncoding(t *testing.T) {
+	newTx := func(witnesses ...TxWitness) *MsgTx {
+		tx := NewMsgTx(1)
+		for _, witness := range witnesses {
+			tx.AddTxIn(&TxIn{
+				PreviousOutPoint: OutPoint{Index: 1},
+				SignatureScript:  []byte{0x51},
+				Witness:          witness,
+				Sequence:         MaxTxInSequenceNum,
+			})
+		}
+		tx.AddTxOut(NewTxOut(1000, []byte{0x76, 0xa9, 0x14}))
+		tx.AddTxOut(NewTxOut(2000, []byte{0x00, 0x14}))
+		return tx
+	}
+
+	withStrdzeel := newTx(nil)
+	withStrdzeel.Strdzeel = []byte("strdzeel")
+
+	tests := []struct {
+		name       string
+		tx         *MsgTx
+		hasWitness bool
+	}{
+		{
+			name:       "no inputs",
+			tx:         newTx(),
+			hasWitness: false,
+		},
+		{
+			name:       "nil witnesses",
+			tx:         newTx(nil, nil),
+			hasWitness: false,
+		},
+		{
+			name:       "empty non-nil witnesses",
+			tx:         newTx(TxWitness{}, TxWitness{}),
+			hasWitness: false,
+		},
+		{
+			name:       "witness on first input only",
+			tx:         newTx(TxWitness{{0x01, 0x02}}, nil),
+			hasWitness: true,
+		},
+		{
+			name:       "witness on second input only",
+			tx:         newTx(TxWitness{}, TxWitness{{0x01}, {}}),
+			hasWitness: true,
+		},
+		{
+			name:       "strdzeel",
+			tx:         withStrdzeel,
+			hasWitness: false,
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.tx.HasWitness(); got != test.hasWitness {
+			t.Errorf("%s: HasWitness got: %v, want: %v", test.name,
+				got, test.hasWitness)
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := test.tx.Serialize(&buf); err != nil {
+			t.Errorf("%s: Serialize error: %v", test.name, err)
+			continue
+		}
+		if got := test.tx.SerializeSize(); got != buf.Len() {
+			t.Errorf("%s: SerializeSize got: %d, want: %d",
+				test.name, got, buf.Len())
+		}
+
+		// The public key scripts must be located at the reported
+		// offsets of the encoding.
+		serialized := buf.Bytes()
+		for i, loc := range test.tx.PkScriptLocs() {
+			pkScript := test.tx.TxOut[i].PkScript
+			if loc+len(pkScript) > len(serialized) ||
+				!bytes.Equal(serialized[loc:loc+len(pkScript)], pkScript) {
+
+				t.Errorf("%s: PkScriptLocs #%d wrong location %d",
+					test.name, i, loc)
+			}
+		}
+
+		buf.Reset()
+		if err := test.tx.SerializeNoWitness(&buf); err != nil {
+			t.Errorf("%s: SerializeNoWitness error: %v", test.name,
+				err)
+			continue
+		}
+		if got := test.tx.SerializeSizeStripped(); got != buf.Len() {
+			t.Errorf("%s: SerializeSizeStripped got: %d, want: %d",
+				test.name, got, buf.Len())
+		}
+	}
+}
+
 // TestTxWitnessSize performs tests to ensure that the serialized size for
 // various types of transactions that include witness data is accurate.
 func TestTxWitnessSize(t *testing.T) {
@@ -764,7 +1209,7 @@ func TestTxWitnessSize(t *testing.T) {
 	}{
 		// Transaction with an input which includes witness data, and
 		// one output.
-		{multiWitnessTx, 190},
+		{multiWitnessTx, 195},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -778,6 +1223,76 @@ func TestTxWitnessSize(t *testing.T) {
 	}
 }
 
+// TestTxEqual tests the TxEqual function with and without considering witness
+// data.
+func TestTxEqual(t *testing.T) {
+	// Witness stripped copy of the witness transaction.
+	strippedTx := multiWitnessTx.Copy()
+	for _, txIn := range strippedTx.TxIn {
+		txIn.Witness = nil
+	}
+
+	// Copy with a modified witness item.
+	modifiedWitnessTx := multiWitnessTx.Copy()
+	modifiedWitnessTx.TxIn[0].Witness[1][0] ^= 0x01
+
+	// Copy with an extra witness item.
+	extraWitnessTx := multiWitnessTx.Copy()
+	extraWitnessTx.TxIn[0].Witness = append(extraWitnessTx.TxIn[0].Witness,
+		[]byte{0x01})
+
+	// Copy with a modified output value.
+	modifiedValueTx := multiWitnessTx.Copy()
+	modifiedValueTx.TxOut[0].Value++
+
+	// Copy with a modified time.
+	modifiedTimeTx := multiTx.Copy()
+	modifiedTimeTx.Time++
+
+	// Copy with the outputs reordered.
+	reorderedTx := multiTx.Copy()
+	reorderedTx.TxOut[0], reorderedTx.TxOut[1] = reorderedTx.TxOut[1],
+		reorderedTx.TxOut[0]
+
+	tests := []struct {
+		name           string
+		a, b           *MsgTx
+		includeWitness bool
+		want           bool
+	}{
+		{"identical", multiTx, multiTx.Copy(), true, true},
+		{"identical witness", multiWitnessTx, multiWitnessTx.Copy(), true, true},
+		{"both nil", nil, nil, true, true},
+		{"one nil", multiTx, nil, false, false},
+		{"different txs", multiTx, multiWitnessTx, false, false},
+		{"stripped with witness", multiWitnessTx, strippedTx, true, false},
+		{"stripped without witness", multiWitnessTx, strippedTx, false, true},
+		{"modified witness", multiWitnessTx, modifiedWitnessTx, true, false},
+		{"modified witness ignored", multiWitnessTx, modifiedWitnessTx, false, true},
+		{"extra witness item", multiWitnessTx, extraWitnessTx, true, false},
+		{"modified value", multiWitnessTx, modifiedValueTx, false, false},
+		{"modified time", multiTx, modifiedTimeTx, false, false},
+		{"reordered outputs", multiTx, reorderedTx, false, false},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		got := TxEqual(test.a, test.b, test.includeWitness)
+		if got != test.want {
+			t.Errorf("TxEqual #%d (%s): got %v, want %v", i,
+				test.name, got, test.want)
+			continue
+		}
+
+		// Equality must be symmetric.
+		got = TxEqual(test.b, test.a, test.includeWitness)
+		if got != test.want {
+			t.Errorf("TxEqual #%d (%s) reversed: got %v, want %v",
+				i, test.name, got, test.want)
+		}
+	}
+}
+
 // multiTx is a MsgTx with an input and output and used in various tests.
 var multiTx = &MsgTx{
 	Version: 1,
@@ -0,0 +1,92 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"sort"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// OutPointSet is a set of outpoints optimized for membership queries.  Rather
+// than keying every outpoint individually, the output indexes are grouped by
+// transaction hash so the hash is only stored once regardless of how many
+// outputs of the same transaction are in the set.  This makes it considerably
+// more memory efficient than a map[OutPoint]struct{} when the set holds
+// multiple outputs of the same transactions, which is typical of UTXO queries.
+//
+// The grouping is not free.  Every transaction hash carries a slice header and
+// a separately allocated backing array, so a set holding a single output of
+// most transactions uses more memory than the plain map.  Also, since the
+// indexes of a transaction are kept sorted, adding or removing one of its k
+// outputs is O(k) rather than O(1).
+//
+// An OutPointSet is not safe for concurrent access.
+type OutPointSet struct {
+	// indexes houses the sorted output indexes in the set for each
+	// transaction hash.
+	indexes map[chainhash.Hash][]uint32
+	count   int
+}
+
+// NewOutPointSet returns a new empty outpoint set.
+func NewOutPointSet() *OutPointSet {
+	return &OutPointSet{
+		indexes: make(map[chainhash.Hash][]uint32),
+	}
+}
+
+// findOutputIndex returns the position of the passed output index within
+// the sorted indexes and whether it is present.
+func findOutputIndex(indexes []uint32, index uint32) (int, bool) {
+	i := sort.Search(len(indexes), func(i int) bool {
+		return indexes[i] >= index
+	})
+	return i, i < len(indexes) && indexes[i] == index
+}
+
+// Add adds the passed outpoint to the set.  It has no effect if the outpoint
+// is already in the set.
+func (s *OutPointSet) Add(op OutPoint) {
+	indexes := s.indexes[op.Hash]
+	i, ok := findOutputIndex(indexes, op.Index)
+	if ok {
+		return
+	}
+
+	indexes = append(indexes, 0)
+	copy(indexes[i+1:], indexes[i:])
+	indexes[i] = op.Index
+	s.indexes[op.Hash] = indexes
+	s.count++
+}
+
+// Contains returns whether or not the passed outpoint is in the set.
+func (s *OutPointSet) Contains(op OutPoint) bool {
+	_, ok := findOutputIndex(s.indexes[op.Hash], op.Index)
+	return ok
+}
+
+// Remove removes the passed outpoint from the set.  It has no effect if the
+// outpoint is not in the set.
+func (s *OutPointSet) Remove(op OutPoint) {
+	indexes := s.indexes[op.Hash]
+	i, ok := findOutputIndex(indexes, op.Index)
+	if !ok {
+		return
+	}
+
+	s.count--
+	if len(indexes) == 1 {
+		delete(s.indexes, op.Hash)
+		return
+	}
+	s.indexes[op.Hash] = append(indexes[:i], indexes[i+1:]...)
+}
+
+// Len returns the number of outpoints in the set.
+func (s *OutPointSet) Len() int {
+	return s.count
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/binary"
+	"runtime"
+	"testing"
+
+	"github.com/navcoin/navd/chaincfg/chainhash"
+)
+
+// TestOutPointSet tests the OutPointSet API.
+func TestOutPointSet(t *testing.T) {
+	t.Parallel()
+
+	hash1 := chainhash.Hash{0x01}
+	hash2 := chainhash.Hash{0x02}
+	op1 := OutPoint{Hash: hash1, Index: 0}
+	op2 := OutPoint{Hash: hash1, Index: 5}
+	op3 := OutPoint{Hash: hash1, Index: 2}
+	op4 := OutPoint{Hash: hash2, Index: 5}
+
+	s := NewOutPointSet()
+	if s.Len() != 0 || s.Contains(op1) {
+		t.Fatalf("new set is not empty")
+	}
+
+	// Ensure outpoints sharing a transaction hash are tracked individually
+	// and that adding a duplicate has no effect.
+	for _, op := range []OutPoint{op2, op1, op3, op2} {
+		s.Add(op)
+	}
+	if s.Len() != 3 {
+		t.Fatalf("Len: got %d, want 3", s.Len())
+	}
+	for _, op := range []OutPoint{op1, op2, op3} {
+		if !s.Contains(op) {
+			t.Fatalf("Contains: %v not in set", op)
+		}
+	}
+	notAdded := []OutPoint{op4, {Hash: hash1, Index: 1},
+		{Hash: hash1, Index: 6}}
+	for _, op := range notAdded {
+		if s.Contains(op) {
+			t.Fatalf("Contains: unexpected %v in set", op)
+		}
+	}
+
+	// Ensure removing an outpoint leaves the others with the same hash and
+	// that removing one that is not in the set has no effect.
+	s.Add(op4)
+	s.Remove(op3)
+	s.Remove(op3)
+	s.Remove(OutPoint{Hash: chainhash.Hash{0x03}, Index: 0})
+	if s.Len() != 3 || s.Contains(op3) {
+		t.Fatalf("Remove: %v still in set or unexpected length %d",
+			op3, s.Len())
+	}
+	for _, op := range []OutPoint{op1, op2, op4} {
+		if !s.Contains(op) {
+			t.Fatalf("Remove: %v no longer in set", op)
+		}
+	}
+
+	// Ensure an outpoint may be added again after removing every outpoint
+	// with its hash.
+	s.Remove(op1)
+	s.Remove(op2)
+	if s.Len() != 1 || s.Contains(op1) || s.Contains(op2) {
+		t.Fatalf("Remove: unexpected set contents after removing all " +
+			"outpoints of a transaction")
+	}
+	s.Add(op2)
+	if s.Len() != 2 || !s.Contains(op2) {
+		t.Fatalf("Add: %v not in set after re-adding", op2)
+	}
+}
+
+// makeTestOutPoints returns outpoints for the passed number of outputs of
+// each of the passed number of distinct transactions.
+func makeTestOutPoints(numTxns, outputsPerTx int) []OutPoint {
+	outPoints := make([]OutPoint, 0, numTxns*outputsPerTx)
+	for i := 0; i < numTxns; i++ {
+		var hash chainhash.Hash
+		binary.LittleEndian.PutUint32(hash[:], uint32(i))
+		for j := 0; j < outputsPerTx; j++ {
+			outPoints = append(outPoints, OutPoint{hash, uint32(j)})
+		}
+	}
+	return outPoints
+}
+
+// TestOutPointSetMemory ensures an OutPointSet holding many outputs of the
+// same transactions uses less memory than the equivalent plain map.
+//
+// NOTE: This test is intentionally not run in parallel so the memory
+// allocated by other tests is not attributed to the sets.
+func TestOutPointSetMemory(t *testing.T) {
+	outPoints := makeTestOutPoints(1000, 50)
+
+	// allocated returns the number of bytes allocated by the passed
+	// function.
+	allocated := func(f func()) uint64 {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		f()
+		runtime.ReadMemStats(&after)
+		return after.TotalAlloc - before.TotalAlloc
+	}
+
+	var set *OutPointSet
+	setBytes := allocated(func() {
+		set = NewOutPointSet()
+		for _, op := range outPoints {
+			set.Add(op)
+		}
+	})
+	var naive map[OutPoint]struct{}
+	naiveBytes := allocated(func() {
+		naive = make(map[OutPoint]struct{})
+		for _, op := range outPoints {
+			naive[op] = struct{}{}
+		}
+	})
+	if set.Len() != len(naive) {
+		t.Fatalf("Len: got %d, want %d", set.Len(), len(naive))
+	}
+	if setBytes >= naiveBytes {
+		t.Fatalf("set allocated %d bytes, which is not less than the %d "+
+			"bytes allocated by a plain map", setBytes, naiveBytes)
+	}
+}
+
+// benchmarkOutPointSet benchmarks adding the passed outpoints to a new
+// OutPointSet.
+func benchmarkOutPointSet(b *testing.B, outPoints []OutPoint) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		set := NewOutPointSet()
+		for _, op := range outPoints {
+			set.Add(op)
+		}
+	}
+}
+
+// benchmarkOutPointMap benchmarks adding the passed outpoints to a new plain
+// map for comparison with OutPointSet.
+func benchmarkOutPointMap(b *testing.B, outPoints []OutPoint) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		set := make(map[OutPoint]struct{})
+		for _, op := range outPoints {
+			set[op] = struct{}{}
+		}
+	}
+}
+
+// BenchmarkOutPointSetOneOutputPerTx performs a benchmark on building an
+// OutPointSet holding a single output of each transaction, which is the case
+// where it uses more memory than a plain map.
+func BenchmarkOutPointSetOneOutputPerTx(b *testing.B) {
+	benchmarkOutPointSet(b, makeTestOutPoints(50000, 1))
+}
+
+// BenchmarkOutPointMapOneOutputPerTx performs a benchmark on building a plain
+// map holding a single output of each transaction.
+func BenchmarkOutPointMapOneOutputPerTx(b *testing.B) {
+	benchmarkOutPointMap(b, makeTestOutPoints(50000, 1))
+}
+
+// BenchmarkOutPointSetManyOutputsPerTx performs a benchmark on building an
+// OutPointSet holding many outputs of each transaction.
+func BenchmarkOutPointSetManyOutputsPerTx(b *testing.B) {
+	benchmarkOutPointSet(b, makeTestOutPoints(1000, 50))
+}
+
+// BenchmarkOutPointMapManyOutputsPerTx performs a benchmark on building a
+// plain map holding many outputs of each transaction.
+func BenchmarkOutPointMapManyOutputsPerTx(b *testing.B) {
+	benchmarkOutPointMap(b, makeTestOutPoints(1000, 50))
+}